@@ -15,6 +15,7 @@ import (
 	core "github.com/v2fly/v2ray-core/v5"
 	"github.com/v2fly/v2ray-core/v5/app/dns/fakedns"
 	"github.com/v2fly/v2ray-core/v5/app/router"
+	"github.com/v2fly/v2ray-core/v5/app/ruleset"
 	"github.com/v2fly/v2ray-core/v5/common"
 	"github.com/v2fly/v2ray-core/v5/common/errors"
 	"github.com/v2fly/v2ray-core/v5/common/net"
@@ -94,6 +95,9 @@ func New(ctx context.Context, config *Config) (*DNS, error) {
 	if err := establishDomainRules(s, config, nsClientMap); err != nil {
 		return nil, err
 	}
+	if err := establishRuleSets(s, config, nsClientMap); err != nil {
+		return nil, err
+	}
 	if err := establishExpectedIPs(s, config, nsClientMap); err != nil {
 		return nil, err
 	}
@@ -166,6 +170,29 @@ func establishDomainRules(s *DNS, config *Config, nsClientMap map[int]int) error
 	return nil
 }
 
+func establishRuleSets(s *DNS, config *Config, nsClientMap map[int]int) error {
+	var matchers []*router.RuleSetMatcher
+	for nsIdx, ns := range config.NameServer {
+		if len(ns.RuleSet) == 0 {
+			continue
+		}
+		matcher := router.NewRuleSetMatcher(config.DomainMatcher, ns.RuleSet, false)
+		s.clients[nsClientMap[nsIdx]].ruleSets = matcher
+		matchers = append(matchers, matcher)
+	}
+	if len(matchers) == 0 {
+		return nil
+	}
+	return core.RequireFeatures(s.ctx, func(manager ruleset.Manager) error {
+		for _, matcher := range matchers {
+			if err := matcher.Bind(manager); err != nil {
+				return newError("failed to bind rule sets").Base(err)
+			}
+		}
+		return nil
+	})
+}
+
 func establishExpectedIPs(s *DNS, config *Config, nsClientMap map[int]int) error {
 	geoipContainer := router.GeoIPMatcherContainer{}
 	for nsIdx, ns := range config.NameServer {
@@ -321,21 +348,46 @@ func (s *DNS) sortClients(domain string, option dns.IPOption) []*Client {
 	clientIdxs := make([]int, 0, len(s.clients))
 	domainRules := []string{}
 
+	use := func(idx int) {
+		client := s.clients[idx]
+		switch {
+		case clientUsed[idx]:
+			return
+		case !option.FakeEnable && isFakeDNS(client.server):
+			return
+		}
+		clientUsed[idx] = true
+		clients = append(clients, client)
+		clientIdxs = append(clientIdxs, idx)
+	}
+
+	// Rule sets are part of the domain lists of their name servers, so the clients matching by rule sets
+	// are ranked among the ones matching by prioritized domains in the order of the name servers.
+	var ruleSetMatches []int
+	for idx, client := range s.clients {
+		if client.ruleSets == nil {
+			continue
+		}
+		if name, matched := client.ruleSets.MatchDomain(domain); matched {
+			domainRules = append(domainRules, fmt.Sprintf("ruleset:%s(DNS idx:%d)", name, idx))
+			ruleSetMatches = append(ruleSetMatches, idx)
+		}
+	}
+
 	// Priority domain matching
 	for _, match := range s.domainMatcher.Match(domain) {
 		info := s.matcherInfos[match]
 		client := s.clients[info.clientIdx]
 		domainRule := client.domains[info.domainRuleIdx]
 		domainRules = append(domainRules, fmt.Sprintf("%s(DNS idx:%d)", domainRule, info.clientIdx))
-		switch {
-		case clientUsed[info.clientIdx]:
-			continue
-		case !option.FakeEnable && isFakeDNS(client.server):
-			continue
+		for len(ruleSetMatches) > 0 && ruleSetMatches[0] <= int(info.clientIdx) {
+			use(ruleSetMatches[0])
+			ruleSetMatches = ruleSetMatches[1:]
 		}
-		clientUsed[info.clientIdx] = true
-		clients = append(clients, client)
-		clientIdxs = append(clientIdxs, int(info.clientIdx))
+		use(int(info.clientIdx))
+	}
+	for _, idx := range ruleSetMatches {
+		use(idx)
 	}
 
 	// Default round-robin query
@@ -441,6 +493,7 @@ func init() {
 				FallbackStrategy: v.FallbackStrategy,
 				SkipFallback:     v.SkipFallback,
 				Geoip:            v.Geoip,
+				RuleSet:          v.RuleSet,
 			}
 			for _, prioritizedDomain := range v.PrioritizedDomain {
 				nameserver.PrioritizedDomain = append(nameserver.PrioritizedDomain, &NameServer_PriorityDomain{
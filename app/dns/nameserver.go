@@ -36,6 +36,7 @@ type Client struct {
 	fallbackStrategy FallbackStrategy
 
 	domains   []string
+	ruleSets  *router.RuleSetMatcher
 	expectIPs []*router.GeoIPMatcher
 	fakeDNS   Server
 }
@@ -0,0 +1,83 @@
+package dns
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/anypb"
+
+	core "github.com/v2fly/v2ray-core/v5"
+	"github.com/v2fly/v2ray-core/v5/app/dispatcher"
+	"github.com/v2fly/v2ray-core/v5/app/policy"
+	"github.com/v2fly/v2ray-core/v5/app/proxyman"
+	_ "github.com/v2fly/v2ray-core/v5/app/proxyman/outbound"
+	"github.com/v2fly/v2ray-core/v5/app/ruleset"
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/serial"
+	feature_dns "github.com/v2fly/v2ray-core/v5/features/dns"
+	"github.com/v2fly/v2ray-core/v5/proxy/freedom"
+)
+
+func TestRuleSetDomainOrder(t *testing.T) {
+	ruleSetPath := filepath.Join(t.TempDir(), "rules.txt")
+	common.Must(os.WriteFile(ruleSetPath, []byte("full:google.com\n"), 0o644))
+
+	nameServer := func(port uint32) *net.Endpoint {
+		return &net.Endpoint{
+			Network: net.Network_UDP,
+			Address: &net.IPOrDomain{
+				Address: &net.IPOrDomain_Ip{
+					Ip: []byte{127, 0, 0, 1},
+				},
+			},
+			Port: port,
+		}
+	}
+	config := &core.Config{
+		App: []*anypb.Any{
+			serial.ToTypedMessage(&Config{
+				NameServer: []*NameServer{
+					{
+						Address: nameServer(9999),
+						PrioritizedDomain: []*NameServer_PriorityDomain{
+							{Type: DomainMatchingType_Full, Domain: "example.com"},
+						},
+					},
+					{
+						Address: nameServer(9998),
+						RuleSet: []string{"test"},
+					},
+					{
+						Address: nameServer(9997),
+						PrioritizedDomain: []*NameServer_PriorityDomain{
+							{Type: DomainMatchingType_Subdomain, Domain: "google.com"},
+						},
+					},
+				},
+			}),
+			serial.ToTypedMessage(&ruleset.Config{
+				Provider: []*ruleset.Provider{{Name: "test", Path: ruleSetPath}},
+			}),
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&policy.Config{}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	}
+
+	v, err := core.New(config)
+	common.Must(err)
+	s := v.GetFeature(feature_dns.ClientType()).(*DNS)
+
+	// The rule set of the second name server ranks before the prioritized domain of the third one.
+	clients := s.sortClients("google.com", feature_dns.IPOption{IPv4Enable: true})
+	if len(clients) != 3 || clients[0] != s.clients[1] || clients[1] != s.clients[2] || clients[2] != s.clients[0] {
+		t.Error("unexpected order of clients")
+	}
+}
@@ -0,0 +1,100 @@
+package alert
+
+//go:generate go run github.com/v2fly/v2ray-core/v5/common/errors/errorgen
+
+import (
+	"context"
+	"time"
+
+	core "github.com/v2fly/v2ray-core/v5"
+	"github.com/v2fly/v2ray-core/v5/app/observatory"
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/features/extension"
+)
+
+const watchRetryInterval = 10 * time.Second
+
+// Alert sends the events of the observatory to webhooks.
+type Alert struct {
+	config      *Config
+	ctx         context.Context
+	observatory extension.Observatory
+	webhooks    []*webhook
+
+	cancel context.CancelFunc
+}
+
+func New(ctx context.Context, config *Config) (*Alert, error) {
+	a := &Alert{
+		config: config,
+		ctx:    ctx,
+	}
+	for _, webhookConfig := range config.Webhook {
+		if webhookConfig.Url == "" {
+			return nil, newError("webhook url is not specified")
+		}
+		a.webhooks = append(a.webhooks, newWebhook(webhookConfig))
+	}
+	err := core.RequireFeatures(ctx, func(observatory extension.Observatory) {
+		a.observatory = observatory
+	})
+	if err != nil {
+		return nil, newError("cannot get depended features").Base(err)
+	}
+	return a, nil
+}
+
+// Type implements common.HasType.
+func (a *Alert) Type() interface{} {
+	return (*Alert)(nil)
+}
+
+// Start implements common.Runnable.
+func (a *Alert) Start() error {
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.cancel = cancel
+	for _, w := range a.webhooks {
+		go w.run(ctx)
+	}
+	go a.watch(ctx)
+	return nil
+}
+
+// Close implements common.Closable.
+func (a *Alert) Close() error {
+	if a.cancel != nil {
+		a.cancel()
+	}
+	return nil
+}
+
+func (a *Alert) watch(ctx context.Context) {
+	for {
+		err := observatory.WatchEvents(ctx, a.observatory, a.config.ObserverTag,
+			time.Duration(a.config.PollInterval), time.Duration(a.config.LatencyThreshold),
+			func(events []*observatory.OutboundEvent) error {
+				for _, event := range events {
+					newError("outbound ", event.OutboundTag, " event: ", event.Type).AtInfo().WriteToLog()
+					for _, w := range a.webhooks {
+						w.send(event)
+					}
+				}
+				return nil
+			})
+		if ctx.Err() != nil {
+			return
+		}
+		newError("failed to watch outbound events").Base(err).AtWarning().WriteToLog()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchRetryInterval):
+		}
+	}
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return New(ctx, config.(*Config))
+	}))
+}
@@ -0,0 +1,126 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+
+	"github.com/v2fly/v2ray-core/v5/app/observatory"
+)
+
+const (
+	defaultWebhookMaxRetry = 5
+	defaultWebhookBackoff  = time.Second
+	defaultWebhookTimeout  = 10 * time.Second
+	maxWebhookBackoff      = time.Minute
+	webhookQueueSize       = 64
+)
+
+// webhook posts events to a url in order, retrying failed deliveries with exponential backoff.
+type webhook struct {
+	url      string
+	headers  map[string]string
+	maxRetry int
+	backoff  time.Duration
+	client   *http.Client
+	queue    chan []byte
+}
+
+func newWebhook(config *WebhookConfig) *webhook {
+	w := &webhook{
+		url:      config.Url,
+		headers:  config.Headers,
+		maxRetry: int(config.MaxRetry),
+		backoff:  time.Duration(config.Backoff),
+		queue:    make(chan []byte, webhookQueueSize),
+	}
+	if w.maxRetry == 0 {
+		w.maxRetry = defaultWebhookMaxRetry
+	}
+	if w.backoff <= 0 {
+		w.backoff = defaultWebhookBackoff
+	}
+	timeout := time.Duration(config.Timeout)
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+	w.client = &http.Client{Timeout: timeout}
+	return w
+}
+
+// send queues the event for delivery, the event is dropped if the queue is full.
+func (w *webhook) send(event *observatory.OutboundEvent) {
+	body, err := (&jsonpb.Marshaler{}).MarshalToString(event)
+	if err != nil {
+		newError("failed to marshal outbound event").Base(err).AtWarning().WriteToLog()
+		return
+	}
+	select {
+	case w.queue <- []byte(body):
+	default:
+		newError("webhook ", w.url, " is congested, outbound event dropped").AtWarning().WriteToLog()
+	}
+}
+
+func (w *webhook) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case body := <-w.queue:
+			w.deliver(ctx, body)
+		}
+	}
+}
+
+func (w *webhook) deliver(ctx context.Context, body []byte) {
+	backoff := w.backoff
+	for retry := 0; ; retry++ {
+		retryable, err := w.post(ctx, body)
+		if err == nil {
+			return
+		}
+		if !retryable || retry >= w.maxRetry {
+			newError("failed to deliver outbound event to webhook ", w.url).Base(err).AtWarning().WriteToLog()
+			return
+		}
+		newError("failed to deliver outbound event to webhook ", w.url, ", retrying in ", backoff).Base(err).AtDebug().WriteToLog()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > maxWebhookBackoff {
+			backoff = maxWebhookBackoff
+		}
+	}
+}
+
+// post sends the body to the webhook, and returns whether a failed delivery should be retried.
+func (w *webhook) post(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range w.headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, newError("unexpected status ", resp.Status)
+	default:
+		return false, newError("unexpected status ", resp.Status)
+	}
+}
@@ -0,0 +1,70 @@
+package alert
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/app/observatory"
+)
+
+func TestWebhookRetry(t *testing.T) {
+	var requests int32
+	bodies := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if atomic.AddInt32(&requests, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		bodies <- string(body)
+	}))
+	defer server.Close()
+
+	w := newWebhook(&WebhookConfig{
+		Url:     server.URL,
+		Headers: map[string]string{"Authorization": "Bearer token"},
+		Backoff: int64(10 * time.Millisecond),
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.run(ctx)
+
+	w.send(&observatory.OutboundEvent{Type: observatory.OutboundEvent_Dead, OutboundTag: "proxy"})
+
+	select {
+	case body := <-bodies:
+		if !strings.Contains(body, `"outboundTag":"proxy"`) || !strings.Contains(body, `"type":"Dead"`) {
+			t.Error("unexpected body: ", body)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("event is not delivered")
+	}
+	if n := atomic.LoadInt32(&requests); n != 3 {
+		t.Error("unexpected requests: ", n)
+	}
+}
+
+func TestWebhookNoRetryOnClientError(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	w := newWebhook(&WebhookConfig{Url: server.URL, Backoff: int64(time.Millisecond)})
+	w.deliver(context.Background(), []byte("{}"))
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Error("unexpected requests: ", n)
+	}
+}
@@ -23,32 +23,45 @@ type Observer struct {
 
 	finished *done.Instance
 
-	ohm outbound.Manager
+	ohm     outbound.Manager
+	passive *observatory.PassiveObservation
 }
 
 func (o *Observer) GetObservation(ctx context.Context) (proto.Message, error) {
+	if o.passive != nil {
+		return &observatory.ObservationResult{Status: o.passive.Merge(o.createResult())}, nil
+	}
 	return &observatory.ObservationResult{Status: o.createResult()}, nil
 }
 
+// ObserveTraffic implements extension.PassiveObserver.
+func (o *Observer) ObserveTraffic(observation *extension.TrafficObservation) {
+	if o.passive != nil {
+		o.passive.Observe(observation)
+	}
+}
+
 func (o *Observer) createResult() []*observatory.OutboundStatus {
 	var result []*observatory.OutboundStatus
 	o.hp.access.Lock()
 	defer o.hp.access.Unlock()
 	for name, value := range o.hp.Results {
+		lastTry, lastSeen := value.lastTryAndSeen()
 		status := observatory.OutboundStatus{
 			Alive:           value.getStatistics().All != value.getStatistics().Fail,
 			Delay:           value.getStatistics().Average.Milliseconds(),
 			LastErrorReason: "",
 			OutboundTag:     name,
-			LastSeenTime:    0,
-			LastTryTime:     0,
+			LastSeenTime:    lastSeen,
+			LastTryTime:     lastTry,
 			HealthPing: &observatory.HealthPingMeasurementResult{
-				All:       int64(value.getStatistics().All),
-				Fail:      int64(value.getStatistics().Fail),
-				Deviation: int64(value.getStatistics().Deviation),
-				Average:   int64(value.getStatistics().Average),
-				Max:       int64(value.getStatistics().Max),
-				Min:       int64(value.getStatistics().Min),
+				All:        int64(value.getStatistics().All),
+				Fail:       int64(value.getStatistics().Fail),
+				Deviation:  int64(value.getStatistics().Deviation),
+				Average:    int64(value.getStatistics().Average),
+				Max:        int64(value.getStatistics().Max),
+				Min:        int64(value.getStatistics().Min),
+				Throughput: value.getStatistics().Throughput,
 			},
 		}
 		result = append(result, &status)
@@ -94,10 +107,11 @@ func New(ctx context.Context, config *Config) (*Observer, error) {
 	}
 	hp := NewHealthPing(ctx, config.PingConfig)
 	return &Observer{
-		config: config,
-		ctx:    ctx,
-		ohm:    outboundManager,
-		hp:     hp,
+		config:  config,
+		ctx:     ctx,
+		ohm:     outboundManager,
+		hp:      hp,
+		passive: observatory.NewPassiveObservation(config.Passive, config.SubjectSelector),
 	}, nil
 }
 
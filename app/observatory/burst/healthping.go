@@ -12,11 +12,12 @@ import (
 
 // HealthPingSettings holds settings for health Checker
 type HealthPingSettings struct {
-	Destination   string        `json:"destination"`
-	Connectivity  string        `json:"connectivity"`
-	Interval      time.Duration `json:"interval"`
-	SamplingCount int           `json:"sampling"`
-	Timeout       time.Duration `json:"timeout"`
+	Destination   string         `json:"destination"`
+	Connectivity  string         `json:"connectivity"`
+	Interval      time.Duration  `json:"interval"`
+	SamplingCount int            `json:"sampling"`
+	Timeout       time.Duration  `json:"timeout"`
+	Probes        []*ProbeConfig `json:"probes"`
 }
 
 // HealthPing is the health checker for balancers
@@ -40,6 +41,7 @@ func NewHealthPing(ctx context.Context, config *HealthPingConfig) *HealthPing {
 			Interval:      time.Duration(config.Interval),
 			SamplingCount: int(config.SamplingCount),
 			Timeout:       time.Duration(config.Timeout),
+			Probes:        config.Probes,
 		}
 	}
 	if settings.Destination == "" {
@@ -122,8 +124,9 @@ func (h *HealthPing) Check(tags []string) error {
 }
 
 type rtt struct {
-	handler string
-	value   time.Duration
+	handler    string
+	value      time.Duration
+	throughput int64
 }
 
 // doCheck performs the 'rounds' amount checks in given 'duration'. You should make
@@ -137,12 +140,7 @@ func (h *HealthPing) doCheck(tags []string, duration time.Duration, rounds int)
 
 	for _, tag := range tags {
 		handler := tag
-		client := newPingClient(
-			h.ctx,
-			h.Settings.Destination,
-			h.Settings.Timeout,
-			handler,
-		)
+		client := newProber(h.ctx, h.Settings, handler)
 		for i := 0; i < rounds; i++ {
 			delay := time.Duration(0)
 			if duration > 0 {
@@ -150,11 +148,19 @@ func (h *HealthPing) doCheck(tags []string, duration time.Duration, rounds int)
 			}
 			time.AfterFunc(delay, func() {
 				newError("checking ", handler).AtDebug().WriteToLog()
-				delay, err := client.MeasureDelay()
+				var delay time.Duration
+				var throughput int64
+				var err error
+				if measurer, ok := client.(throughputMeasurer); ok {
+					delay, throughput, err = measurer.MeasureThroughput()
+				} else {
+					delay, err = client.MeasureDelay()
+				}
 				if err == nil {
 					ch <- &rtt{
-						handler: handler,
-						value:   delay,
+						handler:    handler,
+						value:      delay,
+						throughput: throughput,
 					}
 					return
 				}
@@ -167,8 +173,7 @@ func (h *HealthPing) doCheck(tags []string, duration time.Duration, rounds int)
 					return
 				}
 				newError(fmt.Sprintf(
-					"error ping %s with %s: %s",
-					h.Settings.Destination,
+					"error probing with %s: %s",
 					handler,
 					err,
 				)).AtWarning().WriteToLog()
@@ -183,13 +188,17 @@ func (h *HealthPing) doCheck(tags []string, duration time.Duration, rounds int)
 		rtt := <-ch
 		if rtt.value > 0 {
 			// should not put results when network is down
-			h.PutResult(rtt.handler, rtt.value)
+			h.putResult(rtt.handler, rtt.value, rtt.throughput)
 		}
 	}
 }
 
 // PutResult puts a ping rtt to results
 func (h *HealthPing) PutResult(tag string, rtt time.Duration) {
+	h.putResult(tag, rtt, 0)
+}
+
+func (h *HealthPing) putResult(tag string, rtt time.Duration, throughput int64) {
 	h.access.Lock()
 	defer h.access.Unlock()
 	if h.Results == nil {
@@ -205,7 +214,7 @@ func (h *HealthPing) PutResult(tag string, rtt time.Duration) {
 		r = NewHealthPingResult(h.Settings.SamplingCount, validity)
 		h.Results[tag] = r
 	}
-	r.Put(rtt)
+	r.PutWithThroughput(rtt, throughput)
 }
 
 // Cleanup removes results of removed handlers,
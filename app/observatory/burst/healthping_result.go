@@ -13,6 +13,8 @@ type HealthPingStats struct {
 	Average   time.Duration
 	Max       time.Duration
 	Min       time.Duration
+	// Throughput is the average download speed in bytes per second, if measured
+	Throughput int64
 }
 
 // HealthPingRTTS holds ping rtts for health Checker
@@ -27,8 +29,9 @@ type HealthPingRTTS struct {
 }
 
 type pingRTT struct {
-	time  time.Time
-	value time.Duration
+	time       time.Time
+	value      time.Duration
+	throughput int64
 }
 
 // NewHealthPingResult returns a *HealthPingResult with specified capacity
@@ -56,6 +59,12 @@ func (h *HealthPingRTTS) GetWithCache() *HealthPingStats {
 
 // Put puts a new rtt to the HealthPingResult
 func (h *HealthPingRTTS) Put(d time.Duration) {
+	h.PutWithThroughput(d, 0)
+}
+
+// PutWithThroughput puts a new rtt to the HealthPingResult, along with the download speed
+// in bytes per second measured by the same probe
+func (h *HealthPingRTTS) PutWithThroughput(d time.Duration, throughput int64) {
 	if h.rtts == nil {
 		h.rtts = make([]*pingRTT, h.cap)
 		for i := 0; i < h.cap; i++ {
@@ -67,6 +76,7 @@ func (h *HealthPingRTTS) Put(d time.Duration) {
 	now := time.Now()
 	h.rtts[h.idx].time = now
 	h.rtts[h.idx].value = d
+	h.rtts[h.idx].throughput = throughput
 }
 
 func (h *HealthPingRTTS) calcIndex(step int) int {
@@ -85,6 +95,7 @@ func (h *HealthPingRTTS) getStatistics() *HealthPingStats {
 	stats.Min = rttFailed
 	sum := time.Duration(0)
 	cnt := 0
+	var throughputSum, throughputCnt int64
 	validRTTs := make([]time.Duration, 0)
 	for _, rtt := range h.rtts {
 		switch {
@@ -96,6 +107,10 @@ func (h *HealthPingRTTS) getStatistics() *HealthPingStats {
 		}
 		cnt++
 		sum += rtt.value
+		if rtt.throughput > 0 {
+			throughputSum += rtt.throughput
+			throughputCnt++
+		}
 		validRTTs = append(validRTTs, rtt.value)
 		if stats.Max < rtt.value {
 			stats.Max = rtt.value
@@ -110,6 +125,9 @@ func (h *HealthPingRTTS) getStatistics() *HealthPingStats {
 		return stats
 	}
 	stats.Average = time.Duration(int(sum) / cnt)
+	if throughputCnt > 0 {
+		stats.Throughput = throughputSum / throughputCnt
+	}
 	var std float64
 	if cnt < 2 {
 		// no enough data for standard deviation, we assume it's half of the average rtt
@@ -127,6 +145,30 @@ func (h *HealthPingRTTS) getStatistics() *HealthPingStats {
 	return stats
 }
 
+// lastTryAndSeen returns the unix time of the latest ping, and of the latest successful ping.
+func (h *HealthPingRTTS) lastTryAndSeen() (int64, int64) {
+	var lastTry, lastSeen time.Time
+	for _, rtt := range h.rtts {
+		if rtt.value == 0 {
+			continue
+		}
+		if rtt.time.After(lastTry) {
+			lastTry = rtt.time
+		}
+		if rtt.value != rttFailed && rtt.time.After(lastSeen) {
+			lastSeen = rtt.time
+		}
+	}
+	var try, seen int64
+	if !lastTry.IsZero() {
+		try = lastTry.Unix()
+	}
+	if !lastSeen.IsZero() {
+		seen = lastSeen.Unix()
+	}
+	return try, seen
+}
+
 func (h *HealthPingRTTS) findOutdated(now time.Time) int {
 	for i := h.cap - 1; i < 2*h.cap; i++ {
 		// from oldest to latest
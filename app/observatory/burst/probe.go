@@ -0,0 +1,259 @@
+package burst
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/v2fly/v2ray-core/v5/common/dice"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/transport/internet/tagged"
+)
+
+const (
+	defaultTLSProbeDestination = "www.gstatic.com:443"
+	defaultDNSProbeDestination = "8.8.8.8:53"
+	defaultDNSProbeDomain      = "www.gstatic.com"
+	defaultThroughputSize      = 1024 * 1024
+)
+
+// prober measures the delay of an outbound
+type prober interface {
+	MeasureDelay() (time.Duration, error)
+}
+
+// throughputMeasurer is a prober which also measures the download speed of an outbound
+type throughputMeasurer interface {
+	// MeasureThroughput returns the delay and the download speed in bytes per second
+	MeasureThroughput() (time.Duration, int64, error)
+}
+
+// newProber creates the prober for the handler with the first probe matching it
+func newProber(ctx context.Context, settings *HealthPingSettings, handler string) prober {
+	for _, probe := range settings.Probes {
+		if !matchSelectors(handler, probe.SubjectSelector) {
+			continue
+		}
+		switch probe.Type {
+		case ProbeConfig_TCP:
+			return &tcpProber{ctx: ctx, handler: handler, destination: probe.Destination, payload: probe.Payload, timeout: settings.Timeout}
+		case ProbeConfig_TLS:
+			destination := probe.Destination
+			if destination == "" {
+				destination = defaultTLSProbeDestination
+			}
+			return &tlsProber{ctx: ctx, handler: handler, destination: destination, serverName: probe.ServerName, timeout: settings.Timeout}
+		case ProbeConfig_DNS:
+			destination := probe.Destination
+			if destination == "" {
+				destination = defaultDNSProbeDestination
+			}
+			domain := probe.Domain
+			if domain == "" {
+				domain = defaultDNSProbeDomain
+			}
+			return &dnsProber{ctx: ctx, handler: handler, destination: destination, domain: domain, timeout: settings.Timeout}
+		case ProbeConfig_THROUGHPUT:
+			size := probe.Size
+			if size <= 0 {
+				size = defaultThroughputSize
+			}
+			return &throughputProber{destination: probe.Destination, size: size, httpClient: newHTTPClient(ctx, handler, settings.Timeout)}
+		default:
+			destination := probe.Destination
+			if destination == "" {
+				destination = settings.Destination
+			}
+			return newPingClient(ctx, destination, settings.Timeout, handler)
+		}
+	}
+	return newPingClient(ctx, settings.Destination, settings.Timeout, handler)
+}
+
+func matchSelectors(tag string, selectors []string) bool {
+	for _, selector := range selectors {
+		if strings.HasPrefix(tag, selector) {
+			return true
+		}
+	}
+	return false
+}
+
+// dialTimeout dials the destination through the handler. The connection is closed
+// when the timeout is reached, as connections of tagged dialer ignore deadlines.
+func dialTimeout(ctx context.Context, network, address, handler string, timeout time.Duration) (net.Conn, *time.Timer, error) {
+	dest, err := net.ParseDestination(network + ":" + address)
+	if err != nil {
+		return nil, nil, newError("invalid probe destination ", address).Base(err)
+	}
+	conn, err := tagged.Dialer(ctx, dest, handler)
+	if err != nil {
+		return nil, nil, err
+	}
+	return conn, time.AfterFunc(timeout, func() { conn.Close() }), nil
+}
+
+// tcpProber measures the delay to receive the first byte from a server, after sending the payload if any
+type tcpProber struct {
+	ctx         context.Context
+	handler     string
+	destination string
+	payload     []byte
+	timeout     time.Duration
+}
+
+func (p *tcpProber) MeasureDelay() (time.Duration, error) {
+	if p.destination == "" {
+		return rttFailed, newError("no destination for tcp probe")
+	}
+	start := time.Now()
+	conn, timer, err := dialTimeout(p.ctx, "tcp", p.destination, p.handler, p.timeout)
+	if err != nil {
+		return rttFailed, err
+	}
+	defer timer.Stop()
+	defer conn.Close()
+
+	if len(p.payload) > 0 {
+		if _, err := conn.Write(p.payload); err != nil {
+			return rttFailed, newError("failed to send payload to ", p.destination).Base(err)
+		}
+	}
+	if _, err := conn.Read(make([]byte, 1)); err != nil {
+		return rttFailed, newError("no data received from ", p.destination).Base(err)
+	}
+	return time.Since(start), nil
+}
+
+// tlsProber measures the delay of a TLS handshake
+type tlsProber struct {
+	ctx         context.Context
+	handler     string
+	destination string
+	serverName  string
+	timeout     time.Duration
+}
+
+func (p *tlsProber) MeasureDelay() (time.Duration, error) {
+	serverName := p.serverName
+	if serverName == "" {
+		host, _, err := net.SplitHostPort(p.destination)
+		if err != nil {
+			return rttFailed, newError("invalid probe destination ", p.destination).Base(err)
+		}
+		serverName = host
+	}
+	start := time.Now()
+	conn, timer, err := dialTimeout(p.ctx, "tcp", p.destination, p.handler, p.timeout)
+	if err != nil {
+		return rttFailed, err
+	}
+	defer timer.Stop()
+
+	tlsConn := tls.Client(conn, &tls.Config{ServerName: serverName})
+	defer tlsConn.Close()
+	if err := tlsConn.Handshake(); err != nil {
+		return rttFailed, newError("failed to handshake with ", p.destination).Base(err)
+	}
+	return time.Since(start), nil
+}
+
+// dnsProber measures the delay of a DNS query over UDP
+type dnsProber struct {
+	ctx         context.Context
+	handler     string
+	destination string
+	domain      string
+	timeout     time.Duration
+}
+
+func (p *dnsProber) MeasureDelay() (time.Duration, error) {
+	domain := p.domain
+	if !strings.HasSuffix(domain, ".") {
+		domain += "."
+	}
+	name, err := dnsmessage.NewName(domain)
+	if err != nil {
+		return rttFailed, newError("invalid probe domain ", p.domain).Base(err)
+	}
+	id := dice.RollUint16()
+	query, err := (&dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: id, RecursionDesired: true},
+		Questions: []dnsmessage.Question{{Name: name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET}},
+	}).Pack()
+	if err != nil {
+		return rttFailed, err
+	}
+
+	start := time.Now()
+	conn, timer, err := dialTimeout(p.ctx, "udp", p.destination, p.handler, p.timeout)
+	if err != nil {
+		return rttFailed, err
+	}
+	defer timer.Stop()
+	defer conn.Close()
+
+	if _, err := conn.Write(query); err != nil {
+		return rttFailed, err
+	}
+	response := make([]byte, 1500)
+	for {
+		n, err := conn.Read(response)
+		if err != nil {
+			return rttFailed, newError("no dns response from ", p.destination).Base(err)
+		}
+		var parser dnsmessage.Parser
+		header, err := parser.Start(response[:n])
+		if err != nil || header.ID != id || !header.Response {
+			continue
+		}
+		if header.RCode != dnsmessage.RCodeSuccess {
+			return rttFailed, newError("dns query failed with ", header.RCode)
+		}
+		return time.Since(start), nil
+	}
+}
+
+// throughputProber measures the delay to the response of a download, and the speed of the download
+type throughputProber struct {
+	destination string
+	size        int64
+	httpClient  *http.Client
+}
+
+func (p *throughputProber) MeasureDelay() (time.Duration, error) {
+	delay, _, err := p.MeasureThroughput()
+	return delay, err
+}
+
+func (p *throughputProber) MeasureThroughput() (time.Duration, int64, error) {
+	if p.destination == "" {
+		return rttFailed, 0, newError("no destination for throughput probe")
+	}
+	start := time.Now()
+	resp, err := p.httpClient.Get(p.destination)
+	if err != nil {
+		return rttFailed, 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return rttFailed, 0, newError("unexpected status ", resp.Status)
+	}
+	delay := time.Since(start)
+
+	downloadStart := time.Now()
+	n, err := io.CopyN(io.Discard, resp.Body, p.size)
+	if err != nil && (err != io.EOF || n == 0) {
+		return rttFailed, 0, newError("failed to download from ", p.destination).Base(err)
+	}
+	elapsed := time.Since(downloadStart)
+	if elapsed <= 0 {
+		elapsed = time.Nanosecond
+	}
+	return delay, int64(float64(n) / elapsed.Seconds()), nil
+}
@@ -0,0 +1,150 @@
+package burst
+
+import (
+	"bytes"
+	"context"
+	gonet "net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/transport/internet/tagged"
+)
+
+// dialDirectly makes probes dial their destinations directly instead of through outbounds.
+func dialDirectly(t *testing.T) {
+	original := tagged.Dialer
+	tagged.Dialer = func(ctx context.Context, dest net.Destination, tag string) (net.Conn, error) {
+		var d gonet.Dialer
+		return d.DialContext(ctx, dest.Network.SystemString(), dest.NetAddr())
+	}
+	t.Cleanup(func() { tagged.Dialer = original })
+}
+
+func TestThroughputProber(t *testing.T) {
+	dialDirectly(t)
+	const size = 64 * 1024
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/down":
+			w.Write(bytes.Repeat([]byte{'a'}, size*2))
+		case "/empty":
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	settings := &HealthPingSettings{
+		Timeout: 5 * time.Second,
+		Probes: []*ProbeConfig{{
+			SubjectSelector: []string{"proxy"},
+			Type:            ProbeConfig_THROUGHPUT,
+			Destination:     server.URL + "/down",
+			Size:            size,
+		}},
+	}
+	p, ok := newProber(context.Background(), settings, "proxy").(throughputMeasurer)
+	if !ok {
+		t.Fatal("expected a throughput prober")
+	}
+	delay, throughput, err := p.MeasureThroughput()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delay <= 0 || delay == rttFailed {
+		t.Error("unexpected delay ", delay)
+	}
+	if throughput <= 0 {
+		t.Error("unexpected throughput ", throughput)
+	}
+
+	for _, path := range []string{"/empty", "/notfound"} {
+		p := &throughputProber{destination: server.URL + path, size: size, httpClient: newHTTPClient(context.Background(), "proxy", 5*time.Second)}
+		if delay, _, err := p.MeasureThroughput(); err == nil || delay != rttFailed {
+			t.Error("expected failure for ", path)
+		}
+	}
+
+	p2 := &throughputProber{size: size, httpClient: newHTTPClient(context.Background(), "proxy", 5*time.Second)}
+	if _, err := p2.MeasureDelay(); err == nil {
+		t.Error("expected failure without destination")
+	}
+}
+
+func TestTCPProber(t *testing.T) {
+	dialDirectly(t)
+	listener, err := gonet.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write([]byte("SSH-2.0-test\r\n"))
+			conn.Close()
+		}
+	}()
+
+	p := &tcpProber{ctx: context.Background(), handler: "proxy", destination: listener.Addr().String(), timeout: 5 * time.Second}
+	delay, err := p.MeasureDelay()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delay <= 0 || delay == rttFailed {
+		t.Error("unexpected delay ", delay)
+	}
+}
+
+func TestTCPProberPayload(t *testing.T) {
+	dialDirectly(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	destination := server.Listener.Addr().String()
+
+	p := &tcpProber{ctx: context.Background(), handler: "proxy", destination: destination, payload: []byte("HEAD / HTTP/1.0\r\n\r\n"), timeout: 5 * time.Second}
+	delay, err := p.MeasureDelay()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delay <= 0 || delay == rttFailed {
+		t.Error("unexpected delay ", delay)
+	}
+
+	// HTTP servers send nothing before receiving requests.
+	p = &tcpProber{ctx: context.Background(), handler: "proxy", destination: destination, timeout: 500 * time.Millisecond}
+	if _, err := p.MeasureDelay(); err == nil {
+		t.Error("expected failure without payload")
+	}
+}
+
+func TestNewProber(t *testing.T) {
+	settings := &HealthPingSettings{
+		Destination: "https://example.com/generate_204",
+		Timeout:     time.Second,
+		Probes: []*ProbeConfig{
+			{SubjectSelector: []string{"tcp"}, Type: ProbeConfig_TCP, Destination: "127.0.0.1:22"},
+			{SubjectSelector: []string{"dns"}, Type: ProbeConfig_DNS},
+			{SubjectSelector: []string{"tls"}, Type: ProbeConfig_TLS},
+		},
+	}
+	ctx := context.Background()
+	if _, ok := newProber(ctx, settings, "tcp-a").(*tcpProber); !ok {
+		t.Error("expected tcp prober")
+	}
+	if p, ok := newProber(ctx, settings, "dns-a").(*dnsProber); !ok || p.destination != defaultDNSProbeDestination || p.domain != defaultDNSProbeDomain {
+		t.Error("expected dns prober with defaults")
+	}
+	if p, ok := newProber(ctx, settings, "tls-a").(*tlsProber); !ok || p.destination != defaultTLSProbeDestination {
+		t.Error("expected tls prober with default destination")
+	}
+	if _, ok := newProber(ctx, settings, "other").(*pingClient); !ok {
+		t.Error("expected http ping for unmatched handlers")
+	}
+}
@@ -7,6 +7,7 @@ package command
 
 import (
 	"context"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"google.golang.org/grpc"
@@ -50,6 +51,24 @@ func (s *service) GetOutboundStatus(ctx context.Context, request *GetOutboundSta
 	}, nil
 }
 
+func (s *service) SubscribeOutboundEvents(request *SubscribeOutboundEventsRequest, stream ObservatoryService_SubscribeOutboundEventsServer) error {
+	err := observatory.WatchEvents(stream.Context(), s.observatory, request.Tag,
+		time.Duration(request.PollInterval)*time.Millisecond,
+		time.Duration(request.LatencyThreshold)*time.Millisecond,
+		func(events []*observatory.OutboundEvent) error {
+			for _, event := range events {
+				if err := stream.Send(event); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	if err == stream.Context().Err() {
+		return err
+	}
+	return newError("failed to watch outbound events").Base(err)
+}
+
 func (s *service) Register(server *grpc.Server) {
 	RegisterObservatoryServiceServer(server, s)
 }
@@ -2,6 +2,7 @@ package command
 
 import (
 	context "context"
+	observatory "github.com/v2fly/v2ray-core/v5/app/observatory"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
@@ -17,6 +18,7 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ObservatoryServiceClient interface {
 	GetOutboundStatus(ctx context.Context, in *GetOutboundStatusRequest, opts ...grpc.CallOption) (*GetOutboundStatusResponse, error)
+	SubscribeOutboundEvents(ctx context.Context, in *SubscribeOutboundEventsRequest, opts ...grpc.CallOption) (ObservatoryService_SubscribeOutboundEventsClient, error)
 }
 
 type observatoryServiceClient struct {
@@ -36,11 +38,44 @@ func (c *observatoryServiceClient) GetOutboundStatus(ctx context.Context, in *Ge
 	return out, nil
 }
 
+func (c *observatoryServiceClient) SubscribeOutboundEvents(ctx context.Context, in *SubscribeOutboundEventsRequest, opts ...grpc.CallOption) (ObservatoryService_SubscribeOutboundEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ObservatoryService_ServiceDesc.Streams[0], "/v2ray.core.app.observatory.command.ObservatoryService/SubscribeOutboundEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &observatoryServiceSubscribeOutboundEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ObservatoryService_SubscribeOutboundEventsClient interface {
+	Recv() (*observatory.OutboundEvent, error)
+	grpc.ClientStream
+}
+
+type observatoryServiceSubscribeOutboundEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *observatoryServiceSubscribeOutboundEventsClient) Recv() (*observatory.OutboundEvent, error) {
+	m := new(observatory.OutboundEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ObservatoryServiceServer is the server API for ObservatoryService service.
 // All implementations must embed UnimplementedObservatoryServiceServer
 // for forward compatibility
 type ObservatoryServiceServer interface {
 	GetOutboundStatus(context.Context, *GetOutboundStatusRequest) (*GetOutboundStatusResponse, error)
+	SubscribeOutboundEvents(*SubscribeOutboundEventsRequest, ObservatoryService_SubscribeOutboundEventsServer) error
 	mustEmbedUnimplementedObservatoryServiceServer()
 }
 
@@ -51,6 +86,9 @@ type UnimplementedObservatoryServiceServer struct {
 func (UnimplementedObservatoryServiceServer) GetOutboundStatus(context.Context, *GetOutboundStatusRequest) (*GetOutboundStatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetOutboundStatus not implemented")
 }
+func (UnimplementedObservatoryServiceServer) SubscribeOutboundEvents(*SubscribeOutboundEventsRequest, ObservatoryService_SubscribeOutboundEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeOutboundEvents not implemented")
+}
 func (UnimplementedObservatoryServiceServer) mustEmbedUnimplementedObservatoryServiceServer() {}
 
 // UnsafeObservatoryServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -82,6 +120,27 @@ func _ObservatoryService_GetOutboundStatus_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ObservatoryService_SubscribeOutboundEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeOutboundEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ObservatoryServiceServer).SubscribeOutboundEvents(m, &observatoryServiceSubscribeOutboundEventsServer{stream})
+}
+
+type ObservatoryService_SubscribeOutboundEventsServer interface {
+	Send(*observatory.OutboundEvent) error
+	grpc.ServerStream
+}
+
+type observatoryServiceSubscribeOutboundEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *observatoryServiceSubscribeOutboundEventsServer) Send(m *observatory.OutboundEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // ObservatoryService_ServiceDesc is the grpc.ServiceDesc for ObservatoryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -94,6 +153,12 @@ var ObservatoryService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _ObservatoryService_GetOutboundStatus_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeOutboundEvents",
+			Handler:       _ObservatoryService_SubscribeOutboundEvents_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "app/observatory/command/command.proto",
 }
@@ -0,0 +1,118 @@
+package observatory
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/v2fly/v2ray-core/v5/features"
+	"github.com/v2fly/v2ray-core/v5/features/extension"
+)
+
+const defaultEventPollInterval = time.Second
+
+// DiffObservation returns the events of outbounds changed from the previous observation.
+// Outbounds not in the previous observation only produce an event when they are dead.
+// Latency events are only produced when latencyThreshold is positive.
+func DiffObservation(previous, current []*OutboundStatus, latencyThreshold time.Duration) []*OutboundEvent {
+	known := make(map[string]*OutboundStatus, len(previous))
+	for _, status := range previous {
+		known[status.OutboundTag] = status
+	}
+
+	now := time.Now().Unix()
+	var events []*OutboundEvent
+	add := func(eventType OutboundEvent_Type, status *OutboundStatus) {
+		events = append(events, &OutboundEvent{
+			Type:        eventType,
+			OutboundTag: status.OutboundTag,
+			Status:      proto.Clone(status).(*OutboundStatus),
+			Time:        now,
+		})
+	}
+	threshold := latencyThreshold.Milliseconds()
+	for _, status := range current {
+		last, found := known[status.OutboundTag]
+		switch {
+		case !found:
+			if !status.Alive {
+				add(OutboundEvent_Dead, status)
+			} else if threshold > 0 && status.Delay > threshold {
+				add(OutboundEvent_LatencyHigh, status)
+			}
+		case last.Alive != status.Alive:
+			if status.Alive {
+				add(OutboundEvent_Alive, status)
+			} else {
+				add(OutboundEvent_Dead, status)
+			}
+		case status.Alive && threshold > 0:
+			if last.Delay <= threshold && status.Delay > threshold {
+				add(OutboundEvent_LatencyHigh, status)
+			} else if last.Delay > threshold && status.Delay <= threshold {
+				add(OutboundEvent_LatencyNormal, status)
+			}
+		}
+	}
+	return events
+}
+
+// WatchEvents polls the observatory with the tag, or the default one if the tag is empty,
+// and calls handle with the events found, until the context is done or handle returns an error.
+func WatchEvents(ctx context.Context, observer extension.Observatory, observerTag string, interval, latencyThreshold time.Duration, handle func([]*OutboundEvent) error) error {
+	if observerTag != "" {
+		tagged, ok := observer.(features.TaggedFeatures)
+		if !ok {
+			return newError("observatory is not tagged")
+		}
+		feature, err := tagged.GetFeaturesByTag(observerTag)
+		if err != nil {
+			return newError("cannot get tagged observatory ", observerTag).Base(err)
+		}
+		observer = feature.(extension.Observatory)
+	}
+	if interval <= 0 {
+		interval = defaultEventPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var previous []*OutboundStatus
+	first := true
+	for {
+		observation, err := observer.GetObservation(ctx)
+		if err != nil {
+			return newError("cannot get observation").Base(err)
+		}
+		result, ok := observation.(*ObservationResult)
+		if !ok {
+			return newError("unexpected observation result")
+		}
+		// Outbounds observed before watching are not reported.
+		if !first {
+			events := DiffObservation(previous, result.Status, latencyThreshold)
+			for _, event := range events {
+				event.ObserverTag = observerTag
+			}
+			if len(events) > 0 {
+				if err := handle(events); err != nil {
+					return err
+				}
+			}
+		}
+		// Results may be updated in place by observatories, so a copy is kept.
+		previous = make([]*OutboundStatus, 0, len(result.Status))
+		for _, status := range result.Status {
+			previous = append(previous, proto.Clone(status).(*OutboundStatus))
+		}
+		first = false
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package observatory_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/v2fly/v2ray-core/v5/app/observatory"
+)
+
+func TestDiffObservation(t *testing.T) {
+	previous := []*observatory.OutboundStatus{
+		{OutboundTag: "a", Alive: true, Delay: 100},
+		{OutboundTag: "b", Alive: false, Delay: 99999999},
+		{OutboundTag: "c", Alive: true, Delay: 100},
+		{OutboundTag: "d", Alive: true, Delay: 800},
+	}
+	current := []*observatory.OutboundStatus{
+		{OutboundTag: "a", Alive: false, Delay: 99999999},
+		{OutboundTag: "b", Alive: true, Delay: 100},
+		{OutboundTag: "c", Alive: true, Delay: 600},
+		{OutboundTag: "d", Alive: true, Delay: 200},
+		{OutboundTag: "e", Alive: true, Delay: 100},
+		{OutboundTag: "f", Alive: false, Delay: 99999999},
+	}
+
+	events := observatory.DiffObservation(previous, current, 500*time.Millisecond)
+	expected := map[string]observatory.OutboundEvent_Type{
+		"a": observatory.OutboundEvent_Dead,
+		"b": observatory.OutboundEvent_Alive,
+		"c": observatory.OutboundEvent_LatencyHigh,
+		"d": observatory.OutboundEvent_LatencyNormal,
+		"f": observatory.OutboundEvent_Dead,
+	}
+	if len(events) != len(expected) {
+		t.Fatal("unexpected events: ", events)
+	}
+	for _, event := range events {
+		if expected[event.OutboundTag] != event.Type {
+			t.Error("unexpected event for ", event.OutboundTag, ": ", event.Type)
+		}
+	}
+
+	if events := observatory.DiffObservation(previous, current, 0); len(events) != 3 {
+		t.Error("latency events should be disabled: ", events)
+	}
+}
+
+type fakeObservatory struct {
+	access sync.Mutex
+	status []*observatory.OutboundStatus
+}
+
+func (o *fakeObservatory) GetObservation(ctx context.Context) (proto.Message, error) {
+	o.access.Lock()
+	defer o.access.Unlock()
+	return &observatory.ObservationResult{Status: o.status}, nil
+}
+
+func (o *fakeObservatory) set(status ...*observatory.OutboundStatus) {
+	o.access.Lock()
+	defer o.access.Unlock()
+	o.status = status
+}
+
+func (o *fakeObservatory) Type() interface{} { return nil }
+func (o *fakeObservatory) Start() error      { return nil }
+func (o *fakeObservatory) Close() error      { return nil }
+
+func TestWatchEvents(t *testing.T) {
+	observer := &fakeObservatory{}
+	observer.set(&observatory.OutboundStatus{OutboundTag: "a", Alive: false})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		observer.set(&observatory.OutboundStatus{OutboundTag: "a", Alive: true})
+	}()
+
+	var received []*observatory.OutboundEvent
+	err := observatory.WatchEvents(ctx, observer, "", 10*time.Millisecond, 0, func(events []*observatory.OutboundEvent) error {
+		received = append(received, events...)
+		cancel()
+		return nil
+	})
+	if err != context.Canceled {
+		t.Fatal("unexpected error: ", err)
+	}
+	// The state before watching is not reported.
+	if len(received) != 1 || received[0].OutboundTag != "a" || received[0].Type != observatory.OutboundEvent_Alive {
+		t.Fatal("unexpected events: ", received)
+	}
+}
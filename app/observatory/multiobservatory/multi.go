@@ -22,6 +22,27 @@ func (o Observer) GetObservation(ctx context.Context) (proto.Message, error) {
 	return common.Must2(o.GetFeaturesByTag("")).(extension.Observatory).GetObservation(ctx)
 }
 
+// ObserveTraffic implements extension.PassiveObserver by passing the observation to all observatories.
+func (o Observer) ObserveTraffic(observation *extension.TrafficObservation) {
+	holder, ok := o.TaggedFeatures.(*taggedfeatures.Holder)
+	if !ok {
+		return
+	}
+	tags, err := holder.GetFeaturesTag()
+	if err != nil {
+		return
+	}
+	for _, tag := range tags {
+		feature, err := holder.GetFeaturesByTag(tag)
+		if err != nil {
+			continue
+		}
+		if observer, ok := feature.(extension.PassiveObserver); ok {
+			observer.ObserveTraffic(observation)
+		}
+	}
+}
+
 func (o Observer) Type() interface{} {
 	return extension.ObservatoryType()
 }
@@ -34,11 +34,29 @@ type Observer struct {
 
 	finished *done.Instance
 
-	ohm outbound.Manager
+	ohm     outbound.Manager
+	passive *PassiveObservation
 }
 
 func (o *Observer) GetObservation(ctx context.Context) (proto.Message, error) {
-	return &ObservationResult{Status: o.status}, nil
+	o.statusLock.Lock()
+	defer o.statusLock.Unlock()
+	// The status is updated in place, so copies are returned.
+	status := make([]*OutboundStatus, 0, len(o.status))
+	for _, s := range o.status {
+		status = append(status, proto.Clone(s).(*OutboundStatus))
+	}
+	if o.passive != nil {
+		status = o.passive.Merge(status)
+	}
+	return &ObservationResult{Status: status}, nil
+}
+
+// ObserveTraffic implements extension.PassiveObserver.
+func (o *Observer) ObserveTraffic(observation *extension.TrafficObservation) {
+	if o.passive != nil {
+		o.passive.Observe(observation)
+	}
 }
 
 func (o *Observer) Type() interface{} {
@@ -206,9 +224,10 @@ func New(ctx context.Context, config *Config) (*Observer, error) {
 		return nil, newError("Cannot get depended features").Base(err)
 	}
 	return &Observer{
-		config: config,
-		ctx:    ctx,
-		ohm:    outboundManager,
+		config:  config,
+		ctx:     ctx,
+		ohm:     outboundManager,
+		passive: NewPassiveObservation(config.Passive, config.SubjectSelector),
 	}, nil
 }
 
@@ -0,0 +1,232 @@
+package observatory
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/v2fly/v2ray-core/v5/features/extension"
+)
+
+const (
+	defaultPassiveFailureThreshold = 3
+	defaultPassiveSamplingCount    = 20
+	// passiveRetryAfter is the time after which an outbound found dead only by real traffic
+	// is considered alive again, so that it receives traffic to prove its health.
+	passiveRetryAfter = 30 * time.Second
+)
+
+type passiveOutcome byte
+
+const (
+	passiveSuccess passiveOutcome = iota
+	passiveFailure
+	passiveReset
+)
+
+type passiveSample struct {
+	outcome        passiveOutcome
+	firstByteDelay time.Duration
+}
+
+type passiveRecord struct {
+	samples         []passiveSample
+	next            int
+	consecutiveFail int
+	lastFail        time.Time
+	lastReset       time.Time
+	lastSuccess     time.Time
+	lastErrorReason string
+}
+
+func (r *passiveRecord) put(sample passiveSample, capacity int) {
+	if len(r.samples) < capacity {
+		r.samples = append(r.samples, sample)
+		return
+	}
+	r.samples[r.next] = sample
+	r.next = (r.next + 1) % capacity
+}
+
+func (r *passiveRecord) result() *PassiveObservationResult {
+	result := &PassiveObservationResult{
+		All:             int64(len(r.samples)),
+		ConsecutiveFail: int64(r.consecutiveFail),
+		LastErrorReason: r.lastErrorReason,
+	}
+	if !r.lastFail.IsZero() {
+		result.LastFailTime = r.lastFail.Unix()
+	}
+	if !r.lastSuccess.IsZero() {
+		result.LastSuccessTime = r.lastSuccess.Unix()
+	}
+	var delaySum time.Duration
+	var delayCount int64
+	for _, sample := range r.samples {
+		switch sample.outcome {
+		case passiveFailure:
+			result.Fail++
+		case passiveReset:
+			result.ResetCount++
+		}
+		if sample.firstByteDelay > 0 {
+			delaySum += sample.firstByteDelay
+			delayCount++
+		}
+	}
+	if delayCount > 0 {
+		result.FirstByteDelay = (delaySum / time.Duration(delayCount)).Milliseconds()
+	}
+	return result
+}
+
+// PassiveObservation tracks the health of outbounds from the outcome of real traffic.
+type PassiveObservation struct {
+	selectors        []string
+	failureThreshold int
+	samplingCount    int
+	resetThreshold   float32
+
+	access  sync.Mutex
+	records map[string]*passiveRecord
+}
+
+// NewPassiveObservation creates a PassiveObservation for outbounds matching the selectors,
+// or returns nil if passive observation is not enabled.
+func NewPassiveObservation(config *PassiveObservationConfig, selectors []string) *PassiveObservation {
+	if config == nil || !config.Enabled {
+		return nil
+	}
+	p := &PassiveObservation{
+		selectors:        selectors,
+		failureThreshold: int(config.FailureThreshold),
+		samplingCount:    int(config.SamplingCount),
+		resetThreshold:   config.ResetThreshold,
+		records:          make(map[string]*passiveRecord),
+	}
+	if p.failureThreshold <= 0 {
+		p.failureThreshold = defaultPassiveFailureThreshold
+	}
+	if p.samplingCount <= 0 {
+		p.samplingCount = defaultPassiveSamplingCount
+	}
+	return p
+}
+
+func (p *PassiveObservation) selected(tag string) bool {
+	for _, selector := range p.selectors {
+		if strings.HasPrefix(tag, selector) {
+			return true
+		}
+	}
+	return false
+}
+
+// Observe records the outcome of a connection.
+func (p *PassiveObservation) Observe(observation *extension.TrafficObservation) {
+	if observation == nil || !p.selected(observation.OutboundTag) {
+		return
+	}
+
+	p.access.Lock()
+	defer p.access.Unlock()
+
+	record, found := p.records[observation.OutboundTag]
+	if !found {
+		record = &passiveRecord{}
+		p.records[observation.OutboundTag] = record
+	}
+	now := time.Now()
+	sample := passiveSample{firstByteDelay: observation.FirstByteDelay}
+	switch {
+	case observation.Failed:
+		sample.outcome = passiveFailure
+		record.consecutiveFail++
+		record.lastFail = now
+		if observation.Err != nil {
+			record.lastErrorReason = observation.Err.Error()
+		}
+	case observation.Reset:
+		sample.outcome = passiveReset
+		record.lastReset = now
+	default:
+		sample.outcome = passiveSuccess
+		record.consecutiveFail = 0
+		record.lastSuccess = now
+	}
+	record.put(sample, p.samplingCount)
+}
+
+// deadSince returns the time since which the outbound is found dead by real traffic,
+// or a zero time if it is not.
+func (p *PassiveObservation) deadSince(record *passiveRecord, result *PassiveObservationResult) time.Time {
+	if record.consecutiveFail >= p.failureThreshold {
+		return record.lastFail
+	}
+	if p.resetThreshold > 0 && result.All >= int64(p.failureThreshold) &&
+		float32(result.ResetCount)/float32(result.All) >= p.resetThreshold {
+		return record.lastReset
+	}
+	return time.Time{}
+}
+
+// Merge returns the active observation results merged with the passive ones.
+// Outbounds observed only by real traffic are appended, and the given results are not modified.
+func (p *PassiveObservation) Merge(status []*OutboundStatus) []*OutboundStatus {
+	p.access.Lock()
+	defer p.access.Unlock()
+
+	merged := make([]*OutboundStatus, 0, len(status)+len(p.records))
+	observed := make(map[string]bool, len(status))
+	for _, s := range status {
+		observed[s.OutboundTag] = true
+		if record, found := p.records[s.OutboundTag]; found {
+			s = proto.Clone(s).(*OutboundStatus)
+			p.apply(s, record)
+		}
+		merged = append(merged, s)
+	}
+
+	passiveOnly := make([]string, 0, len(p.records))
+	for tag := range p.records {
+		if !observed[tag] {
+			passiveOnly = append(passiveOnly, tag)
+		}
+	}
+	sort.Strings(passiveOnly)
+	for _, tag := range passiveOnly {
+		s := &OutboundStatus{OutboundTag: tag, Alive: true}
+		p.apply(s, p.records[tag])
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+func (p *PassiveObservation) apply(s *OutboundStatus, record *passiveRecord) {
+	result := record.result()
+	s.Passive = result
+
+	activelyObserved := s.LastTryTime != 0
+	if !activelyObserved && s.Delay == 0 {
+		s.Delay = result.FirstByteDelay
+	}
+	deadSince := p.deadSince(record, result)
+	switch {
+	case !deadSince.IsZero() && deadSince.Unix() >= s.LastSeenTime:
+		if !activelyObserved && time.Since(deadSince) > passiveRetryAfter {
+			s.Alive = true
+			return
+		}
+		s.Alive = false
+		s.Delay = 99999999
+		if record.lastErrorReason != "" {
+			s.LastErrorReason = record.lastErrorReason
+		}
+	case !s.Alive && activelyObserved && result.LastSuccessTime > s.LastTryTime:
+		s.Alive = true
+		s.Delay = result.FirstByteDelay
+	}
+}
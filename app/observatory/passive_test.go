@@ -0,0 +1,87 @@
+package observatory_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/app/observatory"
+	"github.com/v2fly/v2ray-core/v5/features/extension"
+)
+
+func findStatus(status []*observatory.OutboundStatus, tag string) *observatory.OutboundStatus {
+	for _, s := range status {
+		if s.OutboundTag == tag {
+			return s
+		}
+	}
+	return nil
+}
+
+func TestPassiveObservation(t *testing.T) {
+	passive := observatory.NewPassiveObservation(&observatory.PassiveObservationConfig{
+		Enabled:          true,
+		FailureThreshold: 2,
+	}, []string{"proxy"})
+
+	probed := time.Now().Add(-10 * time.Second).Unix()
+	active := []*observatory.OutboundStatus{
+		{OutboundTag: "proxy_a", Alive: true, Delay: 50, LastTryTime: probed, LastSeenTime: probed},
+		{OutboundTag: "proxy_c", Alive: false, Delay: 99999999, LastTryTime: probed},
+	}
+
+	passive.Observe(&extension.TrafficObservation{OutboundTag: "proxy_a", Failed: true, Err: errors.New("dial failed")})
+	if s := findStatus(passive.Merge(active), "proxy_a"); !s.Alive {
+		t.Error("proxy_a should be alive below the failure threshold")
+	}
+	passive.Observe(&extension.TrafficObservation{OutboundTag: "proxy_a", Failed: true, Err: errors.New("dial failed")})
+	passive.Observe(&extension.TrafficObservation{OutboundTag: "direct", Failed: true})
+	passive.Observe(&extension.TrafficObservation{OutboundTag: "proxy_b", FirstByteDelay: 100 * time.Millisecond})
+	passive.Observe(&extension.TrafficObservation{OutboundTag: "proxy_c", FirstByteDelay: 200 * time.Millisecond})
+
+	merged := passive.Merge(active)
+	if len(merged) != 3 {
+		t.Fatal("unexpected merged status: ", merged)
+	}
+	if s := findStatus(merged, "proxy_a"); s.Alive || s.LastErrorReason != "dial failed" || s.Passive.Fail != 2 || s.Passive.ConsecutiveFail != 2 {
+		t.Error("proxy_a should be dead: ", s)
+	}
+	if !active[0].Alive || active[0].Passive != nil {
+		t.Error("active status should not be modified")
+	}
+	if s := findStatus(merged, "proxy_b"); !s.Alive || s.Delay != 100 {
+		t.Error("proxy_b should be alive: ", s)
+	}
+	if s := findStatus(merged, "proxy_c"); !s.Alive || s.Delay != 200 {
+		t.Error("proxy_c should be alive after a successful connection: ", s)
+	}
+	if findStatus(merged, "direct") != nil {
+		t.Error("direct should not be observed")
+	}
+
+	passive.Observe(&extension.TrafficObservation{OutboundTag: "proxy_a", FirstByteDelay: 30 * time.Millisecond})
+	if s := findStatus(passive.Merge(active), "proxy_a"); !s.Alive || s.Passive.ConsecutiveFail != 0 {
+		t.Error("proxy_a should be alive after a successful connection: ", s)
+	}
+}
+
+func TestPassiveObservationResetThreshold(t *testing.T) {
+	passive := observatory.NewPassiveObservation(&observatory.PassiveObservationConfig{
+		Enabled:        true,
+		ResetThreshold: 0.5,
+	}, []string{"proxy"})
+
+	for i := 0; i < 2; i++ {
+		passive.Observe(&extension.TrafficObservation{OutboundTag: "proxy", FirstByteDelay: time.Millisecond})
+		passive.Observe(&extension.TrafficObservation{OutboundTag: "proxy", Reset: true, FirstByteDelay: time.Millisecond})
+	}
+	if s := findStatus(passive.Merge(nil), "proxy"); s.Alive || s.Passive.ResetCount != 2 {
+		t.Error("proxy should be dead with half of the connections reset: ", s)
+	}
+}
+
+func TestPassiveObservationDisabled(t *testing.T) {
+	if observatory.NewPassiveObservation(&observatory.PassiveObservationConfig{}, []string{"proxy"}) != nil {
+		t.Error("passive observation should be disabled")
+	}
+}
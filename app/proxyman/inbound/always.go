@@ -104,6 +104,22 @@ func NewAlwaysOnInboundHandler(ctx context.Context, tag string, receiverConfig *
 			}
 			h.workers = append(h.workers, worker)
 		}
+		if net.HasNetwork(nl, net.Network_UDP) && internet.IsUnixSocketAddress(address) {
+			newError("creating unix datagram socket worker on ", address).AtDebug().WriteToLog()
+
+			worker := &udpWorker{
+				ctx:             ctx,
+				tag:             tag,
+				proxy:           p,
+				address:         address,
+				dispatcher:      h.mux,
+				sniffingConfig:  receiverConfig.GetEffectiveSniffingSettings(),
+				uplinkCounter:   uplinkCounter,
+				downlinkCounter: downlinkCounter,
+				stream:          mss,
+			}
+			h.workers = append(h.workers, worker)
+		}
 	}
 	if pr != nil {
 		for port := pr.From; port <= pr.To; port++ {
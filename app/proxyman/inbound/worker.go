@@ -304,9 +304,13 @@ func (w *udpWorker) callback(b *buf.Buffer, source net.Destination, originalDest
 					Target: originalDest,
 				})
 			}
+			gateway := net.UDPDestination(w.address, w.port)
+			if source.Network == net.Network_UNIX {
+				gateway = net.UnixDestination(w.address)
+			}
 			ctx = session.ContextWithInbound(ctx, &session.Inbound{
 				Source:  source,
-				Gateway: net.UDPDestination(w.address, w.port),
+				Gateway: gateway,
 				Tag:     w.tag,
 			})
 			content := new(session.Content)
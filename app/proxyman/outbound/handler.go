@@ -2,6 +2,9 @@ package outbound
 
 import (
 	"context"
+	"time"
+
+	"google.golang.org/protobuf/proto"
 
 	core "github.com/v2fly/v2ray-core/v5"
 	"github.com/v2fly/v2ray-core/v5/app/proxyman"
@@ -13,6 +16,7 @@ import (
 	"github.com/v2fly/v2ray-core/v5/common/serial"
 	"github.com/v2fly/v2ray-core/v5/common/session"
 	"github.com/v2fly/v2ray-core/v5/features/dns"
+	"github.com/v2fly/v2ray-core/v5/features/extension"
 	"github.com/v2fly/v2ray-core/v5/features/outbound"
 	"github.com/v2fly/v2ray-core/v5/features/policy"
 	"github.com/v2fly/v2ray-core/v5/features/stats"
@@ -48,6 +52,18 @@ func getStatCounter(v *core.Instance, tag string) (stats.Counter, stats.Counter)
 	return uplinkCounter, downlinkCounter
 }
 
+// getMuxStatCounter returns the counters of Mux workers and streams, which are enabled along with the traffic stats of outbounds.
+func getMuxStatCounter(v *core.Instance, tag string) (stats.Counter, stats.Counter) {
+	policy := v.GetFeature(policy.ManagerType()).(policy.Manager)
+	if len(tag) == 0 || !(policy.ForSystem().Stats.OutboundUplink || policy.ForSystem().Stats.OutboundDownlink) {
+		return nil, nil
+	}
+	statsManager := v.GetFeature(stats.ManagerType()).(stats.Manager)
+	workerCounter, _ := stats.GetOrRegisterCounter(statsManager, "outbound>>>"+tag+">>>mux>>>workers")
+	streamCounter, _ := stats.GetOrRegisterCounter(statsManager, "outbound>>>"+tag+">>>mux>>>streams")
+	return workerCounter, streamCounter
+}
+
 // Handler is an implements of outbound.Handler.
 type Handler struct {
 	tag             string
@@ -59,6 +75,9 @@ type Handler struct {
 	uplinkCounter   stats.Counter
 	downlinkCounter stats.Counter
 	dns             dns.Client
+	sourcePool      *sourceAddressPool
+	instance        *core.Instance
+	observer        extension.PassiveObserver
 }
 
 // NewHandler create a new Handler based on the given configuration.
@@ -67,6 +86,7 @@ func NewHandler(ctx context.Context, config *core.OutboundHandlerConfig) (outbou
 	uplinkCounter, downlinkCounter := getStatCounter(v, config.Tag)
 	h := &Handler{
 		tag:             config.Tag,
+		instance:        v,
 		outboundManager: v.GetFeature(outbound.ManagerType()).(outbound.Manager),
 		uplinkCounter:   uplinkCounter,
 		downlinkCounter: downlinkCounter,
@@ -85,6 +105,20 @@ func NewHandler(ctx context.Context, config *core.OutboundHandlerConfig) (outbou
 				return nil, newError("failed to parse stream settings").Base(err).AtWarning()
 			}
 			h.streamSettings = mss
+			if s.ViaPool != nil && len(s.ViaPool.Prefix) > 0 {
+				pool, err := newSourceAddressPool(s.ViaPool)
+				if err != nil {
+					return nil, newError("failed to create source address pool").Base(err).AtWarning()
+				}
+				h.sourcePool = pool
+				// Addresses in the pool, such as those in an IPv6 /64, may not be assigned to any interface.
+				sockopt := &internet.SocketConfig{}
+				if mss.SocketSettings != nil {
+					sockopt = proto.Clone(mss.SocketSettings).(*internet.SocketConfig)
+				}
+				sockopt.FreeBind = true
+				mss.SocketSettings = sockopt
+			}
 		default:
 			return nil, newError("settings is not SenderConfig")
 		}
@@ -110,19 +144,37 @@ func NewHandler(ctx context.Context, config *core.OutboundHandlerConfig) (outbou
 		if config.Concurrency < 1 || config.Concurrency > 1024 {
 			return nil, newError("invalid mux concurrency: ", config.Concurrency).AtWarning()
 		}
+		if config.MaxConnections > 0 && config.MinIdleConnections > config.MaxConnections {
+			return nil, newError("mux min idle connections ", config.MinIdleConnections, " exceeds max connections ", config.MaxConnections).AtWarning()
+		}
+		maxStreams := config.MaxStreams
+		if maxStreams == 0 {
+			maxStreams = 128
+		}
+		picker := &mux.IncrementalWorkerPicker{
+			Factory: mux.NewDialingWorkerFactory(
+				ctx,
+				proxyHandler,
+				// Mux connections are dialed in the background, so their transport failures are observed when dialing.
+				&transportRecorder{Dialer: h, onFailure: func(err error) {
+					h.observeTraffic(err, 0, true)
+				}},
+				mux.ClientStrategy{
+					MaxConcurrency: config.Concurrency,
+					MaxConnection:  maxStreams,
+					IdleTimeout:    time.Duration(config.IdleTimeout),
+					Padding:        config.Padding,
+				},
+			),
+			MaxWorkers:     config.MaxConnections,
+			MinIdleWorkers: config.MinIdleConnections,
+		}
+		picker.WorkerCounter, picker.StreamCounter = getMuxStatCounter(v, h.tag)
 		h.mux = &mux.ClientManager{
-			Enabled: h.senderSettings.MultiplexSettings.Enabled,
-			Picker: &mux.IncrementalWorkerPicker{
-				Factory: mux.NewDialingWorkerFactory(
-					ctx,
-					proxyHandler,
-					h,
-					mux.ClientStrategy{
-						MaxConcurrency: config.Concurrency,
-						MaxConnection:  128,
-					},
-				),
-			},
+			Enabled:     config.Enabled,
+			Picker:      picker,
+			TCPDisabled: config.TcpDisabled,
+			UDPDisabled: config.UdpDisabled,
 		}
 	}
 
@@ -147,15 +199,36 @@ func (h *Handler) Tag() string {
 
 // Dispatch implements proxy.Outbound.Dispatch.
 func (h *Handler) Dispatch(ctx context.Context, link *transport.Link) {
-	if h.mux != nil && (h.mux.Enabled || session.MuxPreferedFromContext(ctx)) {
+	if h.useMux(ctx) {
+		if h.observer != nil {
+			// Mux dispatches return before the remote responds, so success is observed on the first byte.
+			recorder := newFirstByteRecorder(link.Writer)
+			recorder.onFirstByte = func(delay time.Duration) {
+				h.observeTraffic(nil, delay, false)
+			}
+			link = &transport.Link{Reader: link.Reader, Writer: recorder}
+		}
 		if err := h.mux.Dispatch(ctx, link); err != nil {
 			err := newError("failed to process mux outbound traffic").Base(err)
+			h.observeTraffic(err, 0, false)
 			session.SubmitOutboundErrorToOriginator(ctx, err)
 			err.WriteToLog(session.ExportIDToError(ctx))
 			common.Interrupt(link.Writer)
 		}
 	} else {
-		if err := h.proxy.Process(ctx, link, h); err != nil {
+		var recorder *firstByteRecorder
+		var dialer internet.Dialer = h
+		dialRecorder := &transportRecorder{Dialer: h}
+		if h.observer != nil {
+			recorder = newFirstByteRecorder(link.Writer)
+			link = &transport.Link{Reader: link.Reader, Writer: recorder}
+			dialer = dialRecorder
+		}
+		err := h.proxy.Process(ctx, link, dialer)
+		if recorder != nil {
+			h.observeTraffic(err, recorder.delay(), dialRecorder.hasFailed())
+		}
+		if err != nil {
 			// Ensure outbound ray is properly closed.
 			err := newError("failed to process outbound traffic").Base(err)
 			session.SubmitOutboundErrorToOriginator(ctx, err)
@@ -168,8 +241,31 @@ func (h *Handler) Dispatch(ctx context.Context, link *transport.Link) {
 	}
 }
 
+// useMux returns whether the connection in ctx is sent through Mux.
+func (h *Handler) useMux(ctx context.Context) bool {
+	if h.mux == nil || !(h.mux.Enabled || session.MuxPreferedFromContext(ctx)) {
+		return false
+	}
+	if outbound := session.OutboundFromContext(ctx); outbound != nil {
+		return h.mux.Accepts(outbound.Target.Network)
+	}
+	return true
+}
+
+func (h *Handler) observeTraffic(err error, firstByteDelay time.Duration, transportFailed bool) {
+	if h.observer == nil || len(h.tag) == 0 {
+		return
+	}
+	if observation := observeTraffic(h.tag, err, firstByteDelay, transportFailed); observation != nil {
+		h.observer.ObserveTraffic(observation)
+	}
+}
+
 // Address implements internet.Dialer.
 func (h *Handler) Address() net.Address {
+	if h.sourcePool != nil {
+		return h.sourcePool.familyHint()
+	}
 	if h.senderSettings == nil || h.senderSettings.Via == nil {
 		return nil
 	}
@@ -213,7 +309,7 @@ func (h *Handler) Dial(ctx context.Context, dest net.Destination) (internet.Conn
 			newError("failed to get outbound handler with tag: ", tag).AtWarning().WriteToLog(session.ExportIDToError(ctx))
 		}
 
-		if h.senderSettings.Via != nil {
+		if h.senderSettings.Via != nil && h.sourcePool == nil {
 			outbound := session.OutboundFromContext(ctx)
 			if outbound == nil {
 				outbound = new(session.Outbound)
@@ -231,6 +327,9 @@ func (h *Handler) Dial(ctx context.Context, dest net.Destination) (internet.Conn
 			outbound.Resolver = func(ctx context.Context, domain string) net.Address {
 				return h.resolveIP(ctx, domain, h.Address())
 			}
+			outbound.MultiResolver = func(ctx context.Context, domain string) []net.Address {
+				return h.resolveIPs(ctx, domain, h.Address())
+			}
 		}
 	}
 
@@ -251,11 +350,84 @@ func (h *Handler) Dial(ctx context.Context, dest net.Destination) (internet.Conn
 		return h.getStatCouterConnection(conn), nil
 	}
 
+	if h.sourcePool != nil {
+		return h.dialFromSourcePool(ctx, dest)
+	}
+
 	conn, err := internet.Dial(ctx, dest, h.streamSettings)
 	return h.getStatCouterConnection(conn), err
 }
 
-func (h *Handler) resolveIP(ctx context.Context, domain string, localAddr net.Address) net.Address {
+// dialFromSourcePool dials through addresses picked from the source address pool,
+// falling back to the system default source address when none of them can be bound.
+func (h *Handler) dialFromSourcePool(ctx context.Context, dest net.Destination) (internet.Connection, error) {
+	outbound := session.OutboundFromContext(ctx)
+	if outbound == nil {
+		outbound = new(session.Outbound)
+		ctx = session.ContextWithOutbound(ctx, outbound)
+	}
+
+	target := dest.Address
+	if target.Family().IsDomain() && h.sourcePool.familyHint() == nil {
+		// The pool mixes families, so the domain is resolved here to pick source addresses of the same family.
+		target = h.resolveSourcePoolTarget(ctx, outbound, target.Domain())
+		if target == nil {
+			return nil, newError("failed to resolve ", dest.Address)
+		}
+		resolver, multiResolver := outbound.Resolver, outbound.MultiResolver
+		outbound.Resolver = func(context.Context, string) net.Address {
+			return target
+		}
+		outbound.MultiResolver = nil
+		defer func() {
+			outbound.Resolver, outbound.MultiResolver = resolver, multiResolver
+		}()
+	}
+
+	for _, src := range h.sourcePool.candidates(ctx, dest, target) {
+		outbound.Gateway = src
+		conn, err := internet.Dial(ctx, dest, h.streamSettings)
+		if err == nil {
+			return h.getStatCouterConnection(conn), nil
+		}
+		if !isBindError(err) {
+			return nil, err
+		}
+		newError("failed to send through ", src).Base(err).AtWarning().WriteToLog(session.ExportIDToError(ctx))
+	}
+
+	newError("no address in source pool is usable for ", target, ", sending through default address").AtError().WriteToLog(session.ExportIDToError(ctx))
+	outbound.Gateway = nil
+	conn, err := internet.Dial(ctx, dest, h.streamSettings)
+	return h.getStatCouterConnection(conn), err
+}
+
+// resolveSourcePoolTarget resolves the domain with the resolvers of the outbound if any, or the system resolver.
+func (h *Handler) resolveSourcePoolTarget(ctx context.Context, outbound *session.Outbound, domain string) net.Address {
+	var addrs []net.Address
+	switch {
+	case outbound.MultiResolver != nil:
+		addrs = outbound.MultiResolver(ctx, domain)
+	case outbound.Resolver != nil:
+		if addr := outbound.Resolver(ctx, domain); addr != nil {
+			addrs = append(addrs, addr)
+		}
+	default:
+		ips, err := net.LookupIP(domain)
+		if err != nil {
+			newError("failed to get IP address for domain ", domain).Base(err).WriteToLog(session.ExportIDToError(ctx))
+		}
+		for _, ip := range ips {
+			addrs = append(addrs, net.IPAddress(ip))
+		}
+	}
+	if len(addrs) == 0 {
+		return nil
+	}
+	return addrs[dice.Roll(len(addrs))]
+}
+
+func (h *Handler) resolveIPs(ctx context.Context, domain string, localAddr net.Address) []net.Address {
 	strategy := h.senderSettings.DomainStrategy
 	ips, err := dns.LookupIPWithOption(h.dns, domain, dns.IPOption{
 		IPv4Enable: strategy == proxyman.SenderConfig_USE_IP || strategy == proxyman.SenderConfig_USE_IP4 || (localAddr != nil && localAddr.Family().IsIPv4()),
@@ -265,10 +437,19 @@ func (h *Handler) resolveIP(ctx context.Context, domain string, localAddr net.Ad
 	if err != nil {
 		newError("failed to get IP address for domain ", domain).Base(err).WriteToLog(session.ExportIDToError(ctx))
 	}
-	if len(ips) == 0 {
+	addrs := make([]net.Address, 0, len(ips))
+	for _, ip := range ips {
+		addrs = append(addrs, net.IPAddress(ip))
+	}
+	return addrs
+}
+
+func (h *Handler) resolveIP(ctx context.Context, domain string, localAddr net.Address) net.Address {
+	addrs := h.resolveIPs(ctx, domain, localAddr)
+	if len(addrs) == 0 {
 		return nil
 	}
-	return net.IPAddress(ips[dice.Roll(len(ips))])
+	return addrs[dice.Roll(len(addrs))]
 }
 
 func (h *Handler) getStatCouterConnection(conn internet.Connection) internet.Connection {
@@ -289,11 +470,22 @@ func (h *Handler) GetOutbound() proxy.Outbound {
 
 // Start implements common.Runnable.
 func (h *Handler) Start() error {
+	// The observatory is optional, and is only looked up when all features are registered.
+	if h.instance != nil {
+		if observer, ok := h.instance.GetFeature(extension.ObservatoryType()).(extension.PassiveObserver); ok {
+			h.observer = observer
+		}
+	}
+	if h.mux != nil {
+		return h.mux.Start()
+	}
 	return nil
 }
 
 // Close implements common.Closable.
 func (h *Handler) Close() error {
-	common.Close(h.mux)
+	if h.mux != nil {
+		common.Close(h.mux)
+	}
 	return nil
 }
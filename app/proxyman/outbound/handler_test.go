@@ -9,13 +9,18 @@ import (
 
 	core "github.com/v2fly/v2ray-core/v5"
 	"github.com/v2fly/v2ray-core/v5/app/policy"
+	"github.com/v2fly/v2ray-core/v5/app/proxyman"
 	. "github.com/v2fly/v2ray-core/v5/app/proxyman/outbound"
 	"github.com/v2fly/v2ray-core/v5/app/stats"
+	"github.com/v2fly/v2ray-core/v5/common"
 	"github.com/v2fly/v2ray-core/v5/common/net"
 	"github.com/v2fly/v2ray-core/v5/common/serial"
 	"github.com/v2fly/v2ray-core/v5/features/outbound"
+	featurestats "github.com/v2fly/v2ray-core/v5/features/stats"
 	"github.com/v2fly/v2ray-core/v5/proxy/freedom"
+	"github.com/v2fly/v2ray-core/v5/testing/servers/tcp"
 	"github.com/v2fly/v2ray-core/v5/transport/internet"
+	_ "github.com/v2fly/v2ray-core/v5/transport/internet/tcp"
 )
 
 func TestInterfaces(t *testing.T) {
@@ -82,3 +87,81 @@ func TestOutboundWithStatCounter(t *testing.T) {
 		t.Errorf("Expected conn to be StatCouterConnection")
 	}
 }
+
+func TestOutboundSourcePoolMixedFamily(t *testing.T) {
+	tcpServer := tcp.Server{
+		MsgProcessor: func(b []byte) []byte {
+			return b
+		},
+	}
+	dest, err := tcpServer.Start()
+	common.Must(err)
+	defer tcpServer.Close()
+
+	v, err := core.New(&core.Config{})
+	common.Must(err)
+	v.AddFeature((outbound.Manager)(new(Manager)))
+	ctx := toContext(context.Background(), v)
+	h, err := NewHandler(ctx, &core.OutboundHandlerConfig{
+		Tag: "tag",
+		SenderSettings: serial.ToTypedMessage(&proxyman.SenderConfig{
+			ViaPool: &proxyman.SourceAddressPool{
+				Prefix: []*proxyman.SourceAddressPool_Prefix{
+					{Ip: net.ParseAddress("::1").IP()},
+					{Ip: []byte{127, 0, 0, 2}},
+				},
+				Strategy: proxyman.SourceAddressPool_RoundRobin,
+			},
+		}),
+		ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+	})
+	common.Must(err)
+
+	// IPv4 destinations are dialed only from IPv4 addresses in the pool.
+	for i := 0; i < 4; i++ {
+		conn, err := h.(*Handler).Dial(ctx, dest)
+		common.Must(err)
+		if local := conn.LocalAddr().(*net.TCPAddr).IP.String(); local != "127.0.0.2" {
+			t.Error("expected local address 127.0.0.2, but got ", local)
+		}
+		conn.Close()
+	}
+}
+
+func TestOutboundMuxStatCounter(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		config := &core.Config{
+			App: []*anypb.Any{
+				serial.ToTypedMessage(&stats.Config{}),
+				serial.ToTypedMessage(&policy.Config{
+					System: &policy.SystemPolicy{
+						Stats: &policy.SystemPolicy_Stats{
+							OutboundUplink: enabled,
+						},
+					},
+				}),
+			},
+		}
+
+		v, err := core.New(config)
+		common.Must(err)
+		v.AddFeature((outbound.Manager)(new(Manager)))
+		ctx := toContext(context.Background(), v)
+		_, err = NewHandler(ctx, &core.OutboundHandlerConfig{
+			Tag: "tag",
+			SenderSettings: serial.ToTypedMessage(&proxyman.SenderConfig{
+				MultiplexSettings: &proxyman.MultiplexingConfig{
+					Enabled:     true,
+					Concurrency: 8,
+				},
+			}),
+			ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+		})
+		common.Must(err)
+
+		statsManager := v.GetFeature(featurestats.ManagerType()).(featurestats.Manager)
+		if counter := statsManager.GetCounter("outbound>>>tag>>>mux>>>workers"); (counter != nil) != enabled {
+			t.Error("unexpected mux worker counter ", counter, " with outbound stats enabled: ", enabled)
+		}
+	}
+}
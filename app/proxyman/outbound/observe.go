@@ -0,0 +1,134 @@
+package outbound
+
+import (
+	"context"
+	stderrors "errors"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/buf"
+	"github.com/v2fly/v2ray-core/v5/common/errors"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/features/extension"
+	"github.com/v2fly/v2ray-core/v5/transport/internet"
+)
+
+// firstByteRecorder records the time the first byte is received from the remote.
+type firstByteRecorder struct {
+	buf.Writer
+	start     time.Time
+	firstByte int64
+	// onFirstByte is called with the delay when the first byte is received, if not nil.
+	onFirstByte func(time.Duration)
+}
+
+func newFirstByteRecorder(writer buf.Writer) *firstByteRecorder {
+	return &firstByteRecorder{
+		Writer: writer,
+		start:  time.Now(),
+	}
+}
+
+// WriteMultiBuffer implements buf.Writer.
+func (w *firstByteRecorder) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	if !mb.IsEmpty() && atomic.LoadInt64(&w.firstByte) == 0 {
+		delay := time.Since(w.start)
+		if atomic.CompareAndSwapInt64(&w.firstByte, 0, int64(delay)) && w.onFirstByte != nil {
+			w.onFirstByte(delay)
+		}
+	}
+	return w.Writer.WriteMultiBuffer(mb)
+}
+
+// Close implements common.Closable.
+func (w *firstByteRecorder) Close() error {
+	return common.Close(w.Writer)
+}
+
+// Interrupt implements common.Interruptible.
+func (w *firstByteRecorder) Interrupt() {
+	common.Interrupt(w.Writer)
+}
+
+func (w *firstByteRecorder) delay() time.Duration {
+	return time.Duration(atomic.LoadInt64(&w.firstByte))
+}
+
+// transportRecorder is a dialer which records whether the transport of the outbound fails to dial or handshake.
+// Failures of proxies, such as remotes refusing or closing connections, are not recorded.
+type transportRecorder struct {
+	internet.Dialer
+	failed int32
+	// onFailure is called with the error when the transport fails, if not nil.
+	onFailure func(error)
+}
+
+// Dial implements internet.Dialer. Connections with lazy handshakes, such as those of TLS, handshake before they
+// are returned, so that handshake failures are told apart from failures of proxies.
+func (r *transportRecorder) Dial(ctx context.Context, dest net.Destination) (internet.Connection, error) {
+	conn, err := r.Dialer.Dial(ctx, dest)
+	if err != nil {
+		r.fail(err)
+		return conn, err
+	}
+	rawConn := conn
+	if statConn, ok := rawConn.(*internet.StatCouterConnection); ok {
+		rawConn = statConn.Connection
+	}
+	if handshaker, ok := rawConn.(interface{ HandshakeContext(context.Context) error }); ok {
+		if err := handshaker.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			err = newError("failed to handshake with ", dest).Base(err)
+			r.fail(err)
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+func (r *transportRecorder) fail(err error) {
+	atomic.StoreInt32(&r.failed, 1)
+	if r.onFailure != nil {
+		r.onFailure(err)
+	}
+}
+
+func (r *transportRecorder) hasFailed() bool {
+	return atomic.LoadInt32(&r.failed) == 1
+}
+
+func isConnectionReset(err error) bool {
+	return stderrors.Is(errors.Cause(err), syscall.ECONNRESET)
+}
+
+// observeTraffic classifies the outcome of a connection for the passive observer, where transportFailed tells
+// whether the transport of the outbound failed. It returns nil if the outcome tells nothing about the health of the outbound.
+func observeTraffic(tag string, err error, firstByteDelay time.Duration, transportFailed bool) *extension.TrafficObservation {
+	observation := &extension.TrafficObservation{
+		OutboundTag:    tag,
+		FirstByteDelay: firstByteDelay,
+		Err:            err,
+	}
+	switch {
+	case err == nil:
+		if firstByteDelay == 0 {
+			return nil
+		}
+	case stderrors.Is(errors.Cause(err), context.Canceled):
+		// The connection is closed by the client.
+		return nil
+	case transportFailed:
+		observation.Failed = true
+	case firstByteDelay == 0:
+		// Errors relayed from the remote, such as refused connections to the target, are not failures of the outbound.
+		return nil
+	case isConnectionReset(err):
+		observation.Reset = true
+	default:
+		// Errors after the remote responded are mostly caused by the client or the target.
+		observation.Err = nil
+	}
+	return observation
+}
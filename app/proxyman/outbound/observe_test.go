@@ -0,0 +1,114 @@
+package outbound
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	gonet "net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/buf"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/transport/internet"
+)
+
+func TestObserveTraffic(t *testing.T) {
+	reset := &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+	testCases := []struct {
+		name      string
+		err       error
+		delay     time.Duration
+		transport bool
+		nilObs    bool
+		failed    bool
+		reset     bool
+	}{
+		{name: "success", delay: time.Second},
+		{name: "no response", nilObs: true},
+		{name: "canceled", err: newError("closed").Base(context.Canceled), nilObs: true},
+		{name: "failed", err: newError("dial").Base(io.EOF), transport: true, failed: true},
+		{name: "closed by remote", err: newError("failed to read response").Base(io.EOF), nilObs: true},
+		{name: "reset", err: newError("read").Base(reset), delay: time.Second, reset: true},
+		{name: "reset message", err: newError("connection reset by client"), delay: time.Second},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			observation := observeTraffic("out", tc.err, tc.delay, tc.transport)
+			if tc.nilObs {
+				if observation != nil {
+					t.Error("unexpected observation ", observation)
+				}
+				return
+			}
+			if observation.Failed != tc.failed || observation.Reset != tc.reset {
+				t.Error("unexpected observation ", observation)
+			}
+		})
+	}
+}
+
+func TestFirstByteRecorder(t *testing.T) {
+	var observed []time.Duration
+	recorder := newFirstByteRecorder(buf.Discard)
+	recorder.onFirstByte = func(delay time.Duration) {
+		observed = append(observed, delay)
+	}
+	common.Must(recorder.WriteMultiBuffer(buf.MultiBuffer{}))
+	for i := 0; i < 2; i++ {
+		common.Must(recorder.WriteMultiBuffer(buf.MergeBytes(nil, []byte("data"))))
+	}
+	if len(observed) != 1 || observed[0] != recorder.delay() {
+		t.Error("unexpected first bytes observed: ", observed)
+	}
+}
+
+type fakeDialer struct {
+	conn internet.Connection
+	err  error
+}
+
+func (d *fakeDialer) Dial(context.Context, net.Destination) (internet.Connection, error) {
+	return d.conn, d.err
+}
+
+func (d *fakeDialer) Address() net.Address {
+	return nil
+}
+
+func TestTransportRecorder(t *testing.T) {
+	var failures []error
+	recorder := &transportRecorder{Dialer: &fakeDialer{err: newError("refused")}, onFailure: func(err error) {
+		failures = append(failures, err)
+	}}
+	if _, err := recorder.Dial(context.Background(), net.TCPDestination(net.LocalHostIP, 443)); err == nil {
+		t.Fatal("expected dial failure")
+	}
+	if !recorder.hasFailed() || len(failures) != 1 {
+		t.Error("dial failure is not recorded")
+	}
+
+	// The remote closes the connection during the TLS handshake.
+	client, server := gonet.Pipe()
+	server.Close()
+	recorder = &transportRecorder{Dialer: &fakeDialer{conn: tls.Client(client, &tls.Config{ServerName: "example.com"})}}
+	if _, err := recorder.Dial(context.Background(), net.TCPDestination(net.LocalHostIP, 443)); err == nil {
+		t.Fatal("expected handshake failure")
+	}
+	if !recorder.hasFailed() {
+		t.Error("handshake failure is not recorded")
+	}
+
+	client, server = gonet.Pipe()
+	defer server.Close()
+	recorder = &transportRecorder{Dialer: &fakeDialer{conn: client}}
+	conn, err := recorder.Dial(context.Background(), net.TCPDestination(net.LocalHostIP, 443))
+	common.Must(err)
+	conn.Close()
+	if recorder.hasFailed() {
+		t.Error("unexpected failure")
+	}
+}
@@ -0,0 +1,191 @@
+package outbound
+
+import (
+	"context"
+	"hash/fnv"
+	"math/big"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/v2fly/v2ray-core/v5/app/proxyman"
+	"github.com/v2fly/v2ray-core/v5/common/dice"
+	"github.com/v2fly/v2ray-core/v5/common/errors"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/session"
+)
+
+// maxSourceBindAttempts is the number of pool addresses tried before falling back to the system default source.
+const maxSourceBindAttempts = 3
+
+// maxSourceRangeHostBits limits the addresses used in a prefix, so that indexes of all addresses in a pool
+// fit in uint64. Only the first 2^62 addresses of prefixes with more host bits, such as IPv6 /64, are used.
+const maxSourceRangeHostBits = 62
+
+type sourceRange struct {
+	base  *big.Int
+	ipLen int
+	first uint64
+	size  uint64
+}
+
+// sourceAddressPool picks source addresses for outgoing connections from a set of IP prefixes.
+type sourceAddressPool struct {
+	counter  uint64
+	ranges   []sourceRange
+	total    uint64
+	strategy proxyman.SourceAddressPool_Strategy
+}
+
+func newSourceAddressPool(config *proxyman.SourceAddressPool) (*sourceAddressPool, error) {
+	pool := &sourceAddressPool{
+		strategy: config.Strategy,
+	}
+	for _, prefix := range config.Prefix {
+		ipLen := len(prefix.Ip)
+		if ipLen != net.IPv4len && ipLen != net.IPv6len {
+			return nil, newError("invalid source address: ", prefix.Ip)
+		}
+		bits := uint32(ipLen * 8)
+		ones := prefix.Prefix
+		if ones == 0 {
+			ones = bits
+		}
+		if ones > bits {
+			return nil, newError("invalid prefix length ", ones, " for ", net.IP(prefix.Ip))
+		}
+
+		hostBits := bits - ones
+		ip := net.IP(prefix.Ip).Mask(net.CIDRMask(int(ones), int(bits)))
+		r := sourceRange{
+			base:  new(big.Int).SetBytes(ip),
+			ipLen: ipLen,
+			size:  1,
+		}
+		if hostBits > maxSourceRangeHostBits {
+			newError("only the first 2^", maxSourceRangeHostBits, " addresses of ", net.IP(prefix.Ip), "/", ones, " are used as source addresses").AtInfo().WriteToLog()
+			r.size <<= maxSourceRangeHostBits
+		} else {
+			r.size <<= hostBits
+		}
+		if r.size > 2 {
+			// Skip the network address, and the broadcast address for IPv4.
+			r.first = 1
+			r.size--
+			if ipLen == net.IPv4len {
+				r.size--
+			}
+		}
+		if pool.total+r.size < pool.total {
+			return nil, newError("source address pool is too large")
+		}
+		pool.total += r.size
+		pool.ranges = append(pool.ranges, r)
+	}
+	if pool.total == 0 {
+		return nil, newError("empty source address pool")
+	}
+	return pool, nil
+}
+
+// familyHint returns an address of the family shared by all addresses in the pool, or nil if the pool mixes families.
+func (p *sourceAddressPool) familyHint() net.Address {
+	first := p.address(0)
+	for i := range p.ranges {
+		if p.ranges[i].ipLen != p.ranges[0].ipLen {
+			return nil
+		}
+	}
+	return first
+}
+
+func (p *sourceAddressPool) address(index uint64) net.Address {
+	return p.addressOf(index, 0)
+}
+
+// size returns the number of addresses of length ipLen in the pool, or of all addresses if ipLen is 0.
+func (p *sourceAddressPool) size(ipLen int) uint64 {
+	if ipLen == 0 {
+		return p.total
+	}
+	var size uint64
+	for _, r := range p.ranges {
+		if r.ipLen == ipLen {
+			size += r.size
+		}
+	}
+	return size
+}
+
+// addressOf returns the address at index among addresses of length ipLen in the pool, or among all addresses if ipLen is 0.
+func (p *sourceAddressPool) addressOf(index uint64, ipLen int) net.Address {
+	index %= p.size(ipLen)
+	for _, r := range p.ranges {
+		if ipLen != 0 && r.ipLen != ipLen {
+			continue
+		}
+		if index >= r.size {
+			index -= r.size
+			continue
+		}
+		v := new(big.Int).Add(r.base, new(big.Int).SetUint64(r.first+index))
+		ip := make([]byte, r.ipLen)
+		v.FillBytes(ip)
+		return net.IPAddress(ip)
+	}
+	panic("unreachable")
+}
+
+func hashSourceKey(key string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return h.Sum64()
+}
+
+func (p *sourceAddressPool) pick(ctx context.Context, dest net.Destination) uint64 {
+	switch p.strategy {
+	case proxyman.SourceAddressPool_RoundRobin:
+		return atomic.AddUint64(&p.counter, 1) - 1
+	case proxyman.SourceAddressPool_StickyDestination:
+		return hashSourceKey(dest.Address.String())
+	case proxyman.SourceAddressPool_StickyUser:
+		if inbound := session.InboundFromContext(ctx); inbound != nil {
+			if inbound.User != nil && inbound.User.Email != "" {
+				return hashSourceKey(inbound.User.Email)
+			}
+			if inbound.Source.IsValid() {
+				return hashSourceKey(inbound.Source.Address.String())
+			}
+		}
+		// Connections from no user or source, such as those of DNS queries, stick to destinations.
+		return hashSourceKey(dest.Address.String())
+	default:
+		return dice.RollUint64()
+	}
+}
+
+// candidates returns the source addresses to try in order for a connection to dest. If target is an IP address,
+// only addresses of its family are returned, which may be none.
+func (p *sourceAddressPool) candidates(ctx context.Context, dest net.Destination, target net.Address) []net.Address {
+	ipLen := 0
+	if target != nil && target.Family().IsIP() {
+		ipLen = len(target.IP())
+	}
+	attempts := uint64(maxSourceBindAttempts)
+	if size := p.size(ipLen); size < attempts {
+		attempts = size
+	}
+	index := p.pick(ctx, dest)
+	addrs := make([]net.Address, 0, attempts)
+	for i := uint64(0); i < attempts; i++ {
+		addrs = append(addrs, p.addressOf(index+i, ipLen))
+	}
+	return addrs
+}
+
+func isBindError(err error) bool {
+	err = errors.Cause(err)
+	if opErr, ok := err.(*net.OpError); ok {
+		err = errors.Cause(opErr.Err)
+	}
+	return err == syscall.EADDRNOTAVAIL || err == syscall.EADDRINUSE
+}
@@ -0,0 +1,151 @@
+package outbound
+
+import (
+	"context"
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/app/proxyman"
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/protocol"
+	"github.com/v2fly/v2ray-core/v5/common/session"
+)
+
+func TestSourceAddressPoolRoundRobin(t *testing.T) {
+	pool, err := newSourceAddressPool(&proxyman.SourceAddressPool{
+		Prefix: []*proxyman.SourceAddressPool_Prefix{
+			{Ip: []byte{192, 0, 2, 0}, Prefix: 30},
+			{Ip: []byte{198, 51, 100, 7}},
+		},
+		Strategy: proxyman.SourceAddressPool_RoundRobin,
+	})
+	common.Must(err)
+
+	expected := []string{"192.0.2.1", "192.0.2.2", "198.51.100.7", "192.0.2.1"}
+	for _, e := range expected {
+		addr := pool.address(pool.pick(context.Background(), net.Destination{}))
+		if addr.String() != e {
+			t.Error("expected ", e, " but got ", addr)
+		}
+	}
+}
+
+func TestSourceAddressPoolLargeIPv6Prefix(t *testing.T) {
+	pool, err := newSourceAddressPool(&proxyman.SourceAddressPool{
+		Prefix: []*proxyman.SourceAddressPool_Prefix{
+			{Ip: net.ParseAddress("2001:db8::").IP(), Prefix: 64},
+		},
+	})
+	common.Must(err)
+
+	prefix := &net.IPNet{IP: net.ParseAddress("2001:db8::").IP(), Mask: net.CIDRMask(64, 128)}
+	for i := 0; i < 16; i++ {
+		addr := pool.address(pool.pick(context.Background(), net.Destination{}))
+		if !prefix.Contains(addr.IP()) {
+			t.Error("address ", addr, " is not in ", prefix)
+		}
+	}
+	if pool.familyHint() == nil || !pool.familyHint().Family().IsIPv6() {
+		t.Error("expected IPv6 family hint")
+	}
+	// Only the first 2^62 addresses are used.
+	if pool.total != 1<<maxSourceRangeHostBits-1 {
+		t.Error("unexpected pool size ", pool.total)
+	}
+	if addr := pool.address(pool.total - 1); addr.IP().String() != "2001:db8::3fff:ffff:ffff:ffff" {
+		t.Error("unexpected last address ", addr)
+	}
+}
+
+func TestSourceAddressPoolSticky(t *testing.T) {
+	pool, err := newSourceAddressPool(&proxyman.SourceAddressPool{
+		Prefix: []*proxyman.SourceAddressPool_Prefix{
+			{Ip: []byte{192, 0, 2, 0}, Prefix: 24},
+		},
+		Strategy: proxyman.SourceAddressPool_StickyUser,
+	})
+	common.Must(err)
+
+	ctx := session.ContextWithInbound(context.Background(), &session.Inbound{
+		User: &protocol.MemoryUser{Email: "love@v2fly.org"},
+	})
+	dest := net.TCPDestination(net.DomainAddress("www.v2fly.org"), 443)
+	first := pool.candidates(ctx, dest, dest.Address)
+	for i := 0; i < 8; i++ {
+		if addrs := pool.candidates(ctx, dest, dest.Address); addrs[0].String() != first[0].String() {
+			t.Error("expected sticky address ", first[0], " but got ", addrs[0])
+		}
+	}
+	if len(first) != maxSourceBindAttempts {
+		t.Error("unexpected number of candidates: ", len(first))
+	}
+}
+
+func TestSourceAddressPoolStickyUserFallback(t *testing.T) {
+	pool, err := newSourceAddressPool(&proxyman.SourceAddressPool{
+		Prefix: []*proxyman.SourceAddressPool_Prefix{
+			{Ip: []byte{192, 0, 2, 0}, Prefix: 24},
+		},
+		Strategy: proxyman.SourceAddressPool_StickyUser,
+	})
+	common.Must(err)
+
+	dest := net.TCPDestination(net.DomainAddress("www.v2fly.org"), 443)
+	sourceContext := func(source string) context.Context {
+		return session.ContextWithInbound(context.Background(), &session.Inbound{
+			Source: net.TCPDestination(net.ParseAddress(source), 10000),
+		})
+	}
+	// Without users, connections stick to their source addresses.
+	if pool.pick(sourceContext("10.0.0.1"), dest) != pool.pick(sourceContext("10.0.0.1"), net.Destination{}) {
+		t.Error("expected the same pick for the same source")
+	}
+	if pool.pick(sourceContext("10.0.0.1"), dest) == pool.pick(sourceContext("10.0.0.2"), dest) {
+		t.Error("expected different picks for different sources")
+	}
+	// Without inbounds, connections stick to their destinations.
+	other := net.TCPDestination(net.DomainAddress("v2fly.org"), 443)
+	if pool.pick(context.Background(), dest) != pool.pick(context.Background(), dest) {
+		t.Error("expected the same pick for the same destination")
+	}
+	if pool.pick(context.Background(), dest) == pool.pick(context.Background(), other) {
+		t.Error("expected different picks for different destinations")
+	}
+}
+
+func TestSourceAddressPoolMixedFamily(t *testing.T) {
+	pool, err := newSourceAddressPool(&proxyman.SourceAddressPool{
+		Prefix: []*proxyman.SourceAddressPool_Prefix{
+			{Ip: net.ParseAddress("2001:db8::").IP(), Prefix: 64},
+			{Ip: []byte{192, 0, 2, 0}, Prefix: 24},
+		},
+	})
+	common.Must(err)
+	if pool.familyHint() != nil {
+		t.Error("expected no family hint for a mixed pool")
+	}
+
+	for _, target := range []string{"198.51.100.1", "2001:db8:1::1"} {
+		dest := net.TCPDestination(net.ParseAddress(target), 443)
+		addrs := pool.candidates(context.Background(), dest, dest.Address)
+		if len(addrs) != maxSourceBindAttempts {
+			t.Error("unexpected number of candidates: ", len(addrs))
+		}
+		for _, addr := range addrs {
+			if addr.Family() != dest.Address.Family() {
+				t.Error("candidate ", addr, " does not match the family of ", dest.Address)
+			}
+		}
+	}
+
+	ipv6Pool, err := newSourceAddressPool(&proxyman.SourceAddressPool{
+		Prefix: []*proxyman.SourceAddressPool_Prefix{
+			{Ip: net.ParseAddress("2001:db8::1").IP()},
+		},
+	})
+	common.Must(err)
+	dest := net.TCPDestination(net.ParseAddress("198.51.100.1"), 443)
+	if addrs := ipv6Pool.candidates(context.Background(), dest, dest.Address); len(addrs) != 0 {
+		t.Error("expected no candidates for IPv4 destinations, but got ", addrs)
+	}
+}
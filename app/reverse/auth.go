@@ -0,0 +1,115 @@
+package reverse
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+	"time"
+)
+
+const (
+	challengeSize = 32
+	// bridgeAuthTimeout is the time for a bridge to authenticate before it is rejected.
+	bridgeAuthTimeout = 10 * time.Second
+)
+
+var authContext = []byte("v2fly reverse bridge authentication\x00")
+
+func newChallenge() []byte {
+	challenge := make([]byte, challengeSize)
+	if _, err := io.ReadFull(rand.Reader, challenge); err != nil {
+		panic(err)
+	}
+	return challenge
+}
+
+// authMessage returns the message bridges prove their identity with.
+func authMessage(challenge []byte, id string) []byte {
+	msg := make([]byte, 0, len(authContext)+len(challenge)+len(id))
+	msg = append(msg, authContext...)
+	msg = append(msg, challenge...)
+	return append(msg, id...)
+}
+
+func hmacResponse(psk string, msg []byte) []byte {
+	h := hmac.New(sha256.New, []byte(psk))
+	h.Write(msg)
+	return h.Sum(nil)
+}
+
+// bridgeAuthenticator answers the challenges of portals.
+type bridgeAuthenticator struct {
+	psk        string
+	privateKey ed25519.PrivateKey
+}
+
+func newBridgeAuthenticator(config *BridgeConfig) (*bridgeAuthenticator, error) {
+	a := &bridgeAuthenticator{psk: config.Psk}
+	switch len(config.PrivateKey) {
+	case 0:
+	case ed25519.SeedSize:
+		a.privateKey = ed25519.NewKeyFromSeed(config.PrivateKey)
+	case ed25519.PrivateKeySize:
+		a.privateKey = ed25519.PrivateKey(config.PrivateKey)
+	default:
+		return nil, newError("invalid ed25519 private key size: ", len(config.PrivateKey))
+	}
+	if a.psk == "" && a.privateKey == nil {
+		return nil, nil
+	}
+	if config.Id == "" {
+		return nil, newError("bridge id is required for authentication")
+	}
+	return a, nil
+}
+
+func (a *bridgeAuthenticator) respond(challenge []byte, id string) []byte {
+	if a == nil || len(challenge) == 0 {
+		return nil
+	}
+	msg := authMessage(challenge, id)
+	if a.privateKey != nil {
+		return ed25519.Sign(a.privateKey, msg)
+	}
+	return hmacResponse(a.psk, msg)
+}
+
+// portalAuthenticator verifies the responses of bridges.
+type portalAuthenticator struct {
+	credentials map[string]*BridgeCredential
+}
+
+func newPortalAuthenticator(credentials []*BridgeCredential) (*portalAuthenticator, error) {
+	if len(credentials) == 0 {
+		return nil, nil
+	}
+	a := &portalAuthenticator{credentials: make(map[string]*BridgeCredential, len(credentials))}
+	for _, c := range credentials {
+		if c.Id == "" {
+			return nil, newError("bridge id is empty")
+		}
+		if c.Psk == "" && len(c.PublicKey) == 0 {
+			return nil, newError("no credential for bridge ", c.Id)
+		}
+		if len(c.PublicKey) != 0 && len(c.PublicKey) != ed25519.PublicKeySize {
+			return nil, newError("invalid ed25519 public key size for bridge ", c.Id)
+		}
+		a.credentials[c.Id] = c
+	}
+	return a, nil
+}
+
+// verify returns whether the response proves the identity of the bridge.
+func (a *portalAuthenticator) verify(challenge []byte, id string, response []byte) bool {
+	c, found := a.credentials[id]
+	if !found || len(response) == 0 {
+		return false
+	}
+	msg := authMessage(challenge, id)
+	if len(c.PublicKey) != 0 && ed25519.Verify(ed25519.PublicKey(c.PublicKey), msg, response) {
+		return true
+	}
+	return c.Psk != "" && hmac.Equal(hmacResponse(c.Psk, msg), response)
+}
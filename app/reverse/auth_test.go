@@ -0,0 +1,63 @@
+package reverse
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+)
+
+func TestBridgeAuthentication(t *testing.T) {
+	seed := bytes.Repeat([]byte{1}, ed25519.SeedSize)
+	publicKey := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+
+	portal, err := newPortalAuthenticator([]*BridgeCredential{
+		{Id: "site-a", Psk: "secret"},
+		{Id: "site-b", PublicKey: publicKey},
+	})
+	common.Must(err)
+
+	pskBridge, err := newBridgeAuthenticator(&BridgeConfig{Id: "site-a", Psk: "secret"})
+	common.Must(err)
+	keyBridge, err := newBridgeAuthenticator(&BridgeConfig{Id: "site-b", PrivateKey: seed})
+	common.Must(err)
+	wrongBridge, err := newBridgeAuthenticator(&BridgeConfig{Id: "site-a", Psk: "guess"})
+	common.Must(err)
+
+	challenge := newChallenge()
+	testCases := []struct {
+		id       string
+		response []byte
+		accepted bool
+	}{
+		{"site-a", pskBridge.respond(challenge, "site-a"), true},
+		{"site-b", keyBridge.respond(challenge, "site-b"), true},
+		{"site-a", wrongBridge.respond(challenge, "site-a"), false},
+		// Responses are bound to the id and the challenge.
+		{"site-b", pskBridge.respond(challenge, "site-b"), false},
+		{"site-a", pskBridge.respond(newChallenge(), "site-a"), false},
+		{"site-c", pskBridge.respond(challenge, "site-c"), false},
+		{"site-a", nil, false},
+	}
+	for i, tc := range testCases {
+		if accepted := portal.verify(challenge, tc.id, tc.response); accepted != tc.accepted {
+			t.Errorf("case %d: expected %v, but got %v", i, tc.accepted, accepted)
+		}
+	}
+}
+
+func TestBridgeAuthenticatorConfig(t *testing.T) {
+	if a, err := newBridgeAuthenticator(&BridgeConfig{}); a != nil || err != nil {
+		t.Error("expected no authenticator, but got ", a, err)
+	}
+	if _, err := newBridgeAuthenticator(&BridgeConfig{Psk: "secret"}); err == nil {
+		t.Error("expected error for missing id, but nil")
+	}
+	if _, err := newBridgeAuthenticator(&BridgeConfig{Id: "a", PrivateKey: []byte{1, 2, 3}}); err == nil {
+		t.Error("expected error for invalid private key, but nil")
+	}
+	if _, err := newPortalAuthenticator([]*BridgeCredential{{Id: "a"}}); err == nil {
+		t.Error("expected error for missing credential, but nil")
+	}
+}
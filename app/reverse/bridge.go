@@ -6,6 +6,8 @@ import (
 
 	"google.golang.org/protobuf/proto"
 
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/buf"
 	"github.com/v2fly/v2ray-core/v5/common/mux"
 	"github.com/v2fly/v2ray-core/v5/common/net"
 	"github.com/v2fly/v2ray-core/v5/common/session"
@@ -21,6 +23,9 @@ type Bridge struct {
 	dispatcher  routing.Dispatcher
 	tag         string
 	domain      string
+	control     string
+	id          string
+	auth        *bridgeAuthenticator
 	workers     []*BridgeWorker
 	monitorTask *task.Periodic
 }
@@ -34,11 +39,19 @@ func NewBridge(ctx context.Context, config *BridgeConfig, dispatcher routing.Dis
 		return nil, newError("bridge domain is empty")
 	}
 
+	auth, err := newBridgeAuthenticator(config)
+	if err != nil {
+		return nil, newError("invalid credential of bridge ", config.Tag).Base(err)
+	}
+
 	b := &Bridge{
 		ctx:        ctx,
 		dispatcher: dispatcher,
 		tag:        config.Tag,
 		domain:     config.Domain,
+		control:    controlDomain(config.ControlDomain),
+		id:         config.Id,
+		auth:       auth,
 	}
 	b.monitorTask = &task.Periodic{
 		Execute:  b.monitor,
@@ -75,7 +88,7 @@ func (b *Bridge) monitor() error {
 	}
 
 	if numWorker == 0 || numConnections/numWorker > 16 {
-		worker, err := NewBridgeWorker(b.ctx, b.domain, b.tag, b.dispatcher)
+		worker, err := NewBridgeWorker(b.ctx, b.domain, b.control, b.tag, b.id, b.auth, b.dispatcher)
 		if err != nil {
 			newError("failed to create bridge worker").Base(err).AtWarning().WriteToLog()
 			return nil
@@ -96,12 +109,15 @@ func (b *Bridge) Close() error {
 
 type BridgeWorker struct {
 	tag        string
+	control    string
+	id         string
+	auth       *bridgeAuthenticator
 	worker     *mux.ServerWorker
 	dispatcher routing.Dispatcher
 	state      Control_State
 }
 
-func NewBridgeWorker(ctx context.Context, domain string, tag string, d routing.Dispatcher) (*BridgeWorker, error) {
+func NewBridgeWorker(ctx context.Context, domain string, control string, tag string, id string, auth *bridgeAuthenticator, d routing.Dispatcher) (*BridgeWorker, error) {
 	bridgeCtx := session.ContextWithInbound(ctx, &session.Inbound{
 		Tag: tag,
 	})
@@ -117,6 +133,9 @@ func NewBridgeWorker(ctx context.Context, domain string, tag string, d routing.D
 	w := &BridgeWorker{
 		dispatcher: d,
 		tag:        tag,
+		control:    control,
+		id:         id,
+		auth:       auth,
 	}
 
 	worker, err := mux.NewServerWorker(ctx, w, link)
@@ -165,13 +184,30 @@ func (w *BridgeWorker) handleInternalConn(link transport.Link) {
 				if ctl.State != w.state {
 					w.state = ctl.State
 				}
+				if ctl.Identify {
+					w.reply(link.Writer, ctl.Challenge)
+				}
 			}
 		}
 	}()
 }
 
+// reply sends the identity of the bridge to the portal, with the response to the challenge if any.
+func (w *BridgeWorker) reply(writer buf.Writer, challenge []byte) {
+	msg := &Control{
+		BridgeId: w.id,
+		Response: w.auth.respond(challenge, w.id),
+	}
+	msg.FillInRandom()
+	b, err := proto.Marshal(msg)
+	common.Must(err)
+	if err := writer.WriteMultiBuffer(buf.MergeBytes(nil, b)); err != nil {
+		newError("failed to reply to portal").Base(err).AtDebug().WriteToLog()
+	}
+}
+
 func (w *BridgeWorker) Dispatch(ctx context.Context, dest net.Destination) (*transport.Link, error) {
-	if !isInternalDomain(dest) {
+	if !isDomain(dest, w.control) {
 		ctx = session.ContextWithInbound(ctx, &session.Inbound{
 			Tag: w.tag,
 		})
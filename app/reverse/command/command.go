@@ -0,0 +1,70 @@
+package command
+
+//go:generate go run github.com/v2fly/v2ray-core/v5/common/errors/errorgen
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	core "github.com/v2fly/v2ray-core/v5"
+	"github.com/v2fly/v2ray-core/v5/app/reverse"
+	"github.com/v2fly/v2ray-core/v5/common"
+)
+
+type reverseServer struct {
+	reverse *reverse.Reverse
+}
+
+// NewReverseServer creates a ReverseServiceServer for the reverse proxy.
+func NewReverseServer(r *reverse.Reverse) ReverseServiceServer {
+	return &reverseServer{reverse: r}
+}
+
+func (s *reverseServer) ListBridges(ctx context.Context, request *ListBridgesRequest) (*ListBridgesResponse, error) {
+	response := &ListBridgesResponse{}
+	found := false
+	for _, portal := range s.reverse.Portals() {
+		if request.PortalTag != "" && portal.Tag() != request.PortalTag {
+			continue
+		}
+		found = true
+		for _, bridge := range portal.Bridges() {
+			status := &BridgeStatus{
+				PortalTag:         portal.Tag(),
+				Id:                bridge.ID,
+				Workers:           uint32(bridge.Workers),
+				ActiveConnections: bridge.ActiveConnections,
+				ConnectedSince:    bridge.ConnectedSince.Unix(),
+				Healthy:           bridge.Healthy,
+			}
+			if !bridge.LastSeen.IsZero() {
+				status.LastSeen = bridge.LastSeen.Unix()
+			}
+			response.Bridge = append(response.Bridge, status)
+		}
+	}
+	if request.PortalTag != "" && !found {
+		return nil, newError("portal not found: ", request.PortalTag)
+	}
+	return response, nil
+}
+
+func (s *reverseServer) mustEmbedUnimplementedReverseServiceServer() {}
+
+type service struct {
+	v *core.Instance
+}
+
+func (s *service) Register(server *grpc.Server) {
+	common.Must(s.v.RequireFeatures(func(r *reverse.Reverse) {
+		RegisterReverseServiceServer(server, NewReverseServer(r))
+	}))
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, cfg interface{}) (interface{}, error) {
+		s := core.MustFromContext(ctx)
+		return &service{v: s}, nil
+	}))
+}
@@ -0,0 +1,54 @@
+package command_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/app/reverse"
+	. "github.com/v2fly/v2ray-core/v5/app/reverse/command"
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/session"
+	"github.com/v2fly/v2ray-core/v5/transport"
+	"github.com/v2fly/v2ray-core/v5/transport/pipe"
+)
+
+func TestListBridges(t *testing.T) {
+	r := new(reverse.Reverse)
+	common.Must(r.Init(context.Background(), &reverse.Config{
+		PortalConfig: []*reverse.PortalConfig{
+			{Tag: "portal-a", Domain: "a.example.com"},
+			{Tag: "portal-b", Domain: "b.example.com"},
+		},
+	}, nil, nil, nil, nil))
+
+	// A bridge connects to portal-a.
+	uplinkReader, uplinkWriter := pipe.New()
+	downlinkReader, downlinkWriter := pipe.New()
+	defer common.Interrupt(uplinkReader)
+	defer common.Interrupt(downlinkWriter)
+	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{
+		Target: net.TCPDestination(net.DomainAddress("a.example.com"), 0),
+	})
+	common.Must(r.Portals()[0].HandleConnection(ctx, &transport.Link{Reader: downlinkReader, Writer: uplinkWriter}))
+
+	server := NewReverseServer(r)
+	response, err := server.ListBridges(context.Background(), &ListBridgesRequest{})
+	common.Must(err)
+	if len(response.Bridge) != 1 {
+		t.Fatal("expected 1 bridge, got ", len(response.Bridge))
+	}
+	if b := response.Bridge[0]; b.PortalTag != "portal-a" || b.Id != "" || b.Workers != 1 || !b.Healthy || b.ConnectedSince == 0 || b.LastSeen != 0 {
+		t.Error("unexpected bridge ", b)
+	}
+
+	response, err = server.ListBridges(context.Background(), &ListBridgesRequest{PortalTag: "portal-b"})
+	common.Must(err)
+	if len(response.Bridge) != 0 {
+		t.Error("expected no bridges of portal-b, got ", len(response.Bridge))
+	}
+
+	if _, err := server.ListBridges(context.Background(), &ListBridgesRequest{PortalTag: "portal-c"}); err == nil {
+		t.Error("expected error for unknown portal")
+	}
+}
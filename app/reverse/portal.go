@@ -2,6 +2,7 @@ package reverse
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
@@ -14,20 +15,31 @@ import (
 	"github.com/v2fly/v2ray-core/v5/common/session"
 	"github.com/v2fly/v2ray-core/v5/common/task"
 	"github.com/v2fly/v2ray-core/v5/features/outbound"
+	"github.com/v2fly/v2ray-core/v5/features/policy"
+	"github.com/v2fly/v2ray-core/v5/features/stats"
 	"github.com/v2fly/v2ray-core/v5/transport"
 	"github.com/v2fly/v2ray-core/v5/transport/pipe"
 )
 
+// bridgeReplyTimeout is the time after which an identified bridge not replying to heartbeats is unhealthy.
+const bridgeReplyTimeout = 10 * time.Second
+
 type Portal struct {
-	ctx    context.Context
-	ohm    outbound.Manager
-	tag    string
-	domain string
-	picker *StaticMuxPicker
-	client *mux.ClientManager
+	ctx             context.Context
+	ohm             outbound.Manager
+	tag             string
+	domain          string
+	control         string
+	bridgeAttribute string
+	picker          *StaticMuxPicker
+	auth            *portalAuthenticator
+
+	stats         stats.Manager
+	statsUplink   bool
+	statsDownlink bool
 }
 
-func NewPortal(ctx context.Context, config *PortalConfig, ohm outbound.Manager) (*Portal, error) {
+func NewPortal(ctx context.Context, config *PortalConfig, ohm outbound.Manager, sm stats.Manager, pm policy.Manager) (*Portal, error) {
 	if config.Tag == "" {
 		return nil, newError("portal tag is empty")
 	}
@@ -36,21 +48,42 @@ func NewPortal(ctx context.Context, config *PortalConfig, ohm outbound.Manager)
 		return nil, newError("portal domain is empty")
 	}
 
+	auth, err := newPortalAuthenticator(config.Bridge)
+	if err != nil {
+		return nil, newError("invalid bridge credentials of portal ", config.Tag).Base(err)
+	}
+
 	picker, err := NewStaticMuxPicker()
 	if err != nil {
 		return nil, err
 	}
 
-	return &Portal{
-		ctx:    ctx,
-		ohm:    ohm,
-		tag:    config.Tag,
-		domain: config.Domain,
-		picker: picker,
-		client: &mux.ClientManager{
-			Picker: picker,
-		},
-	}, nil
+	p := &Portal{
+		ctx:             ctx,
+		ohm:             ohm,
+		tag:             config.Tag,
+		domain:          config.Domain,
+		control:         controlDomain(config.ControlDomain),
+		bridgeAttribute: config.BridgeAttribute,
+		picker:          picker,
+		auth:            auth,
+		stats:           sm,
+	}
+	if pm != nil {
+		p.statsUplink = pm.ForSystem().Stats.OutboundUplink
+		p.statsDownlink = pm.ForSystem().Stats.OutboundDownlink
+	}
+	return p, nil
+}
+
+// Tag returns the tag of the portal.
+func (p *Portal) Tag() string {
+	return p.tag
+}
+
+// Bridges returns the bridges connected to the portal.
+func (p *Portal) Bridges() []*BridgeInfo {
+	return p.picker.Bridges()
 }
 
 func (p *Portal) Start() error {
@@ -76,7 +109,7 @@ func (p *Portal) HandleConnection(ctx context.Context, link *transport.Link) err
 			return newError("failed to create mux client worker").Base(err).AtWarning()
 		}
 
-		worker, err := NewPortalWorker(ctx, muxClient)
+		worker, err := NewPortalWorker(ctx, muxClient, *link, p.control, p.auth)
 		if err != nil {
 			return newError("failed to create portal worker").Base(err)
 		}
@@ -85,7 +118,46 @@ func (p *Portal) HandleConnection(ctx context.Context, link *transport.Link) err
 		return nil
 	}
 
-	return p.client.Dispatch(ctx, link)
+	var bridgeID string
+	if p.bridgeAttribute != "" {
+		if content := session.ContentFromContext(ctx); content != nil {
+			bridgeID = content.Attribute(p.bridgeAttribute)
+		}
+	}
+	for i := 0; i < 16; i++ {
+		worker, err := p.picker.PickWorker(bridgeID)
+		if err != nil {
+			if bridgeID != "" {
+				return newError("no connection available for bridge ", bridgeID).Base(err)
+			}
+			return err
+		}
+		if worker.client.Dispatch(ctx, p.countTraffic(worker.BridgeID(), link)) {
+			return nil
+		}
+	}
+
+	return newError("unable to find an available mux client").AtWarning()
+}
+
+// countTraffic counts the traffic of the bridge in the stats manager.
+func (p *Portal) countTraffic(bridgeID string, link *transport.Link) *transport.Link {
+	if p.stats == nil || bridgeID == "" {
+		return link
+	}
+	prefix := "portal>>>" + p.tag + ">>>bridge>>>" + bridgeID + ">>>traffic>>>"
+	counted := &transport.Link{Reader: link.Reader, Writer: link.Writer}
+	if p.statsUplink {
+		if c, _ := stats.GetOrRegisterCounter(p.stats, prefix+"uplink"); c != nil {
+			counted.Reader = &sizeStatReader{counter: c, reader: link.Reader}
+		}
+	}
+	if p.statsDownlink {
+		if c, _ := stats.GetOrRegisterCounter(p.stats, prefix+"downlink"); c != nil {
+			counted.Writer = &sizeStatWriter{counter: c, writer: link.Writer}
+		}
+	}
+	return counted
 }
 
 type Outbound struct {
@@ -147,6 +219,16 @@ func (p *StaticMuxPicker) cleanup() error {
 }
 
 func (p *StaticMuxPicker) PickAvailable() (*mux.ClientWorker, error) {
+	worker, err := p.PickWorker("")
+	if err != nil {
+		return nil, err
+	}
+	return worker.client, nil
+}
+
+// PickWorker picks a worker of the bridge with the identity, or of the bridge with least
+// connections if the identity is empty. Workers not identified yet belong to an anonymous bridge.
+func (p *StaticMuxPicker) PickWorker(bridgeID string) (*PortalWorker, error) {
 	p.access.Lock()
 	defer p.access.Unlock()
 
@@ -154,38 +236,85 @@ func (p *StaticMuxPicker) PickAvailable() (*mux.ClientWorker, error) {
 		return nil, newError("empty worker list")
 	}
 
-	minIdx := -1
-	var minConn uint32 = 9999
-	for i, w := range p.workers {
-		if w.draining {
-			continue
-		}
-		if w.client.Closed() {
-			continue
-		}
-		if w.client.ActiveConnections() < minConn {
-			minConn = w.client.ActiveConnections()
-			minIdx = i
+	load := make(map[string]uint32)
+	for _, w := range p.workers {
+		if !w.Closed() && w.Authorized() {
+			load[w.BridgeID()] += w.client.ActiveConnections()
 		}
 	}
 
-	if minIdx == -1 {
-		for i, w := range p.workers {
-			if w.IsFull() {
+	filters := []func(w *PortalWorker) bool{
+		func(w *PortalWorker) bool {
+			return !w.draining && !w.client.Closed()
+		},
+		func(w *PortalWorker) bool {
+			return !w.IsFull()
+		},
+	}
+	for _, usable := range filters {
+		var picked *PortalWorker
+		for _, w := range p.workers {
+			if !w.Authorized() || (bridgeID != "" && w.BridgeID() != bridgeID) {
+				continue
+			}
+			if !usable(w) {
+				continue
+			}
+			if picked == nil {
+				picked = w
 				continue
 			}
-			if w.client.ActiveConnections() < minConn {
-				minConn = w.client.ActiveConnections()
-				minIdx = i
+			wLoad, pickedLoad := load[w.BridgeID()], load[picked.BridgeID()]
+			if wLoad < pickedLoad || (wLoad == pickedLoad && w.client.ActiveConnections() < picked.client.ActiveConnections()) {
+				picked = w
 			}
 		}
+		if picked != nil {
+			return picked, nil
+		}
 	}
 
-	if minIdx != -1 {
-		return p.workers[minIdx].client, nil
+	return nil, newError("no mux client worker available")
+}
+
+// Bridges returns the bridges of the workers, sorted by identity.
+func (p *StaticMuxPicker) Bridges() []*BridgeInfo {
+	p.access.Lock()
+	defer p.access.Unlock()
+
+	bridges := make(map[string]*BridgeInfo)
+	for _, w := range p.workers {
+		if w.Closed() || !w.Authorized() {
+			continue
+		}
+		id := w.BridgeID()
+		info, found := bridges[id]
+		if !found {
+			info = &BridgeInfo{ID: id}
+			bridges[id] = info
+		}
+		info.Workers++
+		info.ActiveConnections += w.client.ActiveConnections()
+		if info.ConnectedSince.IsZero() || w.since.Before(info.ConnectedSince) {
+			info.ConnectedSince = w.since
+		}
+		lastSeen := w.LastSeen()
+		if lastSeen.After(info.LastSeen) {
+			info.LastSeen = lastSeen
+		}
+		if !w.draining && (lastSeen.IsZero() || time.Since(lastSeen) < bridgeReplyTimeout) {
+			info.Healthy = true
+		}
 	}
 
-	return nil, newError("no mux client worker available")
+	result := make([]*BridgeInfo, 0, len(bridges))
+	for _, info := range bridges {
+		result = append(result, info)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result
 }
 
 func (p *StaticMuxPicker) AddWorker(worker *PortalWorker) {
@@ -195,21 +324,49 @@ func (p *StaticMuxPicker) AddWorker(worker *PortalWorker) {
 	p.workers = append(p.workers, worker)
 }
 
+// BridgeInfo is the state of a bridge connected to a portal.
+type BridgeInfo struct {
+	// ID is the identity of the bridge, empty for bridges not identified.
+	ID string
+	// Workers is the number of connections from the bridge.
+	Workers           int
+	ActiveConnections uint32
+	ConnectedSince    time.Time
+	// LastSeen is the last time the bridge replied to the portal, zero for bridges not identified.
+	LastSeen time.Time
+	// Healthy is set if any connection of the bridge accepts new sessions.
+	Healthy bool
+}
+
 type PortalWorker struct {
 	client   *mux.ClientWorker
 	control  *task.Periodic
 	writer   buf.Writer
 	reader   buf.Reader
 	draining bool
+	since    time.Time
+
+	// link is the connection from the bridge.
+	link      transport.Link
+	auth      *portalAuthenticator
+	challenge []byte
+
+	access        sync.Mutex
+	bridgeID      string
+	lastSeen      time.Time
+	authenticated bool
 }
 
-func NewPortalWorker(ctx context.Context, client *mux.ClientWorker) (*PortalWorker, error) {
+// NewPortalWorker creates a PortalWorker for the connection from a bridge, with the control connection
+// to the domain. If auth is not nil, the bridge is not used until it is authenticated, and is rejected
+// if it fails to authenticate.
+func NewPortalWorker(ctx context.Context, client *mux.ClientWorker, link transport.Link, control string, auth *portalAuthenticator) (*PortalWorker, error) {
 	opt := []pipe.Option{pipe.WithSizeLimit(16 * 1024)}
 	uplinkReader, uplinkWriter := pipe.New(opt...)
 	downlinkReader, downlinkWriter := pipe.New(opt...)
 
 	ctx = session.ContextWithOutbound(ctx, &session.Outbound{
-		Target: net.UDPDestination(net.DomainAddress(internalDomain), 0),
+		Target: net.UDPDestination(net.DomainAddress(control), 0),
 	})
 	f := client.Dispatch(ctx, &transport.Link{
 		Reader: uplinkReader,
@@ -222,7 +379,14 @@ func NewPortalWorker(ctx context.Context, client *mux.ClientWorker) (*PortalWork
 		client: client,
 		reader: downlinkReader,
 		writer: uplinkWriter,
+		since:  time.Now(),
+		link:   link,
+		auth:   auth,
 	}
+	if auth != nil {
+		w.challenge = newChallenge()
+	}
+	go w.handleReply(downlinkReader)
 	w.control = &task.Periodic{
 		Execute:  w.heartbeat,
 		Interval: time.Second * 2,
@@ -240,7 +404,12 @@ func (w *PortalWorker) heartbeat() error {
 		return newError("already disposed")
 	}
 
-	msg := &Control{}
+	if !w.Authorized() && time.Since(w.since) > bridgeAuthTimeout {
+		w.reject()
+		return newError("bridge failed to authenticate in time").AtWarning()
+	}
+
+	msg := &Control{Identify: true, Challenge: w.challenge}
 	msg.FillInRandom()
 
 	if w.client.TotalConnections() > 256 {
@@ -260,6 +429,68 @@ func (w *PortalWorker) heartbeat() error {
 	return w.writer.WriteMultiBuffer(mb)
 }
 
+// handleReply reads the replies of the bridge on the control connection.
+func (w *PortalWorker) handleReply(reader buf.Reader) {
+	for {
+		mb, err := reader.ReadMultiBuffer()
+		if err != nil {
+			return
+		}
+		for _, b := range mb {
+			var ctl Control
+			if err := proto.Unmarshal(b.Bytes(), &ctl); err != nil {
+				newError("failed to parse proto message").Base(err).WriteToLog()
+				continue
+			}
+			if w.auth != nil && !w.auth.verify(w.challenge, ctl.BridgeId, ctl.Response) {
+				newError("rejected bridge ", ctl.BridgeId, ": failed to authenticate").AtWarning().WriteToLog()
+				buf.ReleaseMulti(mb)
+				w.reject()
+				return
+			}
+			w.access.Lock()
+			if w.bridgeID != ctl.BridgeId || (w.auth != nil && !w.authenticated) {
+				newError("bridge ", ctl.BridgeId, " connected").AtInfo().WriteToLog()
+				w.bridgeID = ctl.BridgeId
+			}
+			w.authenticated = true
+			w.lastSeen = time.Now()
+			w.access.Unlock()
+		}
+		buf.ReleaseMulti(mb)
+	}
+}
+
+// reject closes the connection from the bridge.
+func (w *PortalWorker) reject() {
+	common.Interrupt(w.link.Reader)
+	common.Interrupt(w.link.Writer)
+}
+
+// Authorized returns whether the bridge is allowed to relay sessions.
+func (w *PortalWorker) Authorized() bool {
+	if w.auth == nil {
+		return true
+	}
+	w.access.Lock()
+	defer w.access.Unlock()
+	return w.authenticated
+}
+
+// BridgeID returns the identity of the bridge, or an empty string if the bridge is not identified yet.
+func (w *PortalWorker) BridgeID() string {
+	w.access.Lock()
+	defer w.access.Unlock()
+	return w.bridgeID
+}
+
+// LastSeen returns the last time the bridge replied.
+func (w *PortalWorker) LastSeen() time.Time {
+	w.access.Lock()
+	defer w.access.Unlock()
+	return w.lastSeen
+}
+
 func (w *PortalWorker) IsFull() bool {
 	return w.client.IsFull()
 }
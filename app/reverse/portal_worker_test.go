@@ -0,0 +1,176 @@
+package reverse
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/buf"
+	"github.com/v2fly/v2ray-core/v5/common/mux"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/session"
+	"github.com/v2fly/v2ray-core/v5/transport"
+	"github.com/v2fly/v2ray-core/v5/transport/pipe"
+)
+
+// newTestWorker creates a worker of the bridge, whose mux connection goes nowhere.
+func newTestWorker(t *testing.T, bridgeID string, sessions int) *PortalWorker {
+	uplinkReader, uplinkWriter := pipe.New()
+	downlinkReader, downlinkWriter := pipe.New()
+	t.Cleanup(func() {
+		common.Interrupt(uplinkReader)
+		common.Interrupt(downlinkWriter)
+	})
+	client, err := mux.NewClientWorker(transport.Link{Reader: downlinkReader, Writer: uplinkWriter}, mux.ClientStrategy{})
+	common.Must(err)
+
+	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{
+		Target: net.TCPDestination(net.DomainAddress("example.com"), 80),
+	})
+	for i := 0; i < sessions; i++ {
+		reader, writer := pipe.New()
+		t.Cleanup(func() { common.Interrupt(writer) })
+		if !client.Dispatch(ctx, &transport.Link{Reader: reader, Writer: writer}) {
+			t.Fatal("failed to dispatch")
+		}
+	}
+	return &PortalWorker{
+		client:   client,
+		since:    time.Now(),
+		bridgeID: bridgeID,
+		lastSeen: time.Now(),
+	}
+}
+
+func newTestPicker(workers ...*PortalWorker) *StaticMuxPicker {
+	picker, err := NewStaticMuxPicker()
+	common.Must(err)
+	for _, w := range workers {
+		picker.AddWorker(w)
+	}
+	return picker
+}
+
+func TestStaticPickerPickWorker(t *testing.T) {
+	a1 := newTestWorker(t, "site-a", 1)
+	a2 := newTestWorker(t, "site-a", 2)
+	b := newTestWorker(t, "site-b", 2)
+	picker := newTestPicker(a1, a2, b)
+
+	// Bridge b has less connections in total than bridge a.
+	if w, err := picker.PickWorker(""); err != nil || w != b {
+		t.Error("expected the worker of the least loaded bridge, got ", w, err)
+	}
+	// The worker with least connections of the bridge is picked.
+	if w, err := picker.PickWorker("site-a"); err != nil || w != a1 {
+		t.Error("expected the least loaded worker of site-a, got ", w, err)
+	}
+	if _, err := picker.PickWorker("site-c"); err == nil {
+		t.Error("expected failure for unknown bridge")
+	}
+
+	// Draining workers are used only if no other worker is available.
+	a1.draining = true
+	if w, err := picker.PickWorker("site-a"); err != nil || w != a2 {
+		t.Error("expected the worker not draining, got ", w, err)
+	}
+	a2.draining = true
+	if w, err := picker.PickWorker("site-a"); err != nil || w.BridgeID() != "site-a" {
+		t.Error("expected a draining worker of site-a, got ", w, err)
+	}
+}
+
+func TestStaticPickerSkipsUnauthorized(t *testing.T) {
+	auth, err := newPortalAuthenticator([]*BridgeCredential{{Id: "site-a", Psk: "secret"}})
+	common.Must(err)
+	w := newTestWorker(t, "", 0)
+	w.auth = auth
+	picker := newTestPicker(w)
+
+	if _, err := picker.PickWorker(""); err == nil {
+		t.Error("expected unauthenticated worker not picked")
+	}
+	if bridges := picker.Bridges(); len(bridges) != 0 {
+		t.Error("expected no bridges, got ", len(bridges))
+	}
+}
+
+func TestStaticPickerBridges(t *testing.T) {
+	a1 := newTestWorker(t, "site-a", 1)
+	a2 := newTestWorker(t, "site-a", 2)
+	b := newTestWorker(t, "site-b", 0)
+	b.draining = true
+	anonymous := newTestWorker(t, "", 0)
+	anonymous.lastSeen = time.Time{}
+	picker := newTestPicker(b, a1, anonymous, a2)
+
+	bridges := picker.Bridges()
+	if len(bridges) != 3 {
+		t.Fatal("expected 3 bridges, got ", len(bridges))
+	}
+	if bridges[0].ID != "" || !bridges[0].Healthy || !bridges[0].LastSeen.IsZero() {
+		t.Error("unexpected anonymous bridge ", bridges[0])
+	}
+	if a := bridges[1]; a.ID != "site-a" || a.Workers != 2 || a.ActiveConnections != 3 || !a.Healthy || !a.ConnectedSince.Equal(a1.since) {
+		t.Error("unexpected bridge ", a)
+	}
+	if bridges[2].ID != "site-b" || bridges[2].Healthy {
+		t.Error("expected draining bridge unhealthy, got ", bridges[2])
+	}
+
+	a1.lastSeen = time.Now().Add(-2 * bridgeReplyTimeout)
+	a2.lastSeen = a1.lastSeen
+	if bridges := picker.Bridges(); bridges[1].Healthy {
+		t.Error("expected bridge not replying unhealthy")
+	}
+}
+
+func writeControl(t *testing.T, writer buf.Writer, ctl *Control) {
+	b, err := proto.Marshal(ctl)
+	common.Must(err)
+	common.Must(writer.WriteMultiBuffer(buf.MergeBytes(nil, b)))
+}
+
+func TestPortalWorkerHandleReply(t *testing.T) {
+	auth, err := newPortalAuthenticator([]*BridgeCredential{{Id: "site-a", Psk: "secret"}})
+	common.Must(err)
+	bridge, err := newBridgeAuthenticator(&BridgeConfig{Id: "site-a", Psk: "secret"})
+	common.Must(err)
+
+	linkReader, _ := pipe.New()
+	w := newTestWorker(t, "", 0)
+	w.lastSeen = time.Time{}
+	w.auth = auth
+	w.challenge = newChallenge()
+	w.link = transport.Link{Reader: linkReader}
+
+	reader, writer := pipe.New()
+	done := make(chan struct{})
+	go func() {
+		w.handleReply(reader)
+		close(done)
+	}()
+
+	writeControl(t, writer, &Control{BridgeId: "site-a", Response: bridge.respond(w.challenge, "site-a")})
+	deadline := time.Now().Add(5 * time.Second)
+	for !w.Authorized() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !w.Authorized() || w.BridgeID() != "site-a" || w.LastSeen().IsZero() {
+		t.Fatal("expected bridge authenticated")
+	}
+
+	// A reply failing to authenticate rejects the bridge.
+	writeControl(t, writer, &Control{BridgeId: "site-a", Response: bridge.respond(newChallenge(), "site-a")})
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected bridge rejected")
+	}
+	if _, err := linkReader.ReadMultiBuffer(); err == nil {
+		t.Error("expected connection from bridge closed")
+	}
+}
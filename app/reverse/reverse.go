@@ -10,26 +10,32 @@ import (
 	"github.com/v2fly/v2ray-core/v5/common/errors"
 	"github.com/v2fly/v2ray-core/v5/common/net"
 	"github.com/v2fly/v2ray-core/v5/features/outbound"
+	"github.com/v2fly/v2ray-core/v5/features/policy"
 	"github.com/v2fly/v2ray-core/v5/features/routing"
+	"github.com/v2fly/v2ray-core/v5/features/stats"
 )
 
 const (
-	internalDomain = "reverse.internal.v2fly.org"
+	defaultControlDomain = "reverse.internal.v2fly.org"
 )
 
 func isDomain(dest net.Destination, domain string) bool {
 	return dest.Address.Family().IsDomain() && dest.Address.Domain() == domain
 }
 
-func isInternalDomain(dest net.Destination) bool {
-	return isDomain(dest, internalDomain)
+// controlDomain returns the domain of control connections, or the default one if it is not configured.
+func controlDomain(domain string) string {
+	if domain == "" {
+		return defaultControlDomain
+	}
+	return domain
 }
 
 func init() {
 	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
 		r := new(Reverse)
-		if err := core.RequireFeatures(ctx, func(d routing.Dispatcher, om outbound.Manager) error {
-			return r.Init(ctx, config.(*Config), d, om)
+		if err := core.RequireFeatures(ctx, func(d routing.Dispatcher, om outbound.Manager, sm stats.Manager, pm policy.Manager) error {
+			return r.Init(ctx, config.(*Config), d, om, sm, pm)
 		}); err != nil {
 			return nil, err
 		}
@@ -42,7 +48,7 @@ type Reverse struct {
 	portals []*Portal
 }
 
-func (r *Reverse) Init(ctx context.Context, config *Config, d routing.Dispatcher, ohm outbound.Manager) error {
+func (r *Reverse) Init(ctx context.Context, config *Config, d routing.Dispatcher, ohm outbound.Manager, sm stats.Manager, pm policy.Manager) error {
 	for _, bConfig := range config.BridgeConfig {
 		b, err := NewBridge(ctx, bConfig, d)
 		if err != nil {
@@ -52,7 +58,7 @@ func (r *Reverse) Init(ctx context.Context, config *Config, d routing.Dispatcher
 	}
 
 	for _, pConfig := range config.PortalConfig {
-		p, err := NewPortal(ctx, pConfig, ohm)
+		p, err := NewPortal(ctx, pConfig, ohm, sm, pm)
 		if err != nil {
 			return err
 		}
@@ -62,6 +68,11 @@ func (r *Reverse) Init(ctx context.Context, config *Config, d routing.Dispatcher
 	return nil
 }
 
+// Portals returns the portals of the reverse proxy.
+func (r *Reverse) Portals() []*Portal {
+	return r.portals
+}
+
 func (r *Reverse) Type() interface{} {
 	return (*Reverse)(nil)
 }
@@ -0,0 +1,52 @@
+package reverse
+
+import (
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/buf"
+	"github.com/v2fly/v2ray-core/v5/features/stats"
+)
+
+type sizeStatReader struct {
+	counter stats.Counter
+	reader  buf.Reader
+}
+
+func (r *sizeStatReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	mb, err := r.reader.ReadMultiBuffer()
+	r.counter.Add(int64(mb.Len()))
+	return mb, err
+}
+
+func (r *sizeStatReader) ReadMultiBufferTimeout(timeout time.Duration) (buf.MultiBuffer, error) {
+	timeoutReader, ok := r.reader.(buf.TimeoutReader)
+	if !ok {
+		return nil, buf.ErrNotTimeoutReader
+	}
+	mb, err := timeoutReader.ReadMultiBufferTimeout(timeout)
+	r.counter.Add(int64(mb.Len()))
+	return mb, err
+}
+
+func (r *sizeStatReader) Interrupt() {
+	common.Interrupt(r.reader)
+}
+
+type sizeStatWriter struct {
+	counter stats.Counter
+	writer  buf.Writer
+}
+
+func (w *sizeStatWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	w.counter.Add(int64(mb.Len()))
+	return w.writer.WriteMultiBuffer(mb)
+}
+
+func (w *sizeStatWriter) Close() error {
+	return common.Close(w.writer)
+}
+
+func (w *sizeStatWriter) Interrupt() {
+	common.Interrupt(w.writer)
+}
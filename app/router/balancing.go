@@ -8,10 +8,13 @@ import (
 
 	"github.com/v2fly/v2ray-core/v5/features/extension"
 	"github.com/v2fly/v2ray-core/v5/features/outbound"
+	"github.com/v2fly/v2ray-core/v5/features/routing"
 )
 
 type BalancingStrategy interface {
-	PickOutbound([]string) string
+	// PickOutbound picks one of the candidates for a connection described by the routing context.
+	// The context may be nil if the pick is not made for a connection.
+	PickOutbound(routing.Context, []string) string
 }
 
 type BalancingPrincipleTarget interface {
@@ -27,8 +30,8 @@ type Balancer struct {
 	override override
 }
 
-// PickOutbound picks the tag of an outbound
-func (b *Balancer) PickOutbound() (string, error) {
+// PickOutbound picks the tag of an outbound for the routing context
+func (b *Balancer) PickOutbound(ctx routing.Context) (string, error) {
 	candidates, err := b.SelectOutbounds()
 	if err != nil {
 		if b.fallbackTag != "" {
@@ -41,7 +44,7 @@ func (b *Balancer) PickOutbound() (string, error) {
 	if o := b.override.Get(); o != "" {
 		tag = o
 	} else {
-		tag = b.strategy.PickOutbound(candidates)
+		tag = b.strategy.PickOutbound(ctx, candidates)
 	}
 	if tag == "" {
 		if b.fallbackTag != "" {
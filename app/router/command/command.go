@@ -9,6 +9,7 @@ import (
 	"google.golang.org/grpc"
 
 	core "github.com/v2fly/v2ray-core/v5"
+	"github.com/v2fly/v2ray-core/v5/app/router"
 	"github.com/v2fly/v2ray-core/v5/common"
 	"github.com/v2fly/v2ray-core/v5/features/routing"
 	"github.com/v2fly/v2ray-core/v5/features/stats"
@@ -67,7 +68,11 @@ func (s *routingServer) TestRoute(ctx context.Context, request *TestRouteRequest
 	if request.RoutingContext == nil {
 		return nil, newError("Invalid routing request.")
 	}
-	route, err := s.router.PickRoute(AsRoutingContext(request.RoutingContext))
+	routingCtx := AsRoutingContext(request.RoutingContext)
+	if request.Timestamp != 0 {
+		routingCtx = router.ContextWithTime(routingCtx, time.Unix(request.Timestamp, 0))
+	}
+	route, err := s.router.PickRoute(routingCtx)
 	if err != nil {
 		return nil, err
 	}
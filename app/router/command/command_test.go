@@ -224,6 +224,14 @@ func TestSerivceTestRoute(t *testing.T) {
 				Protocol:  []string{"bittorrent"},
 				TargetTag: &router.RoutingRule_Tag{Tag: "blocked"},
 			},
+			{
+				Asn:       []*router.ASN{{Number: 13335, Cidr: []*routercommon.CIDR{{Ip: []byte{1, 1, 1, 0}, Prefix: 24}}}},
+				TargetTag: &router.RoutingRule_Tag{Tag: "out"},
+			},
+			{
+				SourceAsn: []*router.ASN{{Number: 64512, Cidr: []*routercommon.CIDR{{Ip: []byte{10, 0, 0, 0}, Prefix: 8}}}},
+				TargetTag: &router.RoutingRule_Tag{Tag: "out"},
+			},
 			{
 				PortList:  &net.PortList{Range: []*net.PortRange{{From: 8080, To: 8080}}},
 				TargetTag: &router.RoutingRule_Tag{Tag: "out"},
@@ -233,6 +241,13 @@ func TestSerivceTestRoute(t *testing.T) {
 				TargetTag:      &router.RoutingRule_Tag{Tag: "out"},
 			},
 			{
+				Domain: []*routercommon.Domain{{Type: routercommon.Domain_Full, Value: "video.example.com"}},
+				Schedule: &router.Schedule{
+					TimeRange: []*router.Schedule_TimeRange{{From: 9 * 3600, To: 18 * 3600}},
+					Timezone:  "UTC",
+				},
+				TargetTag: &router.RoutingRule_Tag{Tag: "blocked"},
+			}, {
 				Domain:    []*routercommon.Domain{{Type: routercommon.Domain_RootDomain, Value: "com"}},
 				TargetTag: &router.RoutingRule_Tag{Tag: "out"},
 			},
@@ -278,6 +293,8 @@ func TestSerivceTestRoute(t *testing.T) {
 		testCases := []*RoutingContext{
 			{InboundTag: "in", OutboundTag: "out"},
 			{TargetIPs: [][]byte{{1, 2, 3, 4}}, TargetPort: 8080, OutboundTag: "out"},
+			{TargetIPs: [][]byte{{1, 1, 1, 1}}, TargetASN: 13335, OutboundTag: "out"},
+			{SourceIPs: [][]byte{{10, 0, 0, 1}}, SourceASN: 64512, OutboundTag: "out"},
 			{TargetDomain: "example.com", TargetPort: 443, OutboundTag: "out"},
 			{SourcePort: 9999, TargetPort: 9999, OutboundTag: "out"},
 			{Network: net.Network_UDP, Protocol: "bittorrent", OutboundTag: "blocked"},
@@ -299,6 +316,30 @@ func TestSerivceTestRoute(t *testing.T) {
 			return nil
 		}
 
+		// Test TestRoute with simulated timestamps
+		testSchedule := func() error {
+			for _, tc := range []struct {
+				timestamp   int64
+				outboundTag string
+			}{
+				{time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC).Unix(), "blocked"},
+				{time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC).Unix(), "out"},
+			} {
+				route, err := client.TestRoute(context.Background(), &TestRouteRequest{
+					RoutingContext: &RoutingContext{TargetDomain: "video.example.com"},
+					FieldSelectors: []string{"outbound"},
+					Timestamp:      tc.timestamp,
+				})
+				if err != nil {
+					return err
+				}
+				if route.OutboundTag != tc.outboundTag {
+					t.Error("expected outbound ", tc.outboundTag, " at ", tc.timestamp, ", but got ", route.OutboundTag)
+				}
+			}
+			return nil
+		}
+
 		// Test TestRoute with special options
 		testOptions := func() error {
 			sub, err := c.Subscribe()
@@ -345,6 +386,9 @@ func TestSerivceTestRoute(t *testing.T) {
 		if err := testSimple(); err != nil {
 			errCh <- err
 		}
+		if err := testSchedule(); err != nil {
+			errCh <- err
+		}
 		if err := testOptions(); err != nil {
 			errCh <- err
 		}
@@ -45,6 +45,12 @@ func AsRoutingRoute(r *RoutingContext) routing.Route {
 	return routingContext{r}
 }
 
+// asnRoute is implemented by routes with the autonomous systems matched by the routing rule.
+type asnRoute interface {
+	GetSourceASN() uint32
+	GetTargetASN() uint32
+}
+
 var fieldMap = map[string]func(*RoutingContext, routing.Route){
 	"inbound":        func(s *RoutingContext, r routing.Route) { s.InboundTag = r.GetInboundTag() },
 	"network":        func(s *RoutingContext, r routing.Route) { s.Network = r.GetNetwork() },
@@ -58,6 +64,16 @@ var fieldMap = map[string]func(*RoutingContext, routing.Route){
 	"attributes":     func(s *RoutingContext, r routing.Route) { s.Attributes = r.GetAttributes() },
 	"outbound_group": func(s *RoutingContext, r routing.Route) { s.OutboundGroupTags = r.GetOutboundGroupTags() },
 	"outbound":       func(s *RoutingContext, r routing.Route) { s.OutboundTag = r.GetOutboundTag() },
+	"asn_source": func(s *RoutingContext, r routing.Route) {
+		if r, ok := r.(asnRoute); ok {
+			s.SourceASN = r.GetSourceASN()
+		}
+	},
+	"asn_target": func(s *RoutingContext, r routing.Route) {
+		if r, ok := r.(asnRoute); ok {
+			s.TargetASN = r.GetTargetASN()
+		}
+	},
 }
 
 // AsProtobufMessage takes selectors of fields and returns a function to convert routing.Route to protobuf RoutingContext.
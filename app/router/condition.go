@@ -42,6 +42,30 @@ func (v *ConditionChan) Len() int {
 	return len(*v)
 }
 
+// AnyCondition matches if any of its conditions matches.
+type AnyCondition []Condition
+
+// Apply implements Condition.
+func (v AnyCondition) Apply(ctx routing.Context) bool {
+	for _, cond := range v {
+		if cond.Apply(ctx) {
+			return true
+		}
+	}
+	return false
+}
+
+// addAny adds the conditions to the chan, which matches if any of the conditions matches.
+func (v *ConditionChan) addAny(conds AnyCondition) {
+	switch len(conds) {
+	case 0:
+	case 1:
+		v.Add(conds[0])
+	default:
+		v.Add(conds)
+	}
+}
+
 var matcherTypeMap = map[routercommon.Domain_Type]strmatcher.Type{
 	routercommon.Domain_Plain:      strmatcher.Substr,
 	routercommon.Domain_Regex:      strmatcher.Regex,
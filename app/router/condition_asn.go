@@ -0,0 +1,94 @@
+package router
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/v2fly/v2ray-core/v5/app/router/routercommon"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/features/routing"
+)
+
+// ASNMatcher matches the target IP addresses, or the source IP addresses if onSource is set, against autonomous systems.
+type ASNMatcher struct {
+	numbers  []uint32
+	matchers []*GeoIPMatcher
+	onSource bool
+}
+
+func NewASNMatcher(asns []*ASN, onSource bool) (*ASNMatcher, error) {
+	matcher := &ASNMatcher{onSource: onSource}
+	for _, asn := range asns {
+		if asn.Number == 0 {
+			return nil, newError("autonomous system number is not specified")
+		}
+		// The IP ranges are shared with the "asn:" geoip rules of the same autonomous system.
+		code := "AS" + strconv.FormatUint(uint64(asn.Number), 10)
+		if asn.FilePath != "" {
+			code = strings.ToUpper(asn.FilePath + "_" + code)
+		}
+		m, err := globalGeoIPContainer.Add(&routercommon.GeoIP{
+			CountryCode: code,
+			Cidr:        asn.Cidr,
+		})
+		if err != nil {
+			return nil, err
+		}
+		matcher.numbers = append(matcher.numbers, asn.Number)
+		matcher.matchers = append(matcher.matchers, m)
+	}
+	return matcher, nil
+}
+
+// Match returns the number of the first autonomous system matching the IP addresses.
+func (m *ASNMatcher) Match(ctx routing.Context) (uint32, bool) {
+	var ips []net.IP
+	if m.onSource {
+		ips = ctx.GetSourceIPs()
+	} else {
+		ips = ctx.GetTargetIPs()
+	}
+	for _, ip := range ips {
+		for idx, matcher := range m.matchers {
+			if matcher.Match(ip) {
+				return m.numbers[idx], true
+			}
+		}
+	}
+	return 0, false
+}
+
+// Apply implements Condition.
+func (m *ASNMatcher) Apply(ctx routing.Context) bool {
+	_, matched := m.Match(ctx)
+	return matched
+}
+
+// matchedASNs returns the autonomous systems matched by the ASN conditions in the condition.
+func matchedASNs(cond Condition, ctx routing.Context) (source uint32, target uint32) {
+	var conds []Condition
+	switch c := cond.(type) {
+	case *ASNMatcher:
+		if asn, matched := c.Match(ctx); matched {
+			if c.onSource {
+				return asn, 0
+			}
+			return 0, asn
+		}
+		return 0, 0
+	case *ConditionChan:
+		conds = *c
+	case AnyCondition:
+		conds = c
+	}
+	for _, c := range conds {
+		s, t := matchedASNs(c, ctx)
+		if source == 0 {
+			source = s
+		}
+		if target == 0 {
+			target = t
+		}
+	}
+	return source, target
+}
@@ -0,0 +1,49 @@
+package router_test
+
+import (
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/app/router"
+	"github.com/v2fly/v2ray-core/v5/app/router/routercommon"
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/session"
+)
+
+func TestASNMatcher(t *testing.T) {
+	asns := []*router.ASN{
+		{Number: 13335, Cidr: []*routercommon.CIDR{
+			{Ip: []byte{1, 1, 1, 0}, Prefix: 24},
+			{Ip: net.ParseAddress("2606:4700::").IP(), Prefix: 32},
+		}},
+		{Number: 15169, Cidr: []*routercommon.CIDR{{Ip: []byte{8, 8, 8, 0}, Prefix: 24}}},
+	}
+	matcher, err := router.NewASNMatcher(asns, false)
+	common.Must(err)
+	sourceMatcher, err := router.NewASNMatcher(asns, true)
+	common.Must(err)
+
+	cases := []struct {
+		address string
+		asn     uint32
+	}{
+		{"1.1.1.1", 13335},
+		{"2606:4700::1111", 13335},
+		{"8.8.8.8", 15169},
+		{"9.9.9.9", 0},
+	}
+	for _, test := range cases {
+		asn, matched := matcher.Match(withOutbound(&session.Outbound{Target: net.TCPDestination(net.ParseAddress(test.address), 443)}))
+		if asn != test.asn || matched != (test.asn != 0) {
+			t.Error("test ", test.address, ": expected asn ", test.asn, " but got ", asn)
+		}
+		asn, _ = sourceMatcher.Match(withInbound(&session.Inbound{Source: net.TCPDestination(net.ParseAddress(test.address), 1234)}))
+		if asn != test.asn {
+			t.Error("test ", test.address, ": expected source asn ", test.asn, " but got ", asn)
+		}
+	}
+
+	if _, err := router.NewASNMatcher([]*router.ASN{{}}, false); err == nil {
+		t.Error("expected error for empty asn")
+	}
+}
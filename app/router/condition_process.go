@@ -0,0 +1,100 @@
+package router
+
+import (
+	"strings"
+
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/process"
+	"github.com/v2fly/v2ray-core/v5/features/routing"
+)
+
+// processContext is an implementation of routing.Context, which finds the local process of the connection once.
+type processContext struct {
+	routing.Context
+	found bool
+	info  *process.Info
+}
+
+func (ctx *processContext) getProcess() *process.Info {
+	if !ctx.found {
+		ctx.info = findProcess(ctx.Context)
+		ctx.found = true
+	}
+	return ctx.info
+}
+
+func findProcess(ctx routing.Context) *process.Info {
+	if ctx, ok := ctx.(*processContext); ok {
+		return ctx.getProcess()
+	}
+	ips := ctx.GetSourceIPs()
+	if len(ips) == 0 || ctx.GetSourcePort() == 0 {
+		return nil
+	}
+	// The source network is usually the same as the target network, which may be unknown without an outbound target.
+	networks := []net.Network{ctx.GetNetwork()}
+	if networks[0] == net.Network_Unknown {
+		networks = []net.Network{net.Network_TCP, net.Network_UDP}
+	}
+	var err error
+	for _, network := range networks {
+		var info *process.Info
+		if info, err = process.FindProcess(network, ips[0], ctx.GetSourcePort()); err == nil {
+			return info
+		}
+	}
+	newError("failed to find process of ", ips[0], ":", ctx.GetSourcePort()).Base(err).AtDebug().WriteToLog()
+	return nil
+}
+
+// ProcessMatcher matches the local process which opened the connection by its name, executable path or user ID.
+type ProcessMatcher struct {
+	names map[string]bool
+	paths []string
+	uids  map[uint32]bool
+}
+
+func NewProcessMatcher(names []string, paths []string, uids []uint32) *ProcessMatcher {
+	m := &ProcessMatcher{
+		names: make(map[string]bool, len(names)),
+		paths: paths,
+		uids:  make(map[uint32]bool, len(uids)),
+	}
+	for _, name := range names {
+		m.names[name] = true
+	}
+	for _, uid := range uids {
+		m.uids[uid] = true
+	}
+	return m
+}
+
+func (m *ProcessMatcher) matchPath(path string) bool {
+	if path == "" {
+		return false
+	}
+	for _, p := range m.paths {
+		if p == path || (strings.HasSuffix(p, "/") && strings.HasPrefix(path, p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply implements Condition.
+func (m *ProcessMatcher) Apply(ctx routing.Context) bool {
+	info := findProcess(ctx)
+	if info == nil {
+		return false
+	}
+	if len(m.names) > 0 && !m.names[info.Name] {
+		return false
+	}
+	if len(m.paths) > 0 && !m.matchPath(info.Path) {
+		return false
+	}
+	if len(m.uids) > 0 && !m.uids[info.UID] {
+		return false
+	}
+	return true
+}
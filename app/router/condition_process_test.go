@@ -0,0 +1,53 @@
+//go:build linux
+// +build linux
+
+package router_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/app/router"
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/session"
+)
+
+func TestProcessMatcher(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	common.Must(err)
+	defer listener.Close()
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	common.Must(err)
+	defer conn.Close()
+
+	ctx := withInbound(&session.Inbound{Source: net.DestinationFromAddr(conn.LocalAddr())})
+	executable, err := os.Executable()
+	common.Must(err)
+	uid := uint32(os.Getuid())
+
+	cases := []struct {
+		matcher *router.ProcessMatcher
+		output  bool
+	}{
+		{router.NewProcessMatcher([]string{filepath.Base(executable)}, nil, nil), true},
+		{router.NewProcessMatcher([]string{"curl"}, nil, nil), false},
+		{router.NewProcessMatcher(nil, []string{executable}, nil), true},
+		{router.NewProcessMatcher(nil, []string{filepath.Dir(executable) + "/"}, nil), true},
+		{router.NewProcessMatcher(nil, []string{filepath.Dir(executable)}, nil), false},
+		{router.NewProcessMatcher(nil, nil, []uint32{uid}), true},
+		{router.NewProcessMatcher(nil, nil, []uint32{uid + 1}), false},
+		{router.NewProcessMatcher([]string{filepath.Base(executable)}, nil, []uint32{uid + 1}), false},
+	}
+	for idx, test := range cases {
+		if actual := test.matcher.Apply(ctx); actual != test.output {
+			t.Error("test #", idx, ": expected ", test.output, " but got ", actual)
+		}
+	}
+
+	unknown := withInbound(&session.Inbound{Source: net.TCPDestination(net.LocalHostIP, 1)})
+	if router.NewProcessMatcher(nil, nil, []uint32{uid}).Apply(unknown) {
+		t.Error("expected no match for unknown process")
+	}
+}
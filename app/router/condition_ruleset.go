@@ -0,0 +1,111 @@
+package router
+
+import (
+	"sync/atomic"
+
+	"github.com/v2fly/v2ray-core/v5/app/ruleset"
+	"github.com/v2fly/v2ray-core/v5/features/routing"
+)
+
+type compiledRuleSet struct {
+	name    string
+	domains *DomainMatcher
+	ips     *GeoIPMatcher
+}
+
+func compileRuleSet(name string, matcherType string, onIP bool, rules *ruleset.Rules) (*compiledRuleSet, error) {
+	compiled := &compiledRuleSet{name: name}
+	switch {
+	case onIP && len(rules.CIDRs) > 0:
+		ips := new(GeoIPMatcher)
+		if err := ips.Init(rules.CIDRs); err != nil {
+			return nil, err
+		}
+		compiled.ips = ips
+	case !onIP && len(rules.Domains) > 0:
+		domains, err := NewDomainMatcher(matcherType, rules.Domains)
+		if err != nil {
+			return nil, err
+		}
+		compiled.domains = domains
+	}
+	return compiled, nil
+}
+
+// RuleSetMatcher matches the target domain, or the target IP addresses if onIP is set, against the rule sets.
+// The matchers of a rule set are compiled and swapped when the rule set is updated.
+type RuleSetMatcher struct {
+	names       []string
+	matcherType string
+	onIP        bool
+	compiled    []atomic.Value
+}
+
+func NewRuleSetMatcher(matcherType string, names []string, onIP bool) *RuleSetMatcher {
+	return &RuleSetMatcher{
+		names:       names,
+		matcherType: matcherType,
+		onIP:        onIP,
+		compiled:    make([]atomic.Value, len(names)),
+	}
+}
+
+// Bind watches the rule sets from the manager. The matcher matches nothing before it is bound.
+func (m *RuleSetMatcher) Bind(manager ruleset.Manager) error {
+	for idx, name := range m.names {
+		set, err := manager.GetRuleSet(name)
+		if err != nil {
+			return err
+		}
+		name, compiled := name, &m.compiled[idx]
+		set.Watch(func(rules *ruleset.Rules) {
+			c, err := compileRuleSet(name, m.matcherType, m.onIP, rules)
+			if err != nil {
+				newError("failed to compile rule set ", name).Base(err).AtWarning().WriteToLog()
+				return
+			}
+			compiled.Store(c)
+		})
+	}
+	return nil
+}
+
+func (m *RuleSetMatcher) load(idx int) *compiledRuleSet {
+	c, _ := m.compiled[idx].Load().(*compiledRuleSet)
+	return c
+}
+
+// MatchDomain returns the name of the first rule set matching the domain.
+func (m *RuleSetMatcher) MatchDomain(domain string) (string, bool) {
+	for idx := range m.compiled {
+		if c := m.load(idx); c != nil && c.domains != nil && c.domains.Match(domain) {
+			return c.name, true
+		}
+	}
+	return "", false
+}
+
+// Apply implements Condition.
+func (m *RuleSetMatcher) Apply(ctx routing.Context) bool {
+	if !m.onIP {
+		domain := ctx.GetTargetDomain()
+		if len(domain) == 0 {
+			return false
+		}
+		_, matched := m.MatchDomain(domain)
+		return matched
+	}
+	ips := ctx.GetTargetIPs()
+	for idx := range m.compiled {
+		c := m.load(idx)
+		if c == nil || c.ips == nil {
+			continue
+		}
+		for _, ip := range ips {
+			if c.ips.Match(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package router_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/app/router"
+	"github.com/v2fly/v2ray-core/v5/app/ruleset"
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/session"
+)
+
+func TestRuleSetMatcher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.txt")
+	common.Must(os.WriteFile(path, []byte("domain:example.com\n10.0.0.0/8\n"), 0o644))
+
+	sets, err := ruleset.New(context.Background(), &ruleset.Config{
+		Provider: []*ruleset.Provider{{Name: "test", Path: path, Interval: int64(10 * time.Millisecond)}},
+	})
+	common.Must(err)
+
+	matcher := router.NewRuleSetMatcher("", []string{"test"}, false)
+	if matcher.Apply(withOutbound(&session.Outbound{Target: net.TCPDestination(net.DomainAddress("www.example.com"), 80)})) {
+		t.Error("expected no match before the matcher is bound")
+	}
+	common.Must(matcher.Bind(sets))
+	ipMatcher := router.NewRuleSetMatcher("", []string{"test"}, true)
+	common.Must(ipMatcher.Bind(sets))
+
+	cases := []struct {
+		dest     net.Destination
+		domainOK bool
+		ipOK     bool
+	}{
+		{net.TCPDestination(net.DomainAddress("www.example.com"), 80), true, false},
+		{net.TCPDestination(net.DomainAddress("example.org"), 80), false, false},
+		{net.TCPDestination(net.ParseAddress("10.1.2.3"), 80), false, true},
+		{net.TCPDestination(net.ParseAddress("192.168.1.1"), 80), false, false},
+	}
+	for _, test := range cases {
+		ctx := withOutbound(&session.Outbound{Target: test.dest})
+		if actual := matcher.Apply(ctx); actual != test.domainOK {
+			t.Error("test ", test.dest, ": expected domain match ", test.domainOK, " but got ", actual)
+		}
+		if actual := ipMatcher.Apply(ctx); actual != test.ipOK {
+			t.Error("test ", test.dest, ": expected IP match ", test.ipOK, " but got ", actual)
+		}
+	}
+
+	// The matcher is swapped when the rule set is updated.
+	common.Must(sets.Start())
+	defer sets.Close()
+	common.Must(os.WriteFile(path, []byte("domain:example.org\n"), 0o644))
+	common.Must(os.Chtimes(path, time.Now(), time.Now().Add(time.Second)))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, matched := matcher.MatchDomain("example.org"); matched {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("rule set is not updated")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if matcher.Apply(withOutbound(&session.Outbound{Target: net.TCPDestination(net.DomainAddress("www.example.com"), 80)})) {
+		t.Error("expected no match for the removed domain")
+	}
+}
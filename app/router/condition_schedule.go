@@ -0,0 +1,110 @@
+package router
+
+import (
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/features/routing"
+)
+
+const (
+	secondsPerDay = 24 * 60 * 60
+	dateLayout    = "2006-01-02"
+)
+
+// ScheduleMatcher matches the time of the routing against a schedule.
+type ScheduleMatcher struct {
+	// weekdays is a bit mask of the days of the week, all days if 0.
+	weekdays   uint8
+	timeRanges []*Schedule_TimeRange
+	dateRanges []*Schedule_DateRange
+	location   *time.Location
+}
+
+func NewScheduleMatcher(schedule *Schedule) (*ScheduleMatcher, error) {
+	m := &ScheduleMatcher{
+		timeRanges: schedule.TimeRange,
+		dateRanges: schedule.DateRange,
+		location:   time.Local,
+	}
+	for _, weekday := range schedule.Weekday {
+		if weekday > uint32(time.Saturday) {
+			return nil, newError("invalid day of the week ", weekday)
+		}
+		m.weekdays |= 1 << weekday
+	}
+	for _, r := range schedule.TimeRange {
+		if r.From >= secondsPerDay || r.To > secondsPerDay {
+			return nil, newError("invalid time range from ", r.From, " to ", r.To)
+		}
+	}
+	for _, r := range schedule.DateRange {
+		from, err := time.Parse(dateLayout, r.From)
+		if err != nil {
+			return nil, newError("invalid date ", r.From).Base(err)
+		}
+		to, err := time.Parse(dateLayout, r.To)
+		if err != nil {
+			return nil, newError("invalid date ", r.To).Base(err)
+		}
+		if to.Before(from) {
+			return nil, newError("invalid date range from ", r.From, " to ", r.To)
+		}
+	}
+	if schedule.Timezone != "" {
+		location, err := time.LoadLocation(schedule.Timezone)
+		if err != nil {
+			return nil, newError("invalid time zone ", schedule.Timezone).Base(err)
+		}
+		m.location = location
+	}
+	return m, nil
+}
+
+func (m *ScheduleMatcher) matchWeekday(weekday time.Weekday) bool {
+	return m.weekdays == 0 || m.weekdays&(1<<weekday) != 0
+}
+
+// Match returns whether the time is in the schedule.
+func (m *ScheduleMatcher) Match(t time.Time) bool {
+	t = t.In(m.location)
+
+	if len(m.dateRanges) > 0 {
+		// Dates in the same layout are compared as strings.
+		date := t.Format(dateLayout)
+		matched := false
+		for _, r := range m.dateRanges {
+			if r.From <= date && date <= r.To {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	weekday := t.Weekday()
+	if len(m.timeRanges) == 0 {
+		return m.matchWeekday(weekday)
+	}
+	seconds := uint32(t.Hour()*3600 + t.Minute()*60 + t.Second())
+	yesterday := (weekday + 6) % 7
+	for _, r := range m.timeRanges {
+		if r.From < r.To {
+			if r.From <= seconds && seconds < r.To && m.matchWeekday(weekday) {
+				return true
+			}
+			continue
+		}
+		// The range crossing midnight belongs to the day it starts.
+		if (seconds >= r.From && m.matchWeekday(weekday)) || (seconds < r.To && m.matchWeekday(yesterday)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply implements Condition.
+func (m *ScheduleMatcher) Apply(ctx routing.Context) bool {
+	return m.Match(contextTime(ctx))
+}
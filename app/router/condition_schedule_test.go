@@ -0,0 +1,69 @@
+package router_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/app/router"
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/session"
+)
+
+func TestScheduleMatcher(t *testing.T) {
+	hour := func(h uint32) uint32 { return h * 3600 }
+	at := func(year int, month time.Month, day, hour, min int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+	}
+
+	cases := []struct {
+		schedule *router.Schedule
+		time     time.Time
+		output   bool
+	}{
+		// 2024-01-01 is a Monday.
+		{&router.Schedule{Weekday: []uint32{1, 2, 3, 4, 5}, Timezone: "UTC"}, at(2024, 1, 1, 12, 0), true},
+		{&router.Schedule{Weekday: []uint32{1, 2, 3, 4, 5}, Timezone: "UTC"}, at(2024, 1, 6, 12, 0), false},
+		{&router.Schedule{TimeRange: []*router.Schedule_TimeRange{{From: hour(9), To: hour(18)}}, Timezone: "UTC"}, at(2024, 1, 1, 9, 0), true},
+		{&router.Schedule{TimeRange: []*router.Schedule_TimeRange{{From: hour(9), To: hour(18)}}, Timezone: "UTC"}, at(2024, 1, 1, 18, 0), false},
+		// 09:00-18:00 in UTC+8 is 01:00-10:00 in UTC.
+		{&router.Schedule{TimeRange: []*router.Schedule_TimeRange{{From: hour(9), To: hour(18)}}, Timezone: "Asia/Shanghai"}, at(2024, 1, 1, 2, 0), true},
+		{&router.Schedule{TimeRange: []*router.Schedule_TimeRange{{From: hour(9), To: hour(18)}}, Timezone: "Asia/Shanghai"}, at(2024, 1, 1, 12, 0), false},
+		// Friday 22:00 to Saturday 02:00.
+		{&router.Schedule{Weekday: []uint32{5}, TimeRange: []*router.Schedule_TimeRange{{From: hour(22), To: hour(2)}}, Timezone: "UTC"}, at(2024, 1, 5, 23, 0), true},
+		{&router.Schedule{Weekday: []uint32{5}, TimeRange: []*router.Schedule_TimeRange{{From: hour(22), To: hour(2)}}, Timezone: "UTC"}, at(2024, 1, 6, 1, 0), true},
+		{&router.Schedule{Weekday: []uint32{5}, TimeRange: []*router.Schedule_TimeRange{{From: hour(22), To: hour(2)}}, Timezone: "UTC"}, at(2024, 1, 5, 1, 0), false},
+		{&router.Schedule{DateRange: []*router.Schedule_DateRange{{From: "2024-12-24", To: "2024-12-26"}}, Timezone: "UTC"}, at(2024, 12, 26, 23, 59), true},
+		{&router.Schedule{DateRange: []*router.Schedule_DateRange{{From: "2024-12-24", To: "2024-12-26"}}, Timezone: "UTC"}, at(2024, 12, 27, 0, 0), false},
+	}
+	for idx, test := range cases {
+		matcher, err := router.NewScheduleMatcher(test.schedule)
+		common.Must(err)
+		if actual := matcher.Match(test.time); actual != test.output {
+			t.Error("test #", idx, ": expected ", test.output, " but got ", actual)
+		}
+	}
+
+	for _, schedule := range []*router.Schedule{
+		{Weekday: []uint32{7}},
+		{TimeRange: []*router.Schedule_TimeRange{{From: hour(24), To: hour(1)}}},
+		{DateRange: []*router.Schedule_DateRange{{From: "2024-12-26", To: "2024-12-24"}}},
+		{DateRange: []*router.Schedule_DateRange{{From: "2024/12/24", To: "2024/12/26"}}},
+		{Timezone: "Mars/Olympus"},
+	} {
+		if _, err := router.NewScheduleMatcher(schedule); err == nil {
+			t.Error("expected error for schedule ", schedule)
+		}
+	}
+
+	// Schedules are evaluated at the time of the context.
+	matcher, err := router.NewScheduleMatcher(&router.Schedule{DateRange: []*router.Schedule_DateRange{{From: "2024-01-01", To: "2024-01-01"}}, Timezone: "UTC"})
+	common.Must(err)
+	ctx := withOutbound(&session.Outbound{Target: net.TCPDestination(net.DomainAddress("example.com"), 80)})
+	if matcher.Apply(ctx) {
+		t.Error("expected no match at the current time")
+	}
+	if !matcher.Apply(router.ContextWithTime(ctx, at(2024, 1, 1, 12, 0))) {
+		t.Error("expected match at the time of the context")
+	}
+}
@@ -23,9 +23,9 @@ type Rule struct {
 	Condition Condition
 }
 
-func (r *Rule) GetTag() (string, error) {
+func (r *Rule) GetTag(ctx routing.Context) (string, error) {
 	if r.Balancer != nil {
-		return r.Balancer.PickOutbound()
+		return r.Balancer.PickOutbound(ctx)
 	}
 	return r.Tag, nil
 }
@@ -35,16 +35,25 @@ func (r *Rule) Apply(ctx routing.Context) bool {
 	return r.Condition.Apply(ctx)
 }
 
+func (rr *RoutingRule) hasProcessCondition() bool {
+	return len(rr.ProcessName) > 0 || len(rr.ProcessPath) > 0 || len(rr.Uid) > 0
+}
+
 func (rr *RoutingRule) BuildCondition() (Condition, error) {
 	conds := NewConditionChan()
 
+	var domainConds AnyCondition
 	if len(rr.Domain) > 0 {
 		cond, err := NewDomainMatcher(rr.DomainMatcher, rr.Domain)
 		if err != nil {
 			return nil, newError("failed to build domain condition").Base(err)
 		}
-		conds.Add(cond)
+		domainConds = append(domainConds, cond)
+	}
+	if len(rr.RuleSet) > 0 {
+		domainConds = append(domainConds, NewRuleSetMatcher(rr.DomainMatcher, rr.RuleSet, false))
 	}
+	conds.addAny(domainConds)
 
 	var geoDomains []*routercommon.Domain
 	for _, geo := range rr.GeoDomain {
@@ -82,31 +91,64 @@ func (rr *RoutingRule) BuildCondition() (Condition, error) {
 		conds.Add(NewNetworkMatcher(rr.NetworkList.Network))
 	}
 
+	var ipConds AnyCondition
 	if len(rr.Geoip) > 0 {
 		cond, err := NewMultiGeoIPMatcher(rr.Geoip, false)
 		if err != nil {
 			return nil, err
 		}
-		conds.Add(cond)
+		ipConds = append(ipConds, cond)
 	} else if len(rr.Cidr) > 0 {
 		cond, err := NewMultiGeoIPMatcher([]*routercommon.GeoIP{{Cidr: rr.Cidr}}, false)
 		if err != nil {
 			return nil, err
 		}
-		conds.Add(cond)
+		ipConds = append(ipConds, cond)
 	}
+	if len(rr.IpRuleSet) > 0 {
+		ipConds = append(ipConds, NewRuleSetMatcher(rr.DomainMatcher, rr.IpRuleSet, true))
+	}
+	if len(rr.Asn) > 0 {
+		cond, err := NewASNMatcher(rr.Asn, false)
+		if err != nil {
+			return nil, newError("failed to build asn condition").Base(err)
+		}
+		ipConds = append(ipConds, cond)
+	}
+	conds.addAny(ipConds)
 
+	var sourceIPConds AnyCondition
 	if len(rr.SourceGeoip) > 0 {
 		cond, err := NewMultiGeoIPMatcher(rr.SourceGeoip, true)
 		if err != nil {
 			return nil, err
 		}
-		conds.Add(cond)
+		sourceIPConds = append(sourceIPConds, cond)
 	} else if len(rr.SourceCidr) > 0 {
 		cond, err := NewMultiGeoIPMatcher([]*routercommon.GeoIP{{Cidr: rr.SourceCidr}}, true)
 		if err != nil {
 			return nil, err
 		}
+		sourceIPConds = append(sourceIPConds, cond)
+	}
+	if len(rr.SourceAsn) > 0 {
+		cond, err := NewASNMatcher(rr.SourceAsn, true)
+		if err != nil {
+			return nil, newError("failed to build source asn condition").Base(err)
+		}
+		sourceIPConds = append(sourceIPConds, cond)
+	}
+	conds.addAny(sourceIPConds)
+
+	if rr.hasProcessCondition() {
+		conds.Add(NewProcessMatcher(rr.ProcessName, rr.ProcessPath, rr.Uid))
+	}
+
+	if rr.Schedule != nil {
+		cond, err := NewScheduleMatcher(rr.Schedule)
+		if err != nil {
+			return nil, newError("failed to build schedule condition").Base(err)
+		}
 		conds.Add(cond)
 	}
 
@@ -161,6 +203,62 @@ func (br *BalancingRule) Build(ohm outbound.Manager, dispatcher routing.Dispatch
 			ohm:       ohm, fallbackTag: br.FallbackTag,
 			strategy: leastLoadStrategy,
 		}, nil
+	case "consistenthash":
+		i, err := serial.GetInstanceOf(br.StrategySettings)
+		if err != nil {
+			return nil, err
+		}
+		s, ok := i.(*StrategyConsistentHashConfig)
+		if !ok {
+			return nil, newError("not a StrategyConsistentHashConfig").AtError()
+		}
+		return &Balancer{
+			selectors: br.OutboundSelector,
+			ohm:       ohm, fallbackTag: br.FallbackTag,
+			strategy: NewConsistentHashStrategy(s),
+		}, nil
+	case "sticky":
+		i, err := serial.GetInstanceOf(br.StrategySettings)
+		if err != nil {
+			return nil, err
+		}
+		s, ok := i.(*StrategyStickyConfig)
+		if !ok {
+			return nil, newError("not a StrategyStickyConfig").AtError()
+		}
+		return &Balancer{
+			selectors: br.OutboundSelector,
+			ohm:       ohm, fallbackTag: br.FallbackTag,
+			strategy: NewStickyStrategy(s),
+		}, nil
+	case "failover":
+		i, err := serial.GetInstanceOf(br.StrategySettings)
+		if err != nil {
+			return nil, err
+		}
+		s, ok := i.(*StrategyFailoverConfig)
+		if !ok {
+			return nil, newError("not a StrategyFailoverConfig").AtError()
+		}
+		return &Balancer{
+			selectors: br.OutboundSelector,
+			ohm:       ohm, fallbackTag: br.FallbackTag,
+			strategy: NewFailoverStrategy(s, br.OutboundSelector),
+		}, nil
+	case "roundrobin":
+		i, err := serial.GetInstanceOf(br.StrategySettings)
+		if err != nil {
+			return nil, err
+		}
+		s, ok := i.(*StrategyRoundRobinConfig)
+		if !ok {
+			return nil, newError("not a StrategyRoundRobinConfig").AtError()
+		}
+		return &Balancer{
+			selectors: br.OutboundSelector,
+			ohm:       ohm, fallbackTag: br.FallbackTag,
+			strategy: NewRoundRobinStrategy(s),
+		}, nil
 	case "random":
 		fallthrough
 	case "":
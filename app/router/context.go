@@ -0,0 +1,35 @@
+package router
+
+import (
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/features/routing"
+	routing_dns "github.com/v2fly/v2ray-core/v5/features/routing/dns"
+)
+
+// timedContext is an implementation of routing.Context, which evaluates rule schedules at the time.
+type timedContext struct {
+	routing.Context
+	time time.Time
+}
+
+// ContextWithTime creates a routing context which evaluates rule schedules at the time, instead of the current time.
+func ContextWithTime(ctx routing.Context, t time.Time) routing.Context {
+	return &timedContext{Context: ctx, time: t}
+}
+
+// contextTime returns the time of the routing, looking through the contexts wrapped by the router.
+func contextTime(ctx routing.Context) time.Time {
+	for {
+		switch c := ctx.(type) {
+		case *timedContext:
+			return c.time
+		case *processContext:
+			ctx = c.Context
+		case *routing_dns.ResolvableContext:
+			ctx = c.Context
+		default:
+			return time.Now()
+		}
+	}
+}
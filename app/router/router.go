@@ -4,8 +4,10 @@ package router
 
 import (
 	"context"
+	"time"
 
 	core "github.com/v2fly/v2ray-core/v5"
+	"github.com/v2fly/v2ray-core/v5/app/ruleset"
 	"github.com/v2fly/v2ray-core/v5/common"
 	"github.com/v2fly/v2ray-core/v5/common/platform"
 	"github.com/v2fly/v2ray-core/v5/features/dns"
@@ -22,6 +24,14 @@ type Router struct {
 	rules          []*Rule
 	balancers      map[string]*Balancer
 	dns            dns.Client
+
+	ruleSets        ruleset.Manager
+	ruleSetMatchers []*RuleSetMatcher
+
+	// findsProcess is set if any rule matches the local process of the connection.
+	findsProcess bool
+	// hasSchedule is set if any rule matches the time of the routing.
+	hasSchedule bool
 }
 
 // Route is an implementation of routing.Route.
@@ -29,6 +39,8 @@ type Route struct {
 	routing.Context
 	outboundGroupTags []string
 	outboundTag       string
+	sourceASN         uint32
+	targetASN         uint32
 }
 
 // Init initializes the Router.
@@ -52,6 +64,15 @@ func (r *Router) Init(ctx context.Context, config *Config, d dns.Client, ohm out
 		if err != nil {
 			return err
 		}
+		if err := r.addRuleSetMatchers(cond); err != nil {
+			return err
+		}
+		if rule.hasProcessCondition() {
+			r.findsProcess = true
+		}
+		if rule.Schedule != nil {
+			r.hasSchedule = true
+		}
 		rr := &Rule{
 			Condition: cond,
 			Tag:       rule.GetTag(),
@@ -70,17 +91,53 @@ func (r *Router) Init(ctx context.Context, config *Config, d dns.Client, ohm out
 	return nil
 }
 
+func (r *Router) addRuleSetMatchers(cond Condition) error {
+	var conds []Condition
+	switch c := cond.(type) {
+	case *RuleSetMatcher:
+		r.ruleSetMatchers = append(r.ruleSetMatchers, c)
+		if r.ruleSets != nil {
+			return c.Bind(r.ruleSets)
+		}
+		return nil
+	case *ConditionChan:
+		conds = *c
+	case AnyCondition:
+		conds = c
+	}
+	for _, c := range conds {
+		if err := r.addRuleSetMatchers(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bindRuleSets binds the rule set conditions to the rule sets from the manager, which may be resolved before or after
+// the router is initialized.
+func (r *Router) bindRuleSets(manager ruleset.Manager) error {
+	r.ruleSets = manager
+	for _, matcher := range r.ruleSetMatchers {
+		if err := matcher.Bind(manager); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // PickRoute implements routing.Router.
 func (r *Router) PickRoute(ctx routing.Context) (routing.Route, error) {
 	rule, ctx, err := r.pickRouteInternal(ctx)
 	if err != nil {
 		return nil, err
 	}
-	tag, err := rule.GetTag()
+	tag, err := rule.GetTag(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return &Route{Context: ctx, outboundTag: tag}, nil
+	route := &Route{Context: ctx, outboundTag: tag}
+	route.sourceASN, route.targetASN = matchedASNs(rule.Condition, ctx)
+	return route, nil
 }
 
 func (r *Router) pickRouteInternal(ctx routing.Context) (*Rule, routing.Context, error) {
@@ -89,9 +146,11 @@ func (r *Router) pickRouteInternal(ctx routing.Context) (*Rule, routing.Context,
 	// this prevents cycle resolving dead loop
 	skipDNSResolve := ctx.GetSkipDNSResolve()
 
+	ctx = r.withTime(ctx)
 	if r.domainStrategy == DomainStrategy_IpOnDemand && !skipDNSResolve {
 		ctx = routing_dns.ContextWithDNSClient(ctx, r.dns)
 	}
+	ctx = r.withProcess(ctx)
 
 	for _, rule := range r.rules {
 		if rule.Apply(ctx) {
@@ -103,7 +162,12 @@ func (r *Router) pickRouteInternal(ctx routing.Context) (*Rule, routing.Context,
 		return nil, ctx, common.ErrNoClue
 	}
 
-	ctx = routing_dns.ContextWithDNSClient(ctx, r.dns)
+	// The process found in the first round is kept.
+	if pc, ok := ctx.(*processContext); ok {
+		pc.Context = routing_dns.ContextWithDNSClient(pc.Context, r.dns)
+	} else {
+		ctx = routing_dns.ContextWithDNSClient(ctx, r.dns)
+	}
 
 	// Try applying rules again if we have IPs.
 	for _, rule := range r.rules {
@@ -115,6 +179,22 @@ func (r *Router) pickRouteInternal(ctx routing.Context) (*Rule, routing.Context,
 	return nil, ctx, common.ErrNoClue
 }
 
+// withProcess wraps the context to find the local process of the connection once for all rules.
+func (r *Router) withProcess(ctx routing.Context) routing.Context {
+	if !r.findsProcess {
+		return ctx
+	}
+	return &processContext{Context: ctx}
+}
+
+// withTime wraps the context to match all rule schedules at the same time.
+func (r *Router) withTime(ctx routing.Context) routing.Context {
+	if _, ok := ctx.(*timedContext); ok || !r.hasSchedule {
+		return ctx
+	}
+	return ContextWithTime(ctx, time.Now())
+}
+
 // Start implements common.Runnable.
 func (r *Router) Start() error {
 	return nil
@@ -140,6 +220,16 @@ func (r *Route) GetOutboundTag() string {
 	return r.outboundTag
 }
 
+// GetSourceASN returns the autonomous system of the source IP addresses matched by the rule, or 0.
+func (r *Route) GetSourceASN() uint32 {
+	return r.sourceASN
+}
+
+// GetTargetASN returns the autonomous system of the target IP addresses matched by the rule, or 0.
+func (r *Route) GetTargetASN() uint32 {
+	return r.targetASN
+}
+
 func init() {
 	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
 		r := new(Router)
@@ -148,6 +238,14 @@ func init() {
 		}); err != nil {
 			return nil, err
 		}
+		for _, rule := range config.(*Config).Rule {
+			if len(rule.RuleSet) > 0 || len(rule.IpRuleSet) > 0 {
+				if err := core.RequireFeatures(ctx, r.bindRuleSets); err != nil {
+					return nil, err
+				}
+				break
+			}
+		}
 		return r, nil
 	}))
 
@@ -208,6 +306,28 @@ func init() {
 			}
 			rule.SourceGeoip = v.SourceGeoip
 
+			for _, asns := range [][]*ASN{v.Asn, v.SourceAsn} {
+				for _, asn := range asns {
+					if len(asn.Cidr) == 0 {
+						filepath := "asn.mmdb"
+						if asn.FilePath != "" {
+							filepath = asn.FilePath
+						}
+						var err error
+						asn.Cidr, err = geoLoader.LoadASN(filepath, asn.Number)
+						if err != nil {
+							return nil, newError("unable to load asn").Base(err)
+						}
+					}
+				}
+			}
+			rule.Asn = v.Asn
+			rule.SourceAsn = v.SourceAsn
+			rule.ProcessName = v.ProcessName
+			rule.ProcessPath = v.ProcessPath
+			rule.Uid = v.Uid
+			rule.Schedule = v.Schedule
+
 			for _, geo := range v.GeoDomain {
 				if geo.Code != "" {
 					filepath := "geosite.dat"
@@ -245,6 +365,8 @@ func init() {
 			rule.UserEmail = v.UserEmail
 			rule.InboundTag = v.InboundTag
 			rule.DomainMatcher = v.DomainMatcher
+			rule.RuleSet = v.RuleSet
+			rule.IpRuleSet = v.IpRuleSet
 			switch s := v.TargetTag.(type) {
 			case *SimplifiedRoutingRule_Tag:
 				rule.TargetTag = &RoutingRule_Tag{s.Tag}
@@ -47,11 +47,16 @@ func TestSimpleRouter(t *testing.T) {
 	}, nil))
 
 	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{Target: net.TCPDestination(net.DomainAddress("v2fly.org"), 80)})
-	route, err := r.PickRoute(routing_session.AsRoutingContext(ctx))
+	routingCtx := routing_session.AsRoutingContext(ctx)
+	route, err := r.PickRoute(routingCtx)
 	common.Must(err)
 	if tag := route.GetOutboundTag(); tag != "test" {
 		t.Error("expect tag 'test', bug actually ", tag)
 	}
+	// The context is not wrapped without schedule or process rules.
+	if route.(*Route).Context != routingCtx {
+		t.Error("expect the context not wrapped")
+	}
 }
 
 func TestSimpleBalancer(t *testing.T) {
@@ -0,0 +1,247 @@
+//go:build !confonly
+// +build !confonly
+
+package router
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	core "github.com/v2fly/v2ray-core/v5"
+	"github.com/v2fly/v2ray-core/v5/app/observatory"
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/dice"
+	"github.com/v2fly/v2ray-core/v5/features"
+	"github.com/v2fly/v2ray-core/v5/features/extension"
+	"github.com/v2fly/v2ray-core/v5/features/routing"
+)
+
+const (
+	defaultStickyTTL = 10 * time.Minute
+	// stickyCleanupSize is the table size above which expired entries are purged on insertion.
+	stickyCleanupSize = 1024
+)
+
+// affinityKey returns the key a connection is kept on the same outbound by, or an empty string if unknown.
+func affinityKey(ctx routing.Context, key AffinityKey) string {
+	if ctx == nil {
+		return ""
+	}
+	switch key {
+	case AffinityKey_UserEmail:
+		if user := ctx.GetUser(); user != "" {
+			return user
+		}
+	case AffinityKey_TargetDomain:
+		if domain := ctx.GetTargetDomain(); domain != "" {
+			return domain
+		}
+		if ips := ctx.GetTargetIPs(); len(ips) > 0 {
+			return ips[0].String()
+		}
+		return ""
+	}
+	if ips := ctx.GetSourceIPs(); len(ips) > 0 {
+		return ips[0].String()
+	}
+	return ""
+}
+
+// rendezvousPick picks the candidate with the highest hash score for the key,
+// so that only keys of a removed candidate move when the candidates change.
+func rendezvousPick(key string, candidates []string) string {
+	var selected string
+	var maxScore uint64
+	for _, tag := range candidates {
+		h := fnv.New64a()
+		h.Write([]byte(key))
+		h.Write([]byte{0})
+		h.Write([]byte(tag))
+		if score := mix64(h.Sum64()); selected == "" || score > maxScore {
+			selected = tag
+			maxScore = score
+		}
+	}
+	return selected
+}
+
+// mix64 is the finalizer of splitmix64, which spreads the bits of similar FNV hashes.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// healthFilter drops candidates reported dead by the observatory.
+type healthFilter struct {
+	ctx         context.Context
+	enabled     bool
+	observerTag string
+	observer    extension.Observatory
+}
+
+func (f *healthFilter) InjectContext(ctx context.Context) {
+	f.ctx = ctx
+}
+
+// deadOutbounds returns the outbounds reported dead by the observatory.
+func (f *healthFilter) deadOutbounds() map[string]bool {
+	if f.observer == nil {
+		common.Must(core.RequireFeatures(f.ctx, func(observatory extension.Observatory) error {
+			if f.observerTag != "" {
+				f.observer = common.Must2(observatory.(features.TaggedFeatures).GetFeaturesByTag(f.observerTag)).(extension.Observatory)
+			} else {
+				f.observer = observatory
+			}
+			return nil
+		}))
+	}
+
+	observeReport, err := f.observer.GetObservation(f.ctx)
+	if err != nil {
+		newError("cannot get observe report").Base(err).WriteToLog()
+		return nil
+	}
+	result, ok := observeReport.(*observatory.ObservationResult)
+	if !ok {
+		return nil
+	}
+	dead := make(map[string]bool)
+	for _, status := range result.Status {
+		if !status.Alive {
+			dead[status.OutboundTag] = true
+		}
+	}
+	return dead
+}
+
+// filter returns the alive candidates. Candidates not yet observed are considered alive,
+// and all candidates are returned if none of them is alive.
+func (f *healthFilter) filter(candidates []string) []string {
+	if !f.enabled || len(candidates) == 0 {
+		return candidates
+	}
+	dead := f.deadOutbounds()
+	alive := make([]string, 0, len(candidates))
+	for _, tag := range candidates {
+		if !dead[tag] {
+			alive = append(alive, tag)
+		}
+	}
+	if len(alive) == 0 {
+		return candidates
+	}
+	return alive
+}
+
+// ConsistentHashStrategy keeps connections with the same affinity key on the same outbound using rendezvous hashing.
+type ConsistentHashStrategy struct {
+	healthFilter
+	key AffinityKey
+}
+
+func NewConsistentHashStrategy(config *StrategyConsistentHashConfig) *ConsistentHashStrategy {
+	return &ConsistentHashStrategy{
+		healthFilter: healthFilter{
+			enabled:     config.HealthCheck,
+			observerTag: config.ObserverTag,
+		},
+		key: config.Key,
+	}
+}
+
+func (s *ConsistentHashStrategy) GetPrincipleTarget(candidates []string) []string {
+	return s.filter(candidates)
+}
+
+func (s *ConsistentHashStrategy) PickOutbound(ctx routing.Context, candidates []string) string {
+	candidates = s.filter(candidates)
+	if len(candidates) == 0 {
+		return ""
+	}
+	key := affinityKey(ctx, s.key)
+	if key == "" {
+		return candidates[dice.Roll(len(candidates))]
+	}
+	return rendezvousPick(key, candidates)
+}
+
+type stickyEntry struct {
+	tag     string
+	expires time.Time
+}
+
+// StickyStrategy remembers the outbound picked for an affinity key, until the key is unused for the TTL.
+type StickyStrategy struct {
+	healthFilter
+	key AffinityKey
+	ttl time.Duration
+
+	access sync.Mutex
+	table  map[string]stickyEntry
+}
+
+func NewStickyStrategy(config *StrategyStickyConfig) *StickyStrategy {
+	ttl := time.Duration(config.Ttl)
+	if ttl <= 0 {
+		ttl = defaultStickyTTL
+	}
+	return &StickyStrategy{
+		healthFilter: healthFilter{
+			enabled:     config.HealthCheck,
+			observerTag: config.ObserverTag,
+		},
+		key:   config.Key,
+		ttl:   ttl,
+		table: make(map[string]stickyEntry),
+	}
+}
+
+func (s *StickyStrategy) GetPrincipleTarget(candidates []string) []string {
+	return s.filter(candidates)
+}
+
+func (s *StickyStrategy) PickOutbound(ctx routing.Context, candidates []string) string {
+	candidates = s.filter(candidates)
+	if len(candidates) == 0 {
+		return ""
+	}
+	key := affinityKey(ctx, s.key)
+	if key == "" {
+		return candidates[dice.Roll(len(candidates))]
+	}
+
+	s.access.Lock()
+	defer s.access.Unlock()
+
+	now := time.Now()
+	if entry, found := s.table[key]; found && now.Before(entry.expires) && outboundList(candidates).contains(entry.tag) {
+		entry.expires = now.Add(s.ttl)
+		s.table[key] = entry
+		return entry.tag
+	}
+
+	if len(s.table) >= stickyCleanupSize {
+		for k, entry := range s.table {
+			if now.After(entry.expires) {
+				delete(s.table, k)
+			}
+		}
+	}
+	tag := candidates[dice.Roll(len(candidates))]
+	s.table[key] = stickyEntry{
+		tag:     tag,
+		expires: now.Add(s.ttl),
+	}
+	return tag
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*StrategyConsistentHashConfig)(nil), nil))
+	common.Must(common.RegisterConfig((*StrategyStickyConfig)(nil), nil))
+}
@@ -0,0 +1,70 @@
+package router_test
+
+import (
+	"testing"
+
+	. "github.com/v2fly/v2ray-core/v5/app/router"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/protocol"
+	"github.com/v2fly/v2ray-core/v5/common/session"
+	routing_session "github.com/v2fly/v2ray-core/v5/features/routing/session"
+)
+
+func contextFromSource(ip string) *routing_session.Context {
+	return &routing_session.Context{
+		Inbound: &session.Inbound{
+			Source: net.TCPDestination(net.ParseAddress(ip), 1234),
+		},
+	}
+}
+
+func TestConsistentHashStrategy(t *testing.T) {
+	strategy := NewConsistentHashStrategy(&StrategyConsistentHashConfig{Key: AffinityKey_SourceIp})
+	candidates := []string{"a", "b", "c", "d"}
+
+	sources := []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4", "10.0.0.5", "10.0.0.6", "10.0.0.7", "10.0.0.8"}
+	picked := make(map[string]string)
+	for _, src := range sources {
+		tag := strategy.PickOutbound(contextFromSource(src), candidates)
+		if again := strategy.PickOutbound(contextFromSource(src), candidates); again != tag {
+			t.Error("source ", src, " moved from ", tag, " to ", again)
+		}
+		picked[src] = tag
+	}
+
+	// Removing an outbound only moves the keys that were on it.
+	remaining := []string{"a", "b", "d"}
+	for _, src := range sources {
+		tag := strategy.PickOutbound(contextFromSource(src), remaining)
+		if picked[src] != "c" && tag != picked[src] {
+			t.Error("source ", src, " moved from ", picked[src], " to ", tag)
+		}
+		if tag == "c" {
+			t.Error("picked removed outbound for ", src)
+		}
+	}
+}
+
+func TestStickyStrategy(t *testing.T) {
+	strategy := NewStickyStrategy(&StrategyStickyConfig{Key: AffinityKey_UserEmail})
+	candidates := []string{"a", "b", "c", "d"}
+
+	ctx := contextFromSource("10.0.0.1")
+	ctx.Inbound.User = &protocol.MemoryUser{Email: "love@v2fly.org"}
+	tag := strategy.PickOutbound(ctx, candidates)
+	for i := 0; i < 16; i++ {
+		if again := strategy.PickOutbound(ctx, candidates); again != tag {
+			t.Fatal("user moved from ", tag, " to ", again)
+		}
+	}
+
+	var remaining []string
+	for _, c := range candidates {
+		if c != tag {
+			remaining = append(remaining, c)
+		}
+	}
+	if moved := strategy.PickOutbound(ctx, remaining); moved == tag || moved == "" {
+		t.Error("unexpected pick after removing ", tag, ": ", moved)
+	}
+}
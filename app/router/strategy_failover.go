@@ -0,0 +1,160 @@
+//go:build !confonly
+// +build !confonly
+
+package router
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/features/routing"
+)
+
+// FailoverStrategy picks the first alive outbound in the order of the balancer selectors.
+// Outbounds matching the same selector are ordered by tag.
+type FailoverStrategy struct {
+	healthFilter
+	selectors []string
+	revert    bool
+	holdDown  time.Duration
+
+	access sync.Mutex
+	state  failoverState
+}
+
+type failoverState struct {
+	// current is the outbound in use.
+	current string
+	// recovering is the higher priority outbound waiting for the hold-down period to pass.
+	recovering      string
+	recoveringSince time.Time
+}
+
+func NewFailoverStrategy(config *StrategyFailoverConfig, selectors []string) *FailoverStrategy {
+	return &FailoverStrategy{
+		healthFilter: healthFilter{
+			enabled:     true,
+			observerTag: config.ObserverTag,
+		},
+		selectors: selectors,
+		revert:    config.Revert,
+		holdDown:  time.Duration(config.HoldDown),
+	}
+}
+
+func (s *FailoverStrategy) priority(tag string) int {
+	for i, selector := range s.selectors {
+		if strings.HasPrefix(tag, selector) {
+			return i
+		}
+	}
+	return len(s.selectors)
+}
+
+// sortCandidates returns the candidates in the order of priority.
+func (s *FailoverStrategy) sortCandidates(candidates []string) []string {
+	sorted := make([]string, len(candidates))
+	copy(sorted, candidates)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		pi, pj := s.priority(sorted[i]), s.priority(sorted[j])
+		if pi != pj {
+			return pi < pj
+		}
+		return sorted[i] < sorted[j]
+	})
+	return sorted
+}
+
+// GetPrincipleTarget returns the outbound that would be picked now, without changing the state of failover.
+func (s *FailoverStrategy) GetPrincipleTarget(candidates []string) []string {
+	if len(candidates) == 0 {
+		return nil
+	}
+	s.access.Lock()
+	defer s.access.Unlock()
+
+	state := s.next(s.state, s.sortCandidates(candidates), s.deadOutbounds(), time.Now())
+	if state.current == "" {
+		return nil
+	}
+	return []string{state.current}
+}
+
+func (s *FailoverStrategy) PickOutbound(ctx routing.Context, candidates []string) string {
+	if len(candidates) == 0 {
+		return ""
+	}
+	return s.pick(s.sortCandidates(candidates), s.deadOutbounds(), time.Now())
+}
+
+func (s *FailoverStrategy) pick(sorted []string, dead map[string]bool, now time.Time) string {
+	s.access.Lock()
+	defer s.access.Unlock()
+
+	state := s.next(s.state, sorted, dead, now)
+	if previous := s.state.current; state.current != previous {
+		if previous != "" && !dead[previous] {
+			newError("failover: returning from [", previous, "] to [", state.current, "]").AtInfo().WriteToLog()
+		} else {
+			newError("failover: switching from [", previous, "] to [", state.current, "]").AtInfo().WriteToLog()
+		}
+	}
+	s.state = state
+	return state.current
+}
+
+// next returns the state of failover after picking an outbound from the sorted candidates.
+func (s *FailoverStrategy) next(state failoverState, sorted []string, dead map[string]bool, now time.Time) failoverState {
+	preferred := ""
+	for _, tag := range sorted {
+		if !dead[tag] {
+			preferred = tag
+			break
+		}
+	}
+	if preferred == "" {
+		// All outbounds are dead, keep using the primary one.
+		preferred = sorted[0]
+	}
+
+	currentIndex := -1
+	for i, tag := range sorted {
+		if tag == state.current {
+			currentIndex = i
+			break
+		}
+	}
+
+	switch {
+	case currentIndex < 0 || dead[state.current]:
+		state.current = preferred
+		state.recovering = ""
+	case preferred != state.current && s.revert:
+		preferredIndex := 0
+		for preferredIndex < len(sorted) && sorted[preferredIndex] != preferred {
+			preferredIndex++
+		}
+		if preferredIndex > currentIndex {
+			state.recovering = ""
+			break
+		}
+		if state.recovering != preferred {
+			state.recovering = preferred
+			state.recoveringSince = now
+		}
+		if now.Sub(state.recoveringSince) >= s.holdDown {
+			state.current = preferred
+			state.recovering = ""
+		}
+	default:
+		state.recovering = ""
+	}
+	return state
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*StrategyFailoverConfig)(nil), nil))
+}
@@ -0,0 +1,102 @@
+package router
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/v2fly/v2ray-core/v5/app/observatory"
+	"github.com/v2fly/v2ray-core/v5/features/extension"
+)
+
+func TestFailoverStrategy(t *testing.T) {
+	strategy := NewFailoverStrategy(&StrategyFailoverConfig{
+		Revert:   true,
+		HoldDown: int64(time.Minute),
+	}, []string{"primary", "backup"})
+
+	sorted := strategy.sortCandidates([]string{"backup-b", "backup-a", "primary"})
+	if sorted[0] != "primary" || sorted[1] != "backup-a" || sorted[2] != "backup-b" {
+		t.Fatal("unexpected order: ", sorted)
+	}
+
+	now := time.Now()
+	if tag := strategy.pick(sorted, nil, now); tag != "primary" {
+		t.Error("expect primary, but got ", tag)
+	}
+	if tag := strategy.pick(sorted, map[string]bool{"primary": true}, now); tag != "backup-a" {
+		t.Error("expect backup-a, but got ", tag)
+	}
+	if tag := strategy.pick(sorted, map[string]bool{"primary": true, "backup-a": true}, now); tag != "backup-b" {
+		t.Error("expect backup-b, but got ", tag)
+	}
+
+	// The primary recovers, but has to stay alive for the hold-down period.
+	if tag := strategy.pick(sorted, nil, now); tag != "backup-b" {
+		t.Error("expect backup-b during hold-down, but got ", tag)
+	}
+	if tag := strategy.pick(sorted, nil, now.Add(30*time.Second)); tag != "backup-b" {
+		t.Error("expect backup-b during hold-down, but got ", tag)
+	}
+	if tag := strategy.pick(sorted, nil, now.Add(time.Minute)); tag != "primary" {
+		t.Error("expect primary after hold-down, but got ", tag)
+	}
+}
+
+func TestFailoverStrategyWithoutRevert(t *testing.T) {
+	strategy := NewFailoverStrategy(&StrategyFailoverConfig{}, []string{"primary", "backup"})
+	sorted := strategy.sortCandidates([]string{"primary", "backup"})
+
+	now := time.Now()
+	if tag := strategy.pick(sorted, map[string]bool{"primary": true}, now); tag != "backup" {
+		t.Error("expect backup, but got ", tag)
+	}
+	if tag := strategy.pick(sorted, nil, now.Add(time.Hour)); tag != "backup" {
+		t.Error("expect to stay on backup, but got ", tag)
+	}
+}
+
+type staticObservatory struct {
+	result *observatory.ObservationResult
+}
+
+func (o *staticObservatory) GetObservation(ctx context.Context) (proto.Message, error) {
+	return o.result, nil
+}
+
+func (o *staticObservatory) Type() interface{} { return extension.ObservatoryType() }
+func (o *staticObservatory) Start() error      { return nil }
+func (o *staticObservatory) Close() error      { return nil }
+
+func TestFailoverStrategyPrincipleTargetIsReadOnly(t *testing.T) {
+	observer := &staticObservatory{result: &observatory.ObservationResult{
+		Status: []*observatory.OutboundStatus{{OutboundTag: "primary", Alive: false}},
+	}}
+	strategy := NewFailoverStrategy(&StrategyFailoverConfig{
+		Revert:   true,
+		HoldDown: int64(time.Minute),
+	}, []string{"primary", "backup"})
+	strategy.observer = observer
+	candidates := []string{"primary", "backup"}
+
+	if r := strategy.GetPrincipleTarget(candidates); len(r) != 1 || r[0] != "backup" {
+		t.Error("expect backup, but got ", r)
+	}
+	if tag := strategy.PickOutbound(nil, candidates); tag != "backup" {
+		t.Error("expect backup, but got ", tag)
+	}
+
+	// Queries during the hold-down period do not start or restart the period.
+	observer.result = &observatory.ObservationResult{}
+	state := strategy.state
+	for i := 0; i < 3; i++ {
+		if r := strategy.GetPrincipleTarget(candidates); len(r) != 1 || r[0] != "backup" {
+			t.Error("expect backup during hold-down, but got ", r)
+		}
+	}
+	if strategy.state != state {
+		t.Error("state changed from ", state, " to ", strategy.state)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/v2fly/v2ray-core/v5/common/dice"
 	"github.com/v2fly/v2ray-core/v5/features"
 	"github.com/v2fly/v2ray-core/v5/features/extension"
+	"github.com/v2fly/v2ray-core/v5/features/routing"
 )
 
 // LeastLoadStrategy represents a least load balancing strategy
@@ -64,7 +65,7 @@ func (l *LeastLoadStrategy) InjectContext(ctx context.Context) {
 	l.ctx = ctx
 }
 
-func (l *LeastLoadStrategy) PickOutbound(candidates []string) string {
+func (l *LeastLoadStrategy) PickOutbound(ctx routing.Context, candidates []string) string {
 	selects := l.pickOutbounds(candidates)
 	count := len(selects)
 	if count == 0 {
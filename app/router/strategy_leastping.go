@@ -11,6 +11,7 @@ import (
 	"github.com/v2fly/v2ray-core/v5/common"
 	"github.com/v2fly/v2ray-core/v5/features"
 	"github.com/v2fly/v2ray-core/v5/features/extension"
+	"github.com/v2fly/v2ray-core/v5/features/routing"
 )
 
 type LeastPingStrategy struct {
@@ -21,14 +22,14 @@ type LeastPingStrategy struct {
 }
 
 func (l *LeastPingStrategy) GetPrincipleTarget(strings []string) []string {
-	return []string{l.PickOutbound(strings)}
+	return []string{l.PickOutbound(nil, strings)}
 }
 
 func (l *LeastPingStrategy) InjectContext(ctx context.Context) {
 	l.ctx = ctx
 }
 
-func (l *LeastPingStrategy) PickOutbound(strings []string) string {
+func (l *LeastPingStrategy) PickOutbound(ctx routing.Context, strings []string) string {
 	if l.observatory == nil {
 		common.Must(core.RequireFeatures(l.ctx, func(observatory extension.Observatory) error {
 			if l.config.ObserverTag != "" {
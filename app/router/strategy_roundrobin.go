@@ -0,0 +1,76 @@
+//go:build !confonly
+// +build !confonly
+
+package router
+
+import (
+	"sync"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/features/routing"
+)
+
+// RoundRobinStrategy picks outbounds in turn, in proportion to their weights.
+// It uses the smooth weighted round-robin algorithm, which interleaves outbounds
+// instead of picking the same outbound several times in a row.
+type RoundRobinStrategy struct {
+	access  sync.Mutex
+	weights *WeightManager
+	current map[string]float64
+	last    string
+}
+
+func NewRoundRobinStrategy(config *StrategyRoundRobinConfig) *RoundRobinStrategy {
+	return &RoundRobinStrategy{
+		weights: NewWeightManager(
+			config.Weights, 1,
+			func(value, weight float64) float64 {
+				return value * weight
+			},
+		),
+		current: make(map[string]float64),
+	}
+}
+
+func (s *RoundRobinStrategy) GetPrincipleTarget(candidates []string) []string {
+	s.access.Lock()
+	defer s.access.Unlock()
+
+	if s.last != "" && outboundList(candidates).contains(s.last) {
+		return []string{s.last}
+	}
+	return candidates
+}
+
+func (s *RoundRobinStrategy) PickOutbound(ctx routing.Context, candidates []string) string {
+	s.access.Lock()
+	defer s.access.Unlock()
+
+	var total float64
+	selected := ""
+	current := make(map[string]float64, len(candidates))
+	for _, tag := range candidates {
+		weight := s.weights.Get(tag)
+		if weight <= 0 {
+			continue
+		}
+		total += weight
+		current[tag] = s.current[tag] + weight
+		if selected == "" || current[tag] > current[selected] || (current[tag] == current[selected] && tag < selected) {
+			selected = tag
+		}
+	}
+	if selected == "" {
+		// goes to fallbackTag
+		return ""
+	}
+	current[selected] -= total
+	// Outbounds no longer in candidates are dropped from the state.
+	s.current = current
+	s.last = selected
+	return selected
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*StrategyRoundRobinConfig)(nil), nil))
+}
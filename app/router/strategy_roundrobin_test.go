@@ -0,0 +1,29 @@
+package router_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	. "github.com/v2fly/v2ray-core/v5/app/router"
+)
+
+func TestRoundRobinStrategy(t *testing.T) {
+	strategy := NewRoundRobinStrategy(&StrategyRoundRobinConfig{
+		Weights: []*StrategyWeight{
+			{Match: "a", Value: 3},
+		},
+	})
+
+	candidates := []string{"a", "b"}
+	var picks []string
+	for i := 0; i < 8; i++ {
+		picks = append(picks, strategy.PickOutbound(nil, candidates))
+	}
+	if r := cmp.Diff(picks, []string{"a", "a", "b", "a", "a", "a", "b", "a"}); r != "" {
+		t.Error(r)
+	}
+	if r := cmp.Diff(strategy.GetPrincipleTarget(candidates), []string{"a"}); r != "" {
+		t.Error(r)
+	}
+}
@@ -0,0 +1,376 @@
+package ruleset
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/platform/filesystem"
+	"github.com/v2fly/v2ray-core/v5/common/task"
+	"github.com/v2fly/v2ray-core/v5/transport/internet/tagged"
+)
+
+const (
+	defaultFileInterval = time.Minute
+	defaultURLInterval  = 24 * time.Hour
+	fetchTimeout        = time.Minute
+	maxRuleSetSize      = 64 * 1024 * 1024
+	// digestSuffix is appended to the cache path for the digest of the cache from the sha256 url.
+	digestSuffix = ".sha256"
+)
+
+// provider loads a rule set from a local file or an url.
+type provider struct {
+	ctx     context.Context
+	config  *Provider
+	set     *RuleSet
+	client  *http.Client
+	refresh *task.Periodic
+	digest  []byte
+	// started is set once the refresh is started, whose first run only schedules the first update.
+	started bool
+	// unverified is set if the rules are loaded from the cache, whose digest is not verified against
+	// the sha256 url yet.
+	unverified bool
+
+	// modTime is the modification time of the loaded file.
+	modTime time.Time
+	// etag and lastModified are the validators of the content fetched from the url.
+	etag         string
+	lastModified string
+}
+
+func newProvider(ctx context.Context, config *Provider) (*provider, error) {
+	if config.Name == "" {
+		return nil, newError("rule set name is not specified")
+	}
+	if (config.Path == "") == (config.Url == "") {
+		return nil, newError("exactly one of path and url should be specified")
+	}
+	if config.Sha256 != "" {
+		if digest, err := hex.DecodeString(config.Sha256); err != nil || len(digest) != sha256.Size {
+			return nil, newError("invalid sha256 digest ", config.Sha256)
+		}
+	}
+
+	p := &provider{
+		ctx:    ctx,
+		config: config,
+		set:    &RuleSet{name: config.Name},
+	}
+	interval := time.Duration(config.Interval)
+	if config.Url != "" {
+		if err := checkURL(config.Url); err != nil {
+			return nil, err
+		}
+		if config.Sha256Url != "" {
+			if err := checkURL(config.Sha256Url); err != nil {
+				return nil, err
+			}
+		}
+		p.client = newHTTPClient(ctx, config.OutboundTag)
+		if interval <= 0 {
+			interval = defaultURLInterval
+		}
+	} else if interval <= 0 {
+		interval = defaultFileInterval
+	}
+	p.refresh = &task.Periodic{
+		Execute: func() error {
+			// The outbound of the provider may not be started yet when the refresh is started,
+			// so the first update is run in the background right after it.
+			if !p.started {
+				p.started = true
+				return nil
+			}
+			p.refresh.Interval = interval
+			if err := p.update(); err != nil {
+				newError("failed to update rule set ", config.Name).Base(err).AtWarning().WriteToLog()
+			}
+			return nil
+		},
+	}
+
+	// The rules are available before the first fetch, if they can be loaded locally.
+	switch {
+	case config.Path != "":
+		if err := p.updateFromFile(); err != nil {
+			return nil, err
+		}
+	case config.CachePath != "":
+		if err := p.loadCache(); err != nil {
+			newError("failed to load cache of rule set ", config.Name).Base(err).AtWarning().WriteToLog()
+		}
+	}
+	return p, nil
+}
+
+func checkURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return newError("invalid url ", rawURL).Base(err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return newError("unsupported url ", rawURL)
+	}
+	return nil
+}
+
+func newHTTPClient(ctx context.Context, outboundTag string) *http.Client {
+	if outboundTag == "" {
+		return &http.Client{Timeout: fetchTimeout}
+	}
+	return &http.Client{
+		Timeout: fetchTimeout,
+		Transport: &http.Transport{
+			DialContext: func(_ context.Context, network, addr string) (net.Conn, error) {
+				dest, err := net.ParseDestination(network + ":" + addr)
+				if err != nil {
+					return nil, err
+				}
+				return tagged.Dialer(ctx, dest, outboundTag)
+			},
+		},
+	}
+}
+
+func (p *provider) update() error {
+	if p.config.Path != "" {
+		return p.updateFromFile()
+	}
+	return p.updateFromURL()
+}
+
+func (p *provider) updateFromFile() error {
+	info, err := os.Stat(p.config.Path)
+	if err != nil {
+		return err
+	}
+	if info.ModTime().Equal(p.modTime) {
+		return nil
+	}
+	content, err := filesystem.ReadFile(p.config.Path)
+	if err != nil {
+		return err
+	}
+	if err := p.verify(content, p.config.Sha256); err != nil {
+		return err
+	}
+	if err := p.load(content); err != nil {
+		return err
+	}
+	p.modTime = info.ModTime()
+	return nil
+}
+
+func (p *provider) updateFromURL() error {
+	req, err := http.NewRequestWithContext(p.ctx, http.MethodGet, p.config.Url, nil)
+	if err != nil {
+		return err
+	}
+	if p.etag != "" {
+		req.Header.Set("If-None-Match", p.etag)
+	}
+	if p.lastModified != "" {
+		req.Header.Set("If-Modified-Since", p.lastModified)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		if p.unverified {
+			return p.verifyCache()
+		}
+		newError("rule set ", p.config.Name, " is not modified").AtDebug().WriteToLog()
+		return nil
+	default:
+		return newError("unexpected status ", resp.Status, " from ", p.config.Url)
+	}
+	content, err := readAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	expected := p.config.Sha256
+	if p.config.Sha256Url != "" {
+		if expected, err = p.fetchDigest(); err != nil {
+			return newError("failed to fetch sha256 digest").Base(err)
+		}
+	}
+	if err := p.verify(content, expected); err != nil {
+		return err
+	}
+	if err := p.load(content); err != nil {
+		return err
+	}
+
+	// Only the validators of good content are kept, so that bad content is fetched again.
+	p.etag = resp.Header.Get("ETag")
+	p.lastModified = resp.Header.Get("Last-Modified")
+	p.unverified = false
+	if p.config.CachePath != "" {
+		if err := writeCache(p.config.CachePath, content); err != nil {
+			newError("failed to write cache of rule set ", p.config.Name).Base(err).AtWarning().WriteToLog()
+		} else if p.config.Sha256Url != "" {
+			if err := writeCache(p.config.CachePath+digestSuffix, []byte(expected)); err != nil {
+				newError("failed to write digest of cache of rule set ", p.config.Name).Base(err).AtWarning().WriteToLog()
+			}
+		}
+	}
+	return nil
+}
+
+// verifyCache verifies the rules loaded from the cache against the digest from the sha256 url, as the
+// content is not modified since the cache. The content is fetched again if it does not match.
+func (p *provider) verifyCache() error {
+	expected, err := p.fetchDigest()
+	if err != nil {
+		return newError("failed to fetch sha256 digest").Base(err)
+	}
+	if !strings.EqualFold(hex.EncodeToString(p.digest), expected) {
+		newError("cache of rule set ", p.config.Name, " does not match the sha256 digest, fetching again").AtWarning().WriteToLog()
+		p.etag = ""
+		p.lastModified = ""
+		p.unverified = false
+		return p.updateFromURL()
+	}
+	p.unverified = false
+	return nil
+}
+
+// fetchDigest fetches the hex encoded SHA-256 digest from the sha256 url, which is the first field of its content.
+func (p *provider) fetchDigest() (string, error) {
+	req, err := http.NewRequestWithContext(p.ctx, http.MethodGet, p.config.Sha256Url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", newError("unexpected status ", resp.Status, " from ", p.config.Sha256Url)
+	}
+	content, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(string(content))
+	if len(fields) == 0 {
+		return "", newError("empty sha256 digest")
+	}
+	return fields[0], nil
+}
+
+func readAll(reader io.Reader) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(reader, maxRuleSetSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxRuleSetSize {
+		return nil, newError("rule set is larger than ", maxRuleSetSize, " bytes")
+	}
+	return content, nil
+}
+
+func (p *provider) verify(content []byte, expected string) error {
+	if expected == "" {
+		return nil
+	}
+	digest := sha256.Sum256(content)
+	if !strings.EqualFold(hex.EncodeToString(digest[:]), expected) {
+		return newError("sha256 digest mismatch, expected ", expected, " but got ", hex.EncodeToString(digest[:]))
+	}
+	return nil
+}
+
+// load parses the content and updates the rule set if the content is changed.
+func (p *provider) load(content []byte) error {
+	digest := sha256.Sum256(content)
+	if bytes.Equal(digest[:], p.digest) {
+		return nil
+	}
+	rules, err := ParseRules(content)
+	if err != nil {
+		return err
+	}
+	p.digest = digest[:]
+	p.set.update(rules)
+	newError("rule set ", p.config.Name, " updated with ", len(rules.Domains), " domains and ", len(rules.CIDRs), " CIDRs").AtInfo().WriteToLog()
+	return nil
+}
+
+func (p *provider) loadCache() error {
+	info, err := os.Stat(p.config.CachePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	content, err := filesystem.ReadFile(p.config.CachePath)
+	if err != nil {
+		return err
+	}
+	// The cache was verified when it was written, but may be from a different pinned digest.
+	expected := p.config.Sha256
+	if expected == "" && p.config.Sha256Url != "" {
+		// The digest from the sha256 url is kept along with the cache, and the content is verified
+		// against the sha256 url again once it is fetched.
+		digest, err := os.ReadFile(p.config.CachePath + digestSuffix)
+		if err != nil {
+			return newError("failed to read digest of cache").Base(err)
+		}
+		if expected = strings.TrimSpace(string(digest)); expected == "" {
+			return newError("empty digest of cache")
+		}
+		p.unverified = true
+	}
+	if err := p.verify(content, expected); err != nil {
+		p.unverified = false
+		return err
+	}
+	if err := p.load(content); err != nil {
+		p.unverified = false
+		return err
+	}
+	p.lastModified = info.ModTime().UTC().Format(http.TimeFormat)
+	return nil
+}
+
+// writeCache writes the content to the path atomically, so that a partial cache is never loaded.
+func writeCache(path string, content []byte) error {
+	temp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = temp.Write(content)
+	if cerr := temp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(temp.Name())
+		return err
+	}
+	if err := os.Rename(temp.Name(), path); err != nil {
+		os.Remove(temp.Name())
+		return err
+	}
+	return nil
+}
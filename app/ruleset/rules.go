@@ -0,0 +1,146 @@
+package ruleset
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/v2fly/v2ray-core/v5/app/router/routercommon"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+)
+
+/*
+Rule set format
+One rule per line, empty lines and lines starting with "#" or "!" are ignored.
+domain:example.com     - example.com and its subdomains
+full:example.com       - example.com only
+keyword:example        - domains containing "example"
+regexp:\.example$      - domains matching the regular expression
+10.0.0.0/8, ::1        - IP addresses in the CIDR, or the IP address
+example.com            - same as domain:example.com
+0.0.0.0 a.com b.com    - hosts file entry, same as full:a.com and full:b.com
+||example.com^         - adblock filter, same as domain:example.com, other adblock filters are ignored
+*/
+
+var ignoredHosts = map[string]bool{
+	"localhost":             true,
+	"localhost.localdomain": true,
+	"local":                 true,
+	"broadcasthost":         true,
+	"ip6-localhost":         true,
+	"ip6-loopback":          true,
+	"ip6-localnet":          true,
+	"ip6-mcastprefix":       true,
+	"ip6-allnodes":          true,
+	"ip6-allrouters":        true,
+	"ip6-allhosts":          true,
+	"0.0.0.0":               true,
+}
+
+// IsComment returns whether the trimmed line is empty or a comment.
+func IsComment(line string) bool {
+	return line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!")
+}
+
+// ParseRules parses the content of a rule set.
+func ParseRules(content []byte) (*Rules, error) {
+	rules := new(Rules)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(nil, 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if IsComment(text) {
+			continue
+		}
+		if err := rules.Add(text); err != nil {
+			return nil, newError("invalid rule at line ", line).Base(err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *Rules) addDomain(domainType routercommon.Domain_Type, value string) {
+	if domainType != routercommon.Domain_Regex {
+		value = strings.ToLower(value)
+	}
+	r.Domains = append(r.Domains, &routercommon.Domain{
+		Type:  domainType,
+		Value: value,
+	})
+}
+
+// Add parses a rule and adds it to the rules. Unsupported adblock filters are ignored.
+func (r *Rules) Add(rule string) error {
+	var domainType routercommon.Domain_Type
+	value := rule
+	switch {
+	case strings.HasPrefix(rule, "domain:"):
+		domainType, value = routercommon.Domain_RootDomain, rule[len("domain:"):]
+	case strings.HasPrefix(rule, "full:"):
+		domainType, value = routercommon.Domain_Full, rule[len("full:"):]
+	case strings.HasPrefix(rule, "keyword:"):
+		domainType, value = routercommon.Domain_Plain, rule[len("keyword:"):]
+	case strings.HasPrefix(rule, "regexp:"):
+		domainType, value = routercommon.Domain_Regex, rule[len("regexp:"):]
+		if _, err := regexp.Compile(value); err != nil {
+			return newError("invalid regexp ", value).Base(err)
+		}
+	case strings.HasPrefix(rule, "||"):
+		// Only adblock filters blocking a whole domain are supported.
+		value = strings.TrimSuffix(strings.TrimPrefix(rule, "||"), "^")
+		if strings.ContainsAny(value, "^$/*|") {
+			return nil
+		}
+		domainType = routercommon.Domain_RootDomain
+	case strings.HasPrefix(rule, "@@") || strings.HasPrefix(rule, "|") || strings.Contains(rule, "##") || strings.Contains(rule, "#@#"):
+		return nil
+	default:
+		if fields := strings.Fields(rule); len(fields) > 1 && net.ParseIP(fields[0]) != nil {
+			for _, host := range fields[1:] {
+				if strings.HasPrefix(host, "#") {
+					break
+				}
+				if !ignoredHosts[strings.ToLower(host)] {
+					r.addDomain(routercommon.Domain_Full, host)
+				}
+			}
+			return nil
+		}
+		if cidr := parseCIDR(rule); cidr != nil {
+			r.CIDRs = append(r.CIDRs, cidr)
+			return nil
+		}
+		domainType = routercommon.Domain_RootDomain
+	}
+	if value == "" {
+		return newError("empty rule ", rule)
+	}
+	r.addDomain(domainType, value)
+	return nil
+}
+func parseCIDR(s string) *routercommon.CIDR {
+	if !strings.Contains(s, "/") {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return nil
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			return &routercommon.CIDR{Ip: ip4, Prefix: 32}
+		}
+		return &routercommon.CIDR{Ip: ip, Prefix: 128}
+	}
+	_, ipNet, err := net.ParseCIDR(s)
+	if err != nil {
+		return nil
+	}
+	ones, _ := ipNet.Mask.Size()
+	ip := ipNet.IP
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	return &routercommon.CIDR{Ip: ip, Prefix: uint32(ones)}
+}
@@ -0,0 +1,143 @@
+package ruleset
+
+//go:generate go run github.com/v2fly/v2ray-core/v5/common/errors/errorgen
+
+import (
+	"context"
+	"sync"
+
+	"github.com/v2fly/v2ray-core/v5/app/router/routercommon"
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/errors"
+	"github.com/v2fly/v2ray-core/v5/features"
+)
+
+// Rules is the content of a rule set at a point of time.
+type Rules struct {
+	Domains []*routercommon.Domain
+	CIDRs   []*routercommon.CIDR
+}
+
+// RuleSet is a named list of domain and IP rules, which may be updated at runtime.
+type RuleSet struct {
+	name string
+
+	access   sync.Mutex
+	rules    *Rules
+	watchers []func(*Rules)
+}
+
+// Name returns the name of the rule set.
+func (s *RuleSet) Name() string {
+	return s.name
+}
+
+// Rules returns the current rules of the set, or nil if the set is not loaded yet.
+func (s *RuleSet) Rules() *Rules {
+	s.access.Lock()
+	defer s.access.Unlock()
+
+	return s.rules
+}
+
+// Watch calls onUpdate with the current rules if the set is loaded, and every time the set is updated afterwards.
+// onUpdate must not call the methods of the set.
+func (s *RuleSet) Watch(onUpdate func(*Rules)) {
+	s.access.Lock()
+	defer s.access.Unlock()
+
+	s.watchers = append(s.watchers, onUpdate)
+	if s.rules != nil {
+		onUpdate(s.rules)
+	}
+}
+
+func (s *RuleSet) update(rules *Rules) {
+	s.access.Lock()
+	defer s.access.Unlock()
+
+	s.rules = rules
+	for _, onUpdate := range s.watchers {
+		onUpdate(rules)
+	}
+}
+
+// Manager provides the rule sets by their names.
+type Manager interface {
+	features.Feature
+	GetRuleSet(name string) (*RuleSet, error)
+}
+
+// ManagerType returns the type of Manager interface. Can be used for implementing common.HasType.
+func ManagerType() interface{} {
+	return (*Manager)(nil)
+}
+
+// RuleSets is a Manager of the rule sets loaded by the providers in the config.
+type RuleSets struct {
+	sets      map[string]*RuleSet
+	providers []*provider
+}
+
+// New creates RuleSets with the providers in the config. The rule sets from local files and on-disk caches are
+// loaded before it returns.
+func New(ctx context.Context, config *Config) (*RuleSets, error) {
+	r := &RuleSets{
+		sets: make(map[string]*RuleSet, len(config.Provider)),
+	}
+	for _, providerConfig := range config.Provider {
+		if _, found := r.sets[providerConfig.Name]; found {
+			return nil, newError("duplicated rule set ", providerConfig.Name)
+		}
+		p, err := newProvider(ctx, providerConfig)
+		if err != nil {
+			return nil, newError("failed to create rule set ", providerConfig.Name).Base(err)
+		}
+		r.sets[providerConfig.Name] = p.set
+		r.providers = append(r.providers, p)
+	}
+	return r, nil
+}
+
+// Type implements common.HasType.
+func (*RuleSets) Type() interface{} {
+	return ManagerType()
+}
+
+// GetRuleSet implements Manager.
+func (r *RuleSets) GetRuleSet(name string) (*RuleSet, error) {
+	if set, found := r.sets[name]; found {
+		return set, nil
+	}
+	return nil, newError("rule set ", name, " not found")
+}
+
+// Start implements common.Runnable.
+func (r *RuleSets) Start() error {
+	for _, p := range r.providers {
+		if err := p.refresh.Start(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close implements common.Closable.
+func (r *RuleSets) Close() error {
+	var errs []error
+	for _, p := range r.providers {
+		if err := p.refresh.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return newError("failed to close all rule set providers").Base(errors.Combine(errs...))
+	}
+	return nil
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return New(ctx, config.(*Config))
+	}))
+}
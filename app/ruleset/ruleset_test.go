@@ -0,0 +1,320 @@
+package ruleset
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	"github.com/v2fly/v2ray-core/v5/app/router/routercommon"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules([]byte(`
+# comment
+example.com
+full:www.Example.org
+keyword:tracker
+regexp:^ads\d+\.
+10.0.0.0/8
+192.168.1.1
+2001:db8::/32
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &Rules{
+		Domains: []*routercommon.Domain{
+			{Type: routercommon.Domain_RootDomain, Value: "example.com"},
+			{Type: routercommon.Domain_Full, Value: "www.example.org"},
+			{Type: routercommon.Domain_Plain, Value: "tracker"},
+			{Type: routercommon.Domain_Regex, Value: `^ads\d+\.`},
+		},
+		CIDRs: []*routercommon.CIDR{
+			{Ip: []byte{10, 0, 0, 0}, Prefix: 8},
+			{Ip: []byte{192, 168, 1, 1}, Prefix: 32},
+			{Ip: []byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, Prefix: 32},
+		},
+	}
+	if r := cmp.Diff(rules, expected, protocmp.Transform()); r != "" {
+		t.Error(r)
+	}
+
+	if _, err := ParseRules([]byte("example.com\nregexp:(")); err == nil {
+		t.Error("expected error for invalid regexp")
+	}
+}
+
+func TestFileProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.txt")
+	if err := os.WriteFile(path, []byte("example.com\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := newProvider(context.Background(), &Provider{Name: "test", Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var updates []*Rules
+	p.set.Watch(func(rules *Rules) {
+		updates = append(updates, rules)
+	})
+	if len(updates) != 1 || len(updates[0].Domains) != 1 {
+		t.Fatal("expected rules to be loaded at creation, but got ", updates)
+	}
+
+	if err := p.update(); err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 1 {
+		t.Error("expected no update if the file is not modified")
+	}
+
+	if err := os.WriteFile(path, []byte("example.com\nexample.org\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, time.Now(), time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.update(); err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 2 || len(updates[1].Domains) != 2 {
+		t.Error("expected rules to be updated, but got ", updates)
+	}
+}
+
+func TestURLProvider(t *testing.T) {
+	var access sync.Mutex
+	content := "example.com\n"
+	digest := func() string {
+		d := sha256.Sum256([]byte(content))
+		return hex.EncodeToString(d[:])
+	}
+	etag := `"v1"`
+	served := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		access.Lock()
+		defer access.Unlock()
+
+		switch r.URL.Path {
+		case "/rules.txt.sha256":
+			w.Write([]byte(digest() + "  rules.txt\n"))
+		case "/rules.txt":
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			served++
+			w.Header().Set("ETag", etag)
+			w.Write([]byte(content))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cachePath := filepath.Join(t.TempDir(), "rules.cache")
+	config := &Provider{
+		Name:      "test",
+		Url:       server.URL + "/rules.txt",
+		Sha256Url: server.URL + "/rules.txt.sha256",
+		CachePath: cachePath,
+	}
+	p, err := newProvider(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.set.Rules() != nil {
+		t.Fatal("expected no rules before the first fetch")
+	}
+
+	if err := p.update(); err != nil {
+		t.Fatal(err)
+	}
+	if rules := p.set.Rules(); rules == nil || len(rules.Domains) != 1 {
+		t.Fatal("unexpected rules ", rules)
+	}
+
+	// The content is not fetched again if it is not modified.
+	if err := p.update(); err != nil {
+		t.Fatal(err)
+	}
+	if served != 1 {
+		t.Error("expected the content to be served once, but got ", served)
+	}
+
+	// Content not matching the digest is rejected, and the last good rules are kept.
+	access.Lock()
+	etag = `"v2"`
+	content = "example.com\nexample.org\n"
+	access.Unlock()
+	config.Sha256Url = ""
+	config.Sha256 = hex.EncodeToString(make([]byte, sha256.Size))
+	if err := p.update(); err == nil {
+		t.Error("expected digest mismatch")
+	}
+	if rules := p.set.Rules(); len(rules.Domains) != 1 {
+		t.Error("expected the last good rules, but got ", rules)
+	}
+
+	// The last good content is loaded from the cache.
+	server.Close()
+	config.Sha256 = ""
+	cached, err := newProvider(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules := cached.set.Rules(); rules == nil || len(rules.Domains) != 1 {
+		t.Error("expected rules from the cache, but got ", rules)
+	}
+	if err := cached.update(); err == nil {
+		t.Error("expected fetch error")
+	}
+}
+
+func TestURLProviderCacheDigest(t *testing.T) {
+	var access sync.Mutex
+	content := "example.com\n"
+	digest := func() string {
+		d := sha256.Sum256([]byte(content))
+		return hex.EncodeToString(d[:])
+	}
+	// stale makes the server reply not modified to every conditional request.
+	stale := false
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		access.Lock()
+		defer access.Unlock()
+
+		switch r.URL.Path {
+		case "/rules.txt.sha256":
+			w.Write([]byte(digest()))
+		case "/rules.txt":
+			if stale && r.Header.Get("If-Modified-Since") != "" {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Write([]byte(content))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cachePath := filepath.Join(t.TempDir(), "rules.cache")
+	config := &Provider{
+		Name:      "test",
+		Url:       server.URL + "/rules.txt",
+		Sha256Url: server.URL + "/rules.txt.sha256",
+		CachePath: cachePath,
+	}
+	p, err := newProvider(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.update(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The cache is verified against the digest kept along with it.
+	if err := os.WriteFile(cachePath, []byte("example.org\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if p, err := newProvider(context.Background(), config); err != nil || p.set.Rules() != nil {
+		t.Fatal("expected the modified cache not loaded")
+	}
+	if err := os.WriteFile(cachePath, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p, err = newProvider(context.Background(), config)
+	if err != nil || p.set.Rules() == nil {
+		t.Fatal("expected the cache loaded")
+	}
+
+	// The cache is verified against the sha256 url even if the content is not modified.
+	access.Lock()
+	stale = true
+	content = "example.com\nexample.org\n"
+	access.Unlock()
+	if err := p.update(); err != nil {
+		t.Fatal(err)
+	}
+	if rules := p.set.Rules(); len(rules.Domains) != 2 {
+		t.Error("expected the content fetched again, but got ", rules)
+	}
+}
+
+func TestRuleSetsStart(t *testing.T) {
+	requested := make(chan struct{}, 16)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested <- struct{}{}
+		w.Write([]byte("example.com\n"))
+	}))
+	defer server.Close()
+
+	r, err := New(context.Background(), &Config{Provider: []*Provider{{Name: "test", Url: server.URL}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Start(); err != nil {
+		t.Fatal(err)
+	}
+	// The first update runs in the background after the start.
+	select {
+	case <-requested:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the rule set to be fetched")
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	set, _ := r.GetRuleSet("test")
+	deadline := time.Now().Add(5 * time.Second)
+	for set.Rules() == nil && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if set.Rules() == nil {
+		t.Error("expected rules fetched")
+	}
+}
+
+func TestRuleSetsConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.txt")
+	if err := os.WriteFile(path, []byte("example.com\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, config := range []*Config{
+		{Provider: []*Provider{{Path: path}}},
+		{Provider: []*Provider{{Name: "test"}}},
+		{Provider: []*Provider{{Name: "test", Path: path, Url: "https://example.com/rules.txt"}}},
+		{Provider: []*Provider{{Name: "test", Url: "file:///rules.txt"}}},
+		{Provider: []*Provider{{Name: "test", Path: path, Sha256: "invalid"}}},
+		{Provider: []*Provider{{Name: "test", Path: path}, {Name: "test", Path: path}}},
+	} {
+		if _, err := New(context.Background(), config); err == nil {
+			t.Error("expected error for config ", config)
+		}
+	}
+
+	r, err := New(context.Background(), &Config{Provider: []*Provider{{Name: "test", Path: path}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.GetRuleSet("test"); err != nil {
+		t.Error(err)
+	}
+	if _, err := r.GetRuleSet("unknown"); err == nil {
+		t.Error("expected error for unknown rule set")
+	}
+}
@@ -0,0 +1,188 @@
+package buf
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/dice"
+)
+
+/*
+Padding frame format
+1 byte  - flags, paddingFlagLast marks the last padded frame
+2 bytes - content length
+2 bytes - padding length
+n bytes - content
+m bytes - padding
+
+The data after the last padded frame is not framed.
+*/
+
+const (
+	paddingHeaderSize = 5
+	paddingFlagLast   = 0x01
+)
+
+// HasPadding returns whether the config pads any writes.
+func (c *ShapingConfig) HasPadding() bool {
+	return c != nil && len(c.Padding) > 0
+}
+
+// IsEmpty returns whether the config changes no writes.
+func (c *ShapingConfig) IsEmpty() bool {
+	return c == nil || (len(c.Padding) == 0 && c.RecordSize == 0 && c.Jitter <= 0)
+}
+
+func (r *ShapingConfig_Range) roll() int32 {
+	min, max := int32(r.Min), int32(r.Max)
+	if max <= min {
+		return min
+	}
+	return min + int32(dice.Roll(int(max-min+1)))
+}
+
+// ShapingWriter is a Writer that shapes the lengths and timing of the writes to the underlying Writer.
+type ShapingWriter struct {
+	writer  Writer
+	config  *ShapingConfig
+	padding bool
+	writes  int
+}
+
+// NewShapingWriter creates a ShapingWriter with the config. The writes are only padded if padding is true,
+// which means the receiver reads with a PaddingReader.
+func NewShapingWriter(writer Writer, config *ShapingConfig, padding bool) Writer {
+	if config.IsEmpty() || (!padding && config.RecordSize == 0 && config.Jitter <= 0) {
+		return writer
+	}
+	return &ShapingWriter{
+		writer:  writer,
+		config:  config,
+		padding: padding && config.HasPadding(),
+	}
+}
+
+// WriteMultiBuffer implements Writer.
+func (w *ShapingWriter) WriteMultiBuffer(mb MultiBuffer) error {
+	if mb.IsEmpty() {
+		return w.writer.WriteMultiBuffer(mb)
+	}
+	for !mb.IsEmpty() {
+		var record MultiBuffer
+		if size := w.recordSize(); size > 0 {
+			mb, record = SplitSize(mb, size)
+		} else {
+			record, mb = mb, nil
+		}
+		if err := w.writeRecord(record); err != nil {
+			ReleaseMulti(mb)
+			return err
+		}
+	}
+	return nil
+}
+
+// recordSize returns the max size of the next record, or 0 if the writes are not split.
+func (w *ShapingWriter) recordSize() int32 {
+	size := int32(w.config.RecordSize)
+	if w.padding && w.writes < len(w.config.Padding) && (size == 0 || size > Size) {
+		// A padded frame holds at most one buffer of content.
+		size = Size
+	}
+	return size
+}
+
+func (w *ShapingWriter) writeRecord(record MultiBuffer) error {
+	index := w.writes
+	w.writes++
+
+	if w.config.Jitter > 0 && (w.config.JitterWrites == 0 || index < int(w.config.JitterWrites)) {
+		time.Sleep(time.Duration(dice.Roll(int(w.config.Jitter))))
+	}
+
+	if !w.padding || index >= len(w.config.Padding) {
+		return w.writer.WriteMultiBuffer(record)
+	}
+
+	var flags byte
+	if index == len(w.config.Padding)-1 {
+		flags |= paddingFlagLast
+	}
+	paddingLen := w.config.Padding[index].roll()
+	if paddingLen > Size {
+		paddingLen = Size
+	}
+
+	header := New()
+	common.Must(header.WriteByte(flags))
+	binary.BigEndian.PutUint16(header.Extend(2), uint16(record.Len()))
+	binary.BigEndian.PutUint16(header.Extend(2), uint16(paddingLen))
+
+	frame := make(MultiBuffer, 0, len(record)+2)
+	frame = append(frame, header)
+	frame = append(frame, record...)
+	if paddingLen > 0 {
+		padding := New()
+		common.Must2(padding.ReadFullFrom(rand.Reader, paddingLen))
+		frame = append(frame, padding)
+	}
+	return w.writer.WriteMultiBuffer(frame)
+}
+
+// PaddingReader is a Reader that removes the padding sent by a ShapingWriter.
+type PaddingReader struct {
+	reader io.Reader
+	raw    Reader
+	header [paddingHeaderSize]byte
+}
+
+// NewPaddingReader creates a PaddingReader reading from the reader.
+func NewPaddingReader(reader io.Reader) *PaddingReader {
+	return &PaddingReader{
+		reader: reader,
+	}
+}
+
+// ReadMultiBuffer implements Reader.
+func (r *PaddingReader) ReadMultiBuffer() (MultiBuffer, error) {
+	for r.raw == nil {
+		if _, err := io.ReadFull(r.reader, r.header[:]); err != nil {
+			return nil, err
+		}
+		contentLen := int32(binary.BigEndian.Uint16(r.header[1:]))
+		paddingLen := int32(binary.BigEndian.Uint16(r.header[3:]))
+		if contentLen > Size || paddingLen > Size {
+			return nil, newError("invalid padding frame: ", contentLen, " ", paddingLen)
+		}
+		if r.header[0]&paddingFlagLast != 0 {
+			r.raw = NewReader(r.reader)
+		}
+
+		var content *Buffer
+		if contentLen > 0 {
+			content = New()
+			if _, err := content.ReadFullFrom(r.reader, contentLen); err != nil {
+				content.Release()
+				return nil, err
+			}
+		}
+		if paddingLen > 0 {
+			padding := New()
+			_, err := padding.ReadFullFrom(r.reader, paddingLen)
+			padding.Release()
+			if err != nil {
+				if content != nil {
+					content.Release()
+				}
+				return nil, err
+			}
+		}
+		if content != nil {
+			return MultiBuffer{content}, nil
+		}
+	}
+	return r.raw.ReadMultiBuffer()
+}
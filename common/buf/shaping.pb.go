@@ -0,0 +1,257 @@
+package buf
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ShapingConfig is the traffic shaping applied to the writes of a connection.
+type ShapingConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Length of the random padding of each of the first writes, in order.
+	// Padding needs support of the receiver, and is only sent when the
+	// protocol negotiates it, or when it is enabled explicitly, like the
+	// padding of VLESS accounts.
+	Padding []*ShapingConfig_Range `protobuf:"bytes,1,rep,name=padding,proto3" json:"padding,omitempty"`
+	// Max size of the records that writes are split into. Writes are not split
+	// if not set.
+	RecordSize uint32 `protobuf:"varint,2,opt,name=record_size,json=recordSize,proto3" json:"record_size,omitempty"`
+	// Max random delay in nanoseconds before each of the first writes.
+	Jitter int64 `protobuf:"varint,3,opt,name=jitter,proto3" json:"jitter,omitempty"`
+	// Number of the first writes that are delayed. Every write is delayed if
+	// not set.
+	JitterWrites uint32 `protobuf:"varint,4,opt,name=jitter_writes,json=jitterWrites,proto3" json:"jitter_writes,omitempty"`
+}
+
+func (x *ShapingConfig) Reset() {
+	*x = ShapingConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_common_buf_shaping_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShapingConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShapingConfig) ProtoMessage() {}
+
+func (x *ShapingConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_common_buf_shaping_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShapingConfig.ProtoReflect.Descriptor instead.
+func (*ShapingConfig) Descriptor() ([]byte, []int) {
+	return file_common_buf_shaping_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ShapingConfig) GetPadding() []*ShapingConfig_Range {
+	if x != nil {
+		return x.Padding
+	}
+	return nil
+}
+
+func (x *ShapingConfig) GetRecordSize() uint32 {
+	if x != nil {
+		return x.RecordSize
+	}
+	return 0
+}
+
+func (x *ShapingConfig) GetJitter() int64 {
+	if x != nil {
+		return x.Jitter
+	}
+	return 0
+}
+
+func (x *ShapingConfig) GetJitterWrites() uint32 {
+	if x != nil {
+		return x.JitterWrites
+	}
+	return 0
+}
+
+type ShapingConfig_Range struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Min uint32 `protobuf:"varint,1,opt,name=min,proto3" json:"min,omitempty"`
+	Max uint32 `protobuf:"varint,2,opt,name=max,proto3" json:"max,omitempty"`
+}
+
+func (x *ShapingConfig_Range) Reset() {
+	*x = ShapingConfig_Range{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_common_buf_shaping_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ShapingConfig_Range) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShapingConfig_Range) ProtoMessage() {}
+
+func (x *ShapingConfig_Range) ProtoReflect() protoreflect.Message {
+	mi := &file_common_buf_shaping_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShapingConfig_Range.ProtoReflect.Descriptor instead.
+func (*ShapingConfig_Range) Descriptor() ([]byte, []int) {
+	return file_common_buf_shaping_proto_rawDescGZIP(), []int{0, 0}
+}
+
+func (x *ShapingConfig_Range) GetMin() uint32 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *ShapingConfig_Range) GetMax() uint32 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+var File_common_buf_shaping_proto protoreflect.FileDescriptor
+
+var file_common_buf_shaping_proto_rawDesc = []byte{
+	0x0a, 0x18, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2f, 0x62, 0x75, 0x66, 0x2f, 0x73, 0x68, 0x61,
+	0x70, 0x69, 0x6e, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x15, 0x76, 0x32, 0x72, 0x61,
+	0x79, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x62, 0x75,
+	0x66, 0x22, 0xe0, 0x01, 0x0a, 0x0d, 0x53, 0x68, 0x61, 0x70, 0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x12, 0x44, 0x0a, 0x07, 0x70, 0x61, 0x64, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x76, 0x32, 0x72, 0x61, 0x79, 0x2e, 0x63, 0x6f, 0x72,
+	0x65, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x68, 0x61,
+	0x70, 0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x52, 0x61, 0x6e, 0x67, 0x65,
+	0x52, 0x07, 0x70, 0x61, 0x64, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a,
+	0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6a, 0x69,
+	0x74, 0x74, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6a, 0x69, 0x74, 0x74,
+	0x65, 0x72, 0x12, 0x23, 0x0a, 0x0d, 0x6a, 0x69, 0x74, 0x74, 0x65, 0x72, 0x5f, 0x77, 0x72, 0x69,
+	0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x6a, 0x69, 0x74, 0x74, 0x65,
+	0x72, 0x57, 0x72, 0x69, 0x74, 0x65, 0x73, 0x1a, 0x2b, 0x0a, 0x05, 0x52, 0x61, 0x6e, 0x67, 0x65,
+	0x12, 0x10, 0x0a, 0x03, 0x6d, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x6d,
+	0x69, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x03, 0x6d, 0x61, 0x78, 0x42, 0x60, 0x0a, 0x19, 0x63, 0x6f, 0x6d, 0x2e, 0x76, 0x32, 0x72, 0x61,
+	0x79, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x62, 0x75,
+	0x66, 0x50, 0x01, 0x5a, 0x29, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x76, 0x32, 0x66, 0x6c, 0x79, 0x2f, 0x76, 0x32, 0x72, 0x61, 0x79, 0x2d, 0x63, 0x6f, 0x72, 0x65,
+	0x2f, 0x76, 0x35, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2f, 0x62, 0x75, 0x66, 0xaa, 0x02,
+	0x15, 0x56, 0x32, 0x52, 0x61, 0x79, 0x2e, 0x43, 0x6f, 0x72, 0x65, 0x2e, 0x43, 0x6f, 0x6d, 0x6d,
+	0x6f, 0x6e, 0x2e, 0x42, 0x75, 0x66, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_common_buf_shaping_proto_rawDescOnce sync.Once
+	file_common_buf_shaping_proto_rawDescData = file_common_buf_shaping_proto_rawDesc
+)
+
+func file_common_buf_shaping_proto_rawDescGZIP() []byte {
+	file_common_buf_shaping_proto_rawDescOnce.Do(func() {
+		file_common_buf_shaping_proto_rawDescData = protoimpl.X.CompressGZIP(file_common_buf_shaping_proto_rawDescData)
+	})
+	return file_common_buf_shaping_proto_rawDescData
+}
+
+var file_common_buf_shaping_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_common_buf_shaping_proto_goTypes = []interface{}{
+	(*ShapingConfig)(nil),       // 0: v2ray.core.common.buf.ShapingConfig
+	(*ShapingConfig_Range)(nil), // 1: v2ray.core.common.buf.ShapingConfig.Range
+}
+var file_common_buf_shaping_proto_depIdxs = []int32{
+	1, // 0: v2ray.core.common.buf.ShapingConfig.padding:type_name -> v2ray.core.common.buf.ShapingConfig.Range
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_common_buf_shaping_proto_init() }
+func file_common_buf_shaping_proto_init() {
+	if File_common_buf_shaping_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_common_buf_shaping_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShapingConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_common_buf_shaping_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ShapingConfig_Range); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_common_buf_shaping_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_common_buf_shaping_proto_goTypes,
+		DependencyIndexes: file_common_buf_shaping_proto_depIdxs,
+		MessageInfos:      file_common_buf_shaping_proto_msgTypes,
+	}.Build()
+	File_common_buf_shaping_proto = out.File
+	file_common_buf_shaping_proto_rawDesc = nil
+	file_common_buf_shaping_proto_goTypes = nil
+	file_common_buf_shaping_proto_depIdxs = nil
+}
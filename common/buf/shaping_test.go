@@ -0,0 +1,94 @@
+package buf_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+	. "github.com/v2fly/v2ray-core/v5/common/buf"
+)
+
+// recordWriter records the length of every write.
+type recordWriter struct {
+	bytes.Buffer
+	writes []int32
+}
+
+func (w *recordWriter) WriteMultiBuffer(mb MultiBuffer) error {
+	w.writes = append(w.writes, mb.Len())
+	for _, b := range mb {
+		common.Must2(w.Write(b.Bytes()))
+	}
+	ReleaseMulti(mb)
+	return nil
+}
+
+func newPayload(size int32) ([]byte, MultiBuffer) {
+	payload := make([]byte, size)
+	common.Must2(rand.Read(payload))
+	return payload, MergeBytes(nil, payload)
+}
+
+func TestShapingPadding(t *testing.T) {
+	config := &ShapingConfig{
+		Padding: []*ShapingConfig_Range{{Min: 100, Max: 200}, {Min: 0, Max: 0}},
+	}
+	writer := new(recordWriter)
+	shaper := NewShapingWriter(writer, config, true)
+
+	var expected []byte
+	for _, size := range []int32{100, Size * 2, 300} {
+		payload, mb := newPayload(size)
+		expected = append(expected, payload...)
+		common.Must(shaper.WriteMultiBuffer(mb))
+	}
+	if writer.Len() <= len(expected) {
+		t.Error("expected padding in ", writer.Len(), " bytes")
+	}
+
+	reader := NewPaddingReader(&writer.Buffer)
+	var actual []byte
+	for {
+		mb, err := reader.ReadMultiBuffer()
+		if err == io.EOF {
+			break
+		}
+		common.Must(err)
+		for _, b := range mb {
+			actual = append(actual, b.Bytes()...)
+		}
+		ReleaseMulti(mb)
+	}
+	if r := cmp.Diff(actual, expected); r != "" {
+		t.Error(r)
+	}
+}
+
+func TestShapingRecordSize(t *testing.T) {
+	writer := new(recordWriter)
+	shaper := NewShapingWriter(writer, &ShapingConfig{RecordSize: 1000}, false)
+
+	payload, mb := newPayload(2500)
+	common.Must(shaper.WriteMultiBuffer(mb))
+
+	if r := cmp.Diff(writer.writes, []int32{1000, 1000, 500}); r != "" {
+		t.Error(r)
+	}
+	if r := cmp.Diff(writer.Bytes(), payload); r != "" {
+		t.Error(r)
+	}
+}
+
+func TestShapingWithoutPadding(t *testing.T) {
+	writer := new(recordWriter)
+	config := &ShapingConfig{
+		Padding: []*ShapingConfig_Range{{Min: 100, Max: 200}},
+	}
+	if shaper := NewShapingWriter(writer, config, false); shaper != Writer(writer) {
+		t.Error("expected the writer to be unchanged if padding is not negotiated")
+	}
+}
@@ -0,0 +1,200 @@
+package mmdb
+
+import (
+	"encoding/binary"
+	"math"
+	"math/big"
+)
+
+// Data types of the data section, see https://maxmind.github.io/MaxMind-DB/
+const (
+	typeExtended = iota
+	typePointer
+	typeString
+	typeDouble
+	typeBytes
+	typeUint16
+	typeUint32
+	typeMap
+	typeInt32
+	typeUint64
+	typeUint128
+	typeArray
+	typeContainer
+	typeEndMarker
+	typeBool
+	typeFloat
+)
+
+const maxDecodeDepth = 512
+
+type decoder struct {
+	buf []byte
+}
+
+// decode decodes the value at the offset, and returns the offset of the next value.
+func (d *decoder) decode(offset int, depth int) (interface{}, int, error) {
+	if depth > maxDecodeDepth {
+		return nil, 0, newError("data is nested too deeply")
+	}
+	if offset >= len(d.buf) {
+		return nil, 0, errInvalidDatabase
+	}
+	ctrl := d.buf[offset]
+	offset++
+
+	typ := int(ctrl >> 5)
+	if typ == typePointer {
+		pointer, next, err := d.decodePointer(ctrl, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, _, err := d.decode(pointer, depth+1)
+		return value, next, err
+	}
+	if typ == typeExtended {
+		if offset >= len(d.buf) {
+			return nil, 0, errInvalidDatabase
+		}
+		typ = 7 + int(d.buf[offset])
+		offset++
+	}
+
+	size := int(ctrl & 0x1f)
+	if size >= 29 {
+		n := size - 28
+		if offset+n > len(d.buf) {
+			return nil, 0, errInvalidDatabase
+		}
+		v := 0
+		for _, b := range d.buf[offset : offset+n] {
+			v = v<<8 | int(b)
+		}
+		offset += n
+		switch size {
+		case 29:
+			size = 29 + v
+		case 30:
+			size = 285 + v
+		default:
+			size = 65821 + v
+		}
+	}
+
+	// Each entry takes at least one byte per value, so sizes larger than the rest of the data are
+	// rejected before allocating for them.
+	switch typ {
+	case typeMap:
+		if size > (len(d.buf)-offset)/2 {
+			return nil, 0, errInvalidDatabase
+		}
+		m := make(map[string]interface{}, size)
+		for i := 0; i < size; i++ {
+			key, next, err := d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			k, ok := key.(string)
+			if !ok {
+				return nil, 0, newError("map key is not a string")
+			}
+			value, next, err := d.decode(next, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			m[k] = value
+			offset = next
+		}
+		return m, offset, nil
+	case typeArray:
+		if size > len(d.buf)-offset {
+			return nil, 0, errInvalidDatabase
+		}
+		a := make([]interface{}, 0, size)
+		for i := 0; i < size; i++ {
+			value, next, err := d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			a = append(a, value)
+			offset = next
+		}
+		return a, offset, nil
+	case typeBool:
+		return size != 0, offset, nil
+	}
+
+	if offset+size > len(d.buf) {
+		return nil, 0, errInvalidDatabase
+	}
+	b := d.buf[offset : offset+size]
+	offset += size
+
+	switch typ {
+	case typeString:
+		return string(b), offset, nil
+	case typeBytes:
+		return append([]byte(nil), b...), offset, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, errInvalidDatabase
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), offset, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, errInvalidDatabase
+		}
+		return math.Float32frombits(binary.BigEndian.Uint32(b)), offset, nil
+	case typeUint16, typeUint32, typeUint64:
+		maxSize := 8
+		switch typ {
+		case typeUint16:
+			maxSize = 2
+		case typeUint32:
+			maxSize = 4
+		}
+		if size > maxSize {
+			return nil, 0, errInvalidDatabase
+		}
+		return decodeUint(b), offset, nil
+	case typeInt32:
+		if size > 4 {
+			return nil, 0, errInvalidDatabase
+		}
+		return int64(int32(decodeUint(b))), offset, nil
+	case typeUint128:
+		if size > 16 {
+			return nil, 0, errInvalidDatabase
+		}
+		return new(big.Int).SetBytes(b), offset, nil
+	default:
+		return nil, 0, newError("unsupported data type ", typ)
+	}
+}
+
+func (d *decoder) decodePointer(ctrl byte, offset int) (int, int, error) {
+	ss := int(ctrl>>3) & 0x3
+	n := ss + 1
+	if offset+n > len(d.buf) {
+		return 0, 0, errInvalidDatabase
+	}
+	p := int(decodeUint(d.buf[offset : offset+n]))
+	vvv := int(ctrl & 0x7)
+	switch ss {
+	case 0:
+		p = vvv<<8 | p
+	case 1:
+		p = (vvv<<16 | p) + 2048
+	case 2:
+		p = (vvv<<24 | p) + 526336
+	}
+	return p, offset + n, nil
+}
+
+func decodeUint(b []byte) uint64 {
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
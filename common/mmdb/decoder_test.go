@@ -0,0 +1,46 @@
+package mmdb
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDecodePointer(t *testing.T) {
+	d := &decoder{buf: []byte{
+		// "en"
+		0x42, 'e', 'n',
+		// {"name": -> "en", "code": uint32 300, "tags": [true, "en"]}
+		0xe3,
+		0x44, 'n', 'a', 'm', 'e', 0x20, 0x00,
+		0x44, 'c', 'o', 'd', 'e', 0xc2, 0x01, 0x2c,
+		0x44, 't', 'a', 'g', 's', 0x02, 0x04, 0x01, 0x07, 0x20, 0x00,
+	}}
+	value, next, err := d.decode(3, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next != len(d.buf) {
+		t.Error("unexpected next offset ", next)
+	}
+	expected := map[string]interface{}{
+		"name": "en",
+		"code": uint64(300),
+		"tags": []interface{}{true, "en"},
+	}
+	if r := cmp.Diff(value, expected); r != "" {
+		t.Error(r)
+	}
+
+	if _, _, err := (&decoder{buf: []byte{0x44, 'e'}}).decode(0, 0); err == nil {
+		t.Error("expected error for truncated data")
+	}
+
+	// Sizes of maps and arrays larger than the data are rejected before allocating.
+	if _, _, err := (&decoder{buf: []byte{0xff, 0xff, 0xff, 0xff, 0x44}}).decode(0, 0); err == nil {
+		t.Error("expected error for map larger than the data")
+	}
+	if _, _, err := (&decoder{buf: []byte{0x1f, 0x04, 0xff, 0xff, 0xff}}).decode(0, 0); err == nil {
+		t.Error("expected error for array larger than the data")
+	}
+}
@@ -0,0 +1,192 @@
+// Package mmdbtest builds small MaxMind DBs for tests.
+package mmdbtest
+
+import (
+	"encoding/binary"
+	"net"
+	"sort"
+)
+
+// Network is a network and its data in a database.
+type Network struct {
+	CIDR string
+	Data map[string]interface{}
+}
+
+type record struct {
+	node *node
+	data int
+}
+
+type node struct {
+	id      uint32
+	records [2]record
+}
+
+func bit(ip net.IP, i int) int {
+	return int(ip[i>>3]>>(7-i&7)) & 1
+}
+
+func (n *node) child(b int) *node {
+	if n.records[b].node == nil {
+		n.records[b] = record{node: new(node)}
+	}
+	return n.records[b].node
+}
+
+// Build builds an IPv6 database with the record size of 24, 28 or 32 bits. Values of the data can be strings,
+// uint16, uint32, uint64, maps and slices. IPv4 networks are stored in ::/96, which is aliased from ::ffff:0:0/96.
+func Build(databaseType string, recordSize int, networks ...Network) []byte {
+	root := new(node)
+	var data []byte
+	for _, network := range networks {
+		_, ipNet, err := net.ParseCIDR(network.CIDR)
+		if err != nil {
+			panic(err)
+		}
+		prefix, _ := ipNet.Mask.Size()
+		ip := ipNet.IP.To16()
+		if ipNet.IP.To4() != nil {
+			prefix += 96
+			ip = append(make(net.IP, 12), ipNet.IP.To4()...)
+		}
+		n := root
+		for i := 0; i < prefix-1; i++ {
+			n = n.child(bit(ip, i))
+		}
+		n.records[bit(ip, prefix-1)] = record{data: len(data) + 1}
+		data = append(data, encode(network.Data)...)
+	}
+
+	ipv4 := root
+	for i := 0; i < 96; i++ {
+		ipv4 = ipv4.child(0)
+	}
+	n := root
+	mapped := net.ParseIP("::ffff:0:0")
+	for i := 0; i < 95; i++ {
+		n = n.child(bit(mapped, i))
+	}
+	n.records[bit(mapped, 95)] = record{node: ipv4}
+
+	var nodes []*node
+	visited := map[*node]bool{root: true}
+	for queue := []*node{root}; len(queue) > 0; queue = queue[1:] {
+		n := queue[0]
+		n.id = uint32(len(nodes))
+		nodes = append(nodes, n)
+		for _, r := range n.records {
+			if r.node != nil && !visited[r.node] {
+				visited[r.node] = true
+				queue = append(queue, r.node)
+			}
+		}
+	}
+
+	nodeCount := uint32(len(nodes))
+	value := func(r record) uint32 {
+		switch {
+		case r.node != nil:
+			return r.node.id
+		case r.data > 0:
+			return nodeCount + 16 + uint32(r.data-1)
+		default:
+			return nodeCount
+		}
+	}
+	var content []byte
+	for _, n := range nodes {
+		left, right := value(n.records[0]), value(n.records[1])
+		switch recordSize {
+		case 24:
+			content = append(content, byte(left>>16), byte(left>>8), byte(left), byte(right>>16), byte(right>>8), byte(right))
+		case 28:
+			content = append(content, byte(left>>16), byte(left>>8), byte(left), byte(left>>20)&0xf0|byte(right>>24)&0x0f, byte(right>>16), byte(right>>8), byte(right))
+		case 32:
+			content = binary.BigEndian.AppendUint32(content, left)
+			content = binary.BigEndian.AppendUint32(content, right)
+		default:
+			panic("unsupported record size")
+		}
+	}
+	content = append(content, make([]byte, 16)...)
+	content = append(content, data...)
+	content = append(content, "\xab\xcd\xefMaxMind.com"...)
+	content = append(content, encode(map[string]interface{}{
+		"binary_format_major_version": uint16(2),
+		"binary_format_minor_version": uint16(0),
+		"database_type":               databaseType,
+		"ip_version":                  uint16(6),
+		"node_count":                  nodeCount,
+		"record_size":                 uint16(recordSize),
+	})...)
+	return content
+}
+
+func encode(value interface{}) []byte {
+	switch v := value.(type) {
+	case string:
+		return append(header(2, len(v)), v...)
+	case uint16:
+		return encodeUint(5, uint64(v))
+	case uint32:
+		return encodeUint(6, uint64(v))
+	case uint64:
+		return encodeUint(9, v)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b := header(7, len(v))
+		for _, k := range keys {
+			b = append(b, encode(k)...)
+			b = append(b, encode(v[k])...)
+		}
+		return b
+	case []interface{}:
+		b := header(11, len(v))
+		for _, e := range v {
+			b = append(b, encode(e)...)
+		}
+		return b
+	default:
+		panic("unsupported type")
+	}
+}
+
+func encodeUint(typ int, v uint64) []byte {
+	var b []byte
+	for ; v > 0; v >>= 8 {
+		b = append([]byte{byte(v)}, b...)
+	}
+	return append(header(typ, len(b)), b...)
+}
+
+func header(typ int, size int) []byte {
+	var ctrl byte
+	var extended []byte
+	if typ > 7 {
+		extended = []byte{byte(typ - 7)}
+	} else {
+		ctrl = byte(typ) << 5
+	}
+	var sizeBytes []byte
+	switch {
+	case size < 29:
+		ctrl |= byte(size)
+	case size < 285:
+		ctrl |= 29
+		sizeBytes = []byte{byte(size - 29)}
+	case size < 65821:
+		ctrl |= 30
+		sizeBytes = []byte{byte((size - 285) >> 8), byte(size - 285)}
+	default:
+		ctrl |= 31
+		size -= 65821
+		sizeBytes = []byte{byte(size >> 16), byte(size >> 8), byte(size)}
+	}
+	b := append([]byte{ctrl}, extended...)
+	return append(b, sizeBytes...)
+}
@@ -0,0 +1,231 @@
+// Package mmdb reads MaxMind DB files, such as the GeoLite2 country and ASN databases.
+package mmdb
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/v2fly/v2ray-core/v5/common/net"
+)
+
+//go:generate go run github.com/v2fly/v2ray-core/v5/common/errors/errorgen
+
+var (
+	metadataStart      = []byte("\xab\xcd\xefMaxMind.com")
+	errInvalidDatabase = newError("invalid MaxMind DB")
+)
+
+// The metadata is stored in the last 128KiB of the file.
+const maxMetadataSize = 128 * 1024
+
+// Metadata is the metadata of a MaxMind DB.
+type Metadata struct {
+	NodeCount    uint32
+	RecordSize   uint16
+	IPVersion    uint16
+	DatabaseType string
+}
+
+// Reader reads a MaxMind DB in memory.
+type Reader struct {
+	Metadata
+	tree []byte
+	data decoder
+
+	nodeSize int
+	// ipv4Start is the node for ::/96 in an IPv6 database, where IPv4 addresses are stored.
+	ipv4Start uint32
+}
+
+func metadataIndex(content []byte) int {
+	tail := content
+	if len(tail) > maxMetadataSize {
+		tail = tail[len(tail)-maxMetadataSize:]
+	}
+	idx := bytes.LastIndex(tail, metadataStart)
+	if idx < 0 {
+		return -1
+	}
+	return len(content) - len(tail) + idx
+}
+
+// IsMaxMindDB returns whether the content is a MaxMind DB.
+func IsMaxMindDB(content []byte) bool {
+	return metadataIndex(content) >= 0
+}
+
+// New creates a Reader of the content of a MaxMind DB.
+func New(content []byte) (*Reader, error) {
+	idx := metadataIndex(content)
+	if idx < 0 {
+		return nil, errInvalidDatabase
+	}
+	meta := &decoder{buf: content[idx+len(metadataStart):]}
+	value, _, err := meta.decode(0, 0)
+	if err != nil {
+		return nil, newError("failed to decode metadata").Base(err)
+	}
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errInvalidDatabase
+	}
+	nodeCount, _ := m["node_count"].(uint64)
+	recordSize, _ := m["record_size"].(uint64)
+	ipVersion, _ := m["ip_version"].(uint64)
+	databaseType, _ := m["database_type"].(string)
+
+	r := &Reader{
+		Metadata: Metadata{
+			NodeCount:    uint32(nodeCount),
+			RecordSize:   uint16(recordSize),
+			IPVersion:    uint16(ipVersion),
+			DatabaseType: databaseType,
+		},
+	}
+	switch r.RecordSize {
+	case 24, 28, 32:
+	default:
+		return nil, newError("unsupported record size ", r.RecordSize)
+	}
+	if r.IPVersion != 4 && r.IPVersion != 6 {
+		return nil, newError("unsupported ip version ", r.IPVersion)
+	}
+	r.nodeSize = int(r.RecordSize) / 4
+	treeSize := int(r.NodeCount) * r.nodeSize
+	if treeSize+16 > idx {
+		return nil, errInvalidDatabase
+	}
+	r.tree = content[:treeSize]
+	r.data = decoder{buf: content[treeSize+16 : idx]}
+
+	if r.IPVersion == 6 {
+		node := uint32(0)
+		for i := 0; i < 96 && node < r.NodeCount; i++ {
+			node = r.record(node, 0)
+		}
+		r.ipv4Start = node
+	}
+	return r, nil
+}
+
+// record returns the left (bit 0) or right (bit 1) record of the node.
+func (r *Reader) record(node uint32, bit int) uint32 {
+	b := r.tree[int(node)*r.nodeSize:]
+	switch r.RecordSize {
+	case 24:
+		b = b[bit*3:]
+		return uint32(b[0])<<16 | uint32(b[1])<<8 | uint32(b[2])
+	case 28:
+		if bit == 0 {
+			return uint32(b[3]&0xf0)<<20 | uint32(b[0])<<16 | uint32(b[1])<<8 | uint32(b[2])
+		}
+		return uint32(b[3]&0x0f)<<24 | uint32(b[4])<<16 | uint32(b[5])<<8 | uint32(b[6])
+	default:
+		return binary.BigEndian.Uint32(b[bit*4:])
+	}
+}
+
+// dataOffset returns the offset in the data section that the record points to.
+func (r *Reader) dataOffset(record uint32) (int, error) {
+	offset := int(record) - int(r.NodeCount) - 16
+	if offset < 0 || offset >= len(r.data.buf) {
+		return 0, errInvalidDatabase
+	}
+	return offset, nil
+}
+
+// Lookup returns the offset of the data of the IP address, or -1 if the IP address is not in the database.
+func (r *Reader) Lookup(ip net.IP) (int, error) {
+	node := uint32(0)
+	bits := ip.To4()
+	if bits != nil {
+		if r.IPVersion == 6 {
+			node = r.ipv4Start
+		}
+	} else {
+		if r.IPVersion == 4 {
+			return -1, nil
+		}
+		bits = ip.To16()
+		if bits == nil {
+			return -1, newError("invalid IP address ", ip)
+		}
+	}
+
+	for i := 0; i < len(bits)*8 && node < r.NodeCount; i++ {
+		node = r.record(node, int(bits[i>>3]>>(7-i&7))&1)
+	}
+	switch {
+	case node == r.NodeCount:
+		return -1, nil
+	case node > r.NodeCount:
+		return r.dataOffset(node)
+	default:
+		return -1, errInvalidDatabase
+	}
+}
+
+// Decode decodes the data at the offset. Maps are decoded as map[string]interface{}, arrays as []interface{},
+// unsigned integers as uint64 or *big.Int, and signed integers as int64.
+func (r *Reader) Decode(offset int) (interface{}, error) {
+	value, _, err := r.data.decode(offset, 0)
+	return value, err
+}
+
+// Networks calls fn with each network in the database and the offset of its data.
+// IPv4 networks in an IPv6 database are reported as IPv4 networks only once, skipping their aliases.
+func (r *Reader) Networks(fn func(network *net.IPNet, offset int) error) error {
+	ip := make(net.IP, 4)
+	if r.IPVersion == 6 {
+		ip = make(net.IP, 16)
+	}
+	return r.walk(0, ip, 0, fn)
+}
+
+func (r *Reader) walk(node uint32, ip net.IP, depth int, fn func(*net.IPNet, int) error) error {
+	for bit := 0; bit < 2; bit++ {
+		next := ip
+		if bit == 1 {
+			next = append(net.IP(nil), ip...)
+			next[depth>>3] |= 0x80 >> (depth & 7)
+		}
+		record := r.record(node, bit)
+		switch {
+		case record < r.NodeCount:
+			if depth+1 >= len(ip)*8 {
+				return errInvalidDatabase
+			}
+			if r.IPVersion == 6 && record == r.ipv4Start && !(depth+1 == 96 && isZero(next[:12])) {
+				continue
+			}
+			if err := r.walk(record, next, depth+1, fn); err != nil {
+				return err
+			}
+		case record > r.NodeCount:
+			offset, err := r.dataOffset(record)
+			if err != nil {
+				return err
+			}
+			if err := fn(network(next, depth+1), offset); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func network(ip net.IP, prefix int) *net.IPNet {
+	if len(ip) == net.IPv6len && prefix >= 96 && isZero(ip[:12]) {
+		return &net.IPNet{IP: append(net.IP(nil), ip[12:]...), Mask: net.CIDRMask(prefix-96, 32)}
+	}
+	return &net.IPNet{IP: append(net.IP(nil), ip...), Mask: net.CIDRMask(prefix, len(ip)*8)}
+}
+
+func isZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
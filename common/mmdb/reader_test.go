@@ -0,0 +1,75 @@
+package mmdb_test
+
+import (
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/common/mmdb"
+	"github.com/v2fly/v2ray-core/v5/common/mmdb/mmdbtest"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+)
+
+func TestReader(t *testing.T) {
+	networks := []mmdbtest.Network{
+		{CIDR: "1.0.0.0/24", Data: map[string]interface{}{"country": map[string]interface{}{"iso_code": "AU"}}},
+		{CIDR: "8.8.8.0/24", Data: map[string]interface{}{"autonomous_system_number": uint32(15169)}},
+		{CIDR: "2001:4860::/32", Data: map[string]interface{}{"autonomous_system_number": uint32(15169)}},
+	}
+	for _, recordSize := range []int{24, 28, 32} {
+		r, err := mmdb.New(mmdbtest.Build("Test", recordSize, networks...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.DatabaseType != "Test" || r.IPVersion != 6 || int(r.RecordSize) != recordSize {
+			t.Error("unexpected metadata ", r.Metadata)
+		}
+
+		for _, test := range []struct {
+			ip  string
+			asn uint64
+		}{
+			{"8.8.8.8", 15169},
+			{"::ffff:8.8.4.4", 0},
+			{"::ffff:8.8.8.8", 15169},
+			{"2001:4860:4860::8888", 15169},
+			{"2001:4861::1", 0},
+		} {
+			offset, err := r.Lookup(net.ParseIP(test.ip))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.asn == 0 {
+				if offset != -1 {
+					t.Error("expected ", test.ip, " not found")
+				}
+				continue
+			}
+			value, err := r.Decode(offset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if asn := value.(map[string]interface{})["autonomous_system_number"]; asn != test.asn {
+				t.Error("expected asn ", test.asn, " of ", test.ip, ", but got ", asn)
+			}
+		}
+
+		var found []string
+		if err := r.Networks(func(network *net.IPNet, offset int) error {
+			found = append(found, network.String())
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if len(found) != len(networks) {
+			t.Fatal("unexpected networks ", found)
+		}
+		for idx, network := range networks {
+			if found[idx] != network.CIDR {
+				t.Error("expected network ", network.CIDR, ", but got ", found[idx])
+			}
+		}
+	}
+
+	if _, err := mmdb.New([]byte("not a database")); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/v2fly/v2ray-core/v5/common"
@@ -14,6 +15,7 @@ import (
 	"github.com/v2fly/v2ray-core/v5/common/session"
 	"github.com/v2fly/v2ray-core/v5/common/signal/done"
 	"github.com/v2fly/v2ray-core/v5/common/task"
+	"github.com/v2fly/v2ray-core/v5/features/stats"
 	"github.com/v2fly/v2ray-core/v5/proxy"
 	"github.com/v2fly/v2ray-core/v5/transport"
 	"github.com/v2fly/v2ray-core/v5/transport/internet"
@@ -23,6 +25,35 @@ import (
 type ClientManager struct {
 	Enabled bool // wheather mux is enabled from user config
 	Picker  WorkerPicker
+
+	// TCPDisabled and UDPDisabled exclude the network from Mux.
+	TCPDisabled bool
+	UDPDisabled bool
+}
+
+// Accepts returns whether connections of the network should be sent through Mux.
+func (m *ClientManager) Accepts(network net.Network) bool {
+	switch network {
+	case net.Network_TCP:
+		return !m.TCPDisabled
+	case net.Network_UDP:
+		return !m.UDPDisabled
+	default:
+		return true
+	}
+}
+
+// Start implements common.Runnable.
+func (m *ClientManager) Start() error {
+	if r, ok := m.Picker.(common.Runnable); ok {
+		return r.Start()
+	}
+	return nil
+}
+
+// Close implements common.Closable.
+func (m *ClientManager) Close() error {
+	return common.Close(m.Picker)
 }
 
 func (m *ClientManager) Dispatch(ctx context.Context, link *transport.Link) error {
@@ -34,6 +65,10 @@ func (m *ClientManager) Dispatch(ctx context.Context, link *transport.Link) erro
 		if worker.Dispatch(ctx, link) {
 			return nil
 		}
+		// Once no more workers are allowed, the picked worker takes the connection beyond its concurrency.
+		if p, ok := m.Picker.(*IncrementalWorkerPicker); ok && p.saturated() && worker.dispatch(ctx, link, true) {
+			return nil
+		}
 	}
 
 	return newError("unable to find an available mux client").AtWarning()
@@ -46,6 +81,17 @@ type WorkerPicker interface {
 type IncrementalWorkerPicker struct {
 	Factory ClientWorkerFactory
 
+	// MaxWorkers limits the number of workers if not zero. Once reached, the least loaded worker is picked
+	// even if it is full.
+	MaxWorkers uint32
+	// MinIdleWorkers is the number of workers that are created in advance and kept open even when idle.
+	MinIdleWorkers uint32
+
+	// WorkerCounter and StreamCounter, if set, report the number of workers and their active connections.
+	// They are updated as workers and connections come and go, and recounted on every cleanup.
+	WorkerCounter stats.Counter
+	StreamCounter stats.Counter
+
 	access      sync.Mutex
 	workers     []*ClientWorker
 	cleanupTask *task.Periodic
@@ -55,11 +101,14 @@ func (p *IncrementalWorkerPicker) cleanupFunc() error {
 	p.access.Lock()
 	defer p.access.Unlock()
 
-	if len(p.workers) == 0 {
+	if len(p.workers) == 0 && p.MinIdleWorkers == 0 {
+		p.report()
 		return newError("no worker")
 	}
 
 	p.cleanup()
+	p.prewarm()
+	p.report()
 	return nil
 }
 
@@ -73,6 +122,76 @@ func (p *IncrementalWorkerPicker) cleanup() {
 	p.workers = activeWorkers
 }
 
+// prewarm creates workers until there are MinIdleWorkers of them that accept new connections, and keeps
+// them open when idle.
+func (p *IncrementalWorkerPicker) prewarm() {
+	var pinned uint32
+	for _, w := range p.workers {
+		keep := pinned < p.MinIdleWorkers && !w.IsClosing()
+		w.keepIdle.Store(keep)
+		if keep {
+			pinned++
+		}
+	}
+	for ; pinned < p.MinIdleWorkers; pinned++ {
+		if p.MaxWorkers > 0 && len(p.workers) >= int(p.MaxWorkers) {
+			return
+		}
+		worker, err := p.Factory.Create()
+		if err != nil {
+			newError("failed to create idle mux worker").Base(err).AtWarning().WriteToLog()
+			return
+		}
+		worker.keepIdle.Store(true)
+		p.add(worker)
+	}
+}
+
+// add adds the worker to the pool, and counts it and its connections.
+func (p *IncrementalWorkerPicker) add(worker *ClientWorker) {
+	p.workers = append(p.workers, worker)
+	worker.countWith(p.WorkerCounter, p.StreamCounter)
+}
+
+// report recounts the workers and streams, correcting the counters.
+func (p *IncrementalWorkerPicker) report() {
+	var workers, streams int64
+	for _, w := range p.workers {
+		if !w.Closed() {
+			workers++
+			streams += int64(w.ActiveConnections())
+		}
+	}
+	if p.WorkerCounter != nil {
+		p.WorkerCounter.Set(workers)
+	}
+	if p.StreamCounter != nil {
+		p.StreamCounter.Set(streams)
+	}
+}
+
+// saturated returns whether no more workers are allowed.
+func (p *IncrementalWorkerPicker) saturated() bool {
+	p.access.Lock()
+	defer p.access.Unlock()
+
+	return p.MaxWorkers > 0 && len(p.workers) >= int(p.MaxWorkers)
+}
+
+// leastLoaded returns the worker with the fewest active connections that still accepts new connections.
+func (p *IncrementalWorkerPicker) leastLoaded() int {
+	picked := -1
+	for idx, w := range p.workers {
+		if w.IsClosing() || w.Closed() {
+			continue
+		}
+		if picked < 0 || w.ActiveConnections() < p.workers[picked].ActiveConnections() {
+			picked = idx
+		}
+	}
+	return picked
+}
+
 func (p *IncrementalWorkerPicker) findAvailable() int {
 	for idx, w := range p.workers {
 		if !w.IsFull() {
@@ -98,20 +217,52 @@ func (p *IncrementalWorkerPicker) pickInternal() (*ClientWorker, bool, error) {
 
 	p.cleanup()
 
+	if p.MaxWorkers > 0 && len(p.workers) >= int(p.MaxWorkers) {
+		if idx := p.leastLoaded(); idx >= 0 {
+			return p.workers[idx], false, nil
+		}
+		return nil, false, newError("too many mux connections")
+	}
+
 	worker, err := p.Factory.Create()
 	if err != nil {
 		return nil, false, err
 	}
-	p.workers = append(p.workers, worker)
+	p.add(worker)
 
+	p.initCleanupTask()
+	return worker, true, nil
+}
+
+func (p *IncrementalWorkerPicker) initCleanupTask() {
 	if p.cleanupTask == nil {
 		p.cleanupTask = &task.Periodic{
 			Interval: time.Second * 30,
 			Execute:  p.cleanupFunc,
 		}
 	}
+}
 
-	return worker, true, nil
+// Start implements common.Runnable. It creates the idle workers in advance.
+func (p *IncrementalWorkerPicker) Start() error {
+	if p.MinIdleWorkers == 0 {
+		return nil
+	}
+	p.access.Lock()
+	p.initCleanupTask()
+	p.access.Unlock()
+	return p.cleanupTask.Start()
+}
+
+// Close implements common.Closable.
+func (p *IncrementalWorkerPicker) Close() error {
+	p.access.Lock()
+	defer p.access.Unlock()
+
+	if p.cleanupTask != nil {
+		return p.cleanupTask.Close()
+	}
+	return nil
 }
 
 func (p *IncrementalWorkerPicker) PickAvailable() (*ClientWorker, error) {
@@ -176,13 +327,23 @@ func (f *DialingWorkerFactory) Create() (*ClientWorker, error) {
 type ClientStrategy struct {
 	MaxConcurrency uint32
 	MaxConnection  uint32
+	// IdleTimeout is the time the worker is kept open without connections. 16 seconds if not set.
+	IdleTimeout time.Duration
+	// Padding sends random padding along with the first frames of every connection.
+	Padding bool
 }
 
+const defaultIdleTimeout = time.Second * 16
+
 type ClientWorker struct {
 	sessionManager *SessionManager
 	link           transport.Link
 	done           *done.Instance
 	strategy       ClientStrategy
+	// keepIdle is set by the picker on workers that are kept open when idle.
+	keepIdle atomic.Bool
+	// counted makes the worker counted by the picker only once.
+	counted sync.Once
 }
 
 var (
@@ -205,6 +366,22 @@ func NewClientWorker(stream transport.Link, s ClientStrategy) (*ClientWorker, er
 	return c, nil
 }
 
+// countWith counts the worker and its connections with the counters, which may be nil.
+func (m *ClientWorker) countWith(workers, streams stats.Counter) {
+	m.counted.Do(func() {
+		m.sessionManager.countWith(streams)
+		if workers == nil {
+			return
+		}
+		workers.Add(1)
+		// The worker may be closed before it is counted.
+		go func() {
+			<-m.done.Wait()
+			workers.Add(-1)
+		}()
+	})
+}
+
 func (m *ClientWorker) TotalConnections() uint32 {
 	return uint32(m.sessionManager.Count())
 }
@@ -219,9 +396,21 @@ func (m *ClientWorker) Closed() bool {
 }
 
 func (m *ClientWorker) monitor() {
-	timer := time.NewTicker(time.Second * 16)
+	idleTimeout := m.strategy.IdleTimeout
+	if idleTimeout <= 0 {
+		idleTimeout = defaultIdleTimeout
+	}
+	interval := idleTimeout / 4
+	if interval < time.Second {
+		interval = time.Second
+	}
+	if interval > defaultIdleTimeout {
+		interval = defaultIdleTimeout
+	}
+	timer := time.NewTicker(interval)
 	defer timer.Stop()
 
+	var idleSince time.Time
 	for {
 		select {
 		case <-m.done.Wait():
@@ -230,8 +419,14 @@ func (m *ClientWorker) monitor() {
 			common.Interrupt(m.link.Reader)
 			return
 		case <-timer.C:
-			size := m.sessionManager.Size()
-			if size == 0 && m.sessionManager.CloseIfNoSession() {
+			if m.sessionManager.Size() != 0 || (m.keepIdle.Load() && !m.IsClosing()) {
+				idleSince = time.Time{}
+				continue
+			}
+			if idleSince.IsZero() {
+				idleSince = time.Now()
+			}
+			if (m.IsClosing() || time.Since(idleSince) >= idleTimeout) && m.sessionManager.CloseIfNoSession() {
 				common.Must(m.done.Close())
 			}
 		}
@@ -251,7 +446,7 @@ func writeFirstPayload(reader buf.Reader, writer *Writer) error {
 	return nil
 }
 
-func fetchInput(ctx context.Context, s *Session, output buf.Writer) {
+func fetchInput(ctx context.Context, s *Session, output buf.Writer, padding bool) {
 	dest := session.OutboundFromContext(ctx).Target
 	transferType := protocol.TransferTypeStream
 	if dest.Network == net.Network_UDP {
@@ -259,6 +454,15 @@ func fetchInput(ctx context.Context, s *Session, output buf.Writer) {
 	}
 	s.transferType = transferType
 	writer := NewWriter(s.ID, dest, output, transferType)
+	if IsPacketAddrDestination(dest) {
+		globalID := NewGlobalID(ctx)
+		s.packetAddr = true
+		writer = NewPacketAddrWriter(s.ID, dest, output, globalID)
+		newError("dispatching full-cone UDP session ", globalID).WriteToLog(session.ExportIDToError(ctx))
+	}
+	if padding {
+		writer.paddingFrames = paddingFrames
+	}
 	defer s.Close()
 	defer writer.Close()
 
@@ -299,7 +503,12 @@ func (m *ClientWorker) IsFull() bool {
 }
 
 func (m *ClientWorker) Dispatch(ctx context.Context, link *transport.Link) bool {
-	if m.IsFull() || m.Closed() {
+	return m.dispatch(ctx, link, false)
+}
+
+// dispatch sends the connection through the worker. If overload is true, the concurrency limit is ignored.
+func (m *ClientWorker) dispatch(ctx context.Context, link *transport.Link, overload bool) bool {
+	if m.IsClosing() || m.Closed() || (!overload && m.IsFull()) {
 		return false
 	}
 
@@ -310,7 +519,7 @@ func (m *ClientWorker) Dispatch(ctx context.Context, link *transport.Link) bool
 	}
 	s.input = link.Reader
 	s.output = link.Writer
-	go fetchInput(ctx, s, m.link.Writer)
+	go fetchInput(ctx, s, m.link.Writer, m.strategy.Padding)
 	return true
 }
 
@@ -343,6 +552,9 @@ func (m *ClientWorker) handleStatusKeep(meta *FrameMetadata, reader *buf.Buffere
 	}
 
 	rr := s.NewReader(reader)
+	if s.packetAddr {
+		rr = &packetAddrReader{reader: rr, source: meta.Target}
+	}
 	err := buf.Copy(rr, s.output)
 	if err != nil && buf.IsWriteError(err) {
 		newError("failed to write to downstream. closing session ", s.ID).Base(err).WriteToLog()
@@ -7,6 +7,7 @@ import (
 
 	"github.com/golang/mock/gomock"
 
+	"github.com/v2fly/v2ray-core/v5/app/stats"
 	"github.com/v2fly/v2ray-core/v5/common"
 	"github.com/v2fly/v2ray-core/v5/common/errors"
 	"github.com/v2fly/v2ray-core/v5/common/mux"
@@ -115,3 +116,129 @@ func TestClientWorkerClose(t *testing.T) {
 
 	common.Must(w2.Close())
 }
+
+func TestIncrementalPickerMaxWorkers(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	r, w := pipe.New(pipe.WithoutSizeLimit())
+	defer w.Close()
+	worker, err := mux.NewClientWorker(transport.Link{Reader: r, Writer: w}, mux.ClientStrategy{MaxConcurrency: 1})
+	common.Must(err)
+
+	factory := mocks.NewMuxClientWorkerFactory(mockCtl)
+	factory.EXPECT().Create().Return(worker, nil).Times(1)
+
+	counter := new(stats.Counter)
+	manager := &mux.ClientManager{
+		Picker: &mux.IncrementalWorkerPicker{
+			Factory:       factory,
+			MaxWorkers:    1,
+			WorkerCounter: counter,
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		tr, tw := pipe.New(pipe.WithoutSizeLimit())
+		defer tw.Close()
+		ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{
+			Target: net.TCPDestination(net.DomainAddress("www.v2fly.org"), 80),
+		})
+		common.Must(manager.Dispatch(ctx, &transport.Link{Reader: tr, Writer: tw}))
+	}
+
+	// The second connection shares the only worker beyond its concurrency.
+	if n := worker.ActiveConnections(); n != 2 {
+		t.Error("expected 2 connections on the worker, but got ", n)
+	}
+	if n := counter.Value(); n != 1 {
+		t.Error("expected 1 worker reported, but got ", n)
+	}
+}
+
+func TestIncrementalPickerMinIdleWorkers(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	factory := mocks.NewMuxClientWorkerFactory(mockCtl)
+	var workers []*mux.ClientWorker
+	for i := 0; i < 2; i++ {
+		r, w := pipe.New(pipe.WithoutSizeLimit())
+		defer w.Close()
+		worker, err := mux.NewClientWorker(transport.Link{Reader: r, Writer: w}, mux.ClientStrategy{
+			IdleTimeout: time.Second,
+		})
+		common.Must(err)
+		workers = append(workers, worker)
+		factory.EXPECT().Create().Return(worker, nil)
+	}
+
+	picker := &mux.IncrementalWorkerPicker{
+		Factory:        factory,
+		MinIdleWorkers: 2,
+	}
+	manager := &mux.ClientManager{Picker: picker}
+	common.Must(manager.Start())
+	defer manager.Close()
+
+	// Idle workers are kept open beyond the idle timeout.
+	time.Sleep(time.Second * 3)
+	for i, worker := range workers {
+		if worker.Closed() {
+			t.Error("worker ", i, " is closed")
+		}
+	}
+}
+
+func TestIncrementalPickerCounters(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	r, w := pipe.New(pipe.WithoutSizeLimit())
+	worker, err := mux.NewClientWorker(transport.Link{Reader: r, Writer: w}, mux.ClientStrategy{})
+	common.Must(err)
+
+	factory := mocks.NewMuxClientWorkerFactory(mockCtl)
+	factory.EXPECT().Create().Return(worker, nil).Times(1)
+
+	workerCounter := new(stats.Counter)
+	streamCounter := new(stats.Counter)
+	manager := &mux.ClientManager{
+		Picker: &mux.IncrementalWorkerPicker{
+			Factory:       factory,
+			WorkerCounter: workerCounter,
+			StreamCounter: streamCounter,
+		},
+	}
+
+	var inputs []*pipe.Writer
+	for i := 0; i < 2; i++ {
+		tr, tw := pipe.New(pipe.WithoutSizeLimit())
+		defer tw.Close()
+		ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{
+			Target: net.TCPDestination(net.DomainAddress("www.v2fly.org"), 80),
+		})
+		common.Must(manager.Dispatch(ctx, &transport.Link{Reader: tr, Writer: tw}))
+		inputs = append(inputs, tw)
+	}
+
+	waitFor := func(counter *stats.Counter, value int64) {
+		deadline := time.Now().Add(5 * time.Second)
+		for counter.Value() != value && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if n := counter.Value(); n != value {
+			t.Error("expected ", value, ", but got ", n)
+		}
+	}
+
+	// Counters follow the workers and streams without waiting for the cleanup.
+	waitFor(workerCounter, 1)
+	waitFor(streamCounter, 2)
+	common.Must(inputs[0].Close())
+	waitFor(streamCounter, 1)
+	common.Must(w.Close())
+	common.Interrupt(r)
+	waitFor(workerCounter, 0)
+	waitFor(streamCounter, 0)
+}
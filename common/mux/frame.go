@@ -51,6 +51,11 @@ Frame format
 2 bytes - port
 n bytes - address
 
+8 bytes - global id, only for new UDP sessions with full-cone semantics
+
+For UDP sessions with full-cone semantics, every keep frame also carries
+the network, port and address of its packet.
+
 */
 
 type FrameMetadata struct {
@@ -58,6 +63,8 @@ type FrameMetadata struct {
 	SessionID     uint16
 	Option        bitmask.Byte
 	SessionStatus SessionStatus
+	// GlobalID identifies a full-cone UDP session across Mux connections.
+	GlobalID GlobalID
 }
 
 func (f FrameMetadata) WriteTo(b *buf.Buffer) error {
@@ -81,6 +88,15 @@ func (f FrameMetadata) WriteTo(b *buf.Buffer) error {
 		if err := addrParser.WriteAddressPort(b, f.Target.Address, f.Target.Port); err != nil {
 			return err
 		}
+
+		if f.Target.Network == net.Network_UDP && !f.GlobalID.IsZero() {
+			common.Must2(b.Write(f.GlobalID[:]))
+		}
+	} else if f.SessionStatus == SessionStatusKeep && f.Target.Network == net.Network_UDP {
+		common.Must(b.WriteByte(byte(TargetNetworkUDP)))
+		if err := addrParser.WriteAddressPort(b, f.Target.Address, f.Target.Port); err != nil {
+			return err
+		}
 	}
 
 	len1 := b.Len()
@@ -117,28 +133,37 @@ func (f *FrameMetadata) UnmarshalFromBuffer(b *buf.Buffer) error {
 	f.SessionID = binary.BigEndian.Uint16(b.BytesTo(2))
 	f.SessionStatus = SessionStatus(b.Byte(2))
 	f.Option = bitmask.Byte(b.Byte(3))
-	f.Target.Network = net.Network_Unknown
+	f.Target = net.Destination{}
+	f.GlobalID = GlobalID{}
+
+	switch {
+	case f.SessionStatus == SessionStatusNew:
+	case f.SessionStatus == SessionStatusKeep && b.Len() > 4 && TargetNetwork(b.Byte(4)) == TargetNetworkUDP:
+	default:
+		return nil
+	}
 
-	if f.SessionStatus == SessionStatusNew {
-		if b.Len() < 8 {
-			return newError("insufficient buffer: ", b.Len())
-		}
-		network := TargetNetwork(b.Byte(4))
-		b.Advance(5)
+	if b.Len() < 8 {
+		return newError("insufficient buffer: ", b.Len())
+	}
+	network := TargetNetwork(b.Byte(4))
+	b.Advance(5)
 
-		addr, port, err := addrParser.ReadAddressPort(nil, b)
-		if err != nil {
-			return newError("failed to parse address and port").Base(err)
-		}
+	addr, port, err := addrParser.ReadAddressPort(nil, b)
+	if err != nil {
+		return newError("failed to parse address and port").Base(err)
+	}
 
-		switch network {
-		case TargetNetworkTCP:
-			f.Target = net.TCPDestination(addr, port)
-		case TargetNetworkUDP:
-			f.Target = net.UDPDestination(addr, port)
-		default:
-			return newError("unknown network type: ", network)
+	switch network {
+	case TargetNetworkTCP:
+		f.Target = net.TCPDestination(addr, port)
+	case TargetNetworkUDP:
+		f.Target = net.UDPDestination(addr, port)
+		if f.SessionStatus == SessionStatusNew && b.Len() == int32(len(f.GlobalID)) {
+			copy(f.GlobalID[:], b.Bytes())
 		}
+	default:
+		return newError("unknown network type: ", network)
 	}
 
 	return nil
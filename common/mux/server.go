@@ -109,7 +109,6 @@ func (w *ServerWorker) handleStatusKeepAlive(meta *FrameMetadata, reader *buf.Bu
 }
 
 func (w *ServerWorker) handleStatusNew(ctx context.Context, meta *FrameMetadata, reader *buf.BufferedReader) error {
-	newError("received request for ", meta.Target).WriteToLog(session.ExportIDToError(ctx))
 	{
 		msg := &log.AccessMessage{
 			To:     meta.Target,
@@ -122,6 +121,11 @@ func (w *ServerWorker) handleStatusNew(ctx context.Context, meta *FrameMetadata,
 		}
 		ctx = log.ContextWithAccessMessage(ctx, msg)
 	}
+	if meta.Target.Network == net.Network_UDP && !meta.GlobalID.IsZero() {
+		return w.handleStatusNewXUDP(ctx, meta, reader)
+	}
+
+	newError("received request for ", meta.Target).WriteToLog(session.ExportIDToError(ctx))
 	link, err := w.dispatcher.Dispatch(ctx, meta.Target)
 	if err != nil {
 		if meta.Option.Has(OptionData) {
@@ -154,6 +158,39 @@ func (w *ServerWorker) handleStatusNew(ctx context.Context, meta *FrameMetadata,
 	return nil
 }
 
+// handleStatusNewXUDP attaches a full-cone UDP session to the Mux session, resuming the one with the same GlobalID if any.
+func (w *ServerWorker) handleStatusNewXUDP(ctx context.Context, meta *FrameMetadata, reader *buf.BufferedReader) error {
+	x, resumed, err := xudpSessions.getOrCreate(ctx, meta.GlobalID, w.dispatcher)
+	if err != nil {
+		if meta.Option.Has(OptionData) {
+			buf.Copy(NewStreamReader(reader), buf.Discard)
+		}
+		return newError("failed to dispatch full-cone UDP session.").Base(err)
+	}
+	if resumed {
+		newError("resumed full-cone UDP session ", meta.GlobalID).WriteToLog(session.ExportIDToError(ctx))
+		// New sessions are logged by the dispatcher, while resumed ones are not dispatched again.
+		if msg := log.AccessMessageFromContext(ctx); msg != nil {
+			log.Record(msg)
+		}
+	} else {
+		newError("received full-cone UDP session ", meta.GlobalID, " for ", meta.Target).WriteToLog(session.ExportIDToError(ctx))
+	}
+
+	s := &Session{
+		parent:       w.sessionManager,
+		ID:           meta.SessionID,
+		transferType: protocol.TransferTypePacket,
+		xudp:         x,
+	}
+	w.sessionManager.Add(s)
+	x.attach(s, w.link.Writer)
+	if !meta.Option.Has(OptionData) {
+		return nil
+	}
+	return x.writePackets(s.NewReader(reader), meta.Target)
+}
+
 func (w *ServerWorker) handleStatusKeep(meta *FrameMetadata, reader *buf.BufferedReader) error {
 	if !meta.Option.Has(OptionData) {
 		return nil
@@ -169,6 +206,9 @@ func (w *ServerWorker) handleStatusKeep(meta *FrameMetadata, reader *buf.Buffere
 	}
 
 	rr := s.NewReader(reader)
+	if s.xudp != nil {
+		return s.xudp.writePackets(rr, meta.Target)
+	}
 	err := buf.Copy(rr, s.output)
 
 	if err != nil && buf.IsWriteError(err) {
@@ -189,6 +229,10 @@ func (w *ServerWorker) handleStatusKeep(meta *FrameMetadata, reader *buf.Buffere
 
 func (w *ServerWorker) handleStatusEnd(meta *FrameMetadata, reader *buf.BufferedReader) error {
 	if s, found := w.sessionManager.Get(meta.SessionID); found {
+		if s.xudp != nil {
+			// The client has ended the full-cone UDP session, so it will not be resumed.
+			s.xudp.Close()
+		}
 		if meta.Option.Has(OptionError) {
 			common.Interrupt(s.input)
 			common.Interrupt(s.output)
@@ -6,6 +6,7 @@ import (
 	"github.com/v2fly/v2ray-core/v5/common"
 	"github.com/v2fly/v2ray-core/v5/common/buf"
 	"github.com/v2fly/v2ray-core/v5/common/protocol"
+	"github.com/v2fly/v2ray-core/v5/features/stats"
 )
 
 type SessionManager struct {
@@ -13,6 +14,8 @@ type SessionManager struct {
 	sessions map[uint16]*Session
 	count    uint16
 	closed   bool
+	// counter, if set, counts the sessions as they are added and removed.
+	counter stats.Counter
 }
 
 func NewSessionManager() *SessionManager {
@@ -43,6 +46,23 @@ func (m *SessionManager) Count() int {
 	return int(m.count)
 }
 
+// countWith counts the sessions with the counter from now on, including the existing ones.
+func (m *SessionManager) countWith(counter stats.Counter) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.counter = counter
+	if counter != nil && !m.closed {
+		counter.Add(int64(len(m.sessions)))
+	}
+}
+
+func (m *SessionManager) addCount(delta int) {
+	if m.counter != nil && delta != 0 {
+		m.counter.Add(int64(delta))
+	}
+}
+
 func (m *SessionManager) Allocate() *Session {
 	m.Lock()
 	defer m.Unlock()
@@ -57,6 +77,7 @@ func (m *SessionManager) Allocate() *Session {
 		parent: m,
 	}
 	m.sessions[s.ID] = s
+	m.addCount(1)
 	return s
 }
 
@@ -69,6 +90,9 @@ func (m *SessionManager) Add(s *Session) {
 	}
 
 	m.count++
+	if _, found := m.sessions[s.ID]; !found {
+		m.addCount(1)
+	}
 	m.sessions[s.ID] = s
 }
 
@@ -80,7 +104,10 @@ func (m *SessionManager) Remove(id uint16) {
 		return
 	}
 
-	delete(m.sessions, id)
+	if _, found := m.sessions[id]; found {
+		delete(m.sessions, id)
+		m.addCount(-1)
+	}
 
 	if len(m.sessions) == 0 {
 		m.sessions = make(map[uint16]*Session, 16)
@@ -124,10 +151,14 @@ func (m *SessionManager) Close() error {
 	}
 
 	m.closed = true
+	m.addCount(-len(m.sessions))
 
 	for _, s := range m.sessions {
 		common.Close(s.input)
 		common.Close(s.output)
+		if s.xudp != nil {
+			s.xudp.detach(s)
+		}
 	}
 
 	m.sessions = nil
@@ -141,12 +172,19 @@ type Session struct {
 	parent       *SessionManager
 	ID           uint16
 	transferType protocol.TransferType
+	// packetAddr is set on clients for full-cone UDP sessions, whose responses carry their own addresses.
+	packetAddr bool
+	// xudp is set on servers for full-cone UDP sessions.
+	xudp *xudpSession
 }
 
 // Close closes all resources associated with this session.
 func (s *Session) Close() error {
 	common.Close(s.output)
 	common.Close(s.input)
+	if s.xudp != nil {
+		s.xudp.detach(s)
+	}
 	s.parent.Remove(s.ID)
 	return nil
 }
@@ -1,10 +1,14 @@
 package mux
 
 import (
+	"crypto/rand"
+
 	"github.com/v2fly/v2ray-core/v5/common"
 	"github.com/v2fly/v2ray-core/v5/common/buf"
+	"github.com/v2fly/v2ray-core/v5/common/dice"
 	"github.com/v2fly/v2ray-core/v5/common/errors"
 	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/net/packetaddr"
 	"github.com/v2fly/v2ray-core/v5/common/protocol"
 	"github.com/v2fly/v2ray-core/v5/common/serial"
 )
@@ -16,8 +20,18 @@ type Writer struct {
 	followup     bool
 	hasError     bool
 	transferType protocol.TransferType
+	// globalID is set for full-cone UDP sessions, whose packets carry their own addresses.
+	globalID GlobalID
+	// paddingFrames is the number of following data frames that are sent with padding.
+	paddingFrames int
 }
 
+const (
+	// paddingFrames is the number of data frames of a connection that are sent with padding.
+	paddingFrames = 8
+	maxPaddingLen = 256
+)
+
 func NewWriter(id uint16, dest net.Destination, writer buf.Writer, transferType protocol.TransferType) *Writer {
 	return &Writer{
 		id:           id,
@@ -28,6 +42,19 @@ func NewWriter(id uint16, dest net.Destination, writer buf.Writer, transferType
 	}
 }
 
+// NewPacketAddrWriter creates a Writer for a full-cone UDP session. The address of every packet
+// written to it is extracted and sent in the frame of the packet.
+func NewPacketAddrWriter(id uint16, dest net.Destination, writer buf.Writer, globalID GlobalID) *Writer {
+	return &Writer{
+		id:           id,
+		dest:         dest,
+		writer:       writer,
+		followup:     false,
+		transferType: protocol.TransferTypePacket,
+		globalID:     globalID,
+	}
+}
+
 func NewResponseWriter(id uint16, writer buf.Writer, transferType protocol.TransferType) *Writer {
 	return &Writer{
 		id:           id,
@@ -40,7 +67,6 @@ func NewResponseWriter(id uint16, writer buf.Writer, transferType protocol.Trans
 func (w *Writer) getNextFrameMeta() FrameMetadata {
 	meta := FrameMetadata{
 		SessionID: w.id,
-		Target:    w.dest,
 	}
 
 	if w.followup {
@@ -48,6 +74,8 @@ func (w *Writer) getNextFrameMeta() FrameMetadata {
 	} else {
 		w.followup = true
 		meta.SessionStatus = SessionStatusNew
+		meta.Target = w.dest
+		meta.GlobalID = w.globalID
 	}
 
 	return meta
@@ -81,11 +109,41 @@ func writeMetaWithFrame(writer buf.Writer, meta FrameMetadata, data buf.MultiBuf
 	return writer.WriteMultiBuffer(mb2)
 }
 
+// writePadding sends random padding in a KeepAlive frame, which is discarded by the receiver.
+func (w *Writer) writePadding() error {
+	meta := FrameMetadata{
+		SessionID:     w.id,
+		SessionStatus: SessionStatusKeepAlive,
+	}
+	meta.Option.Set(OptionData)
+
+	padding := buf.New()
+	common.Must2(padding.ReadFullFrom(rand.Reader, int32(1+dice.Roll(maxPaddingLen))))
+	return writeMetaWithFrame(w.writer, meta, buf.MultiBuffer{padding})
+}
+
 func (w *Writer) writeData(mb buf.MultiBuffer) error {
 	meta := w.getNextFrameMeta()
 	meta.Option.Set(OptionData)
 
-	return writeMetaWithFrame(w.writer, meta, mb)
+	if !w.globalID.IsZero() {
+		b, addr, err := packetaddr.ExtractAddressFromPacket(mb[0])
+		if err != nil {
+			buf.ReleaseMulti(mb)
+			return newError("invalid packet address").Base(err)
+		}
+		mb[0] = b
+		meta.Target = net.DestinationFromAddr(addr)
+	}
+
+	if err := writeMetaWithFrame(w.writer, meta, mb); err != nil {
+		return err
+	}
+	if w.paddingFrames > 0 {
+		w.paddingFrames--
+		return w.writePadding()
+	}
+	return nil
 }
 
 // WriteMultiBuffer implements buf.Writer.
@@ -0,0 +1,300 @@
+package mux
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"sync"
+	"time"
+
+	core "github.com/v2fly/v2ray-core/v5"
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/buf"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/net/packetaddr"
+	"github.com/v2fly/v2ray-core/v5/common/protocol"
+	"github.com/v2fly/v2ray-core/v5/common/session"
+	"github.com/v2fly/v2ray-core/v5/features/routing"
+)
+
+// xudpSessionTimeout is how long a full-cone UDP session is kept for resumption after its Mux connection is gone.
+const xudpSessionTimeout = time.Minute
+
+// GlobalID identifies a full-cone UDP session. Sessions from the same inbound source share the same GlobalID,
+// so that the session can be resumed on another Mux connection with the same mapping on the server.
+type GlobalID [8]byte
+
+// IsZero returns true if the GlobalID is not set.
+func (id GlobalID) IsZero() bool {
+	return id == GlobalID{}
+}
+
+func (id GlobalID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+var globalIDKey = func() []byte {
+	key := make([]byte, 32)
+	common.Must2(io.ReadFull(rand.Reader, key))
+	return key
+}()
+
+// NewGlobalID returns the GlobalID for the full-cone UDP session in the context.
+func NewGlobalID(ctx context.Context) GlobalID {
+	var id GlobalID
+	if inbound := session.InboundFromContext(ctx); inbound != nil && inbound.Source.IsValid() {
+		h := hmac.New(sha256.New, globalIDKey)
+		h.Write([]byte(inbound.Source.String()))
+		copy(id[:], h.Sum(nil))
+	} else {
+		common.Must2(io.ReadFull(rand.Reader, id[:]))
+	}
+	if id.IsZero() {
+		id[0] = 1
+	}
+	return id
+}
+
+// IsPacketAddrDestination returns true if the session to dest carries packets with their addresses, which
+// is sent as a full-cone UDP session over Mux.
+func IsPacketAddrDestination(dest net.Destination) bool {
+	if dest.Network != net.Network_UDP {
+		return false
+	}
+	_, err := packetaddr.GetDestinationSubsetOf(dest)
+	return err == nil
+}
+
+// xudpKey identifies a full-cone UDP session on the server. GlobalIDs are chosen by clients, so sessions
+// are scoped to the inbound and the user they come from, and are never resumed by other users.
+type xudpKey struct {
+	inbound string
+	email   string
+	id      GlobalID
+}
+
+type xudpManager struct {
+	access   sync.Mutex
+	sessions map[xudpKey]*xudpSession
+}
+
+var xudpSessions = &xudpManager{sessions: make(map[xudpKey]*xudpSession)}
+
+// getOrCreate returns the full-cone UDP session of the id, and dispatches a new one if there is none.
+func (m *xudpManager) getOrCreate(ctx context.Context, id GlobalID, dispatcher routing.Dispatcher) (*xudpSession, bool, error) {
+	key := xudpKey{id: id}
+	var user *protocol.MemoryUser
+	if inbound := session.InboundFromContext(ctx); inbound != nil {
+		key.inbound = inbound.Tag
+		user = inbound.User
+		if user != nil {
+			key.email = user.Email
+		}
+	}
+
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	if s, found := m.sessions[key]; found {
+		if !sameUser(s.owner, user) {
+			return nil, false, newError("full-cone UDP session ", id, " belongs to another user")
+		}
+		return s, true, nil
+	}
+
+	// The session outlives the Mux connection it is created on.
+	conn, err := packetaddr.CreatePacketAddrConn(core.ToBackgroundDetachedContext(ctx), dispatcher, false)
+	if err != nil {
+		return nil, false, err
+	}
+	s := &xudpSession{
+		key:    key,
+		owner:  user,
+		conn:   conn,
+		parent: m,
+	}
+	m.sessions[key] = s
+	go s.fetchOutput()
+	return s, false, nil
+}
+
+func (m *xudpManager) remove(s *xudpSession) {
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	if m.sessions[s.key] == s {
+		delete(m.sessions, s.key)
+	}
+}
+
+// sameUser returns true if both users are the same user, or neither is set.
+func sameUser(a, b *protocol.MemoryUser) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Email != b.Email {
+		return false
+	}
+	if a.Account == nil || b.Account == nil {
+		return a.Account == nil && b.Account == nil
+	}
+	return a.Account.Equals(b.Account)
+}
+
+// xudpSession is a full-cone UDP session on the server. It is attached to one Mux session at a time.
+type xudpSession struct {
+	key    xudpKey
+	owner  *protocol.MemoryUser
+	conn   net.PacketConn
+	parent *xudpManager
+
+	access sync.Mutex
+	output buf.Writer
+	mux    *Session
+	expire *time.Timer
+	closed bool
+}
+
+// attach sends the responses of the session to the Mux session s.
+func (x *xudpSession) attach(s *Session, output buf.Writer) {
+	x.access.Lock()
+	defer x.access.Unlock()
+
+	if x.expire != nil {
+		x.expire.Stop()
+		x.expire = nil
+	}
+	x.mux = s
+	x.output = output
+}
+
+// detach stops sending responses to the Mux session s, and closes the session if it is not resumed in time.
+func (x *xudpSession) detach(s *Session) {
+	x.access.Lock()
+	defer x.access.Unlock()
+
+	if x.mux != s || x.closed {
+		return
+	}
+	x.mux = nil
+	x.output = nil
+	x.expire = time.AfterFunc(xudpSessionTimeout, func() {
+		x.Close()
+	})
+}
+
+// Close closes the session and the underlying UDP association.
+func (x *xudpSession) Close() error {
+	x.access.Lock()
+	if x.closed {
+		x.access.Unlock()
+		return nil
+	}
+	x.closed = true
+	if x.expire != nil {
+		x.expire.Stop()
+	}
+	x.access.Unlock()
+
+	x.parent.remove(x)
+	return x.conn.Close()
+}
+
+// writePackets sends the packets from reader to dest.
+func (x *xudpSession) writePackets(reader buf.Reader, dest net.Destination) error {
+	for {
+		mb, err := reader.ReadMultiBuffer()
+		for _, b := range mb {
+			switch {
+			case dest.Network != net.Network_UDP:
+			case dest.Address.Family().IsDomain():
+				newError("dropping packet to domain ", dest, " in full-cone UDP session").AtDebug().WriteToLog()
+			default:
+				if _, werr := x.conn.WriteTo(b.Bytes(), &net.UDPAddr{IP: dest.Address.IP(), Port: int(dest.Port)}); werr != nil {
+					newError("failed to write packet to ", dest).Base(werr).AtDebug().WriteToLog()
+				}
+			}
+		}
+		buf.ReleaseMulti(mb)
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// fetchOutput sends the responses of the session to the attached Mux session.
+func (x *xudpSession) fetchOutput() {
+	defer func() {
+		x.access.Lock()
+		s, output := x.mux, x.output
+		x.access.Unlock()
+		x.Close()
+		if output != nil {
+			writer := NewResponseWriter(s.ID, output, s.transferType)
+			writer.Close()
+			s.Close()
+		}
+	}()
+
+	for {
+		b := buf.New()
+		n, addr, err := x.conn.ReadFrom(b.Extend(buf.Size))
+		if err != nil {
+			b.Release()
+			return
+		}
+		b.Resize(0, int32(n))
+
+		x.access.Lock()
+		s, output := x.mux, x.output
+		x.access.Unlock()
+		if output == nil {
+			b.Release()
+			continue
+		}
+		meta := FrameMetadata{
+			SessionID:     s.ID,
+			SessionStatus: SessionStatusKeep,
+			Target:        net.DestinationFromAddr(addr),
+		}
+		meta.Option.Set(OptionData)
+		if err := writeMetaWithFrame(output, meta, buf.MultiBuffer{b}); err != nil {
+			newError("failed to write response of full-cone UDP session").Base(err).AtDebug().WriteToLog()
+		}
+	}
+}
+
+// packetAddrReader attaches the source address to the packets of a full-cone UDP session on clients.
+type packetAddrReader struct {
+	reader buf.Reader
+	source net.Destination
+}
+
+// ReadMultiBuffer implements buf.Reader.
+func (r *packetAddrReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	mb, err := r.reader.ReadMultiBuffer()
+	if mb.IsEmpty() {
+		return mb, err
+	}
+	if r.source.Network != net.Network_UDP || r.source.Address.Family().IsDomain() {
+		newError("dropping packet from unknown source ", r.source, " in full-cone UDP session").AtDebug().WriteToLog()
+		buf.ReleaseMulti(mb)
+		return nil, err
+	}
+	addr := &net.UDPAddr{IP: r.source.Address.IP(), Port: int(r.source.Port)}
+	for i, b := range mb {
+		packet, aerr := packetaddr.AttachAddressToPacket(b, addr)
+		if aerr != nil {
+			buf.ReleaseMulti(mb)
+			return nil, aerr
+		}
+		mb[i] = packet
+	}
+	return mb, err
+}
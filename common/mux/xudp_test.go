@@ -0,0 +1,168 @@
+package mux_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/buf"
+	"github.com/v2fly/v2ray-core/v5/common/dice"
+	"github.com/v2fly/v2ray-core/v5/common/mux"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/net/packetaddr"
+	"github.com/v2fly/v2ray-core/v5/common/protocol"
+	"github.com/v2fly/v2ray-core/v5/common/session"
+	"github.com/v2fly/v2ray-core/v5/features/routing"
+	"github.com/v2fly/v2ray-core/v5/transport"
+	"github.com/v2fly/v2ray-core/v5/transport/pipe"
+)
+
+func TestFrameGlobalID(t *testing.T) {
+	newFrame := mux.FrameMetadata{
+		SessionID:     1,
+		SessionStatus: mux.SessionStatusNew,
+		Target:        net.UDPDestination(net.LocalHostIP, 53),
+		GlobalID:      mux.GlobalID{1, 2, 3, 4, 5, 6, 7, 8},
+	}
+	keepFrame := mux.FrameMetadata{
+		SessionID:     1,
+		SessionStatus: mux.SessionStatusKeep,
+		Option:        mux.OptionData,
+		Target:        net.UDPDestination(net.LocalHostIPv6, 3478),
+	}
+	for _, frame := range []mux.FrameMetadata{newFrame, keepFrame} {
+		b := buf.New()
+		common.Must(frame.WriteTo(b))
+		b.Advance(2)
+
+		var meta mux.FrameMetadata
+		common.Must(meta.UnmarshalFromBuffer(b))
+		if r := cmp.Diff(meta, frame); r != "" {
+			t.Error(r)
+		}
+		b.Release()
+	}
+}
+
+// dispatcherFunc is a routing.Dispatcher for tests.
+type dispatcherFunc func(ctx context.Context, dest net.Destination) (*transport.Link, error)
+
+func (dispatcherFunc) Type() interface{} { return routing.DispatcherType() }
+func (dispatcherFunc) Start() error      { return nil }
+func (dispatcherFunc) Close() error      { return nil }
+
+func (f dispatcherFunc) Dispatch(ctx context.Context, dest net.Destination) (*transport.Link, error) {
+	return f(ctx, dest)
+}
+
+// fullConeUDPTest connects Mux clients to Mux servers relaying full-cone UDP sessions to an echo server.
+type fullConeUDPTest struct {
+	t          *testing.T
+	dispatched int32
+	// source is the inbound source of clients. Sessions from the same source are resumed,
+	// so every run has its own source.
+	source net.Destination
+}
+
+func newFullConeUDPTest(t *testing.T) *fullConeUDPTest {
+	return &fullConeUDPTest{
+		t:      t,
+		source: net.UDPDestination(net.LocalHostIP, net.Port(dice.RollUint16())),
+	}
+}
+
+// connect connects a client to a server accepting the client as the user.
+func (f *fullConeUDPTest) connect(user *protocol.MemoryUser) (net.PacketConn, func()) {
+	// The server echos every packet back from its destination.
+	server := dispatcherFunc(func(ctx context.Context, dest net.Destination) (*transport.Link, error) {
+		atomic.AddInt32(&f.dispatched, 1)
+		uplinkReader, uplinkWriter := pipe.New(pipe.WithoutSizeLimit())
+		downlinkReader, downlinkWriter := pipe.New(pipe.WithoutSizeLimit())
+		go buf.Copy(uplinkReader, downlinkWriter)
+		return &transport.Link{Reader: downlinkReader, Writer: uplinkWriter}, nil
+	})
+	serverCtx := session.ContextWithInbound(context.Background(), &session.Inbound{
+		Source: net.TCPDestination(net.LocalHostIP, 10000),
+		Tag:    "in",
+		User:   user,
+	})
+	ctx := session.ContextWithInbound(context.Background(), &session.Inbound{Source: f.source})
+
+	uplinkReader, uplinkWriter := pipe.New(pipe.WithoutSizeLimit())
+	downlinkReader, downlinkWriter := pipe.New(pipe.WithoutSizeLimit())
+	_, err := mux.NewServerWorker(serverCtx, server, &transport.Link{Reader: uplinkReader, Writer: downlinkWriter})
+	common.Must(err)
+	client, err := mux.NewClientWorker(transport.Link{Reader: downlinkReader, Writer: uplinkWriter}, mux.ClientStrategy{})
+	common.Must(err)
+
+	conn, err := packetaddr.CreatePacketAddrConn(ctx, dispatcherFunc(func(ctx context.Context, dest net.Destination) (*transport.Link, error) {
+		inputReader, inputWriter := pipe.New(pipe.WithoutSizeLimit())
+		outputReader, outputWriter := pipe.New(pipe.WithoutSizeLimit())
+		ctx = session.ContextWithOutbound(ctx, &session.Outbound{Target: dest})
+		if !client.Dispatch(ctx, &transport.Link{Reader: inputReader, Writer: outputWriter}) {
+			f.t.Fatal("failed to dispatch")
+		}
+		return &transport.Link{Reader: outputReader, Writer: inputWriter}, nil
+	}), false)
+	common.Must(err)
+	return conn, func() {
+		conn.Close()
+		common.Interrupt(uplinkReader)
+		common.Interrupt(downlinkReader)
+	}
+}
+
+func (f *fullConeUDPTest) echo(conn net.PacketConn, addr *net.UDPAddr, payload string) {
+	common.Must2(conn.WriteTo([]byte(payload), addr))
+	b := make([]byte, 1024)
+	n, from, err := conn.ReadFrom(b)
+	common.Must(err)
+	if string(b[:n]) != payload || from.String() != addr.String() {
+		f.t.Error("unexpected response ", string(b[:n]), " from ", from)
+	}
+}
+
+func TestFullConeUDP(t *testing.T) {
+	f := newFullConeUDPTest(t)
+	user := &protocol.MemoryUser{Email: "a@example.com"}
+	addrA := &net.UDPAddr{IP: net.ParseIP("1.2.3.4"), Port: 3478}
+	addrB := &net.UDPAddr{IP: net.ParseIP("5.6.7.8"), Port: 19302}
+
+	conn, disconnect := f.connect(user)
+	f.echo(conn, addrA, "a")
+	f.echo(conn, addrB, "b")
+	disconnect()
+
+	// The session is resumed on a new Mux connection from the same source.
+	time.Sleep(100 * time.Millisecond)
+	conn, disconnect = f.connect(user)
+	defer disconnect()
+	f.echo(conn, addrB, "c")
+
+	if n := atomic.LoadInt32(&f.dispatched); n != 1 {
+		t.Error("expected 1 dispatched session, but got ", n)
+	}
+}
+
+func TestFullConeUDPNotResumedByOtherUsers(t *testing.T) {
+	f := newFullConeUDPTest(t)
+	addr := &net.UDPAddr{IP: net.ParseIP("1.2.3.4"), Port: 3478}
+
+	conn, disconnect := f.connect(&protocol.MemoryUser{Email: "a@example.com"})
+	f.echo(conn, addr, "a")
+	disconnect()
+
+	// Another user sending the same GlobalID gets its own session.
+	time.Sleep(100 * time.Millisecond)
+	conn, disconnect = f.connect(&protocol.MemoryUser{Email: "b@example.com"})
+	defer disconnect()
+	f.echo(conn, addr, "b")
+
+	if n := atomic.LoadInt32(&f.dispatched); n != 2 {
+		t.Error("expected 2 dispatched sessions, but got ", n)
+	}
+}
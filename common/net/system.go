@@ -14,6 +14,7 @@ var (
 	DialUDP         = net.DialUDP
 	DialUnix        = net.DialUnix
 	FileConn        = net.FileConn
+	FileListener    = net.FileListener
 	Listen          = net.Listen
 	ListenTCP       = net.ListenTCP
 	ListenUDP       = net.ListenUDP
@@ -37,6 +38,7 @@ type (
 	IPNet        = net.IPNet
 	ListenConfig = net.ListenConfig
 	Listener     = net.Listener
+	OpError      = net.OpError
 	PacketConn   = net.PacketConn
 	Resolver     = net.Resolver
 	TCPAddr      = net.TCPAddr
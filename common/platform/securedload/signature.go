@@ -0,0 +1,59 @@
+package securedload
+
+import (
+	"bytes"
+	"encoding/base64"
+
+	"github.com/v2fly/VSign/sign/signify"
+)
+
+// decodeSignify decodes data in signify format, with or without the untrusted comment line.
+func decodeSignify(data []byte) ([]byte, error) {
+	var content []byte
+	var err error
+	if bytes.HasPrefix(data, []byte("untrusted comment: ")) {
+		_, content, err, _ = signify.ReadFile(bytes.NewReader(data))
+	} else {
+		content, err = base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Parsers of signify assume the algorithm is present.
+	if len(content) < 2 {
+		return nil, newError("data too short")
+	}
+	return content, nil
+}
+
+// ParsePublicKey parses a signify public key, which may be the content of a key file generated by signify.
+func ParsePublicKey(data []byte) (*signify.PublicKey, error) {
+	raw, err := decodeSignify(data)
+	if err != nil {
+		return nil, newError("invalid public key").Base(err)
+	}
+	key, err := signify.ParsePublicKey(raw)
+	if err != nil {
+		return nil, newError("invalid public key").Base(err)
+	}
+	return key, nil
+}
+
+// VerifyDetachedSignature verifies the signify signature of the content, like "signify -V -x sig -m content".
+func VerifyDetachedSignature(key *signify.PublicKey, content []byte, signature []byte) error {
+	raw, err := decodeSignify(signature)
+	if err != nil {
+		return newError("invalid signature").Base(err)
+	}
+	sig, err := signify.ParseSignature(raw)
+	if err != nil {
+		return newError("invalid signature").Base(err)
+	}
+	if sig.Fingerprint != key.Fingerprint {
+		return newError("signature is made by another key")
+	}
+	if !signify.Verify(key, content, sig) {
+		return newError("signature mismatch")
+	}
+	return nil
+}
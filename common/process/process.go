@@ -0,0 +1,25 @@
+// Package process finds the local processes of connections.
+package process
+
+import (
+	"github.com/v2fly/v2ray-core/v5/common/net"
+)
+
+//go:generate go run github.com/v2fly/v2ray-core/v5/common/errors/errorgen
+
+// Info is the information of a process.
+type Info struct {
+	// PID is 0 if the socket is found but not the process, such as a closing TCP socket.
+	PID  int
+	UID  uint32
+	Name string
+	Path string
+}
+
+// ErrNotFound is returned if no local socket is bound to the address.
+var ErrNotFound = newError("process not found")
+
+// FindProcess finds the local process which owns the socket bound to the source address of a connection.
+func FindProcess(network net.Network, ip net.IP, port net.Port) (*Info, error) {
+	return findProcess(network, ip, port)
+}
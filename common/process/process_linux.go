@@ -0,0 +1,341 @@
+//go:build linux
+// +build linux
+
+package process
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/v2fly/v2ray-core/v5/common/net"
+)
+
+const (
+	sockDiagByFamily = 20
+	inetDiagReqSize  = 56
+	inetDiagMsgSize  = 72
+
+	// Attribute and operations of inet_diag bytecode filters.
+	inetDiagReqBytecode = 1
+	inetDiagBcSGE       = 2
+	inetDiagBcSLE       = 3
+	inetDiagBcSize      = 16
+
+	// pidCacheTTL is how long socket inodes found in /proc are remembered.
+	pidCacheTTL = 2 * time.Second
+)
+
+var nativeEndian binary.ByteOrder = func() binary.ByteOrder {
+	x := uint16(1)
+	if *(*byte)(unsafe.Pointer(&x)) == 1 {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}()
+
+// socket is a local socket owned by the uid, identified by the inode.
+type socket struct {
+	inode uint32
+	uid   uint32
+}
+
+func findProcess(network net.Network, ip net.IP, port net.Port) (*Info, error) {
+	var protocol uint8
+	switch network {
+	case net.Network_TCP:
+		protocol = syscall.IPPROTO_TCP
+	case net.Network_UDP:
+		protocol = syscall.IPPROTO_UDP
+	default:
+		return nil, newError("unsupported network ", network)
+	}
+
+	s, err := findSocketByDiag(protocol, ip, port)
+	if err != nil {
+		newError("failed to find socket with sock_diag, fallback to /proc/net").Base(err).AtDebug().WriteToLog()
+		s, err = findSocketByProcNet(protocol, ip, port)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info := &Info{UID: s.uid}
+	if s.inode == 0 {
+		return info, nil
+	}
+	info.PID, err = findPID(s.inode, s.uid)
+	if err != nil {
+		return nil, err
+	}
+	if info.PID == 0 {
+		return info, nil
+	}
+	if path, err := os.Readlink(filepath.Join("/proc", strconv.Itoa(info.PID), "exe")); err == nil {
+		info.Path = strings.TrimSuffix(path, " (deleted)")
+		info.Name = filepath.Base(info.Path)
+	} else if comm, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(info.PID), "comm")); err == nil {
+		info.Name = strings.TrimSpace(string(comm))
+	}
+	return info, nil
+}
+
+// families returns the address families and addresses of the sockets which may be bound to the IP address.
+// IPv4 connections may be accepted by dual-stack IPv6 sockets.
+func families(ip net.IP) []struct {
+	family uint8
+	ip     net.IP
+} {
+	type familyIP = struct {
+		family uint8
+		ip     net.IP
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return []familyIP{{syscall.AF_INET, ip4}, {syscall.AF_INET6, ip4.To16()}}
+	}
+	return []familyIP{{syscall.AF_INET6, ip.To16()}}
+}
+
+// matchAddress returns whether the local address of a socket matches. UDP sockets may be bound to the unspecified address.
+func matchAddress(protocol uint8, local net.IP, ip net.IP) bool {
+	return local.Equal(ip) || (protocol == syscall.IPPROTO_UDP && local.IsUnspecified())
+}
+
+func findSocketByDiag(protocol uint8, ip net.IP, port net.Port) (*socket, error) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, syscall.NETLINK_INET_DIAG)
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.Close(fd)
+
+	for _, f := range families(ip) {
+		s, err := diag(fd, f.family, protocol, f.ip, port)
+		if err != nil {
+			return nil, err
+		}
+		if s != nil {
+			return s, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// diagRequest builds a sock_diag request dumping the sockets bound to the port.
+// Exact lookups need the remote address, which is unknown, and dumps without bytecode return
+// every socket of the family, so the dump is filtered by a bytecode of sport >= port && sport <= port.
+func diagRequest(family uint8, protocol uint8, port net.Port) []byte {
+	req := make([]byte, syscall.NLMSG_HDRLEN+inetDiagReqSize+syscall.NLA_HDRLEN+inetDiagBcSize)
+	nativeEndian.PutUint32(req[0:4], uint32(len(req)))
+	nativeEndian.PutUint16(req[4:6], sockDiagByFamily)
+	nativeEndian.PutUint16(req[6:8], syscall.NLM_F_REQUEST|syscall.NLM_F_DUMP)
+	r := req[syscall.NLMSG_HDRLEN:]
+	r[0] = family
+	r[1] = protocol
+	nativeEndian.PutUint32(r[4:8], 0xffffffff)
+
+	attr := r[inetDiagReqSize:]
+	nativeEndian.PutUint16(attr[0:2], uint16(syscall.NLA_HDRLEN+inetDiagBcSize))
+	nativeEndian.PutUint16(attr[2:4], inetDiagReqBytecode)
+	// Each operation is {code, yes, no} followed by the port in the "no" field of the next one.
+	// Jumping past the end of the bytecode rejects the socket, and reaching the end accepts it.
+	bc := attr[syscall.NLA_HDRLEN:]
+	putOp := func(b []byte, code uint8, yes uint8, no uint16) {
+		b[0] = code
+		b[1] = yes
+		nativeEndian.PutUint16(b[2:4], no)
+	}
+	putOp(bc[0:4], inetDiagBcSGE, 8, inetDiagBcSize+4)
+	putOp(bc[4:8], 0, 0, uint16(port))
+	putOp(bc[8:12], inetDiagBcSLE, 8, inetDiagBcSize-8+4)
+	putOp(bc[12:16], 0, 0, uint16(port))
+	return req
+}
+
+// diag dumps the sockets bound to the port with sock_diag, and returns the one bound to the IP address.
+func diag(fd int, family uint8, protocol uint8, ip net.IP, port net.Port) (*socket, error) {
+	req := diagRequest(family, protocol, port)
+
+	if err := syscall.Sendto(fd, req, 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		return nil, err
+	}
+
+	var found *socket
+	buf := make([]byte, 64*1024)
+	for {
+		n, _, err := syscall.Recvfrom(fd, buf, 0)
+		if err != nil {
+			return nil, err
+		}
+		msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range msgs {
+			switch msg.Header.Type {
+			case syscall.NLMSG_DONE:
+				return found, nil
+			case syscall.NLMSG_ERROR:
+				if len(msg.Data) >= 4 {
+					if errno := int32(nativeEndian.Uint32(msg.Data)); errno != 0 {
+						return nil, syscall.Errno(-errno)
+					}
+				}
+				return nil, newError("sock_diag error")
+			}
+			// All messages of the dump are read, so that they are not mixed with the next request.
+			if found != nil || len(msg.Data) < inetDiagMsgSize {
+				continue
+			}
+			m := msg.Data
+			sport := net.Port(binary.BigEndian.Uint16(m[4:6]))
+			local := net.IP(m[8 : 8+len(ip)])
+			if sport == port && matchAddress(protocol, local, ip) {
+				found = &socket{
+					uid:   nativeEndian.Uint32(m[64:68]),
+					inode: nativeEndian.Uint32(m[68:72]),
+				}
+			}
+		}
+	}
+}
+
+func findSocketByProcNet(protocol uint8, ip net.IP, port net.Port) (*socket, error) {
+	name := "tcp"
+	if protocol == syscall.IPPROTO_UDP {
+		name = "udp"
+	}
+	for _, f := range families(ip) {
+		path := "/proc/net/" + name
+		if f.family == syscall.AF_INET6 {
+			path += "6"
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if s := parseProcNet(content, protocol, f.ip, port); s != nil {
+			return s, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// parseProcNet finds the socket bound to the address in the content of /proc/net/{tcp,udp}{,6}.
+func parseProcNet(content []byte, protocol uint8, ip net.IP, port net.Port) *socket {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Scan() // header
+	for scanner.Scan() {
+		// sl local_address rem_address st tx_queue:rx_queue tr:tm->when retrnsmt uid timeout inode
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			continue
+		}
+		local, localPort, err := parseProcNetAddress(fields[1])
+		if err != nil || localPort != port || len(local) != len(ip) || !matchAddress(protocol, local, ip) {
+			continue
+		}
+		uid, err := strconv.ParseUint(fields[7], 10, 32)
+		if err != nil {
+			continue
+		}
+		inode, err := strconv.ParseUint(fields[9], 10, 32)
+		if err != nil {
+			continue
+		}
+		return &socket{uid: uint32(uid), inode: uint32(inode)}
+	}
+	return nil
+}
+
+// parseProcNetAddress parses addresses like "0100007F:1F90", where the IP address is in 32-bit words of native byte order.
+func parseProcNetAddress(s string) (net.IP, net.Port, error) {
+	addr, portHex, ok := strings.Cut(s, ":")
+	if !ok {
+		return nil, 0, newError("invalid address ", s)
+	}
+	b, err := hex.DecodeString(addr)
+	if err != nil || (len(b) != net.IPv4len && len(b) != net.IPv6len) {
+		return nil, 0, newError("invalid address ", s)
+	}
+	for i := 0; i < len(b); i += 4 {
+		binary.BigEndian.PutUint32(b[i:], nativeEndian.Uint32(b[i:]))
+	}
+	port, err := strconv.ParseUint(portHex, 16, 16)
+	if err != nil {
+		return nil, 0, newError("invalid port ", s)
+	}
+	return net.IP(b), net.Port(port), nil
+}
+
+// pidCache remembers the processes of socket inodes found in /proc, as walking the file
+// descriptors of all processes is expensive and packets of the same socket are routed repeatedly.
+var pidCache = struct {
+	sync.Mutex
+	pids map[uint32]cachedPID
+}{pids: make(map[uint32]cachedPID)}
+
+type cachedPID struct {
+	pid     int
+	expires time.Time
+}
+
+// findPID finds the process with the socket inode in its file descriptors. Only processes of the uid are searched.
+func findPID(inode uint32, uid uint32) (int, error) {
+	pidCache.Lock()
+	defer pidCache.Unlock()
+
+	now := time.Now()
+	if c, ok := pidCache.pids[inode]; ok && now.Before(c.expires) {
+		return c.pid, nil
+	}
+	for i, c := range pidCache.pids {
+		if !now.Before(c.expires) {
+			delete(pidCache.pids, i)
+		}
+	}
+
+	procs, err := os.ReadDir("/proc")
+	if err != nil {
+		return 0, err
+	}
+	expires := now.Add(pidCacheTTL)
+	for _, proc := range procs {
+		pid, err := strconv.Atoi(proc.Name())
+		if err != nil || !proc.IsDir() {
+			continue
+		}
+		if info, err := proc.Info(); err == nil {
+			if stat, ok := info.Sys().(*syscall.Stat_t); ok && stat.Uid != uid {
+				continue
+			}
+		}
+		fdDir := filepath.Join("/proc", proc.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		// All sockets of the process are cached, for the other sockets opened by it.
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+			if i, err := strconv.ParseUint(link[len("socket:["):len(link)-1], 10, 32); err == nil {
+				pidCache.pids[uint32(i)] = cachedPID{pid: pid, expires: expires}
+			}
+		}
+		if c, ok := pidCache.pids[inode]; ok && c.pid == pid {
+			return pid, nil
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,84 @@
+//go:build linux
+// +build linux
+
+package process
+
+import (
+	"encoding/binary"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+)
+
+func TestParseProcNet(t *testing.T) {
+	ip := net.ParseIP("127.0.0.1").To4()
+	local := "0100007F"
+	if nativeEndian == binary.BigEndian {
+		local = "7F000001"
+	}
+	content := []byte(`  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:0016 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1001 1 0000000000000000 100 0 0 10 0
+   1: ` + local + `:1F90 ` + local + `:0016 01 00000000:00000000 00:00000000 00000000  1000        0 1002 1 0000000000000000 20 4 30 10 -1
+`)
+	s := parseProcNet(content, syscall.IPPROTO_TCP, ip, 8080)
+	if s == nil || s.uid != 1000 || s.inode != 1002 {
+		t.Error("unexpected socket ", s)
+	}
+	if s := parseProcNet(content, syscall.IPPROTO_TCP, ip, 22); s != nil {
+		t.Error("expected no socket, but got ", s)
+	}
+	if s := parseProcNet(content, syscall.IPPROTO_UDP, ip, 22); s == nil || s.inode != 1001 {
+		t.Error("expected the UDP socket bound to the unspecified address, but got ", s)
+	}
+}
+
+func TestFindProcess(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	common.Must(err)
+	defer listener.Close()
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	common.Must(err)
+	defer conn.Close()
+	local := conn.LocalAddr().(*net.TCPAddr)
+
+	executable, err := os.Executable()
+	common.Must(err)
+	for name, find := range map[string]func(uint8, net.IP, net.Port) (*socket, error){
+		"sock_diag": findSocketByDiag,
+		"proc_net":  findSocketByProcNet,
+	} {
+		s, err := find(syscall.IPPROTO_TCP, local.IP, net.Port(local.Port))
+		if err != nil {
+			t.Log(name, " is not available: ", err)
+			continue
+		}
+		if s.uid != uint32(os.Getuid()) || s.inode == 0 {
+			t.Error(name, ": unexpected socket ", s)
+		}
+	}
+
+	info, err := FindProcess(net.Network_TCP, local.IP, net.Port(local.Port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.PID != os.Getpid() || info.UID != uint32(os.Getuid()) || info.Path != executable {
+		t.Error("unexpected process ", info)
+	}
+
+	// Processes of sockets are cached.
+	s, err := findSocketByProcNet(syscall.IPPROTO_TCP, local.IP, net.Port(local.Port))
+	common.Must(err)
+	pidCache.Lock()
+	c, ok := pidCache.pids[s.inode]
+	pidCache.Unlock()
+	if !ok || c.pid != os.Getpid() {
+		t.Error("expected the process of the socket cached, but got ", c)
+	}
+
+	if _, err := FindProcess(net.Network_TCP, local.IP, 1); err != ErrNotFound {
+		t.Error("expected not found, but got ", err)
+	}
+}
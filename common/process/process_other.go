@@ -0,0 +1,12 @@
+//go:build !linux
+// +build !linux
+
+package process
+
+import (
+	"github.com/v2fly/v2ray-core/v5/common/net"
+)
+
+func findProcess(network net.Network, ip net.IP, port net.Port) (*Info, error) {
+	return nil, newError("finding processes is not supported on this platform")
+}
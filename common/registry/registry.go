@@ -17,6 +17,7 @@ import (
 )
 
 type implementationRegistry struct {
+	access  sync.RWMutex
 	implSet map[string]*implementationSet
 }
 
@@ -28,6 +29,8 @@ func (i *implementationRegistry) RegisterImplementation(name string, opt *protoe
 }
 
 func (i *implementationRegistry) registerSingleImplementation(interfaceType, name string, opt *protoext.MessageOpt, loader CustomLoader) {
+	i.access.Lock()
+	defer i.access.Unlock()
 	implSet, found := i.implSet[interfaceType]
 	if !found {
 		implSet = newImplementationSet()
@@ -37,6 +40,8 @@ func (i *implementationRegistry) registerSingleImplementation(interfaceType, nam
 }
 
 func (i *implementationRegistry) findImplementationByAlias(interfaceType, alias string) (string, CustomLoader, error) {
+	i.access.RLock()
+	defer i.access.RUnlock()
 	implSet, found := i.implSet[interfaceType]
 	if !found {
 		return "", nil, newError("cannot find implemention unknown interface type")
@@ -121,11 +126,33 @@ type LoadByAlias interface {
 	LoadImplementationByAlias(ctx context.Context, interfaceType, alias string, data []byte) (proto.Message, error)
 }
 
-func LoadImplementationByAlias(ctx context.Context, interfaceType, alias string, data []byte) (proto.Message, error) {
+func initialize() {
 	initialized.Do(func() {
 		for _, v := range registerRequests {
 			registerImplementation(v.proto, v.loader)
 		}
 	})
+}
+
+func LoadImplementationByAlias(ctx context.Context, interfaceType, alias string, data []byte) (proto.Message, error) {
+	initialize()
 	return globalImplementationRegistry.LoadImplementationByAlias(ctx, interfaceType, alias, data)
 }
+
+// ListImplementations returns the full names of the message types implementing the interface type, by their aliases.
+func ListImplementations(interfaceType string) map[string]string {
+	initialize()
+	return globalImplementationRegistry.listImplementations(interfaceType)
+}
+
+func (i *implementationRegistry) listImplementations(interfaceType string) map[string]string {
+	i.access.RLock()
+	defer i.access.RUnlock()
+	implementations := make(map[string]string)
+	if implSet, found := i.implSet[interfaceType]; found {
+		for alias, impl := range implSet.AliasLookup {
+			implementations[alias] = impl.FullName
+		}
+	}
+	return implementations
+}
@@ -53,6 +53,8 @@ type Outbound struct {
 	Gateway net.Address
 	// Domain resolver to use when dialing
 	Resolver func(ctx context.Context, domain string) net.Address
+	// Domain resolver returning all candidate addresses, for dialers racing connection attempts
+	MultiResolver func(ctx context.Context, domain string) []net.Address
 }
 
 // SniffingRequest controls the behavior of content sniffing.
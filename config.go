@@ -25,6 +25,8 @@ const (
 	FormatTOML = "toml"
 	// FormatYAML represents yaml format
 	FormatYAML = "yaml"
+	// FormatStarlark represents starlark format
+	FormatStarlark = "starlark"
 	// FormatProtobuf represents protobuf format
 	FormatProtobuf = "protobuf"
 	// FormatProtobufShort is the short of FormatProtobuf
@@ -92,11 +94,20 @@ func GetAllExtensions() []string {
 	return extensions
 }
 
+// GetFormatByExtension get the name of the config loader of the extension, or FormatAuto if not found.
+func GetFormatByExtension(ext string) string {
+	if f, found := configLoaderByExt[strings.ToLower(ext)]; found {
+		return f.Name[0]
+	}
+	return FormatAuto
+}
+
 // LoadConfig loads multiple config with given format from given source.
 // input accepts:
 // * string of a single filename/url(s) to open to read
 // * []string slice of multiple filename/url(s) to open to read
 // * io.Reader that reads a config content (the original way)
+// * map[string]interface{} of configs merged by the JSON based loaders, which are not interpolated again
 func LoadConfig(formatName string, input interface{}) (*Config, error) {
 	cnt := getInputCount(input)
 	if cnt == 0 {
@@ -2,6 +2,7 @@ package extension
 
 import (
 	"context"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 
@@ -16,3 +17,21 @@ type Observatory interface {
 func ObservatoryType() interface{} {
 	return (*Observatory)(nil)
 }
+
+// TrafficObservation is the outcome of a connection relayed by an outbound.
+type TrafficObservation struct {
+	OutboundTag string
+	// Failed is set if the connection failed before any data was received from the remote,
+	// like a dial or handshake failure.
+	Failed bool
+	// Reset is set if the connection was reset after data was received from the remote.
+	Reset bool
+	// FirstByteDelay is the time taken to receive the first byte from the remote, 0 if nothing was received.
+	FirstByteDelay time.Duration
+	Err            error
+}
+
+// PassiveObserver is an Observatory which learns the health of outbounds from real traffic.
+type PassiveObserver interface {
+	ObserveTraffic(observation *TrafficObservation)
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"time"
+
+	"github.com/v2fly/v2ray-core/v5/infra/conf/jsonschema"
 )
 
 type Duration int64
@@ -31,3 +33,7 @@ func (d *Duration) UnmarshalJSON(b []byte) error {
 		return fmt.Errorf("invalid duration: %v", v)
 	}
 }
+
+func (d *Duration) JSONSchema(r *jsonschema.Reflector) *jsonschema.Schema {
+	return jsonschema.String().WithDescription("duration, like \"300ms\" or \"1h30m\"")
+}
@@ -42,6 +42,11 @@ func NewJSONConfigLoader(cache ConfigCreatorCache, idKey string, configKey strin
 	}
 }
 
+// Cache returns the creators of configs, keyed by config id.
+func (v *JSONConfigLoader) Cache() ConfigCreatorCache {
+	return v.cache
+}
+
 func (v *JSONConfigLoader) LoadWithID(raw []byte, id string) (interface{}, error) {
 	id = strings.ToLower(id)
 	config, err := v.cache.CreateConfig(id)
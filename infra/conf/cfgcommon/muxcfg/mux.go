@@ -1,10 +1,22 @@
 package muxcfg
 
-import "github.com/v2fly/v2ray-core/v5/app/proxyman"
+import (
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/app/proxyman"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon/duration"
+)
 
 type MuxConfig struct {
-	Enabled     bool  `json:"enabled"`
-	Concurrency int16 `json:"concurrency"`
+	Enabled            bool              `json:"enabled"`
+	Concurrency        int16             `json:"concurrency"`
+	MaxConnections     uint32            `json:"maxConnections"`
+	MaxStreams         uint32            `json:"maxStreams"`
+	IdleTimeout        duration.Duration `json:"idleTimeout"`
+	MinIdleConnections uint32            `json:"minIdleConnections"`
+	Padding            bool              `json:"padding"`
+	TCP                *bool             `json:"tcp"`
+	UDP                *bool             `json:"udp"`
 }
 
 // Build creates MultiplexingConfig, Concurrency < 0 completely disables mux.
@@ -19,7 +31,14 @@ func (m *MuxConfig) Build() *proxyman.MultiplexingConfig {
 	}
 
 	return &proxyman.MultiplexingConfig{
-		Enabled:     m.Enabled,
-		Concurrency: con,
+		Enabled:            m.Enabled,
+		Concurrency:        con,
+		MaxConnections:     m.MaxConnections,
+		MaxStreams:         m.MaxStreams,
+		IdleTimeout:        int64(time.Duration(m.IdleTimeout)),
+		MinIdleConnections: m.MinIdleConnections,
+		Padding:            m.Padding,
+		TcpDisabled:        m.TCP != nil && !*m.TCP,
+		UdpDisabled:        m.UDP != nil && !*m.UDP,
 	}
 }
@@ -0,0 +1,48 @@
+package muxcfg_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/v2fly/v2ray-core/v5/app/proxyman"
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon/muxcfg"
+)
+
+func TestMuxConfig(t *testing.T) {
+	var config muxcfg.MuxConfig
+	common.Must(json.Unmarshal([]byte(`{
+		"enabled": true,
+		"concurrency": 16,
+		"maxConnections": 4,
+		"maxStreams": 256,
+		"idleTimeout": "1m",
+		"minIdleConnections": 1,
+		"padding": true,
+		"udp": false
+	}`), &config))
+
+	expected := &proxyman.MultiplexingConfig{
+		Enabled:            true,
+		Concurrency:        16,
+		MaxConnections:     4,
+		MaxStreams:         256,
+		IdleTimeout:        int64(time.Minute),
+		MinIdleConnections: 1,
+		Padding:            true,
+		UdpDisabled:        true,
+	}
+	if actual := config.Build(); !proto.Equal(actual, expected) {
+		t.Error("unexpected config: ", actual)
+	}
+}
+
+func TestMuxConfigDisabled(t *testing.T) {
+	config := muxcfg.MuxConfig{Concurrency: -1}
+	if actual := config.Build(); actual != nil {
+		t.Error("expected nil, but got ", actual)
+	}
+}
@@ -0,0 +1,37 @@
+package cfgcommon
+
+import (
+	"github.com/v2fly/v2ray-core/v5/infra/conf/jsonschema"
+)
+
+func (v *StringList) JSONSchema(r *jsonschema.Reflector) *jsonschema.Schema {
+	return jsonschema.AnyOf(
+		jsonschema.Array(jsonschema.String()),
+		jsonschema.String().WithDescription("comma separated list"),
+	)
+}
+
+func (v *Address) JSONSchema(r *jsonschema.Reflector) *jsonschema.Schema {
+	return jsonschema.String().WithDescription("IP address or domain")
+}
+
+func (v *NetworkList) JSONSchema(r *jsonschema.Reflector) *jsonschema.Schema {
+	return jsonschema.AnyOf(
+		jsonschema.Array(jsonschema.String()),
+		jsonschema.String().WithDescription("comma separated list of networks, like \"tcp,udp\""),
+	)
+}
+
+func (v *PortRange) JSONSchema(r *jsonschema.Reflector) *jsonschema.Schema {
+	return jsonschema.AnyOf(
+		jsonschema.Unsigned(),
+		jsonschema.String().WithDescription("port or port range, like \"1000-2000\", or \"env:NAME\""),
+	)
+}
+
+func (list *PortList) JSONSchema(r *jsonschema.Reflector) *jsonschema.Schema {
+	return jsonschema.AnyOf(
+		jsonschema.Unsigned(),
+		jsonschema.String().WithDescription("comma separated list of ports and port ranges, like \"53,1000-2000\""),
+	)
+}
@@ -0,0 +1,108 @@
+package sendthroughcfg
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/v2fly/v2ray-core/v5/app/proxyman"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/jsonschema"
+)
+
+//go:generate go run github.com/v2fly/v2ray-core/v5/common/errors/errorgen
+
+// SendThrough is the source address of outgoing connections. It may be a single IP,
+// a CIDR, or a list of IPs and CIDRs to pick source addresses from.
+type SendThrough struct {
+	addresses []string
+}
+
+func (v *SendThrough) UnmarshalJSON(data []byte) error {
+	var address string
+	if err := json.Unmarshal(data, &address); err == nil {
+		v.addresses = []string{address}
+		return nil
+	}
+	var addresses []string
+	if err := json.Unmarshal(data, &addresses); err != nil {
+		return newError("invalid send through address: ", string(data)).Base(err)
+	}
+	v.addresses = addresses
+	return nil
+}
+
+func (v *SendThrough) JSONSchema(r *jsonschema.Reflector) *jsonschema.Schema {
+	return jsonschema.AnyOf(
+		jsonschema.String().WithDescription("IP address or CIDR"),
+		jsonschema.Array(jsonschema.String()),
+	)
+}
+
+func parseStrategy(strategy string) (proxyman.SourceAddressPool_Strategy, error) {
+	switch strings.ToLower(strategy) {
+	case "", "random":
+		return proxyman.SourceAddressPool_Random, nil
+	case "roundrobin", "round_robin", "round-robin":
+		return proxyman.SourceAddressPool_RoundRobin, nil
+	case "stickydestination", "sticky_destination", "sticky-destination":
+		return proxyman.SourceAddressPool_StickyDestination, nil
+	case "stickyuser", "sticky_user", "sticky-user":
+		return proxyman.SourceAddressPool_StickyUser, nil
+	default:
+		return 0, newError("unknown send through strategy: ", strategy)
+	}
+}
+
+// Build returns the single address to send through, or a pool of source
+// addresses if more than one address is given.
+func (v *SendThrough) Build(strategy string) (*net.IPOrDomain, *proxyman.SourceAddressPool, error) {
+	if len(v.addresses) == 0 {
+		return nil, nil, newError("empty send through address")
+	}
+	if len(v.addresses) == 1 && !strings.Contains(v.addresses[0], "/") {
+		address := net.ParseAddress(v.addresses[0])
+		if address.Family().IsDomain() {
+			return nil, nil, newError("unable to send through: " + address.String())
+		}
+		if strategy != "" {
+			return nil, nil, newError("send through strategy requires a CIDR or a list of addresses")
+		}
+		return net.NewIPOrDomain(address), nil, nil
+	}
+
+	s, err := parseStrategy(strategy)
+	if err != nil {
+		return nil, nil, err
+	}
+	pool := &proxyman.SourceAddressPool{
+		Strategy: s,
+	}
+	for _, address := range v.addresses {
+		if strings.Contains(address, "/") {
+			ip, ipNet, err := net.ParseCIDR(address)
+			if err != nil {
+				return nil, nil, newError("invalid send through CIDR: ", address).Base(err)
+			}
+			ones, _ := ipNet.Mask.Size()
+			if ones == 0 {
+				return nil, nil, newError("invalid send through CIDR: ", address)
+			}
+			if ip4 := ip.To4(); ip4 != nil {
+				ip = ip4
+			}
+			pool.Prefix = append(pool.Prefix, &proxyman.SourceAddressPool_Prefix{
+				Ip:     ip,
+				Prefix: uint32(ones),
+			})
+			continue
+		}
+		addr := net.ParseAddress(address)
+		if !addr.Family().IsIP() {
+			return nil, nil, newError("unable to send through: " + address)
+		}
+		pool.Prefix = append(pool.Prefix, &proxyman.SourceAddressPool_Prefix{
+			Ip: addr.IP(),
+		})
+	}
+	return nil, pool, nil
+}
@@ -0,0 +1,57 @@
+package sendthroughcfg_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/v2fly/v2ray-core/v5/app/proxyman"
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon/sendthroughcfg"
+)
+
+func TestSendThroughSingleAddress(t *testing.T) {
+	var sendThrough sendthroughcfg.SendThrough
+	common.Must(json.Unmarshal([]byte(`"192.0.2.1"`), &sendThrough))
+
+	via, pool, err := sendThrough.Build("")
+	common.Must(err)
+	if pool != nil {
+		t.Error("unexpected pool: ", pool)
+	}
+	if !proto.Equal(via, net.NewIPOrDomain(net.ParseAddress("192.0.2.1"))) {
+		t.Error("unexpected via: ", via)
+	}
+}
+
+func TestSendThroughPool(t *testing.T) {
+	var sendThrough sendthroughcfg.SendThrough
+	common.Must(json.Unmarshal([]byte(`["2001:db8::/64", "192.0.2.1"]`), &sendThrough))
+
+	via, pool, err := sendThrough.Build("round-robin")
+	common.Must(err)
+	if via != nil {
+		t.Error("unexpected via: ", via)
+	}
+	expected := &proxyman.SourceAddressPool{
+		Prefix: []*proxyman.SourceAddressPool_Prefix{
+			{Ip: net.ParseAddress("2001:db8::").IP(), Prefix: 64},
+			{Ip: []byte{192, 0, 2, 1}},
+		},
+		Strategy: proxyman.SourceAddressPool_RoundRobin,
+	}
+	if !proto.Equal(pool, expected) {
+		t.Error("unexpected pool: ", pool)
+	}
+}
+
+func TestSendThroughDomain(t *testing.T) {
+	var sendThrough sendthroughcfg.SendThrough
+	common.Must(json.Unmarshal([]byte(`"v2fly.org"`), &sendThrough))
+
+	if _, _, err := sendThrough.Build(""); err == nil {
+		t.Error("expected error for domain address")
+	}
+}
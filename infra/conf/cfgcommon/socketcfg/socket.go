@@ -1,11 +1,14 @@
 package socketcfg
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/v2fly/v2ray-core/v5/transport/internet"
 )
 
+//go:generate go run github.com/v2fly/v2ray-core/v5/common/errors/errorgen
+
 type SocketConfig struct {
 	Mark                 uint32 `json:"mark"`
 	TFO                  *bool  `json:"tcpFastOpen"`
@@ -18,6 +21,34 @@ type SocketConfig struct {
 	RxBufSize            uint64 `json:"rxBufSize"`
 	TxBufSize            uint64 `json:"txBufSize"`
 	ForceBufSize         bool   `json:"forceBufSize"`
+	MPTCP                bool   `json:"mptcp"`
+	TCPCongestion        string `json:"tcpCongestion"`
+	TCPUserTimeout       uint32 `json:"tcpUserTimeout"`
+	TCPNotSentLowat      uint32 `json:"tcpNotSentLowat"`
+	UnixSocketMode       string `json:"unixSocketMode"`
+	UnixSocketOwner      string `json:"unixSocketOwner"`
+	UnixSocketGroup      string `json:"unixSocketGroup"`
+	DialUnixDatagram     string `json:"dialUnixDatagram"`
+	FreeBind             bool   `json:"freeBind"`
+
+	HappyEyeballs *HappyEyeballsConfig `json:"happyEyeballs"`
+}
+
+type HappyEyeballsConfig struct {
+	Disabled                bool   `json:"disabled"`
+	TryDelayMs              uint32 `json:"tryDelayMs"`
+	PreferIPv4              bool   `json:"preferIPv4"`
+	FirstAddressFamilyCount uint32 `json:"firstAddressFamilyCount"`
+}
+
+// Build implements Buildable.
+func (c *HappyEyeballsConfig) Build() (*internet.HappyEyeballsConfig, error) {
+	return &internet.HappyEyeballsConfig{
+		Disabled:                c.Disabled,
+		TryDelayMs:              c.TryDelayMs,
+		PreferIpv4:              c.PreferIPv4,
+		FirstAddressFamilyCount: c.FirstAddressFamilyCount,
+	}, nil
 }
 
 // Build implements Buildable.
@@ -46,6 +77,24 @@ func (c *SocketConfig) Build() (*internet.SocketConfig, error) {
 		tproxy = internet.SocketConfig_Off
 	}
 
+	var happyEyeballs *internet.HappyEyeballsConfig
+	if c.HappyEyeballs != nil {
+		he, err := c.HappyEyeballs.Build()
+		if err != nil {
+			return nil, err
+		}
+		happyEyeballs = he
+	}
+
+	var unixSocketMode uint32
+	if c.UnixSocketMode != "" {
+		mode, err := strconv.ParseUint(c.UnixSocketMode, 8, 32)
+		if err != nil || mode > 0777 {
+			return nil, newError("invalid unix socket mode: ", c.UnixSocketMode).Base(err)
+		}
+		unixSocketMode = uint32(mode)
+	}
+
 	return &internet.SocketConfig{
 		Mark:                 c.Mark,
 		Tfo:                  tfoSettings,
@@ -58,5 +107,15 @@ func (c *SocketConfig) Build() (*internet.SocketConfig, error) {
 		TxBufSize:            int64(c.TxBufSize),
 		ForceBufSize:         c.ForceBufSize,
 		BindToDevice:         c.BindToDevice,
+		HappyEyeballs:        happyEyeballs,
+		Mptcp:                c.MPTCP,
+		TcpCongestion:        c.TCPCongestion,
+		TcpUserTimeout:       c.TCPUserTimeout,
+		TcpNotSentLowat:      c.TCPNotSentLowat,
+		UnixSocketMode:       unixSocketMode,
+		UnixSocketOwner:      c.UnixSocketOwner,
+		UnixSocketGroup:      c.UnixSocketGroup,
+		DialUnixDatagram:     c.DialUnixDatagram,
+		FreeBind:             c.FreeBind,
 	}, nil
 }
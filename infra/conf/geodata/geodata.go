@@ -8,6 +8,9 @@ import (
 
 type loader struct {
 	LoaderImplementation
+	// asnLoader loads autonomous systems if the implementation does not support them,
+	// kept so that its cache of decoded files is shared by all rules.
+	asnLoader ASNLoaderImplementation
 }
 
 func (l *loader) LoadGeoSite(list string) ([]*routercommon.Domain, error) {
@@ -58,6 +61,29 @@ func (l *loader) LoadGeoIP(country string) ([]*routercommon.CIDR, error) {
 	return l.LoadIP("geoip.dat", country)
 }
 
+// LoadASN loads the IP ranges of the autonomous system with the mmdb loader, if the loader does not support it.
+func (l *loader) LoadASN(filename string, asn uint32) ([]*routercommon.CIDR, error) {
+	if impl, ok := l.LoaderImplementation.(ASNLoaderImplementation); ok {
+		return impl.LoadASN(filename, asn)
+	}
+	if l.asnLoader == nil {
+		mmdb, err := getGeoDataLoaderImplementation("mmdb")
+		if err != nil {
+			return nil, newError("autonomous systems are not supported by the geodata loader").Base(err)
+		}
+		impl, ok := mmdb.(ASNLoaderImplementation)
+		if !ok {
+			return nil, newError("autonomous systems are not supported by the mmdb loader")
+		}
+		l.asnLoader = impl
+	}
+	return l.asnLoader.LoadASN(filename, asn)
+}
+
+func (l *loader) LoadGeoASN(asn uint32) ([]*routercommon.CIDR, error) {
+	return l.LoadASN("asn.mmdb", asn)
+}
+
 var loaders map[string]func() LoaderImplementation
 
 func RegisterGeoDataLoaderImplementationCreator(name string, loader func() LoaderImplementation) {
@@ -77,7 +103,7 @@ func getGeoDataLoaderImplementation(name string) (LoaderImplementation, error) {
 func GetGeoDataLoader(name string) (Loader, error) {
 	loadImpl, err := getGeoDataLoaderImplementation(name)
 	if err == nil {
-		return &loader{loadImpl}, nil
+		return &loader{LoaderImplementation: loadImpl}, nil
 	}
 	return nil, err
 }
@@ -11,9 +11,16 @@ type LoaderImplementation interface {
 	LoadIP(filename, country string) ([]*routercommon.CIDR, error)
 }
 
+// ASNLoaderImplementation is implemented by loaders which load the IP ranges of autonomous systems.
+type ASNLoaderImplementation interface {
+	LoadASN(filename string, asn uint32) ([]*routercommon.CIDR, error)
+}
+
 type Loader interface {
 	LoaderImplementation
 	LoadGeoSite(list string) ([]*routercommon.Domain, error)
 	LoadGeoSiteWithAttr(file string, siteWithAttr string) ([]*routercommon.Domain, error)
 	LoadGeoIP(country string) ([]*routercommon.CIDR, error)
+	LoadASN(filename string, asn uint32) ([]*routercommon.CIDR, error)
+	LoadGeoASN(asn uint32) ([]*routercommon.CIDR, error)
 }
@@ -0,0 +1,205 @@
+package mmdb
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/v2fly/v2ray-core/v5/app/router/routercommon"
+	"github.com/v2fly/v2ray-core/v5/common/mmdb"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/platform/filesystem"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/geodata"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/geodata/text"
+)
+
+//go:generate go run github.com/v2fly/v2ray-core/v5/common/errors/errorgen
+
+// CountryCode returns the country code of the data of a country database, or an empty string.
+// The registered country is used if the country is unknown, such as for anycast networks.
+func CountryCode(data interface{}) string {
+	m, _ := data.(map[string]interface{})
+	for _, key := range []string{"country", "registered_country"} {
+		switch country := m[key].(type) {
+		case map[string]interface{}:
+			if code, ok := country["iso_code"].(string); ok && code != "" {
+				return code
+			}
+		case string:
+			if country != "" {
+				return country
+			}
+		}
+	}
+	return ""
+}
+
+// ASN returns the autonomous system number of the data of an ASN database, or 0.
+func ASN(data interface{}) uint32 {
+	m, _ := data.(map[string]interface{})
+	if asn, ok := m["autonomous_system_number"].(uint64); ok {
+		return uint32(asn)
+	}
+	// Some databases use the "asn" field with values like "AS13335".
+	if asn, ok := m["asn"].(string); ok {
+		if n, err := ParseASN(asn); err == nil {
+			return n
+		}
+	}
+	return 0
+}
+
+// ParseASN parses an autonomous system number, with an optional "AS" prefix.
+func ParseASN(s string) (uint32, error) {
+	if len(s) > 2 && strings.EqualFold(s[:2], "AS") {
+		s = s[2:]
+	}
+	asn, err := strconv.ParseUint(s, 10, 32)
+	if err != nil || asn == 0 {
+		return 0, newError("invalid autonomous system number ", s)
+	}
+	return uint32(asn), nil
+}
+
+// mmdbLoader loads IP ranges from MaxMind DBs, and other files with the text loader.
+type mmdbLoader struct {
+	readers  map[string]*mmdb.Reader
+	asns     map[string]map[uint32][]*routercommon.CIDR
+	fallback geodata.LoaderImplementation
+}
+
+func (l *mmdbLoader) open(filename string) (*mmdb.Reader, error) {
+	if reader, ok := l.readers[filename]; ok {
+		return reader, nil
+	}
+	content, err := filesystem.ReadAsset(filename)
+	if err != nil {
+		return nil, newError("failed to open file: ", filename).Base(err)
+	}
+	var reader *mmdb.Reader
+	if mmdb.IsMaxMindDB(content) {
+		if reader, err = mmdb.New(content); err != nil {
+			return nil, newError("failed to read ", filename).Base(err)
+		}
+	}
+	l.readers[filename] = reader
+	return reader, nil
+}
+
+// networks returns the networks in the database whose data matches.
+func networks(reader *mmdb.Reader, match func(data interface{}) bool) ([]*routercommon.CIDR, error) {
+	// Networks share the data of the same offset.
+	matched := make(map[int]bool)
+	var cidrs []*routercommon.CIDR
+	err := reader.Networks(func(network *net.IPNet, offset int) error {
+		m, ok := matched[offset]
+		if !ok {
+			data, err := reader.Decode(offset)
+			if err != nil {
+				return err
+			}
+			m = match(data)
+			matched[offset] = m
+		}
+		if m {
+			ones, _ := network.Mask.Size()
+			cidrs = append(cidrs, &routercommon.CIDR{Ip: network.IP, Prefix: uint32(ones)})
+		}
+		return nil
+	})
+	return cidrs, err
+}
+
+func (l *mmdbLoader) LoadSite(filename, list string) ([]*routercommon.Domain, error) {
+	reader, err := l.open(filename)
+	if err != nil {
+		return nil, err
+	}
+	if reader != nil {
+		return nil, newError("domain lists are not supported in MaxMind DB ", filename)
+	}
+	return l.fallback.LoadSite(filename, list)
+}
+
+func (l *mmdbLoader) LoadIP(filename, country string) ([]*routercommon.CIDR, error) {
+	reader, err := l.open(filename)
+	if err != nil {
+		return nil, err
+	}
+	if reader == nil {
+		return l.fallback.LoadIP(filename, country)
+	}
+	cidrs, err := networks(reader, func(data interface{}) bool {
+		return strings.EqualFold(CountryCode(data), country)
+	})
+	if err != nil {
+		return nil, newError("failed to read ", filename).Base(err)
+	}
+	if len(cidrs) == 0 {
+		return nil, newError("country not found in ", filename, ": ", country)
+	}
+	return cidrs, nil
+}
+
+// loadASNs returns the networks of every autonomous system in the file, decoded in one walk of the
+// database and cached, as configs usually have many ASN rules of the same file.
+func (l *mmdbLoader) loadASNs(filename string) (map[uint32][]*routercommon.CIDR, error) {
+	if asns, ok := l.asns[filename]; ok {
+		return asns, nil
+	}
+	reader, err := l.open(filename)
+	if err != nil {
+		return nil, err
+	}
+	if reader == nil {
+		return nil, newError("not a MaxMind DB: ", filename)
+	}
+	// Networks share the data of the same offset.
+	decoded := make(map[int]uint32)
+	asns := make(map[uint32][]*routercommon.CIDR)
+	err = reader.Networks(func(network *net.IPNet, offset int) error {
+		asn, ok := decoded[offset]
+		if !ok {
+			data, err := reader.Decode(offset)
+			if err != nil {
+				return err
+			}
+			asn = ASN(data)
+			decoded[offset] = asn
+		}
+		if asn != 0 {
+			ones, _ := network.Mask.Size()
+			asns[asn] = append(asns[asn], &routercommon.CIDR{Ip: network.IP, Prefix: uint32(ones)})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, newError("failed to read ", filename).Base(err)
+	}
+	l.asns[filename] = asns
+	return asns, nil
+}
+
+func (l *mmdbLoader) LoadASN(filename string, asn uint32) ([]*routercommon.CIDR, error) {
+	asns, err := l.loadASNs(filename)
+	if err != nil {
+		return nil, err
+	}
+	cidrs := asns[asn]
+	if len(cidrs) == 0 {
+		return nil, newError("autonomous system not found in ", filename, ": AS", asn)
+	}
+	return cidrs, nil
+}
+
+// NewLoader creates a loader of MaxMind DBs, which loads other files with the text loader.
+func NewLoader() geodata.LoaderImplementation {
+	return &mmdbLoader{
+		readers:  make(map[string]*mmdb.Reader),
+		asns:     make(map[string]map[uint32][]*routercommon.CIDR),
+		fallback: text.NewLoader(),
+	}
+}
+
+func init() {
+	geodata.RegisterGeoDataLoaderImplementationCreator("mmdb", NewLoader)
+}
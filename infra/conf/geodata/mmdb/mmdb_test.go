@@ -0,0 +1,84 @@
+package mmdb_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/mmdb/mmdbtest"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/geodata"
+	_ "github.com/v2fly/v2ray-core/v5/infra/conf/geodata/mmdb"
+	_ "github.com/v2fly/v2ray-core/v5/infra/conf/geodata/standard"
+)
+
+func country(code string) map[string]interface{} {
+	return map[string]interface{}{"country": map[string]interface{}{"iso_code": code}}
+}
+
+func TestLoader(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("v2ray.location.asset", dir)
+	common.Must(os.WriteFile(filepath.Join(dir, "geoip.dat"), mmdbtest.Build("GeoLite2-Country", 24,
+		mmdbtest.Network{CIDR: "1.0.0.0/24", Data: country("AU")},
+		mmdbtest.Network{CIDR: "1.0.1.0/24", Data: country("CN")},
+		mmdbtest.Network{CIDR: "2400:cb00::/32", Data: map[string]interface{}{
+			"registered_country": map[string]interface{}{"iso_code": "US"},
+		}},
+		mmdbtest.Network{CIDR: "2401::/32", Data: country("CN")},
+	), 0o644))
+	common.Must(os.WriteFile(filepath.Join(dir, "asn.mmdb"), mmdbtest.Build("GeoLite2-ASN", 28,
+		mmdbtest.Network{CIDR: "1.1.1.0/24", Data: map[string]interface{}{"autonomous_system_number": uint32(13335)}},
+		mmdbtest.Network{CIDR: "8.8.8.0/24", Data: map[string]interface{}{"autonomous_system_number": uint32(15169)}},
+		mmdbtest.Network{CIDR: "2606:4700::/32", Data: map[string]interface{}{"autonomous_system_number": uint32(13335)}},
+	), 0o644))
+	common.Must(os.WriteFile(filepath.Join(dir, "lists.txt"), []byte("example.com\n"), 0o644))
+
+	loader, err := geodata.GetGeoDataLoader("mmdb")
+	common.Must(err)
+
+	cidrs, err := loader.LoadGeoIP("cn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cidrs) != 2 || cidrs[0].Prefix != 24 || len(cidrs[0].Ip) != 4 || cidrs[1].Prefix != 32 || len(cidrs[1].Ip) != 16 {
+		t.Error("unexpected cidrs ", cidrs)
+	}
+	if cidrs, err := loader.LoadGeoIP("US"); err != nil || len(cidrs) != 1 {
+		t.Error("unexpected cidrs ", cidrs, err)
+	}
+	if _, err := loader.LoadGeoIP("JP"); err == nil {
+		t.Error("expected error for unknown country")
+	}
+
+	if cidrs, err := loader.LoadGeoASN(13335); err != nil || len(cidrs) != 2 {
+		t.Error("unexpected cidrs ", cidrs, err)
+	}
+	if _, err := loader.LoadASN("asn.mmdb", 1); err == nil {
+		t.Error("expected error for unknown asn")
+	}
+
+	// Other files are loaded with the text loader.
+	if domains, err := loader.LoadSite("lists.txt", "lists"); err != nil || len(domains) != 1 {
+		t.Error("unexpected domains ", domains, err)
+	}
+	if _, err := loader.LoadSite("asn.mmdb", "lists"); err == nil {
+		t.Error("expected error for domain lists in mmdb")
+	}
+
+	// Other loaders load autonomous systems with the mmdb loader.
+	standard, err := geodata.GetGeoDataLoader("standard")
+	common.Must(err)
+	if cidrs, err := standard.LoadGeoASN(15169); err != nil || len(cidrs) != 1 {
+		t.Error("unexpected cidrs ", cidrs, err)
+	}
+
+	// Files are decoded once for all autonomous systems.
+	common.Must(os.Remove(filepath.Join(dir, "asn.mmdb")))
+	if cidrs, err := loader.LoadGeoASN(15169); err != nil || len(cidrs) != 1 {
+		t.Error("unexpected cidrs ", cidrs, err)
+	}
+	if cidrs, err := standard.LoadGeoASN(13335); err != nil || len(cidrs) != 2 {
+		t.Error("unexpected cidrs ", cidrs, err)
+	}
+}
@@ -0,0 +1,142 @@
+package text
+
+import (
+	"bufio"
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/v2fly/v2ray-core/v5/app/router/routercommon"
+	"github.com/v2fly/v2ray-core/v5/app/ruleset"
+	"github.com/v2fly/v2ray-core/v5/common/platform/filesystem"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/geodata"
+)
+
+//go:generate go run github.com/v2fly/v2ray-core/v5/common/errors/errorgen
+
+// Text lists are rule sets in the format of ruleset.ParseRules, split into lists by lines of
+// "[name]". Rules before any such line belong to the list named after the file.
+
+// List is a list of domains and IP ranges.
+type List = ruleset.Rules
+
+var sectionPattern = regexp.MustCompile(`^\[([\w.!-]+)\]$`)
+
+// IsText returns whether the content is a text list rather than a binary geodata file.
+func IsText(content []byte) bool {
+	return bytes.IndexByte(content, 0) < 0 && utf8.Valid(content)
+}
+
+// Parse parses the content into lists by their lowercase names.
+func Parse(content []byte, defaultName string) (map[string]*List, error) {
+	lists := make(map[string]*List)
+	name := strings.ToLower(defaultName)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(nil, 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if ruleset.IsComment(text) {
+			continue
+		}
+		if strings.HasPrefix(text, "[") {
+			// Headers of adblock lists, such as "[Adblock Plus 2.0]", are ignored.
+			if m := sectionPattern.FindStringSubmatch(text); m != nil {
+				name = strings.ToLower(m[1])
+			}
+			continue
+		}
+		list := lists[name]
+		if list == nil {
+			list = new(List)
+			lists[name] = list
+		}
+		if err := list.Add(text); err != nil {
+			return nil, newError("invalid rule at line ", line).Base(err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+// textLoader loads lists from text files, and other files with the standard loader.
+type textLoader struct {
+	lists map[string]map[string]*List
+}
+
+func (l *textLoader) load(filename string) (map[string]*List, error) {
+	if lists, ok := l.lists[filename]; ok {
+		return lists, nil
+	}
+	content, err := filesystem.ReadAsset(filename)
+	if err != nil {
+		return nil, newError("failed to open file: ", filename).Base(err)
+	}
+	var lists map[string]*List
+	if IsText(content) {
+		name := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+		if lists, err = Parse(content, name); err != nil {
+			return nil, newError("failed to parse ", filename).Base(err)
+		}
+	}
+	l.lists[filename] = lists
+	return lists, nil
+}
+
+func find(lists map[string]*List, filename, name string) (*List, error) {
+	list, ok := lists[strings.ToLower(name)]
+	if !ok {
+		return nil, newError("list not found in ", filename, ": ", name)
+	}
+	return list, nil
+}
+
+func (l *textLoader) LoadSite(filename, list string) ([]*routercommon.Domain, error) {
+	lists, err := l.load(filename)
+	if err != nil {
+		return nil, err
+	}
+	if lists == nil {
+		standard, err := geodata.GetGeoDataLoader("standard")
+		if err != nil {
+			return nil, err
+		}
+		return standard.LoadSite(filename, list)
+	}
+	found, err := find(lists, filename, list)
+	if err != nil {
+		return nil, err
+	}
+	return found.Domains, nil
+}
+
+func (l *textLoader) LoadIP(filename, country string) ([]*routercommon.CIDR, error) {
+	lists, err := l.load(filename)
+	if err != nil {
+		return nil, err
+	}
+	if lists == nil {
+		standard, err := geodata.GetGeoDataLoader("standard")
+		if err != nil {
+			return nil, err
+		}
+		return standard.LoadIP(filename, country)
+	}
+	found, err := find(lists, filename, country)
+	if err != nil {
+		return nil, err
+	}
+	return found.CIDRs, nil
+}
+
+// NewLoader creates a loader of text lists, which loads other files with the standard loader.
+func NewLoader() geodata.LoaderImplementation {
+	return &textLoader{lists: make(map[string]map[string]*List)}
+}
+
+func init() {
+	geodata.RegisterGeoDataLoaderImplementationCreator("text", NewLoader)
+}
@@ -0,0 +1,104 @@
+package text_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	"github.com/v2fly/v2ray-core/v5/app/router/routercommon"
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/geodata"
+	_ "github.com/v2fly/v2ray-core/v5/infra/conf/geodata/standard"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/geodata/text"
+)
+
+func TestParse(t *testing.T) {
+	lists, err := text.Parse([]byte(`
+[Adblock Plus 2.0]
+! adblock comment
+||ads.example.com^
+||tracker.example.org^$third-party
+@@||good.example.com^
+example.com##.banner
+|https://example.net/ads
+# hosts file
+0.0.0.0 Hosts.Example.com other.example.com # comment
+127.0.0.1 localhost
+example.com
+10.0.0.0/8
+
+[cn]
+full:www.example.cn
+keyword:baidu
+regexp:\.cn$
+2001:db8::1
+`), "ads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]*text.List{
+		"ads": {
+			Domains: []*routercommon.Domain{
+				{Type: routercommon.Domain_RootDomain, Value: "ads.example.com"},
+				{Type: routercommon.Domain_Full, Value: "hosts.example.com"},
+				{Type: routercommon.Domain_Full, Value: "other.example.com"},
+				{Type: routercommon.Domain_RootDomain, Value: "example.com"},
+			},
+			CIDRs: []*routercommon.CIDR{
+				{Ip: []byte{10, 0, 0, 0}, Prefix: 8},
+			},
+		},
+		"cn": {
+			Domains: []*routercommon.Domain{
+				{Type: routercommon.Domain_Full, Value: "www.example.cn"},
+				{Type: routercommon.Domain_Plain, Value: "baidu"},
+				{Type: routercommon.Domain_Regex, Value: `\.cn$`},
+			},
+			CIDRs: []*routercommon.CIDR{
+				{Ip: []byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}, Prefix: 128},
+			},
+		},
+	}
+	if r := cmp.Diff(lists, expected, protocmp.Transform()); r != "" {
+		t.Error(r)
+	}
+
+	if _, err := text.Parse([]byte("regexp:("), "test"); err == nil {
+		t.Error("expected error for invalid regexp")
+	}
+}
+
+func TestLoader(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("v2ray.location.asset", dir)
+	common.Must(os.WriteFile(filepath.Join(dir, "lists.txt"), []byte("example.com\n10.0.0.0/8\n[cn]\nexample.cn\n"), 0o644))
+	geoip, err := proto.Marshal(&routercommon.GeoIPList{Entry: []*routercommon.GeoIP{
+		{CountryCode: "PRIVATE", Cidr: []*routercommon.CIDR{{Ip: []byte{192, 168, 0, 0}, Prefix: 16}}},
+	}})
+	common.Must(err)
+	common.Must(os.WriteFile(filepath.Join(dir, "geoip.dat"), geoip, 0o644))
+
+	loader, err := geodata.GetGeoDataLoader("text")
+	common.Must(err)
+
+	if domains, err := loader.LoadSite("lists.txt", "lists"); err != nil || len(domains) != 1 {
+		t.Error("unexpected domains ", domains, err)
+	}
+	if domains, err := loader.LoadGeoSiteWithAttr("lists.txt", "CN"); err != nil || len(domains) != 1 || domains[0].Value != "example.cn" {
+		t.Error("unexpected domains ", domains, err)
+	}
+	if cidrs, err := loader.LoadIP("lists.txt", "lists"); err != nil || len(cidrs) != 1 {
+		t.Error("unexpected cidrs ", cidrs, err)
+	}
+	if _, err := loader.LoadSite("lists.txt", "unknown"); err == nil {
+		t.Error("expected error for unknown list")
+	}
+	// Binary files are loaded with the standard loader.
+	if cidrs, err := loader.LoadGeoIP("private"); err != nil || len(cidrs) != 1 {
+		t.Error("unexpected cidrs ", cidrs, err)
+	}
+}
@@ -0,0 +1,145 @@
+// Package interpolate substitutes references to environment variables and files in string values of
+// configs, so that secrets such as UUIDs, passwords and keys need not be written into config files.
+//
+// A reference is written as "${env:NAME}" or "${file:PATH}", and may give a default value used if the
+// environment variable is unset or empty, or the file does not exist, like "${env:NAME:-default}".
+// "$${" is an escaped "${". Contents of files are substituted with trailing line breaks removed.
+package interpolate
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+)
+
+//go:generate go run github.com/v2fly/v2ray-core/v5/common/errors/errorgen
+
+// Redacted replaces substituted values when redacting.
+const Redacted = "<redacted>"
+
+// Interpolator substitutes references in configs.
+type Interpolator struct {
+	// LookupEnv looks up environment variables.
+	LookupEnv func(name string) (string, bool)
+	// ReadFile reads files.
+	ReadFile func(path string) ([]byte, error)
+	// Redact makes references substituted with Redacted, without looking up their values.
+	Redact bool
+}
+
+// Default is the interpolator applied when loading configs.
+var Default = &Interpolator{
+	LookupEnv: os.LookupEnv,
+	ReadFile:  os.ReadFile,
+}
+
+// String returns the string with references substituted.
+func (i *Interpolator) String(s string) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		if start > 0 && s[start-1] == '$' {
+			// Escaped.
+			b.WriteString(s[:start-1])
+			b.WriteString("${")
+			s = s[start+2:]
+			continue
+		}
+		b.WriteString(s[:start])
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			return "", newError("unterminated reference: ", s[start:])
+		}
+		value, err := i.resolve(s[start+2 : start+end])
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(value)
+		s = s[start+end+1:]
+	}
+}
+
+func (i *Interpolator) resolve(reference string) (string, error) {
+	kind, name, found := strings.Cut(reference, ":")
+	if !found || name == "" {
+		return "", newError("invalid reference: ${", reference, "}")
+	}
+	name, defaultValue, hasDefault := strings.Cut(name, ":-")
+	if kind != "env" && kind != "file" {
+		return "", newError("unknown kind of reference: ${", reference, "}")
+	}
+	if i.Redact {
+		return Redacted, nil
+	}
+
+	switch kind {
+	case "env":
+		if value, found := i.LookupEnv(name); found && value != "" {
+			return value, nil
+		} else if hasDefault {
+			return defaultValue, nil
+		} else if !found {
+			return "", newError("environment variable ", name, " is not set")
+		}
+		return "", nil
+	default:
+		content, err := i.ReadFile(name)
+		if err != nil {
+			if hasDefault && errors.Is(err, fs.ErrNotExist) {
+				return defaultValue, nil
+			}
+			return "", newError("failed to read ", name).Base(err)
+		}
+		return strings.TrimRight(string(content), "\r\n"), nil
+	}
+}
+
+// Value substitutes references in strings within the value decoded from JSON, and returns the result.
+// Maps and slices are modified in place. Keys of maps are not substituted.
+func (i *Interpolator) Value(v interface{}) (interface{}, error) {
+	return i.value(v, "")
+}
+
+func (i *Interpolator) value(v interface{}, path string) (interface{}, error) {
+	switch v := v.(type) {
+	case string:
+		s, err := i.String(v)
+		if err != nil {
+			return nil, newError("failed to interpolate ", path).Base(err)
+		}
+		return s, nil
+	case map[string]interface{}:
+		for key, value := range v {
+			result, err := i.value(value, join(path, key))
+			if err != nil {
+				return nil, err
+			}
+			v[key] = result
+		}
+	case []interface{}:
+		for idx, value := range v {
+			result, err := i.value(value, join(path, strconv.Itoa(idx)))
+			if err != nil {
+				return nil, err
+			}
+			v[idx] = result
+		}
+	}
+	return v, nil
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,131 @@
+package interpolate_test
+
+import (
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/infra/conf/interpolate"
+)
+
+func newInterpolator() *interpolate.Interpolator {
+	env := map[string]string{
+		"ID":    "b831381d-6324-4d53-ad4f-8cda48b30811",
+		"EMPTY": "",
+	}
+	files := map[string]string{
+		"/run/secrets/password": "secret\n",
+	}
+	return &interpolate.Interpolator{
+		LookupEnv: func(name string) (string, bool) {
+			value, found := env[name]
+			return value, found
+		},
+		ReadFile: func(path string) ([]byte, error) {
+			content, found := files[path]
+			if !found {
+				return nil, &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist}
+			}
+			return []byte(content), nil
+		},
+	}
+}
+
+func TestString(t *testing.T) {
+	cases := []struct {
+		input  string
+		output string
+		err    bool
+	}{
+		{input: "plain", output: "plain"},
+		{input: "${env:ID}", output: "b831381d-6324-4d53-ad4f-8cda48b30811"},
+		{input: "id=${env:ID};", output: "id=b831381d-6324-4d53-ad4f-8cda48b30811;"},
+		{input: "${file:/run/secrets/password}", output: "secret"},
+		{input: "${env:EMPTY}", output: ""},
+		{input: "${env:EMPTY:-default}", output: "default"},
+		{input: "${env:UNSET:-default}", output: "default"},
+		{input: "${env:UNSET:-}", output: ""},
+		{input: "${file:/nonexistent:-a:-b}", output: "a:-b"},
+		{input: "$${env:ID}", output: "${env:ID}"},
+		{input: "$$${env:EMPTY}", output: "$${env:EMPTY}"},
+		{input: "${env:UNSET}", err: true},
+		{input: "${file:/nonexistent}", err: true},
+		{input: "${vault:ID}", err: true},
+		{input: "${env:}", err: true},
+		{input: "${env:ID", err: true},
+	}
+	i := newInterpolator()
+	for _, c := range cases {
+		output, err := i.String(c.input)
+		if c.err {
+			if err == nil {
+				t.Error("expected error of ", c.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Error(c.input, ": ", err)
+		} else if output != c.output {
+			t.Error(c.input, ": expected ", c.output, ", got ", output)
+		}
+	}
+}
+
+func TestRedact(t *testing.T) {
+	i := newInterpolator()
+	i.Redact = true
+	output, err := i.String("id=${env:UNSET} password=${file:/run/secrets/password}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "id=<redacted> password=<redacted>"; output != expected {
+		t.Error("expected ", expected, ", got ", output)
+	}
+	if _, err := i.String("${vault:ID}"); err == nil {
+		t.Error("expected error of unknown kind")
+	}
+}
+
+func TestValue(t *testing.T) {
+	i := newInterpolator()
+	v := map[string]interface{}{
+		"inbounds": []interface{}{
+			map[string]interface{}{
+				"port": 443,
+				"settings": map[string]interface{}{
+					"clients":  []interface{}{map[string]interface{}{"id": "${env:ID}"}},
+					"password": "${file:/run/secrets/password}",
+				},
+			},
+		},
+		"${env:ID}": true,
+	}
+	result, err := i.Value(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"inbounds": []interface{}{
+			map[string]interface{}{
+				"port": 443,
+				"settings": map[string]interface{}{
+					"clients":  []interface{}{map[string]interface{}{"id": "b831381d-6324-4d53-ad4f-8cda48b30811"}},
+					"password": "secret",
+				},
+			},
+		},
+		"${env:ID}": true,
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Error("unexpected result: ", result)
+	}
+
+	_, err = i.Value(map[string]interface{}{"outbounds": []interface{}{map[string]interface{}{"tag": "${env:UNSET}"}}})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if msg := err.Error(); !strings.Contains(msg, "outbounds.0.tag") {
+		t.Error("error should locate the value: ", msg)
+	}
+}
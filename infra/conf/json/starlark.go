@@ -0,0 +1,108 @@
+package json
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	starjson "go.starlark.net/lib/json"
+	"go.starlark.net/starlark"
+)
+
+// StarlarkConfigVar is the global variable of Starlark scripts holding the config.
+const StarlarkConfigVar = "config"
+
+// starlarkMaxExecutionSteps limits the computation of each script and module, so that a script
+// looping forever fails to load instead of hanging.
+const starlarkMaxExecutionSteps = 10000000
+
+// FromStarlark evaluates the Starlark script, and converts the config it assigns to the global
+// variable "config" to json. Relative paths in load() statements and read_file() calls are
+// resolved against dir, so that scripts may share modules and read data files beside them.
+// Absolute paths are accepted as well, so scripts may read any file readable by the process.
+//
+// Besides the built-ins of Starlark, scripts may use the "json" module to encode and decode json,
+// and read_file(path) to read the content of a file as a string.
+func FromStarlark(v []byte, dir string) ([]byte, error) {
+	l := &starlarkLoader{
+		dir:     dir,
+		modules: make(map[string]*starlarkModule),
+	}
+	thread := &starlark.Thread{Name: "config", Load: l.load}
+	thread.SetMaxExecutionSteps(starlarkMaxExecutionSteps)
+	globals, err := starlark.ExecFile(thread, "config.star", v, l.predeclared())
+	if err != nil {
+		return nil, err
+	}
+	config, found := globals[StarlarkConfigVar]
+	if !found {
+		return nil, fmt.Errorf("global variable %q is not assigned", StarlarkConfigVar)
+	}
+	if _, ok := config.(*starlark.Dict); !ok {
+		return nil, fmt.Errorf("config should be a dict, got %s", config.Type())
+	}
+	encoded, err := starlark.Call(thread, starjson.Module.Members["encode"], starlark.Tuple{config}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(encoded.(starlark.String)), nil
+}
+
+type starlarkModule struct {
+	globals starlark.StringDict
+	err     error
+}
+
+type starlarkLoader struct {
+	dir     string
+	modules map[string]*starlarkModule
+}
+
+func (l *starlarkLoader) path(name string) string {
+	if filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(l.dir, name)
+}
+
+func (l *starlarkLoader) predeclared() starlark.StringDict {
+	return starlark.StringDict{
+		"json":      starjson.Module,
+		"read_file": starlark.NewBuiltin("read_file", l.readFile),
+	}
+}
+
+func (l *starlarkLoader) readFile(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &name); err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(l.path(name))
+	if err != nil {
+		return nil, err
+	}
+	return starlark.String(content), nil
+}
+
+// load loads Starlark modules once, and detects cyclic loads.
+func (l *starlarkLoader) load(thread *starlark.Thread, module string) (starlark.StringDict, error) {
+	path := l.path(module)
+	m, found := l.modules[path]
+	if found {
+		if m == nil {
+			return nil, fmt.Errorf("cyclic load of %s", module)
+		}
+		return m.globals, m.err
+	}
+	l.modules[path] = nil
+	content, err := os.ReadFile(path)
+	if err != nil {
+		l.modules[path] = &starlarkModule{err: err}
+		return nil, err
+	}
+	loadThread := &starlark.Thread{Name: "load " + module, Load: l.load}
+	loadThread.SetMaxExecutionSteps(starlarkMaxExecutionSteps)
+	globals, err := starlark.ExecFile(loadThread, path, content, l.predeclared())
+	l.modules[path] = &starlarkModule{globals: globals, err: err}
+	return globals, err
+}
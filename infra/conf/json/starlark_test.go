@@ -0,0 +1,72 @@
+package json_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/v2fly/v2ray-core/v5/infra/conf/json"
+)
+
+func TestStarlarkToJSON(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"customers.json": `[{"name": "alice", "port": 10001}, {"name": "bob", "port": 10002}]`,
+		"lib.star": `
+def inbound(customer):
+    return {
+        "tag": "in-" + customer["name"],
+        "port": customer["port"],
+        "protocol": "socks",
+    }
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	input := `
+load("lib.star", "inbound")
+customers = json.decode(read_file("customers.json"))
+config = {
+    "log": {"loglevel": "debug"},
+    "inbounds": [inbound(c) for c in customers],
+}
+`
+	expected := `
+{
+    "log": {
+        "loglevel": "debug"
+    },
+    "inbounds": [
+        {"tag": "in-alice", "port": 10001, "protocol": "socks"},
+        {"tag": "in-bob", "port": 10002, "protocol": "socks"}
+    ]
+}
+`
+	bs, err := FromStarlark([]byte(input), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := make(map[string]interface{})
+	json.Unmarshal(bs, &m)
+	assertResult(t, m, expected)
+}
+
+func TestStarlarkToJSON_Errors(t *testing.T) {
+	for _, input := range []string{
+		`x = 1`,
+		`config = [1]`,
+		`config = {"inbounds": undefined}`,
+		`load("nonexistent.star", "x")`,
+		// Scripts running too long are stopped.
+		"def f():\n  for i in range(1000000000):\n    pass\nf()\nconfig = {}",
+	} {
+		if _, err := FromStarlark([]byte(input), t.TempDir()); err == nil {
+			t.Error("expected error of ", input)
+		}
+	}
+}
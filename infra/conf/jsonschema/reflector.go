@@ -0,0 +1,276 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/golang/protobuf/jsonpb"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/v2fly/v2ray-core/v5/common/protoext"
+)
+
+// Describer is implemented by config types with custom JSON decoding, to describe the JSON values they accept.
+type Describer interface {
+	JSONSchema(r *Reflector) *Schema
+}
+
+var (
+	describerType   = reflect.TypeOf((*Describer)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	rawMessageType  = reflect.TypeOf(json.RawMessage(nil))
+
+	jsonpbUnmarshalerType = reflect.TypeOf((*jsonpb.JSONPBUnmarshaler)(nil)).Elem()
+)
+
+const definitionsPrefix = "#/definitions/"
+
+// Reflector generates schemas of Go types decoded by encoding/json, and protobuf messages decoded by jsonpb.
+// Structs and messages are put into definitions and referred to by name.
+type Reflector struct {
+	definitions map[string]*Schema
+	goTypes     map[string]reflect.Type
+}
+
+func NewReflector() *Reflector {
+	return &Reflector{
+		definitions: make(map[string]*Schema),
+		goTypes:     make(map[string]reflect.Type),
+	}
+}
+
+// Definition returns the definition referred to by the schema, or nil if it is not a reference.
+func (r *Reflector) Definition(ref *Schema) *Schema {
+	if !strings.HasPrefix(ref.Ref, definitionsPrefix) {
+		return nil
+	}
+	return r.definitions[strings.TrimPrefix(ref.Ref, definitionsPrefix)]
+}
+
+// Root makes the schema a root schema, which holds all definitions.
+func (r *Reflector) Root(schema *Schema) *Schema {
+	schema.Schema = Draft
+	schema.Definitions = r.definitions
+	return schema
+}
+
+// Reflect returns the schema of JSON values decoded into the Go type. Unknown properties are allowed,
+// as encoding/json ignores them. Types with custom JSON decoding accept any value, unless they are Describers.
+func (r *Reflector) Reflect(t reflect.Type) *Schema {
+	if t == rawMessageType {
+		return Any()
+	}
+	if t.Kind() != reflect.Ptr && reflect.PtrTo(t).Implements(describerType) {
+		return reflect.New(t).Interface().(Describer).JSONSchema(r)
+	}
+	if t.Kind() == reflect.Ptr {
+		return r.Reflect(t.Elem())
+	}
+	if reflect.PtrTo(t).Implements(unmarshalerType) {
+		return Any()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return Boolean()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Integer()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Unsigned()
+	case reflect.Float32, reflect.Float64:
+		return Number()
+	case reflect.String:
+		return String()
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return String().WithDescription("base64 encoded bytes")
+		}
+		return Array(r.Reflect(t.Elem()))
+	case reflect.Map:
+		return Map(r.Reflect(t.Elem()))
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.reflectStruct(t)
+		}
+		name := r.goTypeName(t)
+		if _, found := r.definitions[name]; !found {
+			// The definition is added before the fields are reflected, for recursive types.
+			def := &Schema{}
+			r.definitions[name] = def
+			*def = *r.reflectStruct(t)
+		}
+		return &Schema{Ref: definitionsPrefix + name}
+	default:
+		return Any()
+	}
+}
+
+// goTypeName returns the definition name of the Go type, like "v4.VMessInboundConfig".
+func (r *Reflector) goTypeName(t reflect.Type) string {
+	name := path.Base(t.PkgPath()) + "." + t.Name()
+	if existing, found := r.goTypes[name]; found && existing != t {
+		name = strings.ReplaceAll(t.PkgPath(), "/", ".") + "." + t.Name()
+	}
+	r.goTypes[name] = t
+	return name
+}
+
+func (r *Reflector) reflectStruct(t reflect.Type) *Schema {
+	s := Object(make(map[string]*Schema))
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct &&
+			!reflect.PtrTo(fieldType).Implements(unmarshalerType) {
+			// Fields of embedded structs are promoted.
+			for k, v := range r.reflectStruct(fieldType).Properties {
+				if _, found := s.Properties[k]; !found {
+					s.Properties[k] = v
+				}
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		s.Properties[name] = r.Reflect(field.Type)
+	}
+	return s
+}
+
+// ReflectMessage returns the schema of JSON values decoded into the protobuf message by jsonpb,
+// which accepts both the JSON names and the original names of fields, and rejects unknown fields.
+func (r *Reflector) ReflectMessage(desc protoreflect.MessageDescriptor) *Schema {
+	switch desc.FullName() {
+	case "google.protobuf.Any":
+		return Object(map[string]*Schema{
+			"@type": String().WithDescription("type URL of the message, like \"types.v2fly.org/v2ray.core.app.log.Config\""),
+		}).WithRequired("@type")
+	case "google.protobuf.Duration", "google.protobuf.Timestamp", "google.protobuf.FieldMask":
+		return String()
+	case "google.protobuf.Struct":
+		return Map(Any())
+	case "google.protobuf.Value":
+		return Any()
+	case "google.protobuf.ListValue":
+		return Array(Any())
+	case "v2ray.core.common.net.IPOrDomain":
+		return String().WithDescription("IP address or domain")
+	case "v2ray.core.common.net.NetworkList":
+		return AnyOf(Array(String()), String().WithDescription("comma separated list of networks, like \"tcp,udp\""))
+	}
+	if hasCustomJSONPB(desc) {
+		return Any()
+	}
+
+	name := string(desc.FullName())
+	if _, found := r.definitions[name]; !found {
+		def := Object(make(map[string]*Schema))
+		def.AdditionalProperties = Never()
+		r.definitions[name] = def
+		fields := desc.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			fd := fields.Get(i)
+			opt, _ := protoext.GetFieldOptions(fd)
+			if opt.GetForbidden() {
+				continue
+			}
+			s := r.reflectField(fd, opt)
+			def.Properties[fd.JSONName()] = s
+			if string(fd.Name()) != fd.JSONName() {
+				def.Properties[string(fd.Name())] = s
+			}
+		}
+	}
+	return &Schema{Ref: definitionsPrefix + name}
+}
+
+func (r *Reflector) reflectField(fd protoreflect.FieldDescriptor, opt *protoext.FieldOpt) *Schema {
+	if fd.IsMap() {
+		return Map(r.reflectValue(fd.MapValue(), nil))
+	}
+	s := r.reflectValue(fd, opt)
+	if fd.IsList() {
+		return Array(s)
+	}
+	return s
+}
+
+func (r *Reflector) reflectValue(fd protoreflect.FieldDescriptor, opt *protoext.FieldOpt) *Schema {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return Boolean()
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return Integer()
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return Unsigned()
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		// 64-bit integers may be quoted.
+		return AnyOf(Integer(), String())
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return AnyOf(Unsigned(), String())
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return Number()
+	case protoreflect.StringKind:
+		if values := opt.GetAllowedValues(); len(values) > 0 {
+			return StringEnum(values...)
+		}
+		return String()
+	case protoreflect.BytesKind:
+		return String().WithDescription("base64 encoded bytes")
+	case protoreflect.EnumKind:
+		if hasCustomJSONPB(fd.Enum()) {
+			return String()
+		}
+		values := fd.Enum().Values()
+		names := make([]string, 0, values.Len())
+		for i := 0; i < values.Len(); i++ {
+			names = append(names, string(values.Get(i).Name()))
+		}
+		return AnyOf(StringEnum(names...), Integer())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		s := r.ReflectMessage(fd.Message())
+		if wants := opt.GetAnyWants(); len(wants) > 0 && fd.Message().FullName() == "google.protobuf.Any" {
+			s.Description = "an implementation of " + strings.Join(wants, " or ")
+		}
+		return s
+	default:
+		return Any()
+	}
+}
+
+// hasCustomJSONPB returns whether the message or enum is decoded by its own UnmarshalJSONPB method.
+func hasCustomJSONPB(desc protoreflect.Descriptor) bool {
+	var t reflect.Type
+	switch desc := desc.(type) {
+	case protoreflect.MessageDescriptor:
+		messageType, err := protoregistry.GlobalTypes.FindMessageByName(desc.FullName())
+		if err != nil {
+			return false
+		}
+		t = reflect.TypeOf(messageType.Zero().Interface())
+	case protoreflect.EnumDescriptor:
+		enumType, err := protoregistry.GlobalTypes.FindEnumByName(desc.FullName())
+		if err != nil {
+			return false
+		}
+		t = reflect.PtrTo(reflect.TypeOf(enumType.New(0)))
+	default:
+		return false
+	}
+	return t.Implements(jsonpbUnmarshalerType)
+}
@@ -0,0 +1,112 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/app/log"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/jsonschema"
+)
+
+type describedPort struct{}
+
+func (*describedPort) UnmarshalJSON([]byte) error { return nil }
+
+func (*describedPort) JSONSchema(*jsonschema.Reflector) *jsonschema.Schema {
+	return jsonschema.Unsigned()
+}
+
+type embedded struct {
+	Tag string `json:"tag"`
+}
+
+type testConfig struct {
+	embedded
+	Name     string            `json:"name"`
+	Ports    []*describedPort  `json:"ports"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Raw      json.RawMessage   `json:"raw"`
+	Ignored  string            `json:"-"`
+	Next     *testConfig       `json:"next"`
+	internal string
+}
+
+func TestReflect(t *testing.T) {
+	r := jsonschema.NewReflector()
+	ref := r.Reflect(reflect.TypeOf(testConfig{}))
+	if ref.Ref != "#/definitions/jsonschema_test.testConfig" {
+		t.Fatal("unexpected reference: ", ref.Ref)
+	}
+	def := r.Definition(ref)
+
+	var names []string
+	for name := range def.Properties {
+		names = append(names, name)
+	}
+	if len(names) != 6 {
+		t.Error("unexpected properties: ", names)
+	}
+	for _, name := range []string{"tag", "name", "labels", "next"} {
+		if def.Properties[name] == nil {
+			t.Error("missing property ", name)
+		}
+	}
+	if s := def.Properties["ports"]; s.Type != "array" || s.Items.Type != "integer" || *s.Items.Minimum != 0 {
+		t.Error("unexpected schema of ports: ", s)
+	}
+	if s := def.Properties["raw"]; !reflect.DeepEqual(s, jsonschema.Any()) {
+		t.Error("unexpected schema of raw: ", s)
+	}
+	if s := def.Properties["next"]; s.Ref != ref.Ref {
+		t.Error("unexpected schema of next: ", s)
+	}
+	if def.AdditionalProperties != nil {
+		t.Error("Go structs should allow unknown properties")
+	}
+}
+
+func TestReflectMessage(t *testing.T) {
+	r := jsonschema.NewReflector()
+	ref := r.ReflectMessage((&log.Config{}).ProtoReflect().Descriptor())
+	def := r.Definition(ref)
+	if def == nil {
+		t.Fatal("no definition of ", ref.Ref)
+	}
+	if def.AdditionalProperties == nil || def.AdditionalProperties.Not == nil {
+		t.Error("messages should reject unknown fields")
+	}
+	access := def.Properties["access"]
+	if access == nil {
+		t.Fatal("missing field access")
+	}
+	spec := r.Definition(access)
+	if spec == nil {
+		t.Fatal("no definition of ", access.Ref)
+	}
+	level := spec.Properties["level"]
+	if level == nil || len(level.AnyOf) != 2 || len(level.AnyOf[0].Enum) == 0 {
+		t.Error("unexpected schema of enum: ", level)
+	}
+}
+
+func TestRoot(t *testing.T) {
+	r := jsonschema.NewReflector()
+	root := r.Root(jsonschema.Object(map[string]*jsonschema.Schema{
+		"config": r.Reflect(reflect.TypeOf(testConfig{})),
+	}))
+	encoded, err := json.Marshal(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded["$schema"] != jsonschema.Draft {
+		t.Error("unexpected $schema: ", decoded["$schema"])
+	}
+	if _, found := decoded["definitions"].(map[string]interface{})["jsonschema_test.testConfig"]; !found {
+		t.Error("missing definition: ", string(encoded))
+	}
+}
@@ -0,0 +1,101 @@
+// Package jsonschema generates JSON Schema (draft-07) of config formats, so that editors and CI can validate configs.
+package jsonschema
+
+// Draft is the URI of the JSON Schema version of generated schemas.
+const Draft = "http://json-schema.org/draft-07/schema#"
+
+// Schema is a JSON Schema. Only keywords used by the config formats are supported.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Const                interface{}        `json:"const,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Not                  *Schema            `json:"not,omitempty"`
+	If                   *Schema            `json:"if,omitempty"`
+	Then                 *Schema            `json:"then,omitempty"`
+	Definitions          map[string]*Schema `json:"definitions,omitempty"`
+}
+
+// Any returns the schema matching any value.
+func Any() *Schema {
+	return &Schema{}
+}
+
+// Never returns the schema matching no value, such as the additional properties of strict objects.
+func Never() *Schema {
+	return &Schema{Not: &Schema{}}
+}
+
+func String() *Schema {
+	return &Schema{Type: "string"}
+}
+
+func Boolean() *Schema {
+	return &Schema{Type: "boolean"}
+}
+
+func Integer() *Schema {
+	return &Schema{Type: "integer"}
+}
+
+// Unsigned returns the schema of non-negative integers.
+func Unsigned() *Schema {
+	min := 0.0
+	return &Schema{Type: "integer", Minimum: &min}
+}
+
+func Number() *Schema {
+	return &Schema{Type: "number"}
+}
+
+// Array returns the schema of arrays of the items.
+func Array(items *Schema) *Schema {
+	return &Schema{Type: "array", Items: items}
+}
+
+// Object returns the schema of objects with the properties.
+func Object(properties map[string]*Schema) *Schema {
+	return &Schema{Type: "object", Properties: properties}
+}
+
+// Map returns the schema of objects whose properties are all of the values.
+func Map(values *Schema) *Schema {
+	return &Schema{Type: "object", AdditionalProperties: values}
+}
+
+// AnyOf returns the schema of values matching any of the schemas.
+func AnyOf(schemas ...*Schema) *Schema {
+	return &Schema{AnyOf: schemas}
+}
+
+// StringEnum returns the schema of strings among the values.
+func StringEnum(values ...string) *Schema {
+	s := String()
+	for _, v := range values {
+		s.Enum = append(s.Enum, v)
+	}
+	return s
+}
+
+// WithDescription sets the description of the schema and returns it.
+func (s *Schema) WithDescription(description string) *Schema {
+	s.Description = description
+	return s
+}
+
+// WithRequired adds required properties to the schema and returns it.
+func (s *Schema) WithRequired(names ...string) *Schema {
+	s.Required = append(s.Required, names...)
+	return s
+}
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"encoding/json"
 
+	"github.com/v2fly/v2ray-core/v5/infra/conf/interpolate"
 	"github.com/v2fly/v2ray-core/v5/infra/conf/serial"
 )
 
@@ -32,7 +33,8 @@ func JSONs(args [][]byte) ([]byte, error) {
 	return FromMap(m)
 }
 
-// ToMap merges json content to target map and returns it
+// ToMap merges json content to target map and returns it.
+// References to environment variables and files in the content are substituted before merging.
 func ToMap(content []byte, target map[string]interface{}) (map[string]interface{}, error) {
 	if target == nil {
 		target = make(map[string]interface{})
@@ -43,12 +45,20 @@ func ToMap(content []byte, target map[string]interface{}) (map[string]interface{
 	if err != nil {
 		return nil, err
 	}
+	if _, err = interpolate.Default.Value(n); err != nil {
+		return nil, err
+	}
 	if err = mergeMaps(target, n); err != nil {
 		return nil, err
 	}
 	return target, nil
 }
 
+// Maps merges source into target.
+func Maps(target map[string]interface{}, source map[string]interface{}) error {
+	return mergeMaps(target, source)
+}
+
 // FromMap apply merge rules to map and convert it to json
 func FromMap(target map[string]interface{}) ([]byte, error) {
 	if target == nil {
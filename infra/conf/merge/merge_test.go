@@ -209,3 +209,19 @@ func assertResult(t *testing.T, value []byte, expected string) {
 		t.Fatalf("expected:\n%s\n\nactual:\n%s", expected, string(value))
 	}
 }
+
+func TestMergeInterpolation(t *testing.T) {
+	t.Setenv("V2RAY_MERGE_TEST", "secret")
+	m, err := merge.ToMap([]byte(`{"password": "${env:V2RAY_MERGE_TEST}", "path": "/$${HOME}/x"}`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m["password"] != "secret" || m["path"] != "/${HOME}/x" {
+		t.Error("unexpected interpolation ", m)
+	}
+
+	// Literal "${" in configs of earlier versions must be escaped now.
+	if _, err := merge.ToMap([]byte(`{"password": "a${b}c"}`), nil); err == nil {
+		t.Error("expected error for unescaped literal ${")
+	}
+}
@@ -0,0 +1,103 @@
+package mergers
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/v2fly/v2ray-core/v5/infra/conf/merge"
+)
+
+// includeKey is the top-level key of config files listing the files to include.
+const includeKey = "_include"
+
+// mergeContent merges the JSON content into target, after the files it includes.
+// Relative paths of included files are resolved against dir.
+func mergeContent(content []byte, dir string, target map[string]interface{}, includedBy []string, loaded map[string]bool) error {
+	m, err := merge.ToMap(content, nil)
+	if err != nil {
+		return err
+	}
+	includes, err := popIncludes(m)
+	if err != nil {
+		return err
+	}
+	for _, pattern := range includes {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(dir, pattern)
+		}
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			return newError("invalid include pattern: ", pattern).Base(err)
+		}
+		if files == nil && !hasMeta(pattern) {
+			// Report the missing file when loading it.
+			files = []string{pattern}
+		}
+		for _, file := range files {
+			// Patterns like "*.json" may match the including file itself.
+			if hasMeta(pattern) && len(includedBy) > 0 && sameFile(file, includedBy[len(includedBy)-1]) {
+				continue
+			}
+			if err := includeFile(file, target, includedBy, loaded); err != nil {
+				return err
+			}
+		}
+	}
+	return merge.Maps(target, m)
+}
+
+func includeFile(file string, target map[string]interface{}, includedBy []string, loaded map[string]bool) error {
+	for _, f := range includedBy {
+		if sameFile(f, file) {
+			return newError("cyclic include of ", file)
+		}
+	}
+	m, found := mergersByExt[getExtension(file)]
+	if !found {
+		return newError("unmergeable format extension of included file: ", file)
+	}
+	if err := loadFile(file, target, m.converter, includedBy, loaded); err != nil {
+		return newError("failed to include ", file).Base(err)
+	}
+	return nil
+}
+
+// popIncludes removes the include directive from m, and returns the files it lists.
+func popIncludes(m map[string]interface{}) ([]string, error) {
+	value, found := m[includeKey]
+	if !found {
+		return nil, nil
+	}
+	delete(m, includeKey)
+	switch v := value.(type) {
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		includes := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, newError("invalid ", includeKey, ": ", item)
+			}
+			includes = append(includes, s)
+		}
+		return includes, nil
+	default:
+		return nil, newError("invalid ", includeKey, ": ", value)
+	}
+}
+
+func hasMeta(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+func sameFile(a, b string) bool {
+	return absPath(a) == absPath(b)
+}
+
+func absPath(file string) string {
+	if abs, err := filepath.Abs(file); err == nil {
+		return abs
+	}
+	return file
+}
@@ -0,0 +1,97 @@
+package mergers_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/infra/conf/mergers"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestInclude(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.json":          `{"_include": ["base.yaml", "tenants/*.json"], "log": {"loglevel": "info"}}`,
+		"base.yaml":            "log: {loglevel: warning, access: none}\noutbounds: [{protocol: freedom}]\n",
+		"tenants/alice.json":   `{"inbounds": [{"tag": "in-alice"}]}`,
+		"tenants/bob.json":     `{"_include": "../common/bob.toml"}`,
+		"common/bob.toml":      "[[inbounds]]\ntag = \"in-bob\"\n",
+		"tenants/ignored.yaml": "inbounds: [{tag: in-ignored}]\n",
+	})
+
+	m := make(map[string]interface{})
+	if err := mergers.MergeAs("json", filepath.Join(dir, "config.json"), m); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"log": map[string]interface{}{"loglevel": "info", "access": "none"},
+		"outbounds": []interface{}{
+			map[string]interface{}{"protocol": "freedom"},
+		},
+		"inbounds": []interface{}{
+			map[string]interface{}{"tag": "in-alice"},
+			map[string]interface{}{"tag": "in-bob"},
+		},
+	}
+	if !reflect.DeepEqual(m, expected) {
+		t.Error("unexpected result: ", m)
+	}
+}
+
+func TestIncludeOnce(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.json": `{"_include": ["*.json", "base.yaml"], "log": {"loglevel": "info"}}`,
+		"other.json":  `{"_include": "base.yaml", "inbounds": [{"tag": "in-other"}]}`,
+		"base.yaml":   "outbounds: [{protocol: freedom}]\n",
+	})
+
+	// The including file matched by its own pattern is skipped, and files included by
+	// several files, or both listed and included, are merged once.
+	m := make(map[string]interface{})
+	files := []string{filepath.Join(dir, "base.yaml"), filepath.Join(dir, "config.json"), filepath.Join(dir, "other.json")}
+	if err := mergers.MergeAs("auto", files, m); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"log": map[string]interface{}{"loglevel": "info"},
+		"outbounds": []interface{}{
+			map[string]interface{}{"protocol": "freedom"},
+		},
+		"inbounds": []interface{}{
+			map[string]interface{}{"tag": "in-other"},
+		},
+	}
+	if !reflect.DeepEqual(m, expected) {
+		t.Error("unexpected result: ", m)
+	}
+}
+
+func TestIncludeErrors(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"cyclic.json":  `{"_include": "cyclic2.yaml"}`,
+		"cyclic2.yaml": `_include: cyclic.json`,
+		"missing.json": `{"_include": ["nonexistent.json"]}`,
+		"invalid.json": `{"_include": [1]}`,
+		"unknown.json": `{"_include": ["config.ini"]}`,
+		"config.ini":   ``,
+	})
+	for _, file := range []string{"cyclic.json", "missing.json", "invalid.json", "unknown.json"} {
+		if err := mergers.MergeAs("json", filepath.Join(dir, file), make(map[string]interface{})); err == nil {
+			t.Error("expected error of ", file)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	core "github.com/v2fly/v2ray-core/v5"
 	"github.com/v2fly/v2ray-core/v5/common/cmdarg"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/merge"
 )
 
 // MergeAs load input and merge as specified format into m
@@ -27,26 +28,28 @@ func Merge(input interface{}, m map[string]interface{}) error {
 	}
 	switch v := input.(type) {
 	case string:
-		err := mergeSingleFile(v, m)
+		err := mergeSingleFile(v, m, make(map[string]bool))
 		if err != nil {
 			return err
 		}
 	case []string:
+		loaded := make(map[string]bool)
 		for _, file := range v {
-			err := mergeSingleFile(file, m)
+			err := mergeSingleFile(file, m, loaded)
 			if err != nil {
 				return err
 			}
 		}
 	case cmdarg.Arg:
+		loaded := make(map[string]bool)
 		for _, file := range v {
-			err := mergeSingleFile(file, m)
+			err := mergeSingleFile(file, m, loaded)
 			if err != nil {
 				return err
 			}
 		}
 	case []byte:
-		err := mergeSingleFile(v, m)
+		err := mergeSingleFile(v, m, nil)
 		if err != nil {
 			return err
 		}
@@ -56,7 +59,13 @@ func Merge(input interface{}, m map[string]interface{}) error {
 		if err != nil {
 			return err
 		}
-		err = mergeSingleFile(bs, m)
+		err = mergeSingleFile(bs, m, nil)
+		if err != nil {
+			return err
+		}
+	case map[string]interface{}:
+		// Merged configs have been interpolated and included already.
+		err := merge.Maps(m, v)
 		if err != nil {
 			return err
 		}
@@ -66,7 +75,9 @@ func Merge(input interface{}, m map[string]interface{}) error {
 	return nil
 }
 
-func mergeSingleFile(input interface{}, m map[string]interface{}) error {
+// mergeSingleFile merges the input, whose format is detected by the extension of the file or by
+// trying all mergers. loaded holds the files loaded by the same merge.
+func mergeSingleFile(input interface{}, m map[string]interface{}, loaded map[string]bool) error {
 	if file, ok := input.(string); ok {
 		ext := getExtension(file)
 		if ext != "" {
@@ -75,7 +86,7 @@ func mergeSingleFile(input interface{}, m map[string]interface{}) error {
 			if !found {
 				return newError("unmergeable format extension: ", ext)
 			}
-			return f.Merge(file, m)
+			return loadFile(file, m, f.converter, nil, loaded)
 		}
 	}
 	// no extension, try all mergers
@@ -3,13 +3,23 @@ package mergers
 import (
 	"fmt"
 	"io"
+	"path/filepath"
 
 	"github.com/v2fly/v2ray-core/v5/common/cmdarg"
 	"github.com/v2fly/v2ray-core/v5/common/errors"
 	"github.com/v2fly/v2ray-core/v5/infra/conf/merge"
 )
 
-type jsonConverter func(v []byte) ([]byte, error)
+// jsonConverter converts content of a format to JSON. dir is the directory of the file
+// the content is loaded from, to resolve relative paths, or "" if not loaded from a file.
+type jsonConverter func(v []byte, dir string) ([]byte, error)
+
+// ignoreDir makes a jsonConverter of a format without paths.
+func ignoreDir(converter func(v []byte) ([]byte, error)) jsonConverter {
+	return func(v []byte, dir string) ([]byte, error) {
+		return converter(v)
+	}
+}
 
 // makeMerger makes a merger who merge the format by converting it to JSON
 func makeMerger(name string, extensions []string, converter jsonConverter) *Merger {
@@ -17,11 +27,12 @@ func makeMerger(name string, extensions []string, converter jsonConverter) *Merg
 		Name:       name,
 		Extensions: extensions,
 		Merge:      makeToJSONMergeFunc(converter),
+		converter:  converter,
 	}
 }
 
 // makeToJSONMergeFunc makes a merge func who merge the format by converting it to JSON
-func makeToJSONMergeFunc(converter func(v []byte) ([]byte, error)) MergeFunc {
+func makeToJSONMergeFunc(converter jsonConverter) MergeFunc {
 	return func(input interface{}, target map[string]interface{}) error {
 		if input == nil {
 			return nil
@@ -31,7 +42,7 @@ func makeToJSONMergeFunc(converter func(v []byte) ([]byte, error)) MergeFunc {
 		}
 		switch v := input.(type) {
 		case string:
-			err := loadFile(v, target, converter)
+			err := loadFile(v, target, converter, nil, make(map[string]bool))
 			if err != nil {
 				return err
 			}
@@ -55,6 +66,12 @@ func makeToJSONMergeFunc(converter func(v []byte) ([]byte, error)) MergeFunc {
 			if err != nil {
 				return err
 			}
+		case map[string]interface{}:
+			// Merged configs have been interpolated and included already.
+			err := merge.Maps(target, v)
+			if err != nil {
+				return err
+			}
 		default:
 			return newError("unknown merge input type")
 		}
@@ -62,9 +79,10 @@ func makeToJSONMergeFunc(converter func(v []byte) ([]byte, error)) MergeFunc {
 	}
 }
 
-func loadFiles(files []string, target map[string]interface{}, converter func(v []byte) ([]byte, error)) error {
+func loadFiles(files []string, target map[string]interface{}, converter jsonConverter) error {
+	loaded := make(map[string]bool)
 	for _, file := range files {
-		err := loadFile(file, target, converter)
+		err := loadFile(file, target, converter, nil, loaded)
 		if err != nil {
 			return err
 		}
@@ -72,22 +90,30 @@ func loadFiles(files []string, target map[string]interface{}, converter func(v [
 	return nil
 }
 
-func loadFile(file string, target map[string]interface{}, converter func(v []byte) ([]byte, error)) error {
+// loadFile loads the file into target. includedBy holds the files including it, to detect cyclic includes.
+// loaded holds the files loaded by the same merge, so that files both listed and included, or included
+// by several files, are merged only the first time.
+func loadFile(file string, target map[string]interface{}, converter jsonConverter, includedBy []string, loaded map[string]bool) error {
+	key := absPath(file)
+	if loaded[key] {
+		return nil
+	}
+	loaded[key] = true
 	bs, err := cmdarg.LoadArgToBytes(file)
 	if err != nil {
 		return fmt.Errorf("fail to load %s: %s", file, err)
 	}
+	dir := filepath.Dir(file)
 	if converter != nil {
-		bs, err = converter(bs)
+		bs, err = converter(bs, dir)
 		if err != nil {
 			return fmt.Errorf("error convert to json '%s': %s", file, err)
 		}
 	}
-	_, err = merge.ToMap(bs, target)
-	return err
+	return mergeContent(bs, dir, target, append(includedBy, file), loaded)
 }
 
-func loadReader(reader io.Reader, target map[string]interface{}, converter func(v []byte) ([]byte, error)) error {
+func loadReader(reader io.Reader, target map[string]interface{}, converter jsonConverter) error {
 	bs, err := io.ReadAll(reader)
 	if err != nil {
 		return err
@@ -95,14 +121,13 @@ func loadReader(reader io.Reader, target map[string]interface{}, converter func(
 	return loadBytes(bs, target, converter)
 }
 
-func loadBytes(bs []byte, target map[string]interface{}, converter func(v []byte) ([]byte, error)) error {
+func loadBytes(bs []byte, target map[string]interface{}, converter jsonConverter) error {
 	var err error
 	if converter != nil {
-		bs, err = converter(bs)
+		bs, err = converter(bs, "")
 		if err != nil {
 			return fmt.Errorf("fail to convert to json: %s", err)
 		}
 	}
-	_, err = merge.ToMap(bs, target)
-	return err
+	return mergeContent(bs, "", target, nil, make(map[string]bool))
 }
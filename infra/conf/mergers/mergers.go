@@ -19,12 +19,17 @@ func init() {
 	common.Must(RegisterMerger(makeMerger(
 		core.FormatTOML,
 		[]string{".toml"},
-		json.FromTOML,
+		ignoreDir(json.FromTOML),
 	)))
 	common.Must(RegisterMerger(makeMerger(
 		core.FormatYAML,
 		[]string{".yml", ".yaml"},
-		json.FromYAML,
+		ignoreDir(json.FromYAML),
+	)))
+	common.Must(RegisterMerger(makeMerger(
+		core.FormatStarlark,
+		[]string{".star"},
+		json.FromStarlark,
 	)))
 	common.Must(RegisterMerger(
 		&Merger{
@@ -40,6 +45,9 @@ type Merger struct {
 	Name       string
 	Extensions []string
 	Merge      MergeFunc
+
+	// converter converts the format to JSON, or nil if the format is JSON.
+	converter jsonConverter
 }
 
 // MergeFunc is a utility to merge V2Ray config from external source into a map and returns it.
@@ -0,0 +1,282 @@
+// Package remote loads configs from sources which may change at runtime, such as HTTPS endpoints
+// polled periodically and local files watched for changes. Configs are delivered with detached
+// signatures verified by a public key, and the last good config is kept on disk for offline restarts.
+// Plain HTTP is refused, so that configs cannot be replaced by older signed ones on the way.
+package remote
+
+import (
+	"context"
+	"crypto/sha256"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/v2fly/VSign/sign/signify"
+
+	"github.com/v2fly/v2ray-core/v5/common/platform/securedload"
+)
+
+//go:generate go run github.com/v2fly/v2ray-core/v5/common/errors/errorgen
+
+// SignatureSuffix is appended to the location of configs to locate their signatures by default.
+// It is appended to the path of URLs, before the query.
+const SignatureSuffix = ".sig"
+
+const fetchTimeout = 30 * time.Second
+
+// Delivery is a config delivered by a source.
+type Delivery struct {
+	Content   []byte
+	Signature []byte
+}
+
+func (d *Delivery) sum() [sha256.Size]byte {
+	return sha256.Sum256(d.Content)
+}
+
+// Source is a config source at an HTTPS URL or a local path.
+type Source struct {
+	// Location is the URL or path of the config.
+	Location string
+	// HTTPClient fetches configs from URLs, a client with a timeout of 30 seconds if nil.
+	HTTPClient *http.Client
+	// SignatureLocation is the URL or path of the detached signature, Location with SignatureSuffix by default.
+	SignatureLocation string
+	// PublicKey verifies signatures. Signatures are not verified if it is nil, which is allowed for local files only.
+	PublicKey *signify.PublicKey
+	// Interval is the interval of polling for changes. Local files are watched for changes if supported by the system.
+	Interval time.Duration
+	// CacheFile keeps the last good config and its signature, if not empty.
+	CacheFile string
+}
+
+// NewSource creates a source. The public key is the content of a signify public key file.
+func NewSource(location string, publicKey []byte, interval time.Duration, cacheFile string) (*Source, error) {
+	s := &Source{
+		Location:  location,
+		Interval:  interval,
+		CacheFile: cacheFile,
+	}
+	if s.isRemote() {
+		u, err := url.Parse(location)
+		if err != nil {
+			return nil, newError("invalid URL: ", location).Base(err)
+		}
+		if !strings.EqualFold(u.Scheme, "https") {
+			return nil, newError("configs must be fetched with HTTPS: ", location)
+		}
+		u.Path += SignatureSuffix
+		if u.RawPath != "" {
+			u.RawPath += SignatureSuffix
+		}
+		s.SignatureLocation = u.String()
+	} else {
+		s.SignatureLocation = location + SignatureSuffix
+	}
+	if publicKey != nil {
+		key, err := securedload.ParsePublicKey(publicKey)
+		if err != nil {
+			return nil, err
+		}
+		s.PublicKey = key
+	} else if s.isRemote() {
+		return nil, newError("a public key is required to verify configs from ", location)
+	}
+	if interval <= 0 {
+		return nil, newError("invalid polling interval: ", interval)
+	}
+	return s, nil
+}
+
+func (s *Source) isRemote() bool {
+	return isURL(s.Location)
+}
+
+func isURL(location string) bool {
+	lower := strings.ToLower(location)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+func (s *Source) load(location string) ([]byte, error) {
+	if !isURL(location) {
+		return os.ReadFile(location)
+	}
+	client := s.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: fetchTimeout}
+	}
+	resp, err := client.Get(location)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, newError("unexpected HTTP status code: ", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (s *Source) verify(d *Delivery) error {
+	if s.PublicKey == nil {
+		return nil
+	}
+	return securedload.VerifyDetachedSignature(s.PublicKey, d.Content, d.Signature)
+}
+
+// Fetch fetches the config and its signature, and verifies the signature.
+func (s *Source) Fetch() (*Delivery, error) {
+	content, err := s.load(s.Location)
+	if err != nil {
+		return nil, newError("failed to load config from ", s.Location).Base(err)
+	}
+	d := &Delivery{Content: content}
+	if s.PublicKey != nil {
+		if d.Signature, err = s.load(s.SignatureLocation); err != nil {
+			return nil, newError("failed to load signature from ", s.SignatureLocation).Base(err)
+		}
+	}
+	if err := s.verify(d); err != nil {
+		return nil, newError("failed to verify config from ", s.Location).Base(err)
+	}
+	return d, nil
+}
+
+// LoadCache loads the last good config from the cache file, and verifies its signature.
+func (s *Source) LoadCache() (*Delivery, error) {
+	if s.CacheFile == "" {
+		return nil, newError("no cache file")
+	}
+	content, err := os.ReadFile(s.CacheFile)
+	if err != nil {
+		return nil, newError("failed to read cached config").Base(err)
+	}
+	d := &Delivery{Content: content}
+	if s.PublicKey != nil {
+		if d.Signature, err = os.ReadFile(s.CacheFile + SignatureSuffix); err != nil {
+			return nil, newError("failed to read signature of cached config").Base(err)
+		}
+	}
+	if err := s.verify(d); err != nil {
+		return nil, newError("failed to verify cached config").Base(err)
+	}
+	return d, nil
+}
+
+// SaveCache saves the config as the last good config, if the cache file is set.
+func (s *Source) SaveCache(d *Delivery) error {
+	if s.CacheFile == "" {
+		return nil
+	}
+	if d.Signature != nil {
+		if err := writeFileAtomically(s.CacheFile+SignatureSuffix, d.Signature); err != nil {
+			return newError("failed to save signature of config").Base(err)
+		}
+	}
+	if err := writeFileAtomically(s.CacheFile, d.Content); err != nil {
+		return newError("failed to save config").Base(err)
+	}
+	return nil
+}
+
+// writeFileAtomically writes the file by renaming a temporary file, so that the file is never partially written.
+func writeFileAtomically(name string, content []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), name)
+}
+
+// Load fetches the config, or loads the last good config from the cache file if fetching fails.
+func (s *Source) Load() (*Delivery, error) {
+	d, err := s.Fetch()
+	if err == nil {
+		return d, nil
+	}
+	if s.CacheFile == "" {
+		return nil, err
+	}
+	newError("using cached config").Base(err).AtWarning().WriteToLog()
+	cached, errCache := s.LoadCache()
+	if errCache != nil {
+		return nil, newError("failed to load config from either the source or the cache: ", errCache).Base(err)
+	}
+	return cached, nil
+}
+
+// Watch calls apply with the config whenever it changes from current, until the context is done.
+// Configs that fail to be fetched, verified or applied are skipped, and the config is saved to the
+// cache file when it is applied.
+func (s *Source) Watch(ctx context.Context, current *Delivery, apply func(*Delivery) error) {
+	last := current.sum()
+	for range s.changes(ctx) {
+		d, err := s.Fetch()
+		if err != nil {
+			newError("failed to check config for changes").Base(err).AtWarning().WriteToLog()
+			continue
+		}
+		if d.sum() == last {
+			continue
+		}
+		newError("config changed at ", s.Location).AtInfo().WriteToLog()
+		// Configs failed to apply are not retried until they change.
+		last = d.sum()
+		if err := apply(d); err != nil {
+			newError("failed to apply changed config").Base(err).AtError().WriteToLog()
+			continue
+		}
+		if err := s.SaveCache(d); err != nil {
+			newError("failed to keep the applied config").Base(err).AtWarning().WriteToLog()
+		}
+	}
+}
+
+// changes returns a channel notified when the config may have changed, which is closed when the context is done.
+func (s *Source) changes(ctx context.Context) <-chan struct{} {
+	if !s.isRemote() {
+		paths := []string{s.Location}
+		if s.PublicKey != nil {
+			paths = append(paths, s.SignatureLocation)
+		}
+		c, err := watchFiles(ctx, paths)
+		if err == nil {
+			return c
+		}
+		newError("failed to watch ", s.Location, ", polling for changes instead").Base(err).AtWarning().WriteToLog()
+	}
+	c := make(chan struct{}, 1)
+	go func() {
+		defer close(c)
+		ticker := time.NewTicker(s.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				notify(c)
+			}
+		}
+	}()
+	return c
+}
+
+// notify notifies the channel without blocking. Pending notifications are merged.
+func notify(c chan struct{}) {
+	select {
+	case c <- struct{}{}:
+	default:
+	}
+}
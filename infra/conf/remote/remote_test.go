@@ -0,0 +1,251 @@
+package remote_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/v2fly/VSign/sign/signify"
+
+	"github.com/v2fly/v2ray-core/v5/infra/conf/remote"
+)
+
+type signer struct {
+	publicKey  []byte
+	privateKey *signify.PrivateKey
+}
+
+func newSigner(t *testing.T) *signer {
+	pub, priv, err := signify.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var key bytes.Buffer
+	if err := signify.WriteFile(&key, "signify public key", signify.MarshalPublicKey(pub)); err != nil {
+		t.Fatal(err)
+	}
+	return &signer{publicKey: key.Bytes(), privateKey: priv}
+}
+
+func (s *signer) sign(content []byte) []byte {
+	return []byte(base64.StdEncoding.EncodeToString(signify.MarshalSignature(signify.Sign(s.privateKey, content))))
+}
+
+func writeSigned(t *testing.T, s *signer, path string, content string) {
+	if err := os.WriteFile(path+remote.SignatureSuffix, s.sign([]byte(content)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFetchFile(t *testing.T) {
+	s := newSigner(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	writeSigned(t, s, path, `{"log": {}}`)
+
+	source, err := remote.NewSource(path, s.publicKey, time.Minute, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := source.Fetch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(d.Content) != `{"log": {}}` {
+		t.Error("unexpected content: ", string(d.Content))
+	}
+
+	// Tampered.
+	if err := os.WriteFile(path, []byte(`{"log": {"loglevel": "none"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := source.Fetch(); err == nil {
+		t.Error("expected error of tampered config")
+	}
+
+	// Signed by another key.
+	writeSigned(t, newSigner(t), path, `{"log": {}}`)
+	if _, err := source.Fetch(); err == nil {
+		t.Error("expected error of config signed by another key")
+	}
+}
+
+func TestFetchHTTPS(t *testing.T) {
+	s := newSigner(t)
+	content := []byte(`{"outbounds": []}`)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("token") != "secret" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/config.json":
+			w.Write(content)
+		case "/config.json.sig":
+			w.Write(s.sign(content))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	location := server.URL + "/config.json?token=secret"
+	if _, err := remote.NewSource(location, nil, time.Minute, ""); err == nil {
+		t.Error("expected error of remote source without public key")
+	}
+	if _, err := remote.NewSource(strings.Replace(location, "https://", "http://", 1), s.publicKey, time.Minute, ""); err == nil {
+		t.Error("expected error of plain HTTP source")
+	}
+	source, err := remote.NewSource(location, s.publicKey, time.Minute, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The signature is at the path with the suffix, keeping the query.
+	if source.SignatureLocation != server.URL+"/config.json.sig?token=secret" {
+		t.Error("unexpected signature location: ", source.SignatureLocation)
+	}
+	source.HTTPClient = server.Client()
+	d, err := source.Fetch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(d.Content, content) {
+		t.Error("unexpected content: ", string(d.Content))
+	}
+
+	source.SignatureLocation = server.URL + "/nonexistent.sig?token=secret"
+	if _, err := source.Fetch(); err == nil {
+		t.Error("expected error of missing signature")
+	}
+}
+
+func TestCache(t *testing.T) {
+	s := newSigner(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	cache := filepath.Join(dir, "cache", "config.json")
+	if err := os.Mkdir(filepath.Dir(cache), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	writeSigned(t, s, path, `{"log": {}}`)
+
+	source, err := remote.NewSource(path, s.publicKey, time.Minute, cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := source.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := source.SaveCache(d); err != nil {
+		t.Fatal(err)
+	}
+
+	// The source is unavailable.
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	cached, err := source.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(cached.Content, d.Content) {
+		t.Error("unexpected cached content: ", string(cached.Content))
+	}
+
+	// The cache is tampered.
+	if err := os.WriteFile(cache, []byte(`{}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := source.Load(); err == nil {
+		t.Error("expected error of tampered cache")
+	}
+}
+
+func TestWatch(t *testing.T) {
+	s := newSigner(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	writeSigned(t, s, path, `{"version": 1}`)
+
+	source, err := remote.NewSource(path, s.publicKey, 50*time.Millisecond, filepath.Join(dir, "cache.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := source.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	applied := make(chan string, 10)
+	done := make(chan struct{})
+	go func() {
+		source.Watch(ctx, current, func(d *remote.Delivery) error {
+			applied <- string(d.Content)
+			if strings.Contains(string(d.Content), "invalid") {
+				return os.ErrInvalid
+			}
+			return nil
+		})
+		close(done)
+	}()
+
+	expect := func(content string) {
+		t.Helper()
+		select {
+		case got := <-applied:
+			if got != content {
+				t.Error("expected ", content, ", got ", got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for ", content)
+		}
+	}
+
+	// The config is cached after it is applied.
+	waitForCache := func(content string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			cached, err := os.ReadFile(filepath.Join(dir, "cache.json"))
+			if err == nil && string(cached) == content {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("applied config is not cached: ", string(cached), err)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	writeSigned(t, s, path, `{"version": 2}`)
+	expect(`{"version": 2}`)
+	waitForCache(`{"version": 2}`)
+
+	writeSigned(t, s, path, `{"version": "invalid"}`)
+	expect(`{"version": "invalid"}`)
+	time.Sleep(100 * time.Millisecond)
+	if cached, _ := os.ReadFile(filepath.Join(dir, "cache.json")); string(cached) != `{"version": 2}` {
+		t.Error("config failed to apply is cached: ", string(cached))
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch does not stop")
+	}
+}
@@ -0,0 +1,77 @@
+//go:build linux
+// +build linux
+
+package remote
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// watchFiles returns a channel notified when any of the files is written, created, moved or removed,
+// which is closed when the context is done. Directories of the files are watched with inotify, as
+// files are usually replaced by renaming.
+func watchFiles(ctx context.Context, paths []string) (<-chan struct{}, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, newError("failed to initialize inotify").Base(err)
+	}
+	// The file is pollable, so that closing it interrupts reading.
+	file := os.NewFile(uintptr(fd), "inotify")
+
+	watched := make(map[string]bool)
+	dirs := make(map[int32]string)
+	for _, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		watched[abs] = true
+		dir := filepath.Dir(abs)
+		wd, err := unix.InotifyAddWatch(fd, dir, unix.IN_CLOSE_WRITE|unix.IN_CREATE|unix.IN_MOVED_TO|unix.IN_MOVED_FROM|unix.IN_DELETE)
+		if err != nil {
+			file.Close()
+			return nil, newError("failed to watch ", dir).Base(err)
+		}
+		dirs[int32(wd)] = dir
+	}
+
+	go func() {
+		<-ctx.Done()
+		file.Close()
+	}()
+
+	c := make(chan struct{}, 1)
+	go func() {
+		defer close(c)
+		buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+		for {
+			n, err := file.Read(buf)
+			if err != nil {
+				if ctx.Err() == nil {
+					newError("stopped watching files").Base(err).AtWarning().WriteToLog()
+				}
+				return
+			}
+			for offset := 0; offset+unix.SizeofInotifyEvent <= n; {
+				event := (*unix.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+				nameStart := offset + unix.SizeofInotifyEvent
+				offset = nameStart + int(event.Len)
+				if offset > n {
+					break
+				}
+				name := strings.TrimRight(string(buf[nameStart:offset]), "\x00")
+				if watched[filepath.Join(dirs[event.Wd], name)] {
+					notify(c)
+				}
+			}
+		}
+	}()
+	return c, nil
+}
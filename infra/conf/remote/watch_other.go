@@ -0,0 +1,12 @@
+//go:build !linux
+// +build !linux
+
+package remote
+
+import (
+	"context"
+)
+
+func watchFiles(ctx context.Context, paths []string) (<-chan struct{}, error) {
+	return nil, newError("watching files is not supported on this platform")
+}
@@ -3,6 +3,7 @@ package rule
 import (
 	"context"
 	"encoding/json"
+	"reflect"
 	"strconv"
 	"strings"
 
@@ -10,6 +11,9 @@ import (
 	"github.com/v2fly/v2ray-core/v5/app/router/routercommon"
 	"github.com/v2fly/v2ray-core/v5/common/net"
 	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/geodata"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/geodata/mmdb"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/jsonschema"
 )
 
 //go:generate go run github.com/v2fly/v2ray-core/v5/common/errors/errorgen
@@ -142,6 +146,15 @@ func toCidrList(ctx context.Context, ips cfgcommon.StringList) ([]*routercommon.
 			continue
 		}
 
+		if strings.HasPrefix(ip, "asn:") || strings.HasPrefix(ip, "ext-asn:") {
+			geoip, err := parseASNRule(geoLoader, ip)
+			if err != nil {
+				return nil, err
+			}
+			geoipList = append(geoipList, geoip)
+			continue
+		}
+
 		isExtDatFile := 0
 		{
 			const prefix = "ext:"
@@ -201,22 +214,101 @@ func toCidrList(ctx context.Context, ips cfgcommon.StringList) ([]*routercommon.
 	return geoipList, nil
 }
 
+// parseASNRule parses "asn:13335" with the default asn.mmdb, or "ext-asn:file.mmdb:AS13335".
+func parseASNRule(geoLoader geodata.Loader, rule string) (*routercommon.GeoIP, error) {
+	filename, value := "", strings.TrimPrefix(rule, "asn:")
+	if strings.HasPrefix(rule, "ext-asn:") {
+		kv := strings.Split(rule[len("ext-asn:"):], ":")
+		if len(kv) != 2 || len(kv[0]) == 0 {
+			return nil, newError("invalid external resource: ", rule)
+		}
+		filename, value = kv[0], kv[1]
+	}
+	isInverseMatch := false
+	if strings.HasPrefix(value, "!") {
+		value = value[1:]
+		isInverseMatch = true
+	}
+	asn, err := mmdb.ParseASN(value)
+	if err != nil {
+		return nil, newError("invalid asn rule: ", rule).Base(err)
+	}
+
+	code := "AS" + strconv.FormatUint(uint64(asn), 10)
+	var cidrs []*routercommon.CIDR
+	if filename == "" {
+		cidrs, err = geoLoader.LoadGeoASN(asn)
+	} else {
+		cidrs, err = geoLoader.LoadASN(filename, asn)
+		code = strings.ToUpper(filename + "_" + code)
+	}
+	if err != nil {
+		return nil, newError("failed to load asn: ", value).Base(err)
+	}
+	return &routercommon.GeoIP{
+		CountryCode:  code,
+		Cidr:         cidrs,
+		InverseMatch: isInverseMatch,
+	}, nil
+}
+
+// parseASNList parses autonomous systems like "AS13335" in the default asn.mmdb, or "ext:file.mmdb:AS13335".
+func parseASNList(ctx context.Context, list cfgcommon.StringList) ([]*router.ASN, error) {
+	geoLoader := cfgcommon.GetConfigureLoadingEnvironment(ctx).GetGeoLoader()
+
+	var asns []*router.ASN
+	for _, s := range list {
+		asn := new(router.ASN)
+		value := s
+		if strings.HasPrefix(s, "ext:") {
+			kv := strings.Split(s[len("ext:"):], ":")
+			if len(kv) != 2 || len(kv[0]) == 0 {
+				return nil, newError("invalid external resource: ", s)
+			}
+			asn.FilePath, value = kv[0], kv[1]
+		}
+		number, err := mmdb.ParseASN(value)
+		if err != nil {
+			return nil, newError("invalid asn: ", s).Base(err)
+		}
+		asn.Number = number
+		if asn.FilePath == "" {
+			asn.Cidr, err = geoLoader.LoadGeoASN(number)
+		} else {
+			asn.Cidr, err = geoLoader.LoadASN(asn.FilePath, number)
+		}
+		if err != nil {
+			return nil, newError("failed to load asn: ", s).Base(err)
+		}
+		asns = append(asns, asn)
+	}
+	return asns, nil
+}
+
+// rawFieldRule is the JSON form of a field rule.
+type rawFieldRule struct {
+	RouterRule
+	Domain      *cfgcommon.StringList  `json:"domain"`
+	Domains     *cfgcommon.StringList  `json:"domains"`
+	IP          *cfgcommon.StringList  `json:"ip"`
+	Port        *cfgcommon.PortList    `json:"port"`
+	Network     *cfgcommon.NetworkList `json:"network"`
+	SourceIP    *cfgcommon.StringList  `json:"source"`
+	SourcePort  *cfgcommon.PortList    `json:"sourcePort"`
+	User        *cfgcommon.StringList  `json:"user"`
+	InboundTag  *cfgcommon.StringList  `json:"inboundTag"`
+	Protocols   *cfgcommon.StringList  `json:"protocol"`
+	Attributes  string                 `json:"attrs"`
+	ASN         *cfgcommon.StringList  `json:"asn"`
+	SourceASN   *cfgcommon.StringList  `json:"sourceAsn"`
+	ProcessName *cfgcommon.StringList  `json:"processName"`
+	ProcessPath *cfgcommon.StringList  `json:"processPath"`
+	UID         []uint32               `json:"uid"`
+	Schedule    *ScheduleConfig        `json:"schedule"`
+}
+
 func parseFieldRule(ctx context.Context, msg json.RawMessage) (*router.RoutingRule, error) {
-	type RawFieldRule struct {
-		RouterRule
-		Domain     *cfgcommon.StringList  `json:"domain"`
-		Domains    *cfgcommon.StringList  `json:"domains"`
-		IP         *cfgcommon.StringList  `json:"ip"`
-		Port       *cfgcommon.PortList    `json:"port"`
-		Network    *cfgcommon.NetworkList `json:"network"`
-		SourceIP   *cfgcommon.StringList  `json:"source"`
-		SourcePort *cfgcommon.PortList    `json:"sourcePort"`
-		User       *cfgcommon.StringList  `json:"user"`
-		InboundTag *cfgcommon.StringList  `json:"inboundTag"`
-		Protocols  *cfgcommon.StringList  `json:"protocol"`
-		Attributes string                 `json:"attrs"`
-	}
-	rawFieldRule := new(RawFieldRule)
+	rawFieldRule := new(rawFieldRule)
 	err := json.Unmarshal(msg, rawFieldRule)
 	if err != nil {
 		return nil, err
@@ -242,6 +334,10 @@ func parseFieldRule(ctx context.Context, msg json.RawMessage) (*router.RoutingRu
 
 	if rawFieldRule.Domain != nil {
 		for _, domain := range *rawFieldRule.Domain {
+			if name, ok := ParseRuleSetRule(domain); ok {
+				rule.RuleSet = append(rule.RuleSet, name)
+				continue
+			}
 			rules, err := parseDomainRule(ctx, domain)
 			if err != nil {
 				return nil, newError("failed to parse domain rule: ", domain).Base(err)
@@ -252,6 +348,10 @@ func parseFieldRule(ctx context.Context, msg json.RawMessage) (*router.RoutingRu
 
 	if rawFieldRule.Domains != nil {
 		for _, domain := range *rawFieldRule.Domains {
+			if name, ok := ParseRuleSetRule(domain); ok {
+				rule.RuleSet = append(rule.RuleSet, name)
+				continue
+			}
 			rules, err := parseDomainRule(ctx, domain)
 			if err != nil {
 				return nil, newError("failed to parse domain rule: ", domain).Base(err)
@@ -261,11 +361,21 @@ func parseFieldRule(ctx context.Context, msg json.RawMessage) (*router.RoutingRu
 	}
 
 	if rawFieldRule.IP != nil {
-		geoipList, err := toCidrList(ctx, *rawFieldRule.IP)
-		if err != nil {
-			return nil, err
+		var ips cfgcommon.StringList
+		for _, ip := range *rawFieldRule.IP {
+			if name, ok := ParseRuleSetRule(ip); ok {
+				rule.IpRuleSet = append(rule.IpRuleSet, name)
+				continue
+			}
+			ips = append(ips, ip)
+		}
+		if len(ips) > 0 {
+			geoipList, err := toCidrList(ctx, ips)
+			if err != nil {
+				return nil, err
+			}
+			rule.Geoip = geoipList
 		}
-		rule.Geoip = geoipList
 	}
 
 	if rawFieldRule.Port != nil {
@@ -284,6 +394,40 @@ func parseFieldRule(ctx context.Context, msg json.RawMessage) (*router.RoutingRu
 		rule.SourceGeoip = geoipList
 	}
 
+	if rawFieldRule.ASN != nil {
+		asns, err := parseASNList(ctx, *rawFieldRule.ASN)
+		if err != nil {
+			return nil, err
+		}
+		rule.Asn = asns
+	}
+
+	if rawFieldRule.SourceASN != nil {
+		asns, err := parseASNList(ctx, *rawFieldRule.SourceASN)
+		if err != nil {
+			return nil, err
+		}
+		rule.SourceAsn = asns
+	}
+
+	if rawFieldRule.ProcessName != nil {
+		rule.ProcessName = *rawFieldRule.ProcessName
+	}
+
+	if rawFieldRule.ProcessPath != nil {
+		rule.ProcessPath = *rawFieldRule.ProcessPath
+	}
+
+	rule.Uid = rawFieldRule.UID
+
+	if rawFieldRule.Schedule != nil {
+		schedule, err := rawFieldRule.Schedule.Build()
+		if err != nil {
+			return nil, newError("failed to parse schedule").Base(err)
+		}
+		rule.Schedule = schedule
+	}
+
 	if rawFieldRule.SourcePort != nil {
 		rule.SourcePortList = rawFieldRule.SourcePort.Build()
 	}
@@ -313,6 +457,14 @@ func parseFieldRule(ctx context.Context, msg json.RawMessage) (*router.RoutingRu
 	return rule, nil
 }
 
+// ParseRuleSetRule returns the name of the rule set if the rule is like "ruleset:NAME".
+func ParseRuleSetRule(rule string) (string, bool) {
+	if !strings.HasPrefix(rule, "ruleset:") {
+		return "", false
+	}
+	return rule[len("ruleset:"):], true
+}
+
 func ParseRule(ctx context.Context, msg json.RawMessage) (*router.RoutingRule, error) {
 	rawRule := new(RouterRule)
 	err := json.Unmarshal(msg, rawRule)
@@ -393,3 +545,12 @@ type RouterRule struct {
 
 	DomainMatcher string `json:"domainMatcher"`
 }
+
+// JSONSchema returns the JSON Schema of routing rules.
+func JSONSchema(r *jsonschema.Reflector) *jsonschema.Schema {
+	s := r.Reflect(reflect.TypeOf(rawFieldRule{}))
+	def := r.Definition(s)
+	def.Properties["type"] = jsonschema.StringEnum("field")
+	def.Required = []string{"type"}
+	return s
+}
@@ -0,0 +1,116 @@
+package rule
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/v2fly/v2ray-core/v5/app/router"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon"
+)
+
+var weekdays = map[string]uint32{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// ScheduleConfig is the JSON form of a routing rule schedule.
+//
+//	"schedule": {
+//	  "weekday": ["mon", "tue", "wed", "thu", "fri"],
+//	  "time": ["09:00-12:00", "13:00-18:00"],
+//	  "date": ["2024-12-24~2024-12-26"],
+//	  "timezone": "Asia/Shanghai"
+//	}
+type ScheduleConfig struct {
+	Weekday  *cfgcommon.StringList `json:"weekday"`
+	Time     *cfgcommon.StringList `json:"time"`
+	Date     *cfgcommon.StringList `json:"date"`
+	Timezone string                `json:"timezone"`
+}
+
+func parseWeekday(s string) (uint32, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if len(s) >= 3 {
+		if day, found := weekdays[s[:3]]; found {
+			return day, nil
+		}
+	}
+	day, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, newError("invalid weekday: ", s)
+	}
+	// Both 0 and 7 are Sunday.
+	if day == 7 {
+		day = 0
+	}
+	return uint32(day), nil
+}
+
+// parseTimeOfDay parses "hh:mm" or "hh:mm:ss" into the seconds since midnight. "24:00" is the end of the day.
+func parseTimeOfDay(s string) (uint32, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, newError("invalid time: ", s)
+	}
+	var seconds uint32
+	limits := []uint64{24, 59, 59}
+	for i, part := range parts {
+		n, err := strconv.ParseUint(part, 10, 32)
+		if err != nil || n > limits[i] {
+			return 0, newError("invalid time: ", s)
+		}
+		seconds = seconds*60 + uint32(n)
+	}
+	if len(parts) == 2 {
+		seconds *= 60
+	}
+	if seconds > 24*3600 {
+		return 0, newError("invalid time: ", s)
+	}
+	return seconds, nil
+}
+
+func (c *ScheduleConfig) Build() (*router.Schedule, error) {
+	schedule := &router.Schedule{Timezone: c.Timezone}
+	if c.Weekday != nil {
+		for _, s := range *c.Weekday {
+			day, err := parseWeekday(s)
+			if err != nil {
+				return nil, err
+			}
+			schedule.Weekday = append(schedule.Weekday, day)
+		}
+	}
+	if c.Time != nil {
+		for _, s := range *c.Time {
+			from, to, found := strings.Cut(s, "-")
+			if !found {
+				return nil, newError("invalid time range: ", s)
+			}
+			fromSeconds, err := parseTimeOfDay(from)
+			if err != nil {
+				return nil, err
+			}
+			toSeconds, err := parseTimeOfDay(to)
+			if err != nil {
+				return nil, err
+			}
+			schedule.TimeRange = append(schedule.TimeRange, &router.Schedule_TimeRange{From: fromSeconds, To: toSeconds})
+		}
+	}
+	if c.Date != nil {
+		for _, s := range *c.Date {
+			from, to, found := strings.Cut(s, "~")
+			if !found {
+				to = from
+			}
+			schedule.DateRange = append(schedule.DateRange, &router.Schedule_DateRange{
+				From: strings.TrimSpace(from),
+				To:   strings.TrimSpace(to),
+			})
+		}
+	}
+	if _, err := router.NewScheduleMatcher(schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
@@ -0,0 +1,55 @@
+package rule_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/v2fly/v2ray-core/v5/app/router"
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/rule"
+)
+
+func TestScheduleConfig(t *testing.T) {
+	var config rule.ScheduleConfig
+	common.Must(json.Unmarshal([]byte(`{
+		"weekday": ["mon", "Friday", "0", "7"],
+		"time": ["09:00-12:30", "22:00-02:00", "18:00-24:00"],
+		"date": ["2024-12-24~2024-12-26", "2025-01-01"],
+		"timezone": "UTC"
+	}`), &config))
+	schedule, err := config.Build()
+	common.Must(err)
+
+	expected := &router.Schedule{
+		Weekday: []uint32{1, 5, 0, 0},
+		TimeRange: []*router.Schedule_TimeRange{
+			{From: 9 * 3600, To: 12*3600 + 30*60},
+			{From: 22 * 3600, To: 2 * 3600},
+			{From: 18 * 3600, To: 24 * 3600},
+		},
+		DateRange: []*router.Schedule_DateRange{
+			{From: "2024-12-24", To: "2024-12-26"},
+			{From: "2025-01-01", To: "2025-01-01"},
+		},
+		Timezone: "UTC",
+	}
+	if !proto.Equal(schedule, expected) {
+		t.Error("expected ", expected, " but got ", schedule)
+	}
+
+	for _, s := range []string{
+		`{"weekday": ["someday"]}`,
+		`{"time": ["09:00"]}`,
+		`{"time": ["25:00-26:00"]}`,
+		`{"date": ["2024-12-26~2024-12-24"]}`,
+		`{"timezone": "Mars/Olympus"}`,
+	} {
+		var config rule.ScheduleConfig
+		common.Must(json.Unmarshal([]byte(s), &config))
+		if _, err := config.Build(); err == nil {
+			t.Error("expected error for ", s)
+		}
+	}
+}
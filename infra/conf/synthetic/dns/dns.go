@@ -5,6 +5,7 @@ package dns
 import (
 	"context"
 	"encoding/json"
+	"reflect"
 	"sort"
 	"strings"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/v2fly/v2ray-core/v5/common/platform"
 	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon"
 	"github.com/v2fly/v2ray-core/v5/infra/conf/geodata"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/jsonschema"
 	rule2 "github.com/v2fly/v2ray-core/v5/infra/conf/rule"
 )
 
@@ -34,6 +36,21 @@ type NameServerConfig struct {
 	cfgctx context.Context
 }
 
+// advancedNameServerConfig is the JSON object form of a name server.
+type advancedNameServerConfig struct {
+	Address          *cfgcommon.Address   `json:"address"`
+	ClientIP         *cfgcommon.Address   `json:"clientIp"`
+	Port             uint16               `json:"port"`
+	Tag              string               `json:"tag"`
+	QueryStrategy    string               `json:"queryStrategy"`
+	CacheStrategy    string               `json:"cacheStrategy"`
+	FallbackStrategy string               `json:"fallbackStrategy"`
+	SkipFallback     bool                 `json:"skipFallback"`
+	Domains          []string             `json:"domains"`
+	ExpectIPs        cfgcommon.StringList `json:"expectIps"`
+	FakeDNS          FakeDNSConfigExtend  `json:"fakedns"`
+}
+
 func (c *NameServerConfig) UnmarshalJSON(data []byte) error {
 	var address cfgcommon.Address
 	if err := json.Unmarshal(data, &address); err == nil {
@@ -41,19 +58,7 @@ func (c *NameServerConfig) UnmarshalJSON(data []byte) error {
 		return nil
 	}
 
-	var advanced struct {
-		Address          *cfgcommon.Address   `json:"address"`
-		ClientIP         *cfgcommon.Address   `json:"clientIp"`
-		Port             uint16               `json:"port"`
-		Tag              string               `json:"tag"`
-		QueryStrategy    string               `json:"queryStrategy"`
-		CacheStrategy    string               `json:"cacheStrategy"`
-		FallbackStrategy string               `json:"fallbackStrategy"`
-		SkipFallback     bool                 `json:"skipFallback"`
-		Domains          []string             `json:"domains"`
-		ExpectIPs        cfgcommon.StringList `json:"expectIps"`
-		FakeDNS          FakeDNSConfigExtend  `json:"fakedns"`
-	}
+	var advanced advancedNameServerConfig
 	if err := json.Unmarshal(data, &advanced); err == nil {
 		c.Address = advanced.Address
 		c.ClientIP = advanced.ClientIP
@@ -72,6 +77,13 @@ func (c *NameServerConfig) UnmarshalJSON(data []byte) error {
 	return newError("failed to parse name server: ", string(data))
 }
 
+func (c *NameServerConfig) JSONSchema(r *jsonschema.Reflector) *jsonschema.Schema {
+	return jsonschema.AnyOf(
+		jsonschema.String().WithDescription("address of the name server, like \"8.8.8.8\", \"localhost\" or \"https://dns.google/dns-query\""),
+		r.Reflect(reflect.TypeOf(advancedNameServerConfig{})),
+	)
+}
+
 func toDomainMatchingType(t routercommon.Domain_Type) dns.DomainMatchingType {
 	switch t {
 	case routercommon.Domain_RootDomain:
@@ -102,7 +114,12 @@ func (c *NameServerConfig) Build() (*dns.NameServer, error) {
 	var domains []*dns.NameServer_PriorityDomain
 	var originalRules []*dns.NameServer_OriginalRule
 
+	var ruleSets []string
 	for _, rule := range c.Domains {
+		if name, ok := rule2.ParseRuleSetRule(rule); ok {
+			ruleSets = append(ruleSets, name)
+			continue
+		}
 		parsedDomain, err := rule2.ParseDomainRule(cfgctx, rule)
 		if err != nil {
 			return nil, newError("invalid domain rule: ", rule).Base(err)
@@ -192,6 +209,7 @@ func (c *NameServerConfig) Build() (*dns.NameServer, error) {
 		Geoip:             geoipList,
 		OriginalRules:     originalRules,
 		FakeDns:           fakeDNS,
+		RuleSet:           ruleSets,
 	}, nil
 }
 
@@ -239,6 +257,13 @@ func (h *HostAddress) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+func (h *HostAddress) JSONSchema(r *jsonschema.Reflector) *jsonschema.Schema {
+	return jsonschema.AnyOf(
+		jsonschema.String().WithDescription("IP address, or domain to be proxied"),
+		jsonschema.Array(jsonschema.String()),
+	)
+}
+
 func getHostMapping(ha *HostAddress) *dns.HostMapping {
 	if ha.addr != nil {
 		if ha.addr.Family().IsDomain() {
@@ -3,8 +3,10 @@ package dns
 import (
 	"encoding/json"
 	"net"
+	"reflect"
 
 	"github.com/v2fly/v2ray-core/v5/app/dns/fakedns"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/jsonschema"
 )
 
 type FakeDNSPoolElementConfig struct {
@@ -50,6 +52,15 @@ func (f *FakeDNSConfig) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+func (f *FakeDNSConfig) JSONSchema(r *jsonschema.Reflector) *jsonschema.Schema {
+	pool := r.Reflect(reflect.TypeOf(FakeDNSPoolElementConfig{}))
+	return jsonschema.AnyOf(
+		pool,
+		jsonschema.Array(pool),
+		jsonschema.Array(jsonschema.String().WithDescription("CIDR of the IP pool")),
+	)
+}
+
 func (f *FakeDNSConfig) Build() (*fakedns.FakeDnsPoolMulti, error) {
 	fakeDNSPool := fakedns.FakeDnsPoolMulti{}
 
@@ -85,3 +96,7 @@ func (f *FakeDNSConfigExtend) UnmarshalJSON(data []byte) error {
 	}
 	return json.Unmarshal(data, &f.FakeDNSConfig)
 }
+
+func (f *FakeDNSConfigExtend) JSONSchema(r *jsonschema.Reflector) *jsonschema.Schema {
+	return jsonschema.AnyOf(jsonschema.Boolean(), r.Reflect(reflect.TypeOf(FakeDNSConfig{})))
+}
@@ -53,6 +53,14 @@ func (r *BalancingRule) Build() (*router.BalancingRule, error) {
 		strategy = strategyLeastLoad
 	case strategyLeastPing:
 		strategy = "leastping"
+	case strategyConsistentHash:
+		strategy = strategyConsistentHash
+	case strategySticky:
+		strategy = strategySticky
+	case strategyFailover:
+		strategy = strategyFailover
+	case strategyRoundRobin:
+		strategy = strategyRoundRobin
 	default:
 		return nil, newError("unknown balancing strategy: " + r.Strategy.Type)
 	}
@@ -1,6 +1,8 @@
 package router
 
 import (
+	"strings"
+
 	"github.com/golang/protobuf/proto"
 
 	"github.com/v2fly/v2ray-core/v5/app/observatory/burst"
@@ -13,12 +15,22 @@ const (
 	strategyRandom    string = "random"
 	strategyLeastLoad string = "leastload"
 	strategyLeastPing string = "leastping"
+
+	strategyConsistentHash string = "consistenthash"
+	strategySticky         string = "sticky"
+	strategyFailover       string = "failover"
+	strategyRoundRobin     string = "roundrobin"
 )
 
 var strategyConfigLoader = loader.NewJSONConfigLoader(loader.ConfigCreatorCache{
 	strategyRandom:    func() interface{} { return new(strategyEmptyConfig) },
 	strategyLeastLoad: func() interface{} { return new(strategyLeastLoadConfig) },
 	strategyLeastPing: func() interface{} { return new(strategyLeastPingConfig) },
+
+	strategyConsistentHash: func() interface{} { return new(strategyConsistentHashConfig) },
+	strategySticky:         func() interface{} { return new(strategyStickyConfig) },
+	strategyFailover:       func() interface{} { return new(strategyFailoverConfig) },
+	strategyRoundRobin:     func() interface{} { return new(strategyRoundRobinConfig) },
 }, "type", "settings")
 
 type strategyEmptyConfig struct{}
@@ -44,21 +56,80 @@ type strategyLeastLoadConfig struct {
 
 // HealthCheckSettings holds settings for health Checker
 type HealthCheckSettings struct {
-	Destination   string            `json:"destination"`
-	Connectivity  string            `json:"connectivity"`
-	Interval      duration.Duration `json:"interval"`
-	SamplingCount int               `json:"sampling"`
-	Timeout       duration.Duration `json:"timeout"`
+	Destination   string             `json:"destination"`
+	Connectivity  string             `json:"connectivity"`
+	Interval      duration.Duration  `json:"interval"`
+	SamplingCount int                `json:"sampling"`
+	Timeout       duration.Duration  `json:"timeout"`
+	Probes        []HealthCheckProbe `json:"probes,omitempty"`
+}
+
+// HealthCheckProbe holds settings of the probe for outbounds matching the selectors
+type HealthCheckProbe struct {
+	SubjectSelector []string `json:"subjectSelector"`
+	Type            string   `json:"type"`
+	Destination     string   `json:"destination"`
+	ServerName      string   `json:"serverName"`
+	Domain          string   `json:"domain"`
+	Size            int64    `json:"size"`
+	Payload         string   `json:"payload"`
+}
+
+func (p HealthCheckProbe) Build() (*burst.ProbeConfig, error) {
+	var probeType burst.ProbeConfig_Type
+	switch strings.ToLower(p.Type) {
+	case "", "http":
+		probeType = burst.ProbeConfig_HTTP
+	case "tcp":
+		probeType = burst.ProbeConfig_TCP
+		if p.Destination == "" {
+			return nil, newError("tcp probe requires a destination")
+		}
+	case "tls":
+		probeType = burst.ProbeConfig_TLS
+	case "dns":
+		probeType = burst.ProbeConfig_DNS
+	case "throughput":
+		probeType = burst.ProbeConfig_THROUGHPUT
+		if p.Destination == "" {
+			return nil, newError("throughput probe requires a destination")
+		}
+	default:
+		return nil, newError("unknown probe type: ", p.Type)
+	}
+	if len(p.SubjectSelector) == 0 {
+		return nil, newError("probe requires subject selectors")
+	}
+	if p.Size < 0 {
+		return nil, newError("invalid throughput probe size: ", p.Size)
+	}
+	return &burst.ProbeConfig{
+		SubjectSelector: p.SubjectSelector,
+		Type:            probeType,
+		Destination:     p.Destination,
+		ServerName:      p.ServerName,
+		Domain:          p.Domain,
+		Size:            p.Size,
+		Payload:         []byte(p.Payload),
+	}, nil
 }
 
 func (h HealthCheckSettings) Build() (proto.Message, error) {
-	return &burst.HealthPingConfig{
+	config := &burst.HealthPingConfig{
 		Destination:   h.Destination,
 		Connectivity:  h.Connectivity,
 		Interval:      int64(h.Interval),
 		Timeout:       int64(h.Timeout),
 		SamplingCount: int32(h.SamplingCount),
-	}, nil
+	}
+	for _, p := range h.Probes {
+		probe, err := p.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.Probes = append(config.Probes, probe)
+	}
+	return config, nil
 }
 
 // Build implements Buildable.
@@ -98,3 +169,88 @@ type strategyLeastPingConfig struct {
 func (s strategyLeastPingConfig) Build() (proto.Message, error) {
 	return &router.StrategyLeastPingConfig{ObserverTag: s.ObserverTag}, nil
 }
+
+func buildAffinityKey(key string) (router.AffinityKey, error) {
+	switch strings.ToLower(key) {
+	case "", "sourceip", "source":
+		return router.AffinityKey_SourceIp, nil
+	case "user", "useremail", "email":
+		return router.AffinityKey_UserEmail, nil
+	case "targetdomain", "domain", "target":
+		return router.AffinityKey_TargetDomain, nil
+	default:
+		return router.AffinityKey_SourceIp, newError("unknown balancing affinity key: ", key)
+	}
+}
+
+type strategyConsistentHashConfig struct {
+	Key         string `json:"key,omitempty"`
+	HealthCheck bool   `json:"healthCheck,omitempty"`
+	ObserverTag string `json:"observerTag,omitempty"`
+}
+
+// Build implements Buildable.
+func (v *strategyConsistentHashConfig) Build() (proto.Message, error) {
+	key, err := buildAffinityKey(v.Key)
+	if err != nil {
+		return nil, err
+	}
+	return &router.StrategyConsistentHashConfig{
+		Key:         key,
+		HealthCheck: v.HealthCheck,
+		ObserverTag: v.ObserverTag,
+	}, nil
+}
+
+type strategyStickyConfig struct {
+	Key         string            `json:"key,omitempty"`
+	TTL         duration.Duration `json:"ttl,omitempty"`
+	HealthCheck bool              `json:"healthCheck,omitempty"`
+	ObserverTag string            `json:"observerTag,omitempty"`
+}
+
+// Build implements Buildable.
+func (v *strategyStickyConfig) Build() (proto.Message, error) {
+	key, err := buildAffinityKey(v.Key)
+	if err != nil {
+		return nil, err
+	}
+	if v.TTL < 0 {
+		return nil, newError("invalid sticky ttl: ", v.TTL)
+	}
+	return &router.StrategyStickyConfig{
+		Key:         key,
+		Ttl:         int64(v.TTL),
+		HealthCheck: v.HealthCheck,
+		ObserverTag: v.ObserverTag,
+	}, nil
+}
+
+type strategyFailoverConfig struct {
+	ObserverTag string            `json:"observerTag,omitempty"`
+	Revert      bool              `json:"revert,omitempty"`
+	HoldDown    duration.Duration `json:"holdDown,omitempty"`
+}
+
+// Build implements Buildable.
+func (v *strategyFailoverConfig) Build() (proto.Message, error) {
+	if v.HoldDown < 0 {
+		return nil, newError("invalid failover hold down: ", v.HoldDown)
+	}
+	return &router.StrategyFailoverConfig{
+		ObserverTag: v.ObserverTag,
+		Revert:      v.Revert,
+		HoldDown:    int64(v.HoldDown),
+	}, nil
+}
+
+type strategyRoundRobinConfig struct {
+	Weights []*router.StrategyWeight `json:"weights,omitempty"`
+}
+
+// Build implements Buildable.
+func (v *strategyRoundRobinConfig) Build() (proto.Message, error) {
+	return &router.StrategyRoundRobinConfig{
+		Weights: v.Weights,
+	}, nil
+}
@@ -387,5 +387,40 @@ func TestRouterConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			Input: `{
+				"rules": [
+					{
+						"type": "field",
+						"domain": [
+							"ruleset:ads",
+							"qq.com"
+						],
+						"ip": [
+							"ruleset:private"
+						],
+						"outboundTag": "blocked"
+					}
+				]
+			}`,
+			Parser: createParser(),
+			Output: &router.Config{
+				Rule: []*router.RoutingRule{
+					{
+						Domain: []*routercommon.Domain{
+							{
+								Type:  routercommon.Domain_Plain,
+								Value: "qq.com",
+							},
+						},
+						RuleSet:   []string{"ads"},
+						IpRuleSet: []string{"private"},
+						TargetTag: &router.RoutingRule_Tag{
+							Tag: "blocked",
+						},
+					},
+				},
+			},
+		},
 	})
 }
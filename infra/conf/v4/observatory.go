@@ -2,11 +2,13 @@ package v4
 
 import (
 	"encoding/json"
+	"net/url"
 
 	"github.com/golang/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 
 	"github.com/v2fly/v2ray-core/v5/app/observatory"
+	"github.com/v2fly/v2ray-core/v5/app/observatory/alert"
 	"github.com/v2fly/v2ray-core/v5/app/observatory/burst"
 	"github.com/v2fly/v2ray-core/v5/app/observatory/multiobservatory"
 	"github.com/v2fly/v2ray-core/v5/common/serial"
@@ -15,28 +17,99 @@ import (
 	"github.com/v2fly/v2ray-core/v5/infra/conf/synthetic/router"
 )
 
+// PassiveObservationConfig holds settings for observing outbounds with real traffic
+type PassiveObservationConfig struct {
+	Enabled          bool    `json:"enabled"`
+	FailureThreshold uint32  `json:"failureThreshold"`
+	SamplingCount    uint32  `json:"sampling"`
+	ResetThreshold   float32 `json:"resetThreshold"`
+}
+
+func (p *PassiveObservationConfig) Build() (*observatory.PassiveObservationConfig, error) {
+	if p == nil {
+		return nil, nil
+	}
+	if p.ResetThreshold < 0 || p.ResetThreshold > 1 {
+		return nil, newError("invalid passive observation reset threshold: ", p.ResetThreshold)
+	}
+	return &observatory.PassiveObservationConfig{
+		Enabled:          p.Enabled,
+		FailureThreshold: p.FailureThreshold,
+		SamplingCount:    p.SamplingCount,
+		ResetThreshold:   p.ResetThreshold,
+	}, nil
+}
+
 type ObservatoryConfig struct {
-	SubjectSelector []string          `json:"subjectSelector"`
-	ProbeURL        string            `json:"probeURL"`
-	ProbeInterval   duration.Duration `json:"probeInterval"`
+	SubjectSelector []string                  `json:"subjectSelector"`
+	ProbeURL        string                    `json:"probeURL"`
+	ProbeInterval   duration.Duration         `json:"probeInterval"`
+	Passive         *PassiveObservationConfig `json:"passive,omitempty"`
 }
 
 func (o *ObservatoryConfig) Build() (proto.Message, error) {
-	return &observatory.Config{SubjectSelector: o.SubjectSelector, ProbeUrl: o.ProbeURL, ProbeInterval: int64(o.ProbeInterval)}, nil
+	passive, err := o.Passive.Build()
+	if err != nil {
+		return nil, err
+	}
+	return &observatory.Config{SubjectSelector: o.SubjectSelector, ProbeUrl: o.ProbeURL, ProbeInterval: int64(o.ProbeInterval), Passive: passive}, nil
 }
 
 type BurstObservatoryConfig struct {
 	SubjectSelector []string `json:"subjectSelector"`
 	// health check settings
 	HealthCheck *router.HealthCheckSettings `json:"pingConfig,omitempty"`
+	// passive observation settings
+	Passive *PassiveObservationConfig `json:"passive,omitempty"`
 }
 
 func (b BurstObservatoryConfig) Build() (proto.Message, error) {
 	result, err := b.HealthCheck.Build()
-	if err == nil {
-		return &burst.Config{SubjectSelector: b.SubjectSelector, PingConfig: result.(*burst.HealthPingConfig)}, nil
+	if err != nil {
+		return nil, err
+	}
+	passive, err := b.Passive.Build()
+	if err != nil {
+		return nil, err
+	}
+	return &burst.Config{SubjectSelector: b.SubjectSelector, PingConfig: result.(*burst.HealthPingConfig), Passive: passive}, nil
+}
+
+type ObservatoryWebhookConfig struct {
+	URL      string            `json:"url"`
+	Headers  map[string]string `json:"headers"`
+	MaxRetry uint32            `json:"maxRetry"`
+	Backoff  duration.Duration `json:"backoff"`
+	Timeout  duration.Duration `json:"timeout"`
+}
+
+type ObservatoryAlertConfig struct {
+	ObserverTag      string                     `json:"observerTag"`
+	PollInterval     duration.Duration          `json:"pollInterval"`
+	LatencyThreshold duration.Duration          `json:"latencyThreshold"`
+	Webhooks         []ObservatoryWebhookConfig `json:"webhooks"`
+}
+
+func (o *ObservatoryAlertConfig) Build() (proto.Message, error) {
+	config := &alert.Config{
+		ObserverTag:      o.ObserverTag,
+		PollInterval:     int64(o.PollInterval),
+		LatencyThreshold: int64(o.LatencyThreshold),
+	}
+	for _, w := range o.Webhooks {
+		u, err := url.Parse(w.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, newError("invalid webhook url: ", w.URL).Base(err)
+		}
+		config.Webhook = append(config.Webhook, &alert.WebhookConfig{
+			Url:      w.URL,
+			Headers:  w.Headers,
+			MaxRetry: w.MaxRetry,
+			Backoff:  int64(w.Backoff),
+			Timeout:  int64(w.Timeout),
+		})
 	}
-	return nil, err
+	return config, nil
 }
 
 type MultiObservatoryItem struct {
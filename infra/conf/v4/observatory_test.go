@@ -0,0 +1,114 @@
+package v4_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/v2fly/v2ray-core/v5/app/observatory"
+	"github.com/v2fly/v2ray-core/v5/app/observatory/burst"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon/testassist"
+	v4 "github.com/v2fly/v2ray-core/v5/infra/conf/v4"
+)
+
+func TestBurstObservatoryConfig(t *testing.T) {
+	createParser := func() func(string) (proto.Message, error) {
+		return func(s string) (proto.Message, error) {
+			config := new(v4.BurstObservatoryConfig)
+			if err := json.Unmarshal([]byte(s), config); err != nil {
+				return nil, err
+			}
+			return config.Build()
+		}
+	}
+
+	testassist.RunMultiTestCase(t, []testassist.TestCase{
+		{
+			Input: `{
+				"subjectSelector": ["proxy", "udp"],
+				"pingConfig": {
+					"interval": "30s",
+					"probes": [
+						{
+							"subjectSelector": ["udp"],
+							"type": "dns",
+							"destination": "1.1.1.1:53",
+							"domain": "example.com"
+						},
+						{
+							"subjectSelector": ["proxy_ssh"],
+							"type": "tcp",
+							"destination": "10.0.0.1:22"
+						},
+						{
+							"subjectSelector": ["proxy_http"],
+							"type": "tcp",
+							"destination": "10.0.0.1:80",
+							"payload": "HEAD / HTTP/1.0\r\n\r\n"
+						},
+						{
+							"subjectSelector": ["proxy_tls"],
+							"type": "tls",
+							"serverName": "example.com"
+						},
+						{
+							"subjectSelector": ["proxy"],
+							"type": "throughput",
+							"destination": "https://speed.example.com/down?bytes=1048576",
+							"size": 1048576
+						}
+					]
+				},
+				"passive": {
+					"enabled": true,
+					"failureThreshold": 5,
+					"resetThreshold": 0.5
+				}
+			}`,
+			Parser: createParser(),
+			Output: &burst.Config{
+				SubjectSelector: []string{"proxy", "udp"},
+				PingConfig: &burst.HealthPingConfig{
+					Interval: int64(30 * time.Second),
+					Probes: []*burst.ProbeConfig{
+						{
+							SubjectSelector: []string{"udp"},
+							Type:            burst.ProbeConfig_DNS,
+							Destination:     "1.1.1.1:53",
+							Domain:          "example.com",
+						},
+						{
+							SubjectSelector: []string{"proxy_ssh"},
+							Type:            burst.ProbeConfig_TCP,
+							Destination:     "10.0.0.1:22",
+						},
+						{
+							SubjectSelector: []string{"proxy_http"},
+							Type:            burst.ProbeConfig_TCP,
+							Destination:     "10.0.0.1:80",
+							Payload:         []byte("HEAD / HTTP/1.0\r\n\r\n"),
+						},
+						{
+							SubjectSelector: []string{"proxy_tls"},
+							Type:            burst.ProbeConfig_TLS,
+							ServerName:      "example.com",
+						},
+						{
+							SubjectSelector: []string{"proxy"},
+							Type:            burst.ProbeConfig_THROUGHPUT,
+							Destination:     "https://speed.example.com/down?bytes=1048576",
+							Size:            1048576,
+						},
+					},
+				},
+				Passive: &observatory.PassiveObservationConfig{
+					Enabled:          true,
+					FailureThreshold: 5,
+					ResetThreshold:   0.5,
+				},
+			},
+		},
+	})
+}
@@ -9,25 +9,71 @@ import (
 type BridgeConfig struct {
 	Tag    string `json:"tag"`
 	Domain string `json:"domain"`
+	ID     string `json:"id"`
+	PSK    string `json:"psk"`
+	// PrivateKey is a base64 encoded ed25519 seed or private key.
+	PrivateKey    []byte `json:"privateKey"`
+	ControlDomain string `json:"controlDomain"`
 }
 
 func (c *BridgeConfig) Build() (*reverse.BridgeConfig, error) {
+	if (c.PSK != "" || len(c.PrivateKey) != 0) && c.ID == "" {
+		return nil, newError("bridge id is required for authentication")
+	}
 	return &reverse.BridgeConfig{
-		Tag:    c.Tag,
-		Domain: c.Domain,
+		Tag:           c.Tag,
+		Domain:        c.Domain,
+		Id:            c.ID,
+		Psk:           c.PSK,
+		PrivateKey:    c.PrivateKey,
+		ControlDomain: c.ControlDomain,
+	}, nil
+}
+
+type BridgeCredentialConfig struct {
+	ID  string `json:"id"`
+	PSK string `json:"psk"`
+	// PublicKey is a base64 encoded ed25519 public key.
+	PublicKey []byte `json:"publicKey"`
+}
+
+func (c *BridgeCredentialConfig) Build() (*reverse.BridgeCredential, error) {
+	if c.ID == "" {
+		return nil, newError("bridge id is not specified")
+	}
+	if c.PSK == "" && len(c.PublicKey) == 0 {
+		return nil, newError("no psk or public key for bridge ", c.ID)
+	}
+	return &reverse.BridgeCredential{
+		Id:        c.ID,
+		Psk:       c.PSK,
+		PublicKey: c.PublicKey,
 	}, nil
 }
 
 type PortalConfig struct {
-	Tag    string `json:"tag"`
-	Domain string `json:"domain"`
+	Tag             string                   `json:"tag"`
+	Domain          string                   `json:"domain"`
+	BridgeAttribute string                   `json:"bridgeAttribute"`
+	Bridges         []BridgeCredentialConfig `json:"bridges"`
+	ControlDomain   string                   `json:"controlDomain"`
 }
 
 func (c *PortalConfig) Build() (*reverse.PortalConfig, error) {
-	return &reverse.PortalConfig{
-		Tag:    c.Tag,
-		Domain: c.Domain,
-	}, nil
+	config := &reverse.PortalConfig{
+		Tag:             c.Tag,
+		Domain:          c.Domain,
+		BridgeAttribute: c.BridgeAttribute,
+		ControlDomain:   c.ControlDomain,
+	}
+	for _, credential := range c.Bridges {
+		b, err := credential.Build()
+		if err != nil {
+			return nil, newError("invalid bridge of portal ", c.Tag).Base(err)
+		}
+		config.Bridge = append(config.Bridge, b)
+	}
+	return config, nil
 }
 
 type ReverseConfig struct {
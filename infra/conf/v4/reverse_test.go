@@ -1,6 +1,7 @@
 package v4_test
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/v2fly/v2ray-core/v5/app/reverse"
@@ -29,6 +30,70 @@ func TestReverseConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			Input: `{
+				"bridges": [{
+					"tag": "test",
+					"domain": "test.v2fly.org",
+					"id": "site-a",
+					"controlDomain": "control.test.v2fly.org"
+				}],
+				"portals": [{
+					"tag": "test",
+					"domain": "test.v2fly.org",
+					"bridgeAttribute": "bridge",
+					"controlDomain": "control.test.v2fly.org"
+				}]
+			}`,
+			Parser: testassist.LoadJSON(creator),
+			Output: &reverse.Config{
+				BridgeConfig: []*reverse.BridgeConfig{
+					{Tag: "test", Domain: "test.v2fly.org", Id: "site-a", ControlDomain: "control.test.v2fly.org"},
+				},
+				PortalConfig: []*reverse.PortalConfig{
+					{Tag: "test", Domain: "test.v2fly.org", BridgeAttribute: "bridge", ControlDomain: "control.test.v2fly.org"},
+				},
+			},
+		},
+		{
+			Input: `{
+				"bridges": [{
+					"tag": "test",
+					"domain": "test.v2fly.org",
+					"id": "site-a",
+					"psk": "secret"
+				}, {
+					"tag": "test2",
+					"domain": "test.v2fly.org",
+					"id": "site-b",
+					"privateKey": "AQIDBAUGBwgJCgsMDQ4PEBESExQVFhcYGRobHB0eHyA="
+				}],
+				"portals": [{
+					"tag": "test",
+					"domain": "test.v2fly.org",
+					"bridges": [
+						{"id": "site-a", "psk": "secret"},
+						{"id": "site-b", "publicKey": "BwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwc="}
+					]
+				}]
+			}`,
+			Parser: testassist.LoadJSON(creator),
+			Output: &reverse.Config{
+				BridgeConfig: []*reverse.BridgeConfig{
+					{Tag: "test", Domain: "test.v2fly.org", Id: "site-a", Psk: "secret"},
+					{Tag: "test2", Domain: "test.v2fly.org", Id: "site-b", PrivateKey: []byte{
+						1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16,
+						17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32,
+					}},
+				},
+				PortalConfig: []*reverse.PortalConfig{
+					{Tag: "test", Domain: "test.v2fly.org", Bridge: []*reverse.BridgeCredential{
+						{Id: "site-a", Psk: "secret"},
+						{Id: "site-b", PublicKey: bytes.Repeat([]byte{7}, 32)},
+					}},
+				},
+			},
+		},
 		{
 			Input: `{
 				"portals": [{
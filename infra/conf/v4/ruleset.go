@@ -0,0 +1,44 @@
+package v4
+
+import (
+	"github.com/golang/protobuf/proto"
+
+	"github.com/v2fly/v2ray-core/v5/app/ruleset"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon/duration"
+)
+
+type RuleSetProviderConfig struct {
+	Name        string            `json:"name"`
+	Path        string            `json:"path"`
+	URL         string            `json:"url"`
+	OutboundTag string            `json:"outboundTag"`
+	Interval    duration.Duration `json:"interval"`
+	SHA256      string            `json:"sha256"`
+	SHA256URL   string            `json:"sha256Url"`
+	CachePath   string            `json:"cachePath"`
+}
+
+type RuleSetConfig []RuleSetProviderConfig
+
+func (c RuleSetConfig) Build() (proto.Message, error) {
+	config := new(ruleset.Config)
+	for _, p := range c {
+		if p.Name == "" {
+			return nil, newError("rule set name is not specified")
+		}
+		if (p.Path == "") == (p.URL == "") {
+			return nil, newError("rule set ", p.Name, ": exactly one of path and url should be specified")
+		}
+		config.Provider = append(config.Provider, &ruleset.Provider{
+			Name:        p.Name,
+			Path:        p.Path,
+			Url:         p.URL,
+			OutboundTag: p.OutboundTag,
+			Interval:    int64(p.Interval),
+			Sha256:      p.SHA256,
+			Sha256Url:   p.SHA256URL,
+			CachePath:   p.CachePath,
+		})
+	}
+	return config, nil
+}
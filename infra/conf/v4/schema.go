@@ -0,0 +1,64 @@
+package v4
+
+import (
+	"reflect"
+	"sort"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/v2fly/v2ray-core/v5/common/registry"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon/loader"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/jsonschema"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/rule"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/synthetic/router"
+)
+
+// JSONSchema returns the JSON Schema of the v4 config format.
+func JSONSchema() *jsonschema.Schema {
+	r := jsonschema.NewReflector()
+	root := r.Definition(r.Reflect(reflect.TypeOf(Config{})))
+
+	inbound := r.Definition(r.Reflect(reflect.TypeOf(InboundDetourConfig{})))
+	setProtocolSettings(r, inbound, inboundConfigLoader)
+	outbound := r.Definition(r.Reflect(reflect.TypeOf(OutboundDetourConfig{})))
+	setProtocolSettings(r, outbound, outboundConfigLoader)
+
+	routing := r.Definition(r.Reflect(reflect.TypeOf(router.RouterConfig{})))
+	routing.Properties["rules"] = jsonschema.Array(rule.JSONSchema(r))
+
+	// Services are keyed by the full names of their config messages.
+	services := jsonschema.Object(make(map[string]*jsonschema.Schema))
+	for _, name := range registry.ListImplementations("service") {
+		messageType, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(name))
+		if err != nil {
+			continue
+		}
+		services.Properties[name] = r.ReflectMessage(messageType.Descriptor())
+	}
+	root.Properties["services"] = services
+
+	schema := *root
+	schema.Title = "V2Ray config (v4)"
+	return r.Root(&schema)
+}
+
+// setProtocolSettings makes the settings property of the object be of the config of the protocol.
+func setProtocolSettings(r *jsonschema.Reflector, object *jsonschema.Schema, configLoader *loader.JSONConfigLoader) {
+	cache := configLoader.Cache()
+	protocols := make([]string, 0, len(cache))
+	for protocol := range cache {
+		protocols = append(protocols, protocol)
+	}
+	sort.Strings(protocols)
+
+	object.Properties["protocol"] = jsonschema.StringEnum(protocols...)
+	object.Required = append(object.Required, "protocol")
+	for _, protocol := range protocols {
+		settings := r.Reflect(reflect.TypeOf(cache[protocol]()))
+		object.AllOf = append(object.AllOf, &jsonschema.Schema{
+			If:   jsonschema.Object(map[string]*jsonschema.Schema{"protocol": {Const: protocol}}).WithRequired("protocol"),
+			Then: jsonschema.Object(map[string]*jsonschema.Schema{"settings": settings}),
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package v4_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/infra/conf/jsonschema"
+	v4 "github.com/v2fly/v2ray-core/v5/infra/conf/v4"
+)
+
+func TestJSONSchema(t *testing.T) {
+	schema := v4.JSONSchema()
+	if schema.Schema != jsonschema.Draft || schema.Title == "" {
+		t.Error("not a root schema")
+	}
+	definition := func(s *jsonschema.Schema) *jsonschema.Schema {
+		t.Helper()
+		if s.Items != nil {
+			s = s.Items
+		}
+		def := schema.Definitions[s.Ref[len("#/definitions/"):]]
+		if def == nil {
+			t.Fatal("no definition of ", s.Ref)
+		}
+		return def
+	}
+
+	for _, name := range []string{"inbounds", "outbounds", "routing", "dns", "log", "transport"} {
+		if schema.Properties[name] == nil {
+			t.Error("missing property ", name)
+		}
+	}
+
+	inbound := definition(schema.Properties["inbounds"])
+	if inbound.Properties["protocol"] == nil || len(inbound.Properties["protocol"].Enum) == 0 {
+		t.Fatal("missing protocols of inbounds")
+	}
+	found := false
+	for _, condition := range inbound.AllOf {
+		if condition.If.Properties["protocol"].Const == "vmess" {
+			found = true
+			settings := definition(condition.Then.Properties["settings"])
+			if settings.Properties["clients"] == nil {
+				t.Error("unexpected settings of vmess inbound")
+			}
+		}
+	}
+	if !found {
+		t.Error("no settings of vmess inbound")
+	}
+
+	rules := definition(schema.Properties["routing"]).Properties["rules"]
+	rule := definition(rules)
+	for _, name := range []string{"type", "outboundTag", "balancerTag", "domain", "ip", "port", "schedule"} {
+		if rule.Properties[name] == nil {
+			t.Error("missing property of routing rules: ", name)
+		}
+	}
+
+	if _, err := json.Marshal(schema); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,110 @@
+package v4
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/common/buf"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon/duration"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/jsonschema"
+)
+
+// ShapingPaddingRange is the padding length of a write, either a number or a range like "100-200".
+type ShapingPaddingRange struct {
+	Min uint32
+	Max uint32
+}
+
+// UnmarshalJSON implements encoding/json.Unmarshaler.UnmarshalJSON
+func (r *ShapingPaddingRange) UnmarshalJSON(data []byte) error {
+	var number uint32
+	if err := json.Unmarshal(data, &number); err == nil {
+		r.Min, r.Max = number, number
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return newError("invalid padding range: ", string(data)).Base(err)
+	}
+	parts := strings.SplitN(str, "-", 2)
+	min, err := strconv.ParseUint(strings.TrimSpace(parts[0]), 10, 16)
+	if err != nil {
+		return newError("invalid padding range: ", str).Base(err)
+	}
+	max := min
+	if len(parts) == 2 {
+		if max, err = strconv.ParseUint(strings.TrimSpace(parts[1]), 10, 16); err != nil {
+			return newError("invalid padding range: ", str).Base(err)
+		}
+	}
+	if max < min {
+		return newError("invalid padding range: ", str)
+	}
+	r.Min, r.Max = uint32(min), uint32(max)
+	return nil
+}
+
+func (r *ShapingPaddingRange) JSONSchema(*jsonschema.Reflector) *jsonschema.Schema {
+	return jsonschema.AnyOf(
+		jsonschema.Unsigned(),
+		jsonschema.String().WithDescription("padding length range, like \"100-200\""),
+	)
+}
+
+type ShapingConfig struct {
+	Padding      []ShapingPaddingRange `json:"padding"`
+	RecordSize   uint32                `json:"recordSize"`
+	Jitter       duration.Duration     `json:"jitter"`
+	JitterWrites uint32                `json:"jitterWrites"`
+}
+
+func (c *ShapingConfig) Build() (*buf.ShapingConfig, error) {
+	if c.RecordSize > 0 && c.RecordSize < 64 {
+		return nil, newError("record size ", c.RecordSize, " is too small")
+	}
+	if c.Jitter < 0 {
+		return nil, newError("negative jitter")
+	}
+	config := &buf.ShapingConfig{
+		RecordSize:   c.RecordSize,
+		Jitter:       int64(time.Duration(c.Jitter)),
+		JitterWrites: c.JitterWrites,
+	}
+	for _, r := range c.Padding {
+		if r.Max > buf.Size {
+			return nil, newError("padding length ", r.Max, " exceeds ", buf.Size)
+		}
+		config.Padding = append(config.Padding, &buf.ShapingConfig_Range{Min: r.Min, Max: r.Max})
+	}
+	return config, nil
+}
+
+// extractShaping removes the shaping settings from a raw user config, whose account is decoded as is, and
+// returns them built.
+func extractShaping(rawUser json.RawMessage) (json.RawMessage, *buf.ShapingConfig, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(rawUser, &fields); err != nil {
+		return nil, nil, err
+	}
+	rawShaping, found := fields["shaping"]
+	if !found {
+		return rawUser, nil, nil
+	}
+	delete(fields, "shaping")
+	rest, err := json.Marshal(fields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shaping := new(ShapingConfig)
+	if err := json.Unmarshal(rawShaping, shaping); err != nil {
+		return nil, nil, newError("invalid shaping settings").Base(err)
+	}
+	config, err := shaping.Build()
+	if err != nil {
+		return nil, nil, newError("invalid shaping settings").Base(err)
+	}
+	return rest, config, nil
+}
@@ -48,6 +48,13 @@ type KCPConfig struct {
 	WriteBufferSize *uint32         `json:"writeBufferSize"`
 	HeaderConfig    json.RawMessage `json:"header"`
 	Seed            *string         `json:"seed"`
+	FEC             *KCPFECConfig   `json:"fec"`
+	Pacing          bool            `json:"pacing"`
+}
+
+type KCPFECConfig struct {
+	DataShards   uint32 `json:"dataShards"`
+	ParityShards uint32 `json:"parityShards"`
 }
 
 // Build implements Buildable.
@@ -109,6 +116,24 @@ func (c *KCPConfig) Build() (proto.Message, error) {
 		config.Seed = &kcp.EncryptionSeed{Seed: *c.Seed}
 	}
 
+	if c.FEC != nil {
+		if c.FEC.DataShards == 0 || c.FEC.ParityShards == 0 || c.FEC.DataShards+c.FEC.ParityShards > 255 {
+			return nil, newError("invalid mKCP FEC shards: ", c.FEC.DataShards, "+", c.FEC.ParityShards).AtError()
+		}
+		config.Fec = &kcp.FEC{
+			DataShards:   c.FEC.DataShards,
+			ParityShards: c.FEC.ParityShards,
+		}
+		overhead, err := config.GetPacketOverhead()
+		if err != nil {
+			return nil, newError("invalid mKCP config").Base(err).AtError()
+		}
+		if err := config.CheckFEC(overhead); err != nil {
+			return nil, newError("invalid mKCP FEC config").Base(err).AtError()
+		}
+	}
+	config.Pacing = c.Pacing
+
 	return config, nil
 }
 
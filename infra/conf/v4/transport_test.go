@@ -87,7 +87,12 @@ func TestTransportConfig(t *testing.T) {
 					"mtu": 1200,
 					"header": {
 						"type": "none"
-					}
+					},
+					"fec": {
+						"dataShards": 10,
+						"parityShards": 3
+					},
+					"pacing": true
 				},
 				"wsSettings": {
 					"path": "/t"
@@ -149,6 +154,8 @@ func TestTransportConfig(t *testing.T) {
 						Settings: serial.ToTypedMessage(&kcp.Config{
 							Mtu:          &kcp.MTU{Value: 1200},
 							HeaderConfig: serial.ToTypedMessage(&noop.Config{}),
+							Fec:          &kcp.FEC{DataShards: 10, ParityShards: 3},
+							Pacing:       true,
 						}),
 					},
 					{
@@ -172,3 +179,18 @@ func TestTransportConfig(t *testing.T) {
 		},
 	})
 }
+
+func TestKCPConfigLargeFEC(t *testing.T) {
+	config := new(v4.KCPConfig)
+	if err := json.Unmarshal([]byte(`{
+		"fec": {
+			"dataShards": 200,
+			"parityShards": 10
+		}
+	}`), config); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config.Build(); err == nil {
+		t.Error("expect an error for FEC overhead larger than the segment")
+	}
+}
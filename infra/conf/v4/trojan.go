@@ -22,6 +22,7 @@ type TrojanServerTarget struct {
 	Password string             `json:"password"`
 	Email    string             `json:"email"`
 	Level    byte               `json:"level"`
+	Shaping  *ShapingConfig     `json:"shaping"`
 }
 
 // TrojanClientConfig is configuration of trojan servers
@@ -51,6 +52,13 @@ func (c *TrojanClientConfig) Build() (proto.Message, error) {
 		account := &trojan.Account{
 			Password: rec.Password,
 		}
+		if rec.Shaping != nil {
+			shaping, err := rec.Shaping.Build()
+			if err != nil {
+				return nil, newError("Trojan server: invalid shaping settings").Base(err)
+			}
+			account.Shaping = shaping
+		}
 		trojan := &protocol.ServerEndpoint{
 			Address: rec.Address.Build(),
 			Port:    uint32(rec.Port),
@@ -82,9 +90,10 @@ type TrojanInboundFallback struct {
 
 // TrojanUserConfig is user configuration
 type TrojanUserConfig struct {
-	Password string `json:"password"`
-	Level    byte   `json:"level"`
-	Email    string `json:"email"`
+	Password string         `json:"password"`
+	Level    byte           `json:"level"`
+	Email    string         `json:"email"`
+	Shaping  *ShapingConfig `json:"shaping"`
 }
 
 // TrojanServerConfig is Inbound configuration
@@ -103,6 +112,13 @@ func (c *TrojanServerConfig) Build() (proto.Message, error) {
 		account := &trojan.Account{
 			Password: rawUser.Password,
 		}
+		if rawUser.Shaping != nil {
+			shaping, err := rawUser.Shaping.Build()
+			if err != nil {
+				return nil, newError("Trojan clients: invalid shaping settings").Base(err)
+			}
+			account.Shaping = shaping
+		}
 
 		user.Email = rawUser.Email
 		user.Level = uint32(rawUser.Level)
@@ -16,6 +16,7 @@ import (
 	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon/loader"
 	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon/muxcfg"
 	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon/proxycfg"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon/sendthroughcfg"
 	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon/sniffer"
 	"github.com/v2fly/v2ray-core/v5/infra/conf/synthetic/dns"
 	"github.com/v2fly/v2ray-core/v5/infra/conf/synthetic/log"
@@ -203,14 +204,15 @@ func (c *InboundDetourConfig) Build() (*core.InboundHandlerConfig, error) {
 }
 
 type OutboundDetourConfig struct {
-	Protocol       string                `json:"protocol"`
-	SendThrough    *cfgcommon.Address    `json:"sendThrough"`
-	Tag            string                `json:"tag"`
-	Settings       *json.RawMessage      `json:"settings"`
-	StreamSetting  *StreamConfig         `json:"streamSettings"`
-	ProxySettings  *proxycfg.ProxyConfig `json:"proxySettings"`
-	MuxSettings    *muxcfg.MuxConfig     `json:"mux"`
-	DomainStrategy string                `json:"domainStrategy"`
+	Protocol            string                      `json:"protocol"`
+	SendThrough         *sendthroughcfg.SendThrough `json:"sendThrough"`
+	SendThroughStrategy string                      `json:"sendThroughStrategy"`
+	Tag                 string                      `json:"tag"`
+	Settings            *json.RawMessage            `json:"settings"`
+	StreamSetting       *StreamConfig               `json:"streamSettings"`
+	ProxySettings       *proxycfg.ProxyConfig       `json:"proxySettings"`
+	MuxSettings         *muxcfg.MuxConfig           `json:"mux"`
+	DomainStrategy      string                      `json:"domainStrategy"`
 }
 
 // Build implements Buildable.
@@ -218,11 +220,12 @@ func (c *OutboundDetourConfig) Build() (*core.OutboundHandlerConfig, error) {
 	senderSettings := &proxyman.SenderConfig{}
 
 	if c.SendThrough != nil {
-		address := c.SendThrough
-		if address.Family().IsDomain() {
-			return nil, newError("unable to send through: " + address.String())
+		via, pool, err := c.SendThrough.Build(c.SendThroughStrategy)
+		if err != nil {
+			return nil, err
 		}
-		senderSettings.Via = address.Build()
+		senderSettings.Via = via
+		senderSettings.ViaPool = pool
 	}
 
 	if c.StreamSetting != nil {
@@ -319,6 +322,8 @@ type Config struct {
 	Observatory      *ObservatoryConfig      `json:"observatory"`
 	BurstObservatory *BurstObservatoryConfig `json:"burstObservatory"`
 	MultiObservatory *MultiObservatoryConfig `json:"multiObservatory"`
+	ObservatoryAlert *ObservatoryAlertConfig `json:"observatoryAlert"`
+	RuleSets         RuleSetConfig           `json:"ruleSets"`
 
 	Services map[string]*json.RawMessage `json:"services"`
 }
@@ -478,6 +483,22 @@ func (c *Config) Build() (*core.Config, error) {
 		config.App = append(config.App, serial.ToTypedMessage(r))
 	}
 
+	if c.ObservatoryAlert != nil {
+		r, err := c.ObservatoryAlert.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.App = append(config.App, serial.ToTypedMessage(r))
+	}
+
+	if len(c.RuleSets) > 0 {
+		r, err := c.RuleSets.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.App = append(config.App, serial.ToTypedMessage(r))
+	}
+
 	// Load Additional Services that do not have a json translator
 
 	if msg, err := c.BuildServices(c.Services); err != nil {
@@ -41,10 +41,15 @@ func (c *VLessInboundConfig) Build() (proto.Message, error) {
 		if err := json.Unmarshal(rawUser, user); err != nil {
 			return nil, newError(`VLESS clients: invalid user`).Base(err)
 		}
+		rawAccount, shaping, err := extractShaping(rawUser)
+		if err != nil {
+			return nil, newError(`VLESS clients: invalid user`).Base(err)
+		}
 		account := new(vless.Account)
-		if err := json.Unmarshal(rawUser, account); err != nil {
+		if err := json.Unmarshal(rawAccount, account); err != nil {
 			return nil, newError(`VLESS clients: invalid user`).Base(err)
 		}
+		account.Shaping = shaping
 
 		if account.Encryption != "" {
 			return nil, newError(`VLESS clients: "encryption" should not in inbound settings`)
@@ -155,10 +160,15 @@ func (c *VLessOutboundConfig) Build() (proto.Message, error) {
 			if err := json.Unmarshal(rawUser, user); err != nil {
 				return nil, newError(`VLESS users: invalid user`).Base(err)
 			}
+			rawAccount, shaping, err := extractShaping(rawUser)
+			if err != nil {
+				return nil, newError(`VLESS users: invalid user`).Base(err)
+			}
 			account := new(vless.Account)
-			if err := json.Unmarshal(rawUser, account); err != nil {
+			if err := json.Unmarshal(rawAccount, account); err != nil {
 				return nil, newError(`VLESS users: invalid user`).Base(err)
 			}
+			account.Shaping = shaping
 
 			if account.Encryption != "none" {
 				return nil, newError(`VLESS users: please add/set "encryption":"none" for every user`)
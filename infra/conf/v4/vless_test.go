@@ -2,7 +2,9 @@ package v4_test
 
 import (
 	"testing"
+	"time"
 
+	"github.com/v2fly/v2ray-core/v5/common/buf"
 	"github.com/v2fly/v2ray-core/v5/common/net"
 	"github.com/v2fly/v2ray-core/v5/common/protocol"
 	"github.com/v2fly/v2ray-core/v5/common/serial"
@@ -57,6 +59,58 @@ func TestVLessOutbound(t *testing.T) {
 				},
 			},
 		},
+		{
+			Input: `{
+				"vnext": [{
+					"address": "example.com",
+					"port": 443,
+					"users": [
+						{
+							"id": "27848739-7e62-4138-9fd3-098a63964b6b",
+							"encryption": "none",
+							"padding": true,
+							"shaping": {
+								"padding": ["100-200", 64],
+								"recordSize": 1400,
+								"jitter": "20ms",
+								"jitterWrites": 4
+							}
+						}
+					]
+				}]
+			}`,
+			Parser: testassist.LoadJSON(creator),
+			Output: &outbound.Config{
+				Vnext: []*protocol.ServerEndpoint{
+					{
+						Address: &net.IPOrDomain{
+							Address: &net.IPOrDomain_Domain{
+								Domain: "example.com",
+							},
+						},
+						Port: 443,
+						User: []*protocol.User{
+							{
+								Account: serial.ToTypedMessage(&vless.Account{
+									Id:         "27848739-7e62-4138-9fd3-098a63964b6b",
+									Encryption: "none",
+									Padding:    true,
+									Shaping: &buf.ShapingConfig{
+										Padding: []*buf.ShapingConfig_Range{
+											{Min: 100, Max: 200},
+											{Min: 64, Max: 64},
+										},
+										RecordSize:   1400,
+										Jitter:       int64(20 * time.Millisecond),
+										JitterWrites: 4,
+									},
+								}),
+							},
+						},
+					},
+				},
+			},
+		},
 	})
 }
 
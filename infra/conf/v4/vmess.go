@@ -15,14 +15,15 @@ import (
 )
 
 type VMessAccount struct {
-	ID          string `json:"id"`
-	AlterIds    uint16 `json:"alterId"`
-	Security    string `json:"security"`
-	Experiments string `json:"experiments"`
+	ID          string         `json:"id"`
+	AlterIds    uint16         `json:"alterId"`
+	Security    string         `json:"security"`
+	Experiments string         `json:"experiments"`
+	Shaping     *ShapingConfig `json:"shaping"`
 }
 
 // Build implements Buildable
-func (a *VMessAccount) Build() *vmess.Account {
+func (a *VMessAccount) Build() (*vmess.Account, error) {
 	var st protocol.SecurityType
 	switch strings.ToLower(a.Security) {
 	case "aes-128-gcm":
@@ -38,7 +39,7 @@ func (a *VMessAccount) Build() *vmess.Account {
 	default:
 		st = protocol.SecurityType_AUTO
 	}
-	return &vmess.Account{
+	account := &vmess.Account{
 		Id:      a.ID,
 		AlterId: uint32(a.AlterIds),
 		SecuritySettings: &protocol.SecurityConfig{
@@ -46,6 +47,14 @@ func (a *VMessAccount) Build() *vmess.Account {
 		},
 		TestsEnabled: a.Experiments,
 	}
+	if a.Shaping != nil {
+		shaping, err := a.Shaping.Build()
+		if err != nil {
+			return nil, newError("invalid shaping settings").Base(err)
+		}
+		account.Shaping = shaping
+	}
+	return account, nil
 }
 
 type VMessDetourConfig struct {
@@ -110,7 +119,11 @@ func (c *VMessInboundConfig) Build() (proto.Message, error) {
 		if err := json.Unmarshal(rawData, account); err != nil {
 			return nil, newError("invalid VMess user").Base(err)
 		}
-		user.Account = serial.ToTypedMessage(account.Build())
+		vmessAccount, err := account.Build()
+		if err != nil {
+			return nil, newError("invalid VMess user").Base(err)
+		}
+		user.Account = serial.ToTypedMessage(vmessAccount)
 		config.User[idx] = user
 	}
 
@@ -155,7 +168,11 @@ func (c *VMessOutboundConfig) Build() (proto.Message, error) {
 			if err := json.Unmarshal(rawUser, account); err != nil {
 				return nil, newError("invalid VMess user").Base(err)
 			}
-			user.Account = serial.ToTypedMessage(account.Build())
+			vmessAccount, err := account.Build()
+			if err != nil {
+				return nil, newError("invalid VMess user").Base(err)
+			}
+			user.Account = serial.ToTypedMessage(vmessAccount)
 			spec.User = append(spec.User, user)
 		}
 		serverSpecs[idx] = spec
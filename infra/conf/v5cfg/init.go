@@ -2,6 +2,7 @@ package v5cfg
 
 import (
 	"bytes"
+	stdjson "encoding/json"
 	"io"
 
 	core "github.com/v2fly/v2ray-core/v5"
@@ -48,6 +49,13 @@ func init() {
 					return nil, err
 				}
 				return loadJSONConfig(data)
+			case map[string]interface{}:
+				// Merged configs have been interpolated already.
+				data, err := stdjson.Marshal(v)
+				if err != nil {
+					return nil, err
+				}
+				return buildJSONConfig(data)
 			default:
 				return nil, newError("unknown type")
 			}
@@ -15,11 +15,12 @@ func (c OutboundConfig) BuildV5(ctx context.Context) (proto.Message, error) {
 	senderSettings := &proxyman.SenderConfig{}
 
 	if c.SendThrough != nil {
-		address := c.SendThrough
-		if address.Family().IsDomain() {
-			return nil, newError("unable to send through: " + address.String())
+		via, pool, err := c.SendThrough.Build(c.SendThroughStrategy)
+		if err != nil {
+			return nil, err
 		}
-		senderSettings.Via = address.Build()
+		senderSettings.Via = via
+		senderSettings.ViaPool = pool
 	}
 
 	if c.StreamSetting != nil {
@@ -1,6 +1,7 @@
 package v5cfg
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/v2fly/v2ray-core/v5/common/serial"
 	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon"
 	"github.com/v2fly/v2ray-core/v5/infra/conf/geodata"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/interpolate"
 	"github.com/v2fly/v2ray-core/v5/infra/conf/synthetic/log"
 )
 
@@ -82,9 +84,35 @@ func (c RootConfig) BuildV5(ctx context.Context) (proto.Message, error) {
 	return config, nil
 }
 
+// interpolateJSON substitutes references to environment variables and files in the JSON content.
+func interpolateJSON(data []byte) ([]byte, error) {
+	if !bytes.Contains(data, []byte("${")) {
+		return data, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	v, err := interpolate.Default.Value(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
 func loadJSONConfig(data []byte) (*core.Config, error) {
-	rootConfig := &RootConfig{}
+	data, err := interpolateJSON(data)
+	if err != nil {
+		return nil, newError("unable to interpolate json").Base(err)
+	}
+	return buildJSONConfig(data)
+}
 
+// buildJSONConfig builds the JSON content, whose references have been substituted.
+func buildJSONConfig(data []byte) (*core.Config, error) {
+	rootConfig := &RootConfig{}
 	err := json.Unmarshal(data, rootConfig)
 	if err != nil {
 		return nil, newError("unable to load json").Base(err)
@@ -0,0 +1,95 @@
+package v5cfg
+
+import (
+	"reflect"
+	"sort"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/v2fly/v2ray-core/v5/common/registry"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/jsonschema"
+)
+
+// JSONSchema returns the JSON Schema of the v5 config format.
+// Settings are described by the implementations registered by the packages linked into the binary.
+func JSONSchema() *jsonschema.Schema {
+	r := jsonschema.NewReflector()
+	root := r.Definition(r.Reflect(reflect.TypeOf(RootConfig{})))
+	root.Properties["log"] = implementationSchema(r, "service", "log")
+	root.Properties["dns"] = implementationSchema(r, "service", "dns")
+	root.Properties["router"] = implementationSchema(r, "service", "router")
+
+	services := jsonschema.Object(make(map[string]*jsonschema.Schema))
+	services.AdditionalProperties = jsonschema.Never()
+	for _, alias := range sortedAliases("service") {
+		services.Properties[alias] = implementationSchema(r, "service", alias)
+	}
+	root.Properties["services"] = services
+
+	inbound := r.Definition(r.Reflect(reflect.TypeOf(InboundConfig{})))
+	setDependentSettings(r, inbound, "inbound", "protocol", "settings", "")
+	outbound := r.Definition(r.Reflect(reflect.TypeOf(OutboundConfig{})))
+	setDependentSettings(r, outbound, "outbound", "protocol", "settings", "")
+	stream := r.Definition(r.Reflect(reflect.TypeOf(StreamConfig{})))
+	setDependentSettings(r, stream, "transport", "transport", "transportSettings", "tcp")
+	setDependentSettings(r, stream, "security", "security", "securitySettings", "none")
+
+	schema := *root
+	schema.Title = "V2Ray config (v5)"
+	return r.Root(&schema)
+}
+
+func sortedAliases(interfaceType string) []string {
+	var aliases []string
+	for alias := range registry.ListImplementations(interfaceType) {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+func implementationSchema(r *jsonschema.Reflector, interfaceType, alias string) *jsonschema.Schema {
+	name, found := registry.ListImplementations(interfaceType)[alias]
+	if !found {
+		return jsonschema.Any()
+	}
+	messageType, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(name))
+	if err != nil {
+		return jsonschema.Any()
+	}
+	return r.ReflectMessage(messageType.Descriptor())
+}
+
+// setDependentSettings makes the settings property of the object be of the implementation named by the key property.
+// The implementation named by defaultAlias is used if the key property is absent.
+func setDependentSettings(r *jsonschema.Reflector, object *jsonschema.Schema, interfaceType, key, settings, defaultAlias string) {
+	aliases := sortedAliases(interfaceType)
+	if defaultAlias != "" && !contains(aliases, defaultAlias) {
+		// The default implementation may take no settings.
+		aliases = append([]string{defaultAlias}, aliases...)
+	}
+	object.Properties[key] = jsonschema.StringEnum(aliases...)
+	if defaultAlias == "" {
+		object.Required = append(object.Required, key)
+	}
+	for _, alias := range aliases {
+		condition := jsonschema.Object(map[string]*jsonschema.Schema{key: {Const: alias}})
+		if alias != defaultAlias {
+			condition.Required = []string{key}
+		}
+		object.AllOf = append(object.AllOf, &jsonschema.Schema{
+			If:   condition,
+			Then: jsonschema.Object(map[string]*jsonschema.Schema{settings: implementationSchema(r, interfaceType, alias)}),
+		})
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon"
 	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon/muxcfg"
 	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon/proxycfg"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon/sendthroughcfg"
 	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon/sniffer"
 	"github.com/v2fly/v2ray-core/v5/infra/conf/cfgcommon/socketcfg"
 )
@@ -31,13 +32,14 @@ type InboundConfig struct {
 }
 
 type OutboundConfig struct {
-	Protocol      string                `json:"protocol"`
-	SendThrough   *cfgcommon.Address    `json:"sendThrough"`
-	Tag           string                `json:"tag"`
-	Settings      json.RawMessage       `json:"settings"`
-	StreamSetting *StreamConfig         `json:"streamSettings"`
-	ProxySettings *proxycfg.ProxyConfig `json:"proxySettings"`
-	MuxSettings   *muxcfg.MuxConfig     `json:"mux"`
+	Protocol            string                      `json:"protocol"`
+	SendThrough         *sendthroughcfg.SendThrough `json:"sendThrough"`
+	SendThroughStrategy string                      `json:"sendThroughStrategy"`
+	Tag                 string                      `json:"tag"`
+	Settings            json.RawMessage             `json:"settings"`
+	StreamSetting       *StreamConfig               `json:"streamSettings"`
+	ProxySettings       *proxycfg.ProxyConfig       `json:"proxySettings"`
+	MuxSettings         *muxcfg.MuxConfig           `json:"mux"`
 }
 
 type StreamConfig struct {
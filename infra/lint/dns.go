@@ -0,0 +1,82 @@
+package lint
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	dns_proxy "github.com/v2fly/v2ray-core/v5/proxy/dns"
+)
+
+// isDispatched returns whether queries to the name server are sent through the router, in the same way as
+// app/dns.NewServer creates the name server.
+func isDispatched(dest net.Destination) bool {
+	if dest.Address.Family().IsDomain() {
+		u, err := url.Parse(dest.Address.Domain())
+		if err != nil {
+			return false
+		}
+		switch {
+		case strings.EqualFold(u.String(), "localhost"), strings.EqualFold(u.String(), "fakedns"):
+			return false
+		case strings.EqualFold(u.Scheme, "https"), strings.EqualFold(u.Scheme, "tcp"):
+			return true
+		case strings.EqualFold(u.Scheme, "https+local"), strings.EqualFold(u.Scheme, "tcp+local"), strings.EqualFold(u.Scheme, "quic+local"):
+			return false
+		}
+	}
+	return dest.Network == net.Network_Unknown || dest.Network == net.Network_UDP
+}
+
+func isDNSOutbound(out *outbound) bool {
+	_, ok := out.proxy.(*dns_proxy.Config)
+	return ok
+}
+
+// checkDNSLoops reports name servers whose queries may be routed to a DNS outbound,
+// which resolves them with the built-in DNS client again.
+func checkDNSLoops(l *linter) {
+	conditions := l.conditions()
+	for idx, ns := range l.nameServers {
+		if ns.GetAddress() == nil {
+			continue
+		}
+		dest := ns.GetAddress().AsDestination()
+		if !isDispatched(dest) {
+			continue
+		}
+		// Queries have a random inbound tag if no tag is set, which is matched by no inbound tag condition.
+		tag := ns.GetTag()
+		if tag == "" {
+			tag = l.dnsTag
+		}
+		location := fmt.Sprintf("name server #%d", idx)
+
+		matched := false
+		for i, rule := range l.rules {
+			c := conditions[i]
+			if !c.matchesInbound(tag) {
+				continue
+			}
+			for _, out := range l.ruleTargets(rule) {
+				if !isDNSOutbound(out) {
+					continue
+				}
+				if c.restricted && len(c.inboundTags) == 0 {
+					l.report(SeverityWarning, "dns-loop", location, "queries to ", dest.Address, " may be routed by ", ruleLocation(i), " to DNS outbound ", quote(out.tag), ", which sends them back to the DNS client")
+				} else {
+					l.report(SeverityError, "dns-loop", location, "queries to ", dest.Address, " are routed by ", ruleLocation(i), " to DNS outbound ", quote(out.tag), ", which sends them back to the DNS client")
+				}
+				break
+			}
+			if !c.restricted {
+				matched = true
+				break
+			}
+		}
+		if !matched && len(l.outbounds) > 0 && isDNSOutbound(l.outbounds[0]) {
+			l.report(SeverityError, "dns-loop", location, "queries to ", dest.Address, " are sent to the default outbound, which is a DNS outbound sending them back to the DNS client")
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package lint
+
+import (
+	"fmt"
+
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/proxy/dokodemo"
+	"github.com/v2fly/v2ray-core/v5/proxy/shadowsocks"
+	"github.com/v2fly/v2ray-core/v5/proxy/socks"
+)
+
+// inboundNetworks returns the networks of the sockets which the inbound listens on.
+// Proxies handle TCP connections through the transport, and UDP packets directly.
+func inboundNetworks(in *inbound) []net.Network {
+	transport := net.Network_TCP
+	switch in.receiver.GetStreamSettings().GetProtocolName() {
+	case "mkcp", "quic":
+		transport = net.Network_UDP
+	}
+
+	var proxyNetworks []net.Network
+	switch config := in.proxy.(type) {
+	case *dokodemo.Config:
+		proxyNetworks = config.Networks
+		if len(proxyNetworks) == 0 {
+			proxyNetworks = config.NetworkList.GetNetwork()
+		}
+	case *shadowsocks.ServerConfig:
+		proxyNetworks = config.Network
+		if len(proxyNetworks) == 0 {
+			proxyNetworks = []net.Network{net.Network_TCP}
+		}
+		if config.UdpEnabled {
+			proxyNetworks = append(proxyNetworks, net.Network_UDP)
+		}
+	case *socks.ServerConfig:
+		proxyNetworks = []net.Network{net.Network_TCP}
+		if config.UdpEnabled {
+			proxyNetworks = append(proxyNetworks, net.Network_UDP)
+		}
+	default:
+		proxyNetworks = []net.Network{net.Network_TCP}
+	}
+
+	var networks []net.Network
+	for _, network := range proxyNetworks {
+		if network == net.Network_TCP {
+			network = transport
+		}
+		if !net.HasNetwork(networks, network) {
+			networks = append(networks, network)
+		}
+	}
+	return networks
+}
+
+// listenConflicts returns whether sockets bound to both addresses conflict. Unspecified IPv6 addresses are dual-stack.
+func listenConflicts(a, b net.Address) bool {
+	if a == nil {
+		a = net.AnyIP
+	}
+	if b == nil {
+		b = net.AnyIP
+	}
+	if a.Family().IsDomain() || b.Family().IsDomain() {
+		// Domain sockets are listened on paths.
+		return a.Family().IsDomain() && b.Family().IsDomain() && a.Domain() == b.Domain()
+	}
+	if a.IP().Equal(b.IP()) {
+		return true
+	}
+	for _, pair := range [][2]net.Address{{a, b}, {b, a}} {
+		if pair[0].IP().IsUnspecified() && (pair[0].Family().IsIPv6() || pair[1].Family().IsIPv4()) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkPortCollisions(l *linter) {
+	for j, b := range l.inbounds {
+		for i := 0; i < j; i++ {
+			a := l.inbounds[i]
+			ra, rb := a.receiver.GetPortRange(), b.receiver.GetPortRange()
+			// Port 0 is picked by the system.
+			if ra.GetFrom() == 0 || rb.GetFrom() == 0 || ra.To < rb.From || rb.To < ra.From {
+				continue
+			}
+			if !listenConflicts(a.receiver.GetListen().AsAddress(), b.receiver.GetListen().AsAddress()) {
+				continue
+			}
+			for _, network := range inboundNetworks(a) {
+				if net.HasNetwork(inboundNetworks(b), network) {
+					l.report(SeverityError, "port-collision", inboundLocation(j, b),
+						fmt.Sprintf("%s port %s overlaps with %s", network, portRangeString(rb), inboundLocation(i, a)))
+					break
+				}
+			}
+		}
+	}
+}
+
+func portRangeString(r *net.PortRange) string {
+	if r.From == r.To {
+		return fmt.Sprint(r.From)
+	}
+	return fmt.Sprint(r.From, "-", r.To)
+}
@@ -0,0 +1,297 @@
+// Package lint reports semantic problems of a built V2Ray config, which is valid but may not work as intended.
+package lint
+
+//go:generate go run github.com/v2fly/v2ray-core/v5/common/errors/errorgen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	core "github.com/v2fly/v2ray-core/v5"
+	"github.com/v2fly/v2ray-core/v5/app/commander"
+	"github.com/v2fly/v2ray-core/v5/app/dns"
+	"github.com/v2fly/v2ray-core/v5/app/proxyman"
+	"github.com/v2fly/v2ray-core/v5/app/reverse"
+	"github.com/v2fly/v2ray-core/v5/app/router"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/serial"
+)
+
+// Severity is the severity of an issue.
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+var severityNames = []string{"info", "warning", "error"}
+
+func (s Severity) String() string {
+	if s < 0 || int(s) >= len(severityNames) {
+		return fmt.Sprintf("severity(%d)", int(s))
+	}
+	return severityNames[s]
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// ParseSeverity parses the name of a severity.
+func ParseSeverity(name string) (Severity, error) {
+	for i, n := range severityNames {
+		if strings.EqualFold(n, name) {
+			return Severity(i), nil
+		}
+	}
+	return 0, newError("unknown severity: ", name)
+}
+
+// Issue is a problem found in a config.
+type Issue struct {
+	Severity Severity `json:"severity"`
+	// Check is the name of the check which finds the issue.
+	Check string `json:"check"`
+	// Location is the part of the config where the issue is found, such as `outbound "proxy"` or `rule #3`.
+	Location string `json:"location"`
+	Message  string `json:"message"`
+}
+
+func (i *Issue) String() string {
+	return fmt.Sprintf("[%s] %s: %s (%s)", i.Severity, i.Location, i.Message, i.Check)
+}
+
+// MaxSeverity returns the highest severity of the issues, or -1 if there is no issue.
+func MaxSeverity(issues []*Issue) Severity {
+	max := Severity(-1)
+	for _, issue := range issues {
+		if issue.Severity > max {
+			max = issue.Severity
+		}
+	}
+	return max
+}
+
+// routingRule is implemented by both router.RoutingRule and router.SimplifiedRoutingRule.
+type routingRule interface {
+	proto.Message
+	GetTag() string
+	GetBalancingTag() string
+	GetInboundTag() []string
+}
+
+// nameServer is implemented by both dns.NameServer and dns.SimplifiedNameServer.
+type nameServer interface {
+	GetAddress() *net.Endpoint
+	GetTag() string
+}
+
+type inbound struct {
+	tag      string
+	receiver *proxyman.ReceiverConfig
+	proxy    proto.Message
+}
+
+type outbound struct {
+	tag    string
+	sender *proxyman.SenderConfig
+	proxy  proto.Message
+}
+
+type linter struct {
+	issues []*Issue
+
+	inbounds  []*inbound
+	outbounds []*outbound
+
+	rules     []routingRule
+	balancers []*router.BalancingRule
+
+	dnsTag      string
+	nameServers []nameServer
+
+	// Tags of the handlers added by apps rather than the inbound and outbound configs.
+	appInboundTags  map[string]bool
+	appOutboundTags map[string]bool
+}
+
+type check func(l *linter)
+
+var checks = []check{
+	checkTags,
+	checkShadowedRules,
+	checkUnreachableOutbounds,
+	checkPortCollisions,
+	checkTLS,
+	checkCiphers,
+	checkDNSLoops,
+}
+
+// Lint inspects the config and returns the issues found, sorted by severity from high to low.
+// Settings of types which are not linked into the binary are skipped.
+func Lint(config *core.Config) []*Issue {
+	l := newLinter(config)
+	for _, c := range checks {
+		c(l)
+	}
+	sort.SliceStable(l.issues, func(i, j int) bool {
+		return l.issues[i].Severity > l.issues[j].Severity
+	})
+	return l.issues
+}
+
+// instanceOf returns the message in the settings, or nil if the settings are empty or of an unknown type.
+func instanceOf(settings *anypb.Any) proto.Message {
+	if settings == nil {
+		return nil
+	}
+	msg, err := serial.GetInstanceOf(settings)
+	if err != nil {
+		return nil
+	}
+	m, _ := msg.(proto.Message)
+	return m
+}
+
+func newLinter(config *core.Config) *linter {
+	l := &linter{
+		appInboundTags:  make(map[string]bool),
+		appOutboundTags: make(map[string]bool),
+	}
+	for _, in := range config.Inbound {
+		i := &inbound{tag: in.Tag}
+		i.receiver, _ = instanceOf(in.ReceiverSettings).(*proxyman.ReceiverConfig)
+		i.proxy = instanceOf(in.ProxySettings)
+		l.inbounds = append(l.inbounds, i)
+	}
+	for _, out := range config.Outbound {
+		o := &outbound{tag: out.Tag}
+		o.sender, _ = instanceOf(out.SenderSettings).(*proxyman.SenderConfig)
+		o.proxy = instanceOf(out.ProxySettings)
+		l.outbounds = append(l.outbounds, o)
+	}
+	for _, app := range config.App {
+		switch app := instanceOf(app).(type) {
+		case *router.Config:
+			for _, rule := range app.Rule {
+				l.rules = append(l.rules, rule)
+			}
+			l.balancers = app.BalancingRule
+		case *router.SimplifiedConfig:
+			for _, rule := range app.Rule {
+				l.rules = append(l.rules, rule)
+			}
+			l.balancers = app.BalancingRule
+		case *dns.Config:
+			l.dnsTag = app.Tag
+			for _, ns := range app.NameServer {
+				l.nameServers = append(l.nameServers, ns)
+			}
+			if app.Tag != "" {
+				l.appInboundTags[app.Tag] = true
+			}
+		case *dns.SimplifiedConfig:
+			l.dnsTag = app.Tag
+			for _, ns := range app.NameServer {
+				l.nameServers = append(l.nameServers, ns)
+			}
+			if app.Tag != "" {
+				l.appInboundTags[app.Tag] = true
+			}
+		case *commander.Config:
+			l.appOutboundTags[app.Tag] = true
+		case *reverse.Config:
+			for _, bridge := range app.BridgeConfig {
+				l.appInboundTags[bridge.Tag] = true
+			}
+			for _, portal := range app.PortalConfig {
+				l.appOutboundTags[portal.Tag] = true
+			}
+		}
+	}
+	for _, ns := range l.nameServers {
+		if tag := ns.GetTag(); tag != "" {
+			l.appInboundTags[tag] = true
+		}
+	}
+	return l
+}
+
+func (l *linter) report(severity Severity, check string, location string, message ...interface{}) {
+	l.issues = append(l.issues, &Issue{
+		Severity: severity,
+		Check:    check,
+		Location: location,
+		Message:  fmt.Sprint(message...),
+	})
+}
+
+func (l *linter) hasInbound(tag string) bool {
+	if l.appInboundTags[tag] {
+		return true
+	}
+	for _, in := range l.inbounds {
+		if in.tag == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *linter) outbound(tag string) *outbound {
+	if tag == "" {
+		return nil
+	}
+	for _, out := range l.outbounds {
+		if out.tag == tag {
+			return out
+		}
+	}
+	return nil
+}
+
+func (l *linter) hasOutbound(tag string) bool {
+	return l.appOutboundTags[tag] || l.outbound(tag) != nil
+}
+
+func (l *linter) balancer(tag string) *router.BalancingRule {
+	for _, b := range l.balancers {
+		if b.Tag == tag {
+			return b
+		}
+	}
+	return nil
+}
+
+func inboundLocation(idx int, in *inbound) string {
+	if in.tag != "" {
+		return fmt.Sprintf("inbound %q", in.tag)
+	}
+	return fmt.Sprintf("inbound #%d", idx)
+}
+
+func outboundLocation(idx int, out *outbound) string {
+	if out.tag != "" {
+		return fmt.Sprintf("outbound %q", out.tag)
+	}
+	return fmt.Sprintf("outbound #%d", idx)
+}
+
+func ruleLocation(idx int) string {
+	return fmt.Sprintf("rule #%d", idx)
+}
+
+func balancerLocation(b *router.BalancingRule) string {
+	return fmt.Sprintf("balancer %q", b.Tag)
+}
+
+func quote(s string) string {
+	return fmt.Sprintf("%q", s)
+}
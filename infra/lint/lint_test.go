@@ -0,0 +1,161 @@
+package lint_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/serial"
+	"github.com/v2fly/v2ray-core/v5/infra/lint"
+	_ "github.com/v2fly/v2ray-core/v5/main/distro/all"
+)
+
+func lintJSON(t *testing.T, s string) []*lint.Issue {
+	config, err := serial.DecodeJSONConfig(strings.NewReader(s))
+	common.Must(err)
+	pbConfig, err := config.Build()
+	common.Must(err)
+	return lint.Lint(pbConfig)
+}
+
+func expectIssues(t *testing.T, issues []*lint.Issue, expected ...string) {
+	t.Helper()
+	var actual []string
+	for _, issue := range issues {
+		actual = append(actual, issue.Severity.String()+" "+issue.Check+" "+issue.Location)
+	}
+	if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected issues:\n%s\nbut got:\n%s", strings.Join(expected, "\n"), strings.Join(actual, "\n"))
+	}
+}
+
+func TestLintClean(t *testing.T) {
+	issues := lintJSON(t, `{
+		"inbounds": [
+			{"tag": "socks", "port": 1080, "protocol": "socks", "settings": {"udp": true}},
+			{"tag": "api", "port": 10085, "listen": "127.0.0.1", "protocol": "dokodemo-door", "settings": {"address": "127.0.0.1"}}
+		],
+		"outbounds": [
+			{"tag": "direct", "protocol": "freedom"},
+			{"tag": "proxy-a", "protocol": "freedom"},
+			{"tag": "proxy-b", "protocol": "freedom", "proxySettings": {"tag": "chain"}},
+			{"tag": "chain", "protocol": "freedom"}
+		],
+		"api": {"tag": "api", "services": ["StatsService"]},
+		"routing": {
+			"rules": [
+				{"type": "field", "inboundTag": ["api"], "outboundTag": "api"},
+				{"type": "field", "inboundTag": ["socks"], "domain": ["example.com"], "balancerTag": "balancer"}
+			],
+			"balancers": [{"tag": "balancer", "selector": ["proxy-"]}]
+		}
+	}`)
+	expectIssues(t, issues)
+}
+
+func TestLintPortCollision(t *testing.T) {
+	issues := lintJSON(t, `{
+		"inbounds": [
+			{"tag": "socks", "port": 1080, "protocol": "socks", "settings": {"udp": true}},
+			{"tag": "kcp", "port": 1080, "protocol": "vmess", "settings": {"clients": []}, "streamSettings": {"network": "kcp"}},
+			{"tag": "tcp", "port": "2000-2010", "listen": "127.0.0.1", "protocol": "http"},
+			{"tag": "udp", "port": 2005, "protocol": "dokodemo-door", "settings": {"address": "1.1.1.1", "network": "udp"}},
+			{"tag": "any", "port": 2010, "listen": "0.0.0.0", "protocol": "http"},
+			{"tag": "ipv6", "port": 2010, "listen": "::1", "protocol": "http"}
+		],
+		"outbounds": [{"protocol": "freedom"}]
+	}`)
+	// mKCP runs over UDP. IPv4 unspecified addresses do not collide with IPv6 ones.
+	expectIssues(t, issues,
+		"error port-collision inbound \"kcp\"",
+		"error port-collision inbound \"any\"",
+	)
+}
+
+func TestLintRouting(t *testing.T) {
+	issues := lintJSON(t, `{
+		"inbounds": [{"tag": "in", "port": 1080, "protocol": "socks"}],
+		"outbounds": [
+			{"tag": "direct", "protocol": "freedom"},
+			{"tag": "block", "protocol": "blackhole"},
+			{"tag": "unused", "protocol": "freedom"}
+		],
+		"routing": {
+			"rules": [
+				{"type": "field", "inboundTag": ["in"], "port": "1-100,101-65535", "outboundTag": "block"},
+				{"type": "field", "inboundTag": ["in"], "domain": ["example.com"], "outboundTag": "direct"},
+				{"type": "field", "inboundTag": ["other"], "outboundTag": "missing"},
+				{"type": "field", "network": "tcp", "balancerTag": "missing"}
+			]
+		}
+	}`)
+	expectIssues(t, issues,
+		"error unknown-tag rule #2",
+		"error unknown-tag rule #3",
+		"warning unknown-tag rule #2",
+		"warning shadowed-rule rule #1",
+		"warning unreachable-outbound outbound \"unused\"",
+	)
+}
+
+func TestLintSecurity(t *testing.T) {
+	issues := lintJSON(t, `{
+		"inbounds": [
+			{"port": 8388, "protocol": "shadowsocks", "settings": {"method": "none", "password": "password"}},
+			{"port": 10086, "protocol": "vmess", "settings": {"clients": [{"id": "b831381d-6324-4d53-ad4f-8cda48b30811", "alterId": 0}]}}
+		],
+		"outbounds": [
+			{"tag": "direct", "protocol": "freedom"},
+			{"tag": "proxy", "protocol": "vmess",
+				"settings": {"vnext": [{"address": "example.com", "port": 443, "users": [{"id": "b831381d-6324-4d53-ad4f-8cda48b30811", "security": "none"}]}]},
+				"streamSettings": {"security": "tls", "tlsSettings": {"allowInsecure": true}}},
+			{"tag": "ip", "protocol": "trojan",
+				"settings": {"servers": [{"address": "1.2.3.4", "port": 443, "password": "password"}]},
+				"streamSettings": {"security": "tls"}}
+		],
+		"routing": {"rules": [
+			{"type": "field", "domain": ["example.com"], "outboundTag": "proxy"},
+			{"type": "field", "domain": ["example.org"], "outboundTag": "ip"}
+		]}
+	}`)
+	expectIssues(t, issues,
+		"warning insecure-tls outbound \"proxy\"",
+		"warning insecure-tls outbound \"ip\"",
+		"warning weak-cipher inbound #0",
+		"warning weak-cipher outbound \"proxy\"",
+	)
+}
+
+func TestLintDNSLoop(t *testing.T) {
+	issues := lintJSON(t, `{
+		"dns": {"tag": "dns", "servers": ["8.8.8.8", "localhost", {"address": "1.1.1.1", "tag": "dns-direct"}]},
+		"inbounds": [{"tag": "in", "port": 53, "protocol": "dokodemo-door", "settings": {"address": "8.8.8.8", "port": 53, "network": "udp"}}],
+		"outbounds": [
+			{"tag": "dns-out", "protocol": "dns"},
+			{"tag": "direct", "protocol": "freedom"}
+		],
+		"routing": {"rules": [
+			{"type": "field", "inboundTag": ["dns-direct"], "outboundTag": "direct"},
+			{"type": "field", "inboundTag": ["in"], "outboundTag": "dns-out"},
+			{"type": "field", "port": 53, "outboundTag": "dns-out"}
+		]}
+	}`)
+	expectIssues(t, issues,
+		"error dns-loop name server #0",
+		"warning dns-loop name server #0",
+	)
+}
+
+func TestSeverity(t *testing.T) {
+	severity, err := lint.ParseSeverity("Warning")
+	common.Must(err)
+	if severity != lint.SeverityWarning {
+		t.Error("expected warning, but got ", severity)
+	}
+	if _, err := lint.ParseSeverity("fatal"); err == nil {
+		t.Error("expected error for unknown severity")
+	}
+	if lint.MaxSeverity(nil) >= lint.SeverityInfo {
+		t.Error("expected no severity for no issues")
+	}
+}
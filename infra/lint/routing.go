@@ -0,0 +1,232 @@
+package lint
+
+import (
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/proxy/loopback"
+)
+
+// ruleCondition is the condition of a routing rule, reduced to what is needed to tell whether it always matches.
+type ruleCondition struct {
+	inboundTags []string
+	// restricted is whether the rule has conditions other than inbound tags which may not match all traffic.
+	restricted bool
+}
+
+func newRuleCondition(rule routingRule) ruleCondition {
+	c := ruleCondition{inboundTags: rule.GetInboundTag()}
+	rule.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch fd.Name() {
+		case "tag", "balancing_tag", "rule_tag", "domain_matcher", "inbound_tag":
+		case "networks", "network_list":
+			if !matchesAllNetworks(fd, v) {
+				c.restricted = true
+			}
+		case "port_list":
+			if list, ok := v.Message().Interface().(*net.PortList); !ok || !matchesAllPorts(list.Range) {
+				c.restricted = true
+			}
+		case "port_range":
+			if r, ok := v.Message().Interface().(*net.PortRange); !ok || !matchesAllPorts([]*net.PortRange{r}) {
+				c.restricted = true
+			}
+		default:
+			c.restricted = true
+		}
+		return true
+	})
+	return c
+}
+
+// matchesInbound returns whether the condition may match traffic from the inbound.
+func (c ruleCondition) matchesInbound(tag string) bool {
+	if len(c.inboundTags) == 0 {
+		return true
+	}
+	for _, t := range c.inboundTags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// shadows returns whether all traffic matched by the other condition is matched by this one.
+func (c ruleCondition) shadows(other ruleCondition) bool {
+	if c.restricted {
+		return false
+	}
+	if len(c.inboundTags) == 0 {
+		return true
+	}
+	if len(other.inboundTags) == 0 {
+		return false
+	}
+	for _, tag := range other.inboundTags {
+		if !c.matchesInbound(tag) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesAllNetworks(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+	var networks []net.Network
+	if fd.IsList() {
+		list := v.List()
+		for i := 0; i < list.Len(); i++ {
+			networks = append(networks, net.Network(list.Get(i).Enum()))
+		}
+	} else if list, ok := v.Message().Interface().(*net.NetworkList); ok {
+		networks = list.Network
+	}
+	return net.HasNetwork(networks, net.Network_TCP) && net.HasNetwork(networks, net.Network_UDP)
+}
+
+func matchesAllPorts(ranges []*net.PortRange) bool {
+	sorted := append([]*net.PortRange(nil), ranges...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].From < sorted[j].From })
+	next := uint32(1)
+	for _, r := range sorted {
+		if r.From > next {
+			return false
+		}
+		if r.To+1 > next {
+			next = r.To + 1
+		}
+	}
+	return next > 65535
+}
+
+func (l *linter) conditions() []ruleCondition {
+	conditions := make([]ruleCondition, len(l.rules))
+	for i, rule := range l.rules {
+		conditions[i] = newRuleCondition(rule)
+	}
+	return conditions
+}
+
+// selectOutbounds returns the outbounds selected by a balancer. Selectors match tags by prefix.
+func (l *linter) selectOutbounds(selectors []string) []*outbound {
+	var selected []*outbound
+	for _, out := range l.outbounds {
+		for _, selector := range selectors {
+			if out.tag != "" && strings.HasPrefix(out.tag, selector) {
+				selected = append(selected, out)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+// ruleTargets returns the outbounds which the rule may send traffic to.
+func (l *linter) ruleTargets(rule routingRule) []*outbound {
+	if tag := rule.GetTag(); tag != "" {
+		if out := l.outbound(tag); out != nil {
+			return []*outbound{out}
+		}
+		return nil
+	}
+	b := l.balancer(rule.GetBalancingTag())
+	if b == nil {
+		return nil
+	}
+	targets := l.selectOutbounds(b.OutboundSelector)
+	if out := l.outbound(b.FallbackTag); out != nil {
+		targets = append(targets, out)
+	}
+	return targets
+}
+
+func checkTags(l *linter) {
+	for i, rule := range l.rules {
+		if tag := rule.GetTag(); tag != "" && !l.hasOutbound(tag) {
+			l.report(SeverityError, "unknown-tag", ruleLocation(i), "outbound ", quote(tag), " does not exist")
+		}
+		if tag := rule.GetBalancingTag(); tag != "" && l.balancer(tag) == nil {
+			l.report(SeverityError, "unknown-tag", ruleLocation(i), "balancer ", quote(tag), " does not exist")
+		}
+		for _, tag := range rule.GetInboundTag() {
+			if !l.hasInbound(tag) {
+				l.report(SeverityWarning, "unknown-tag", ruleLocation(i), "inbound ", quote(tag), " does not exist")
+			}
+		}
+	}
+	for _, b := range l.balancers {
+		for _, selector := range b.OutboundSelector {
+			if len(l.selectOutbounds([]string{selector})) == 0 {
+				l.report(SeverityError, "unknown-tag", balancerLocation(b), "no outbound tag starts with ", quote(selector))
+			}
+		}
+		if b.FallbackTag != "" && !l.hasOutbound(b.FallbackTag) {
+			l.report(SeverityError, "unknown-tag", balancerLocation(b), "fallback outbound ", quote(b.FallbackTag), " does not exist")
+		}
+	}
+	for i, out := range l.outbounds {
+		if tag := out.sender.GetProxySettings().GetTag(); tag != "" {
+			switch {
+			case tag == out.tag:
+				l.report(SeverityError, "unknown-tag", outboundLocation(i, out), "outbound proxies through itself")
+			case !l.hasOutbound(tag):
+				l.report(SeverityError, "unknown-tag", outboundLocation(i, out), "proxy outbound ", quote(tag), " does not exist")
+			}
+		}
+		if config, ok := out.proxy.(*loopback.Config); ok && !l.hasInbound(config.InboundTag) {
+			l.report(SeverityError, "unknown-tag", outboundLocation(i, out), "loopback inbound ", quote(config.InboundTag), " does not exist")
+		}
+	}
+}
+
+func checkShadowedRules(l *linter) {
+	conditions := l.conditions()
+	for j := range conditions {
+		for i := 0; i < j; i++ {
+			if conditions[i].shadows(conditions[j]) {
+				l.report(SeverityWarning, "shadowed-rule", ruleLocation(j), "rule is never matched, because ", ruleLocation(i), " matches all its traffic")
+				break
+			}
+		}
+	}
+}
+
+func checkUnreachableOutbounds(l *linter) {
+	reachable := make(map[*outbound]bool)
+	if len(l.outbounds) > 0 {
+		// The first outbound is the default one.
+		reachable[l.outbounds[0]] = true
+	}
+	usedBalancers := make(map[string]bool)
+	for _, rule := range l.rules {
+		if tag := rule.GetBalancingTag(); tag != "" {
+			usedBalancers[tag] = true
+		}
+		for _, out := range l.ruleTargets(rule) {
+			reachable[out] = true
+		}
+	}
+	for _, b := range l.balancers {
+		if !usedBalancers[b.Tag] {
+			l.report(SeverityInfo, "unused-balancer", balancerLocation(b), "no routing rule uses the balancer")
+		}
+	}
+	// Outbounds chained with proxy settings are reachable through the outbounds using them.
+	for changed := true; changed; {
+		changed = false
+		for out := range reachable {
+			if next := l.outbound(out.sender.GetProxySettings().GetTag()); next != nil && !reachable[next] {
+				reachable[next] = true
+				changed = true
+			}
+		}
+	}
+	for i, out := range l.outbounds {
+		if !reachable[out] {
+			l.report(SeverityWarning, "unreachable-outbound", outboundLocation(i, out), "no routing rule, balancer or outbound sends traffic to the outbound")
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package lint
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/v2fly/v2ray-core/v5/common/protocol"
+	"github.com/v2fly/v2ray-core/v5/proxy/shadowsocks"
+	"github.com/v2fly/v2ray-core/v5/proxy/vmess"
+	"github.com/v2fly/v2ray-core/v5/transport/internet"
+	"github.com/v2fly/v2ray-core/v5/transport/internet/tls"
+	"github.com/v2fly/v2ray-core/v5/transport/internet/tls/utls"
+)
+
+// tlsConfig returns the TLS settings of the stream, or nil if TLS is not used.
+func tlsConfig(stream *internet.StreamConfig) *tls.Config {
+	for _, settings := range stream.GetSecuritySettings() {
+		switch config := instanceOf(settings).(type) {
+		case *tls.Config:
+			return config
+		case *utls.Config:
+			return config.TlsConfig
+		}
+	}
+	return nil
+}
+
+// walkMessages calls fn with the message and all messages nested in it. Messages nested in the one which fn returns
+// true for are skipped.
+func walkMessages(msg proto.Message, fn func(proto.Message) bool) {
+	if msg == nil || fn(msg) {
+		return
+	}
+	msg.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Message() == nil || fd.IsMap() {
+			return true
+		}
+		if fd.IsList() {
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				walkMessages(list.Get(i).Message().Interface(), fn)
+			}
+			return true
+		}
+		walkMessages(v.Message().Interface(), fn)
+		return true
+	})
+}
+
+func checkTLS(l *linter) {
+	for i, out := range l.outbounds {
+		config := tlsConfig(out.sender.GetStreamSettings())
+		if config == nil {
+			continue
+		}
+		if config.AllowInsecure && len(config.PinnedPeerCertificateChainSha256) == 0 {
+			l.report(SeverityWarning, "insecure-tls", outboundLocation(i, out), "certificate verification is disabled by allowInsecure")
+		}
+		if config.ServerName != "" {
+			continue
+		}
+		// The server name is taken from the server address if it is a domain.
+		walkMessages(out.proxy, func(msg proto.Message) bool {
+			server, ok := msg.(*protocol.ServerEndpoint)
+			if !ok {
+				return false
+			}
+			if address := server.Address.AsAddress(); address != nil && address.Family().IsIP() {
+				l.report(SeverityWarning, "insecure-tls", outboundLocation(i, out),
+					"no server name is set for server ", address, ", so the certificate cannot be verified")
+			}
+			return true
+		})
+	}
+}
+
+// checkAccount reports weak ciphers in the account of the user.
+func (l *linter) checkAccount(location string, user *protocol.User, isInbound bool) {
+	name := user.Email
+	if name == "" {
+		name = "user"
+	} else {
+		name = "user " + quote(name)
+	}
+	switch account := instanceOf(user.Account).(type) {
+	case *shadowsocks.Account:
+		if account.CipherType == shadowsocks.CipherType_NONE {
+			l.report(SeverityWarning, "weak-cipher", location, name, " uses Shadowsocks without encryption")
+		}
+	case *vmess.Account:
+		if account.AlterId > 0 {
+			l.report(SeverityWarning, "weak-cipher", location, name, " uses legacy VMess MD5 authentication (alterId > 0)")
+		}
+		// Inbounds accept the security chosen by clients.
+		if isInbound {
+			break
+		}
+		switch account.SecuritySettings.GetType() {
+		case protocol.SecurityType_NONE, protocol.SecurityType_ZERO:
+			l.report(SeverityWarning, "weak-cipher", location, name, " uses VMess without encryption")
+		}
+	}
+}
+
+func checkCiphers(l *linter) {
+	for i, in := range l.inbounds {
+		walkMessages(in.proxy, func(msg proto.Message) bool {
+			if user, ok := msg.(*protocol.User); ok {
+				l.checkAccount(inboundLocation(i, in), user, true)
+				return true
+			}
+			return false
+		})
+	}
+	for i, out := range l.outbounds {
+		walkMessages(out.proxy, func(msg proto.Message) bool {
+			if user, ok := msg.(*protocol.User); ok {
+				l.checkAccount(outboundLocation(i, out), user, false)
+				return true
+			}
+			return false
+		})
+	}
+}
@@ -9,6 +9,7 @@ var cmdEngineering = &base.Command{
 	Commands: []*base.Command{
 		cmdConvertPb,
 		cmdReversePb,
+		cmdSchema,
 	},
 }
 
@@ -0,0 +1,50 @@
+package engineering
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/v2fly/v2ray-core/v5/infra/conf/jsonschema"
+	v4 "github.com/v2fly/v2ray-core/v5/infra/conf/v4"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/v5cfg"
+	"github.com/v2fly/v2ray-core/v5/main/commands/base"
+)
+
+var cmdSchema = &base.Command{
+	UsageLine:   "{{.Exec}} engineering schema [-format v5]",
+	Short:       "print JSON Schema of config formats",
+	CustomFlags: true,
+	Long: `
+Print the JSON Schema (draft-07) of a JSON config format, for editors
+and CI to validate configs. The schema of the v5 format is generated
+from the protocols and services built into this binary.
+
+Arguments:
+
+	-format <format>
+		The config format, "v4" or "v5". (default "v5")
+
+Examples:
+
+	{{.Exec}} {{.LongName}} -format v4 > v4.schema.json
+	`,
+	Run: func(cmd *base.Command, args []string) {
+		format := cmd.Flag.String("format", "v5", "")
+		cmd.Flag.Parse(args)
+
+		var schema *jsonschema.Schema
+		switch *format {
+		case "v4", "jsonv4":
+			schema = v4.JSONSchema()
+		case "v5", "jsonv5":
+			schema = v5cfg.JSONSchema()
+		default:
+			base.Fatalf("unknown config format: %s", *format)
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(schema); err != nil {
+			base.Fatalf("%s", newError("failed to encode schema").Base(err))
+		}
+	},
+}
@@ -24,6 +24,18 @@ config formats:
 	* yaml (.yml, .yaml)
 	  The yaml loader, multiple files support, mergeable.
 
+	* starlark (.star)
+	  The starlark loader, multiple files support, mergeable. A 
+	  Starlark script assigns the config to the global variable 
+	  "config", and may generate it with loops and functions:
+
+		load("lib.star", "make_inbound")
+		customers = json.decode(read_file("customers.json"))
+		config = {"inbounds": [make_inbound(c) for c in customers]}
+
+	  Paths of load() and read_file() are relative to the script.
+	  Modules without a config should not be placed in config dirs.
+
 	* protobuf / pb (.pb)
 	  Single file support, unmergeable.
 
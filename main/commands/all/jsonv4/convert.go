@@ -11,6 +11,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	core "github.com/v2fly/v2ray-core/v5"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/interpolate"
 	"github.com/v2fly/v2ray-core/v5/infra/conf/jsonpb"
 	"github.com/v2fly/v2ray-core/v5/infra/conf/merge"
 	"github.com/v2fly/v2ray-core/v5/infra/conf/v2jsonpb"
@@ -30,7 +31,7 @@ Arguments:
 
 	-i, -input <format>
 		The input format.
-		Available values: "auto", "json", "toml", "yaml"
+		Available values: "auto", "json", "toml", "yaml", "starlark"
 		Default: "auto"
 
 	-o, -output <format>
@@ -41,6 +42,11 @@ Arguments:
 	-r
 		Load folders recursively.
 
+	-redact
+		Show references to environment variables and files, like
+		"${env:NAME}" and "${file:/run/secrets/x}", as "<redacted>"
+		instead of their values. Not applicable to protobuf outputs.
+
 Examples:
 
 	{{.Exec}} {{.LongName}} -output=protobuf "path/to/dir"   (1)
@@ -65,6 +71,7 @@ var (
 	inputFormat        string
 	outputFormat       string
 	confDirRecursively bool
+	redact             bool
 )
 
 func setConfArgs(cmd *base.Command) {
@@ -73,6 +80,7 @@ func setConfArgs(cmd *base.Command) {
 	cmd.Flag.StringVar(&outputFormat, "output", "json", "")
 	cmd.Flag.StringVar(&outputFormat, "o", "json", "")
 	cmd.Flag.BoolVar(&confDirRecursively, "r", false, "")
+	cmd.Flag.BoolVar(&redact, "redact", false, "")
 }
 
 func executeConvert(cmd *base.Command, args []string) {
@@ -80,6 +88,14 @@ func executeConvert(cmd *base.Command, args []string) {
 	cmd.Flag.Parse(args)
 	inputFormat = strings.ToLower(inputFormat)
 	outputFormat = strings.ToLower(outputFormat)
+	if redact {
+		switch outputFormat {
+		case core.FormatJSON, core.FormatTOML, core.FormatYAML:
+		default:
+			base.Fatalf("-redact is not applicable to output format: %s", outputFormat)
+		}
+		interpolate.Default.Redact = true
+	}
 
 	inputFormatMerge := inputFormat
 	if inputFormat == "jsonv5" {
@@ -112,12 +128,7 @@ func executeConvert(cmd *base.Command, args []string) {
 			base.Fatalf("failed to convert to yaml: %s", err)
 		}
 	case core.FormatProtobuf, core.FormatProtobufShort:
-		data, err := json.Marshal(m)
-		if err != nil {
-			base.Fatalf("failed to marshal json: %s", err)
-		}
-		r := bytes.NewReader(data)
-		pbConfig, err := core.LoadConfig(inputFormat, r)
+		pbConfig, err := loadMergedConfig(inputFormat, m)
 		if err != nil {
 			base.Fatalf(err.Error())
 		}
@@ -126,12 +137,7 @@ func executeConvert(cmd *base.Command, args []string) {
 			base.Fatalf("failed to convert to protobuf: %s", err)
 		}
 	case jsonpb.FormatProtobufJSONPB:
-		data, err := json.Marshal(m)
-		if err != nil {
-			base.Fatalf("failed to marshal json: %s", err)
-		}
-		r := bytes.NewReader(data)
-		pbConfig, err := core.LoadConfig(inputFormat, r)
+		pbConfig, err := loadMergedConfig(inputFormat, m)
 		if err != nil {
 			base.Fatalf(err.Error())
 		}
@@ -142,12 +148,7 @@ func executeConvert(cmd *base.Command, args []string) {
 		}
 		out = w.Bytes()
 	case v2jsonpb.FormatProtobufV2JSONPB:
-		data, err := json.Marshal(m)
-		if err != nil {
-			base.Fatalf("failed to marshal json: %s", err)
-		}
-		r := bytes.NewReader(data)
-		pbConfig, err := core.LoadConfig(inputFormat, r)
+		pbConfig, err := loadMergedConfig(inputFormat, m)
 		if err != nil {
 			base.Fatalf(err.Error())
 		}
@@ -165,3 +166,12 @@ func executeConvert(cmd *base.Command, args []string) {
 		base.Fatalf("failed to write stdout: %s", err)
 	}
 }
+
+// loadMergedConfig builds the config merged from the inputs. References in the merged config have
+// been substituted, so it is loaded as it is, rather than marshaled and interpolated again.
+func loadMergedConfig(inputFormat string, m map[string]interface{}) (*core.Config, error) {
+	if inputFormat == core.FormatAuto {
+		inputFormat = core.FormatJSON
+	}
+	return core.LoadConfig(inputFormat, m)
+}
@@ -0,0 +1,47 @@
+package jsonv4
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+	_ "github.com/v2fly/v2ray-core/v5/infra/conf/geodata/standard"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/merge"
+	_ "github.com/v2fly/v2ray-core/v5/infra/conf/v5cfg"
+	"github.com/v2fly/v2ray-core/v5/main/commands/helpers"
+	_ "github.com/v2fly/v2ray-core/v5/main/formats"
+)
+
+func TestLoadMergedConfig(t *testing.T) {
+	dir := t.TempDir()
+	cases := []struct {
+		format  string
+		file    string
+		content string
+	}{
+		{"json", "config.json", `{"outbounds": [{"tag": "$${env:NOPE}", "protocol": "freedom"}]}`},
+		{"auto", "config.json", `{"outbounds": [{"tag": "$${env:NOPE}", "protocol": "freedom"}]}`},
+		{"jsonv5", "config.v5.json", `{"outbounds": [{"tag": "$${env:NOPE}", "protocol": "freedom"}]}`},
+	}
+	for _, test := range cases {
+		file := filepath.Join(dir, test.file)
+		common.Must(os.WriteFile(file, []byte(test.content), 0o644))
+		mergeFormat := test.format
+		if mergeFormat == "jsonv5" {
+			mergeFormat = "json"
+		}
+		m, err := helpers.LoadConfigToMap([]string{file}, mergeFormat, false)
+		common.Must(err)
+		common.Must(merge.ApplyRules(m))
+
+		// The escaped reference is not interpolated again.
+		config, err := loadMergedConfig(test.format, m)
+		if err != nil {
+			t.Fatal(test.format, ": ", err)
+		}
+		if len(config.Outbound) != 1 || config.Outbound[0].Tag != "${env:NOPE}" {
+			t.Error(test.format, ": unexpected outbounds ", config.Outbound)
+		}
+	}
+}
@@ -68,5 +68,46 @@ Explained:
 - Simple values (string, number, boolean) are overwritten, others are merged
 - Elements with same "tag" (or "_tag") in an array will be merged
 - Add "_priority" property to array elements will help sort the array
+
+A config file may include other files with the top-level "_include"
+property, a path or a list of paths relative to the file, where glob 
+patterns are accepted. Included files are merged in order before the 
+content of the including file, so that the file may override them:
+
+	{
+	  "_include": ["base.yaml", "tenants/*.star"],
+	  "log": {"loglevel": "info"}
+	}
+
+Use "{{.Exec}} convert" to show the config with files included.
+
+Each file is merged once: a file included by several files, or both
+included and loaded with "-c" or "-d", is merged only the first time.
+A glob pattern matching the including file itself skips it. "_include"
+is not supported by the "jsonv5" format, which is not merged.
+
+Before merging, references to environment variables and files in string
+values are substituted, so that secrets need not be written into config
+files:
+
+	{
+	  "id": "${env:VMESS_ID}",
+	  "password": "${file:/run/secrets/trojan_password}",
+	  "loglevel": "${env:LOGLEVEL:-warning}"
+	}
+
+- "${env:NAME}" is the value of the environment variable NAME
+- "${file:PATH}" is the content of the file, without trailing line breaks
+- ":-" gives a default value used if the variable is unset or empty, or
+  the file does not exist; otherwise a missing value is an error
+- "$${" is an escaped "${"
+
+Compatibility: substitution applies to every string value of JSON, TOML,
+YAML and Starlark configs. A config written for earlier versions with a
+literal "${" in a string, such as in a password or a path, now fails to
+load with an error naming the value; write it as "$${" instead.
+
+Use "{{.Exec}} convert -redact" to show the merged config with the
+substituted values redacted.
 `,
 }
@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	core "github.com/v2fly/v2ray-core/v5"
+	"github.com/v2fly/v2ray-core/v5/infra/lint"
+	"github.com/v2fly/v2ray-core/v5/main/commands/base"
+)
+
+// CmdLint reports semantic problems of config files
+var CmdLint = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} lint [-json] [-severity=info] [-format=auto] [-c config.json] [-d dir]",
+	Short:       "report semantic problems of config files",
+	Long: `
+Lint config files, without launching V2Ray server. Besides the checks
+of "{{.Exec}} test", it reports problems of a config that builds but
+may not work as intended:
+
+	- routing rules shadowed by earlier rules matching all their traffic
+	- references to nonexistent inbound, outbound and balancer tags
+	- outbounds which no traffic is routed to
+	- insecure TLS settings, such as allowInsecure or no server name
+	- weak ciphers, such as Shadowsocks "none" and VMess alterId > 0
+	- inbounds listening on colliding ports
+	- DNS queries routed back to the DNS client by DNS outbounds
+
+Config files are found in the same way as "{{.Exec}} test".
+
+Arguments:
+
+	-c, -config <file>
+		Config file for V2Ray. Multiple assign is accepted.
+
+	-d, -confdir <dir>
+		A directory with config files. Multiple assign is accepted.
+
+	-r
+		Load confdir recursively.
+
+	-format <format>
+		Format of config input. (default "auto")
+
+	-json
+		Print the issues as a JSON array.
+
+	-severity <severity>
+		The lowest severity of the issues to report, one of "info",
+		"warning" and "error". (default "info")
+
+Exit status is 0 if no warning or error is found, 1 if the config
+failed to load, 2 if warnings are found, and 3 if errors are found.
+
+Examples:
+
+	{{.Exec}} {{.LongName}} -c config.json
+	{{.Exec}} {{.LongName}} -json -severity=warning -d path/to/dir
+
+Use "{{.Exec}} help format-loader" for more information about format.
+	`,
+	Run: executeLint,
+}
+
+func executeLint(cmd *base.Command, args []string) {
+	setConfigFlags(cmd)
+	jsonOutput := cmd.Flag.Bool("json", false, "")
+	severityName := cmd.Flag.String("severity", "info", "")
+	cmd.Flag.Parse(args)
+
+	minSeverity, err := lint.ParseSeverity(*severityName)
+	if err != nil {
+		base.Fatalf("%s", err)
+	}
+	configFiles = getConfigFilePath()
+	config, err := core.LoadConfig(*configFormat, configFiles)
+	if err != nil {
+		base.Fatalf("failed to load config: %s", err)
+	}
+
+	issues := make([]*lint.Issue, 0)
+	for _, issue := range lint.Lint(config) {
+		if issue.Severity >= minSeverity {
+			issues = append(issues, issue)
+		}
+	}
+
+	if *jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(issues); err != nil {
+			base.Fatalf("failed to encode issues: %s", err)
+		}
+	} else {
+		for _, issue := range issues {
+			fmt.Println(issue)
+		}
+		if len(issues) == 0 {
+			fmt.Println("No issues found.")
+		}
+	}
+
+	switch lint.MaxSeverity(issues) {
+	case lint.SeverityError:
+		base.SetExitStatus(3)
+	case lint.SeverityWarning:
+		base.SetExitStatus(2)
+	}
+	base.Exit()
+}
@@ -20,7 +20,7 @@ import (
 // CmdRun runs V2Ray with config
 var CmdRun = &base.Command{
 	CustomFlags: true,
-	UsageLine:   "{{.Exec}} run [-c config.json] [-d dir]",
+	UsageLine:   "{{.Exec}} run [-c config.json] [-d dir] [-remote url]",
 	Short:       "run V2Ray with config",
 	Long: `
 Run V2Ray with config.
@@ -47,10 +47,39 @@ Arguments:
 	-format <format>
 		Format of config input. (default "auto")
 
+	-remote <url>
+		Load config from an HTTPS URL or a local file, and apply 
+		changes of it at runtime, instead of -c and -d. The config 
+		is delivered with a detached signature at the URL with ".sig" 
+		appended to its path, verified by the public key of 
+		-remote-key. Plain HTTP URLs are refused. When the config 
+		changes, a new instance is built and swapped in, and the 
+		running instance is kept if the config fails to apply. If 
+		neither can be started, V2Ray exits with an error. 
+
+		Remote configs are trusted like local config files: they 
+		may read local files with "_include", "${file:}" references 
+		and Starlark load() and read_file(), with relative paths 
+		resolved against the working directory.
+
+	-remote-key <file>
+		Signify public key to verify signatures of remote configs, 
+		as generated by "signify -G". Required for HTTPS URLs. 
+		Signatures of local files are not verified without a key.
+
+	-remote-interval <duration>
+		Interval of polling the remote config for changes. Local 
+		files are watched for changes where supported. (default 5m)
+
+	-remote-cache <file>
+		Keep the last applied remote config and its signature in the
+		file, to start with when the remote config is unavailable.
+
 Examples:
 
 	{{.Exec}} {{.LongName}} -c config.json
 	{{.Exec}} {{.LongName}} -d path/to/dir
+	{{.Exec}} {{.LongName}} -remote https://example.com/config.json -remote-key key.pub -remote-cache /var/lib/v2ray/config.json
 
 Use "{{.Exec}} help format-loader" for more information about format.
 	`,
@@ -76,8 +105,13 @@ func setConfigFlags(cmd *base.Command) {
 
 func executeRun(cmd *base.Command, args []string) {
 	setConfigFlags(cmd)
+	setRemoteFlags(cmd)
 	cmd.Flag.Parse(args)
 	printVersion()
+	if *remoteLocation != "" {
+		executeRunRemote()
+		return
+	}
 	configFiles = getConfigFilePath()
 	server, err := startV2Ray()
 	if err != nil {
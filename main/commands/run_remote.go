@@ -0,0 +1,175 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"syscall"
+	"time"
+
+	core "github.com/v2fly/v2ray-core/v5"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/remote"
+	"github.com/v2fly/v2ray-core/v5/main/commands/base"
+)
+
+var (
+	remoteLocation *string
+	remoteKey      *string
+	remoteInterval *time.Duration
+	remoteCache    *string
+)
+
+func setRemoteFlags(cmd *base.Command) {
+	remoteLocation = cmd.Flag.String("remote", "", "")
+	remoteKey = cmd.Flag.String("remote-key", "", "")
+	remoteInterval = cmd.Flag.Duration("remote-interval", 5*time.Minute, "")
+	remoteCache = cmd.Flag.String("remote-cache", "", "")
+}
+
+// instanceSwapper runs the instance of the config applied last.
+type instanceSwapper struct {
+	access  sync.Mutex
+	server  core.Server
+	applied *remote.Delivery
+	// lost receives the error if neither the new config nor the running one can be started,
+	// leaving no instance running.
+	lost chan error
+}
+
+func newInstanceSwapper() *instanceSwapper {
+	return &instanceSwapper{lost: make(chan error, 1)}
+}
+
+// remoteConfigFormat returns the format of the remote config, detected by the extension of its location if not specified.
+func remoteConfigFormat() string {
+	if *configFormat != core.FormatAuto {
+		return *configFormat
+	}
+	location := *remoteLocation
+	if u, err := url.Parse(location); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		location = u.Path
+	}
+	return core.GetFormatByExtension(path.Ext(filepath.ToSlash(location)))
+}
+
+func buildInstance(d *remote.Delivery) (core.Server, error) {
+	config, err := core.LoadConfig(remoteConfigFormat(), bytes.NewReader(d.Content))
+	if err != nil {
+		return nil, newError("failed to load config").Base(err)
+	}
+	server, err := core.New(config)
+	if err != nil {
+		return nil, newError("failed to create server").Base(err)
+	}
+	return server, nil
+}
+
+// apply replaces the running instance by the instance of the config. The new instance is built before the
+// running one is closed, and the running config is restored if the new instance fails to start. The
+// error of restoring is sent to lost, as no instance is running then.
+func (s *instanceSwapper) apply(d *remote.Delivery) error {
+	s.access.Lock()
+	defer s.access.Unlock()
+
+	server, err := buildInstance(d)
+	if err != nil {
+		return err
+	}
+	if s.server != nil {
+		// Listeners of the running instance are released for the new one.
+		s.server.Close()
+		s.server = nil
+	}
+	if err := server.Start(); err != nil {
+		server.Close()
+		if s.applied != nil {
+			if errRestore := s.restore(); errRestore != nil {
+				errLost := newError("failed to restore the running config").Base(errRestore)
+				select {
+				case s.lost <- errLost:
+				default:
+				}
+			}
+		}
+		return newError("failed to start server").Base(err)
+	}
+	s.server = server
+	s.applied = d
+
+	// Explicitly triggering GC to remove garbage from config loading.
+	runtime.GC()
+	return nil
+}
+
+// restore starts the instance of the config applied last again.
+func (s *instanceSwapper) restore() error {
+	restored, err := buildInstance(s.applied)
+	if err != nil {
+		return err
+	}
+	if err := restored.Start(); err != nil {
+		restored.Close()
+		return err
+	}
+	s.server = restored
+	return nil
+}
+
+func (s *instanceSwapper) close() {
+	s.access.Lock()
+	defer s.access.Unlock()
+	if s.server != nil {
+		s.server.Close()
+	}
+}
+
+func executeRunRemote() {
+	if len(configFiles) > 0 || len(configDirs) > 0 {
+		base.Fatalf("-remote cannot be used with -c or -d")
+	}
+	var publicKey []byte
+	if *remoteKey != "" {
+		var err error
+		if publicKey, err = os.ReadFile(*remoteKey); err != nil {
+			base.Fatalf("Failed to read public key: %s", err)
+		}
+	}
+	source, err := remote.NewSource(*remoteLocation, publicKey, *remoteInterval, *remoteCache)
+	if err != nil {
+		base.Fatalf("Failed to start: %s", err)
+	}
+	delivery, err := source.Load()
+	if err != nil {
+		base.Fatalf("Failed to start: %s", err)
+	}
+
+	swapper := newInstanceSwapper()
+	if err := swapper.apply(delivery); err != nil {
+		base.Fatalf("Failed to start: %s", err)
+	}
+	defer swapper.close()
+	if err := source.SaveCache(delivery); err != nil {
+		newError("failed to keep the applied config").Base(err).AtWarning().WriteToLog()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go source.Watch(ctx, delivery, swapper.apply)
+
+	osSignals := make(chan os.Signal, 1)
+	signal.Notify(osSignals, os.Interrupt, syscall.SIGTERM)
+	select {
+	case <-osSignals:
+	case err := <-swapper.lost:
+		// base.Fatalf exits without deferred calls, so the watch is stopped first.
+		cancel()
+		swapper.close()
+		base.Fatalf("No instance is running: %s", err)
+	}
+}
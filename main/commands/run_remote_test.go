@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"fmt"
+	"testing"
+
+	_ "github.com/v2fly/v2ray-core/v5/app/dispatcher"
+	_ "github.com/v2fly/v2ray-core/v5/app/proxyman/inbound"
+	_ "github.com/v2fly/v2ray-core/v5/app/proxyman/outbound"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/infra/conf/remote"
+	_ "github.com/v2fly/v2ray-core/v5/main/formats"
+	_ "github.com/v2fly/v2ray-core/v5/proxy/dokodemo"
+	_ "github.com/v2fly/v2ray-core/v5/proxy/freedom"
+	"github.com/v2fly/v2ray-core/v5/testing/servers/tcp"
+)
+
+func remoteConfig(port net.Port) *remote.Delivery {
+	return &remote.Delivery{Content: []byte(fmt.Sprintf(`{
+		"inbounds": [{"listen": "127.0.0.1", "port": %d, "protocol": "dokodemo-door", "settings": {"address": "127.0.0.1", "port": 1, "network": "tcp"}}],
+		"outbounds": [{"protocol": "freedom"}]
+	}`, port))}
+}
+
+func listening(port net.Port) bool {
+	conn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+func TestInstanceSwapper(t *testing.T) {
+	format, location := "json", "config.json"
+	configFormat, remoteLocation = &format, &location
+
+	swapper := newInstanceSwapper()
+	defer swapper.close()
+
+	firstPort, secondPort := tcp.PickPort(), tcp.PickPort()
+	if err := swapper.apply(remoteConfig(firstPort)); err != nil {
+		t.Fatal(err)
+	}
+	second := remoteConfig(secondPort)
+	if err := swapper.apply(second); err != nil {
+		t.Fatal(err)
+	}
+	if swapper.applied != second || listening(firstPort) || !listening(secondPort) {
+		t.Error("expected the second config applied")
+	}
+
+	// Configs failing to build leave the running instance untouched.
+	running := swapper.server
+	if err := swapper.apply(&remote.Delivery{Content: []byte(`{"inbounds": 1}`)}); err == nil {
+		t.Error("expected error of invalid config")
+	}
+	if swapper.server != running || swapper.applied != second {
+		t.Error("expected the running instance kept")
+	}
+
+	// The running config is restored if the new instance fails to start.
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer busy.Close()
+	busyPort := net.Port(busy.Addr().(*net.TCPAddr).Port)
+	if err := swapper.apply(remoteConfig(busyPort)); err == nil {
+		t.Error("expected error of config failing to start")
+	}
+	if swapper.server == nil || swapper.applied != second || !listening(secondPort) {
+		t.Error("expected the running config restored")
+	}
+	select {
+	case err := <-swapper.lost:
+		t.Error("unexpected lost instance: ", err)
+	default:
+	}
+
+	// No instance is running if the running config fails to start again.
+	swapper.applied = &remote.Delivery{Content: []byte(`{"inbounds": 1}`)}
+	if err := swapper.apply(remoteConfig(busyPort)); err == nil {
+		t.Error("expected error of config failing to start")
+	}
+	if swapper.server != nil {
+		t.Error("expected no instance running")
+	}
+	select {
+	case <-swapper.lost:
+	default:
+		t.Error("expected the lost instance reported")
+	}
+}
@@ -17,6 +17,7 @@ import (
 	// Developer preview services
 	_ "github.com/v2fly/v2ray-core/v5/app/instman/command"
 	_ "github.com/v2fly/v2ray-core/v5/app/observatory/command"
+	_ "github.com/v2fly/v2ray-core/v5/app/reverse/command"
 
 	// Other optional features.
 	_ "github.com/v2fly/v2ray-core/v5/app/dns"
@@ -33,7 +34,9 @@ import (
 	// Developer preview features
 	_ "github.com/v2fly/v2ray-core/v5/app/instman"
 	_ "github.com/v2fly/v2ray-core/v5/app/observatory"
+	_ "github.com/v2fly/v2ray-core/v5/app/observatory/alert"
 	_ "github.com/v2fly/v2ray-core/v5/app/restfulapi"
+	_ "github.com/v2fly/v2ray-core/v5/app/ruleset"
 
 	// Inbound and outbound proxies.
 	_ "github.com/v2fly/v2ray-core/v5/proxy/blackhole"
@@ -76,7 +79,9 @@ import (
 
 	// Geo loaders
 	_ "github.com/v2fly/v2ray-core/v5/infra/conf/geodata/memconservative"
+	_ "github.com/v2fly/v2ray-core/v5/infra/conf/geodata/mmdb"
 	_ "github.com/v2fly/v2ray-core/v5/infra/conf/geodata/standard"
+	_ "github.com/v2fly/v2ray-core/v5/infra/conf/geodata/text"
 
 	// JSON, TOML, YAML config support. (jsonv4) This disable selective compile
 	_ "github.com/v2fly/v2ray-core/v5/main/formats"
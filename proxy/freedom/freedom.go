@@ -64,7 +64,7 @@ func (h *Handler) policy() policy.Session {
 	return p
 }
 
-func (h *Handler) resolveIP(ctx context.Context, domain string, localAddr net.Address) net.Address {
+func (h *Handler) resolveIPs(ctx context.Context, domain string, localAddr net.Address) []net.Address {
 	ips, err := dns.LookupIPWithOption(h.dns, domain, dns.IPOption{
 		IPv4Enable: h.config.DomainStrategy == Config_USE_IP || h.config.DomainStrategy == Config_USE_IP4 || (localAddr != nil && localAddr.Family().IsIPv4()),
 		IPv6Enable: h.config.DomainStrategy == Config_USE_IP || h.config.DomainStrategy == Config_USE_IP6 || (localAddr != nil && localAddr.Family().IsIPv6()),
@@ -73,10 +73,19 @@ func (h *Handler) resolveIP(ctx context.Context, domain string, localAddr net.Ad
 	if err != nil {
 		newError("failed to get IP address for domain ", domain).Base(err).WriteToLog(session.ExportIDToError(ctx))
 	}
-	if len(ips) == 0 {
+	addrs := make([]net.Address, 0, len(ips))
+	for _, ip := range ips {
+		addrs = append(addrs, net.IPAddress(ip))
+	}
+	return addrs
+}
+
+func (h *Handler) resolveIP(ctx context.Context, domain string, localAddr net.Address) net.Address {
+	addrs := h.resolveIPs(ctx, domain, localAddr)
+	if len(addrs) == 0 {
 		return nil
 	}
-	return net.IPAddress(ips[dice.Roll(len(ips))])
+	return addrs[dice.Roll(len(addrs))]
 }
 
 func isValidAddress(addr *net.IPOrDomain) bool {
@@ -108,6 +117,9 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer inte
 		outbound.Resolver = func(ctx context.Context, domain string) net.Address {
 			return h.resolveIP(ctx, domain, dialer.Address())
 		}
+		outbound.MultiResolver = func(ctx context.Context, domain string) []net.Address {
+			return h.resolveIPs(ctx, domain, dialer.Address())
+		}
 	}
 	newError("opening connection to ", destination).WriteToLog(session.ExportIDToError(ctx))
 
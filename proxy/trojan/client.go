@@ -142,7 +142,7 @@ func (c *Client) Process(ctx context.Context, link *transport.Link, dialer inter
 		if destination.Network == net.Network_UDP {
 			bodyWriter = &PacketWriter{Writer: connWriter, Target: destination}
 		} else {
-			bodyWriter = connWriter
+			bodyWriter = buf.NewShapingWriter(connWriter, account.Shaping, false)
 		}
 
 		// write some request payload to buffer
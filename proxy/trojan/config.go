@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/buf"
 	"github.com/v2fly/v2ray-core/v5/common/protocol"
 )
 
@@ -13,6 +14,8 @@ import (
 type MemoryAccount struct {
 	Password string
 	Key      []byte
+	// Shaping of the writes to the other side.
+	Shaping *buf.ShapingConfig
 }
 
 // AsAccount implements protocol.AsAccount.
@@ -22,6 +25,7 @@ func (a *Account) AsAccount() (protocol.Account, error) {
 	return &MemoryAccount{
 		Password: password,
 		Key:      key,
+		Shaping:  a.Shaping,
 	}, nil
 }
 
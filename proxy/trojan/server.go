@@ -203,7 +203,11 @@ func (s *Server) Process(ctx context.Context, network net.Network, conn internet
 	})
 
 	newError("received request for ", destination).WriteToLog(sid)
-	return s.handleConnection(ctx, sessionPolicy, destination, clientReader, buf.NewWriter(conn), dispatcher)
+	var clientWriter buf.Writer = buf.NewWriter(conn)
+	if account, ok := user.Account.(*MemoryAccount); ok {
+		clientWriter = buf.NewShapingWriter(clientWriter, account.Shaping, false)
+	}
+	return s.handleConnection(ctx, sessionPolicy, destination, clientReader, clientWriter, dispatcher)
 }
 
 func (s *Server) handleUDPPayload(ctx context.Context, clientReader *PacketReader, clientWriter *PacketWriter, dispatcher routing.Dispatcher) error {
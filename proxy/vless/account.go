@@ -4,6 +4,7 @@
 package vless
 
 import (
+	"github.com/v2fly/v2ray-core/v5/common/buf"
 	"github.com/v2fly/v2ray-core/v5/common/protocol"
 	"github.com/v2fly/v2ray-core/v5/common/uuid"
 )
@@ -18,6 +19,8 @@ func (a *Account) AsAccount() (protocol.Account, error) {
 		ID:         protocol.NewID(id),
 		Flow:       a.Flow,       // needs parser here?
 		Encryption: a.Encryption, // needs parser here?
+		Shaping:    a.Shaping,
+		Padding:    a.Padding,
 	}, nil
 }
 
@@ -29,6 +32,10 @@ type MemoryAccount struct {
 	Flow string
 	// Encryption of the account. Used for client connections, and only accepts "none" for now.
 	Encryption string
+	// Shaping of the writes to the other side.
+	Shaping *buf.ShapingConfig
+	// Padding makes clients pad their requests, which needs support of the server.
+	Padding bool
 }
 
 // Equals implements protocol.Account.Equals().
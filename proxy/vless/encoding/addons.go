@@ -15,9 +15,24 @@ import (
 
 // EncodeHeaderAddons Add addons byte to the header
 func EncodeHeaderAddons(buffer *buf.Buffer, addons *Addons) error {
-	if err := buffer.WriteByte(0); err != nil {
+	// Only the negotiated addons are sent.
+	if addons == nil || !addons.Padding {
+		if err := buffer.WriteByte(0); err != nil {
+			return newError("failed to write addons protobuf length").Base(err)
+		}
+		return nil
+	}
+
+	value, err := proto.Marshal(&Addons{Padding: addons.Padding})
+	if err != nil {
+		return newError("failed to marshal addons protobuf value").Base(err)
+	}
+	if err := buffer.WriteByte(byte(len(value))); err != nil {
 		return newError("failed to write addons protobuf length").Base(err)
 	}
+	if _, err := buffer.Write(value); err != nil {
+		return newError("failed to write addons protobuf value").Base(err)
+	}
 	return nil
 }
 
@@ -42,12 +57,13 @@ func DecodeHeaderAddons(buffer *buf.Buffer, reader io.Reader) (*Addons, error) {
 	return addons, nil
 }
 
-// EncodeBodyAddons returns a Writer that auto-encrypt content written by caller.
-func EncodeBodyAddons(writer io.Writer, request *protocol.RequestHeader, addons *Addons) buf.Writer {
+// EncodeBodyAddons returns a Writer that auto-encrypt content written by caller. The writes are shaped with
+// shaping, and are padded if addons has padding.
+func EncodeBodyAddons(writer io.Writer, request *protocol.RequestHeader, addons *Addons, shaping *buf.ShapingConfig) buf.Writer {
 	if request.Command == protocol.RequestCommandUDP {
 		return NewMultiLengthPacketWriter(writer.(buf.Writer))
 	}
-	return buf.NewWriter(writer)
+	return buf.NewShapingWriter(buf.NewWriter(writer), shaping, addons.GetPadding())
 }
 
 // DecodeBodyAddons returns a Reader from which caller can fetch decrypted body.
@@ -55,6 +71,9 @@ func DecodeBodyAddons(reader io.Reader, request *protocol.RequestHeader, addons
 	if request.Command == protocol.RequestCommandUDP {
 		return NewLengthPacketReader(reader)
 	}
+	if addons.GetPadding() {
+		return buf.NewPaddingReader(reader)
+	}
 	return buf.NewReader(reader)
 }
 
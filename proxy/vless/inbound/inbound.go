@@ -369,7 +369,11 @@ func (h *Handler) Process(ctx context.Context, network net.Network, connection i
 	}
 	inbound.User = request.User
 
-	responseAddons := &encoding.Addons{}
+	// The response is padded only if the client pads its request, which means it supports padding.
+	account := request.User.Account.(*vless.MemoryAccount)
+	responseAddons := &encoding.Addons{
+		Padding: requestAddons.GetPadding() && account.Shaping.HasPadding(),
+	}
 
 	if request.Command != protocol.RequestCommandMux {
 		ctx = log.ContextWithAccessMessage(ctx, &log.AccessMessage{
@@ -417,7 +421,7 @@ func (h *Handler) Process(ctx context.Context, network net.Network, connection i
 		}
 
 		// default: clientWriter := bufferWriter
-		clientWriter := encoding.EncodeBodyAddons(bufferWriter, request, responseAddons)
+		clientWriter := encoding.EncodeBodyAddons(bufferWriter, request, responseAddons, account.Shaping)
 		{
 			multiBuffer, err := serverReader.ReadMultiBuffer()
 			if err != nil {
@@ -118,8 +118,11 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer inte
 
 	account := request.User.Account.(*vless.MemoryAccount)
 
+	// Padding is not acknowledged by servers before the request is sent, so it is only sent if the
+	// server is configured to support it.
 	requestAddons := &encoding.Addons{
-		Flow: account.Flow,
+		Flow:    account.Flow,
+		Padding: account.Padding && account.Shaping.HasPadding() && request.Command != protocol.RequestCommandUDP,
 	}
 
 	sessionPolicy := h.policyManager.ForLevel(request.User.Level)
@@ -138,7 +141,7 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer inte
 		}
 
 		// default: serverWriter := bufferWriter
-		serverWriter := encoding.EncodeBodyAddons(bufferWriter, request, requestAddons)
+		serverWriter := encoding.EncodeBodyAddons(bufferWriter, request, requestAddons, account.Shaping)
 		if err := buf.CopyOnceTimeout(clientReader, serverWriter, proxy.FirstPayloadTimeout); err != nil && err != buf.ErrNotTimeoutReader && err != buf.ErrReadTimeout {
 			return err // ...
 		}
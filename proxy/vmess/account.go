@@ -6,6 +6,7 @@ package vmess
 import (
 	"strings"
 
+	"github.com/v2fly/v2ray-core/v5/common/buf"
 	"github.com/v2fly/v2ray-core/v5/common/dice"
 	"github.com/v2fly/v2ray-core/v5/common/protocol"
 	"github.com/v2fly/v2ray-core/v5/common/uuid"
@@ -22,6 +23,9 @@ type MemoryAccount struct {
 
 	AuthenticatedLengthExperiment bool
 	NoTerminationSignal           bool
+
+	// Shaping of the writes to the other side.
+	Shaping *buf.ShapingConfig
 }
 
 // AnyValidID returns an ID that is either the main ID or one of the alternative IDs if any.
@@ -62,5 +66,6 @@ func (a *Account) AsAccount() (protocol.Account, error) {
 		Security:                      a.SecuritySettings.GetSecurityType(),
 		AuthenticatedLengthExperiment: AuthenticatedLength,
 		NoTerminationSignal:           NoTerminationSignal,
+		Shaping:                       a.Shaping,
 	}, nil
 }
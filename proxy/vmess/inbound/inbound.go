@@ -184,6 +184,9 @@ func transferResponse(timer signal.ActivityUpdater, session *encoding.ServerSess
 	if err != nil {
 		return newError("failed to start decoding response").Base(err)
 	}
+	if account, ok := request.User.Account.(*vmess.MemoryAccount); ok && request.Command == protocol.RequestCommandTCP {
+		bodyWriter = buf.NewShapingWriter(bodyWriter, account.Shaping, false)
+	}
 	{
 		// Optimize for small response packet
 		data, err := input.ReadMultiBuffer()
@@ -108,7 +108,7 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer inte
 		request.Option.Set(protocol.RequestOptionChunkMasking)
 	}
 
-	if shouldEnablePadding(request.Security) && request.Option.Has(protocol.RequestOptionChunkMasking) {
+	if (shouldEnablePadding(request.Security) || account.Shaping.HasPadding()) && request.Option.Has(protocol.RequestOptionChunkMasking) {
 		request.Option.Set(protocol.RequestOptionGlobalPadding)
 	}
 
@@ -153,6 +153,9 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer inte
 		if err != nil {
 			return newError("failed to start encoding").Base(err)
 		}
+		if request.Command == protocol.RequestCommandTCP {
+			bodyWriter = buf.NewShapingWriter(bodyWriter, account.Shaping, false)
+		}
 		if err := buf.CopyOnceTimeout(input, bodyWriter, proxy.FirstPayloadTimeout); err != nil && err != buf.ErrNotTimeoutReader && err != buf.ErrReadTimeout {
 			return newError("failed to write first payload").Base(err)
 		}
@@ -49,6 +49,9 @@ func TestReverseProxy(t *testing.T) {
 					{
 						Tag:    "portal",
 						Domain: "test.v2fly.org",
+						Bridge: []*reverse.BridgeCredential{
+							{Id: "bridge-a", Psk: "bridge-a-secret"},
+						},
 					},
 				},
 			}),
@@ -118,6 +121,8 @@ func TestReverseProxy(t *testing.T) {
 					{
 						Tag:    "bridge",
 						Domain: "test.v2fly.org",
+						Id:     "bridge-a",
+						Psk:    "bridge-a-secret",
 					},
 				},
 			}),
@@ -421,6 +421,34 @@ type SocketConfig struct {
 	RxBufSize                  int64  `protobuf:"varint,12,opt,name=rx_buf_size,json=rxBufSize,proto3" json:"rx_buf_size,omitempty"`
 	TxBufSize                  int64  `protobuf:"varint,13,opt,name=tx_buf_size,json=txBufSize,proto3" json:"tx_buf_size,omitempty"`
 	ForceBufSize               bool   `protobuf:"varint,14,opt,name=force_buf_size,json=forceBufSize,proto3" json:"force_buf_size,omitempty"`
+	// Race connection attempts over all resolved addresses of domains. Happy
+	// Eyeballs is used only when this is set.
+	HappyEyeballs *HappyEyeballsConfig `protobuf:"bytes,15,opt,name=happy_eyeballs,json=happyEyeballs,proto3" json:"happy_eyeballs,omitempty"`
+	// Use Multipath TCP for TCP sockets, falling back to plain TCP when the
+	// system does not support it. Linux only.
+	Mptcp bool `protobuf:"varint,16,opt,name=mptcp,proto3" json:"mptcp,omitempty"`
+	// Name of the TCP congestion control algorithm, e.g. "bbr". Linux only.
+	TcpCongestion string `protobuf:"bytes,17,opt,name=tcp_congestion,json=tcpCongestion,proto3" json:"tcp_congestion,omitempty"`
+	// TCP_USER_TIMEOUT in milliseconds. Linux only.
+	TcpUserTimeout uint32 `protobuf:"varint,18,opt,name=tcp_user_timeout,json=tcpUserTimeout,proto3" json:"tcp_user_timeout,omitempty"`
+	// TCP_NOTSENT_LOWAT in bytes. Linux only.
+	TcpNotSentLowat uint32 `protobuf:"varint,19,opt,name=tcp_not_sent_lowat,json=tcpNotSentLowat,proto3" json:"tcp_not_sent_lowat,omitempty"`
+	// File mode of unix domain sockets created on the file system by listeners,
+	// e.g. 0660. Zero keeps the mode from the process umask.
+	UnixSocketMode uint32 `protobuf:"varint,20,opt,name=unix_socket_mode,json=unixSocketMode,proto3" json:"unix_socket_mode,omitempty"`
+	// Owner of unix domain sockets created on the file system by listeners. It
+	// can be a user name or a numeric user ID.
+	UnixSocketOwner string `protobuf:"bytes,21,opt,name=unix_socket_owner,json=unixSocketOwner,proto3" json:"unix_socket_owner,omitempty"`
+	// Group of unix domain sockets created on the file system by listeners. It
+	// can be a group name or a numeric group ID.
+	UnixSocketGroup string `protobuf:"bytes,22,opt,name=unix_socket_group,json=unixSocketGroup,proto3" json:"unix_socket_group,omitempty"`
+	// Path of a unix datagram socket, or its name in the abstract namespace
+	// prefixed with '@', that UDP connections are dialed to instead of their
+	// destinations.
+	DialUnixDatagram string `protobuf:"bytes,23,opt,name=dial_unix_datagram,json=dialUnixDatagram,proto3" json:"dial_unix_datagram,omitempty"`
+	// Allow binding to source addresses that are not yet assigned to any local
+	// interface. Only works on Linux.
+	FreeBind bool `protobuf:"varint,24,opt,name=free_bind,json=freeBind,proto3" json:"free_bind,omitempty"`
 }
 
 func (x *SocketConfig) Reset() {
@@ -553,6 +581,155 @@ func (x *SocketConfig) GetForceBufSize() bool {
 	return false
 }
 
+func (x *SocketConfig) GetHappyEyeballs() *HappyEyeballsConfig {
+	if x != nil {
+		return x.HappyEyeballs
+	}
+	return nil
+}
+
+func (x *SocketConfig) GetMptcp() bool {
+	if x != nil {
+		return x.Mptcp
+	}
+	return false
+}
+
+func (x *SocketConfig) GetTcpCongestion() string {
+	if x != nil {
+		return x.TcpCongestion
+	}
+	return ""
+}
+
+func (x *SocketConfig) GetTcpUserTimeout() uint32 {
+	if x != nil {
+		return x.TcpUserTimeout
+	}
+	return 0
+}
+
+func (x *SocketConfig) GetTcpNotSentLowat() uint32 {
+	if x != nil {
+		return x.TcpNotSentLowat
+	}
+	return 0
+}
+
+func (x *SocketConfig) GetUnixSocketMode() uint32 {
+	if x != nil {
+		return x.UnixSocketMode
+	}
+	return 0
+}
+
+func (x *SocketConfig) GetUnixSocketOwner() string {
+	if x != nil {
+		return x.UnixSocketOwner
+	}
+	return ""
+}
+
+func (x *SocketConfig) GetUnixSocketGroup() string {
+	if x != nil {
+		return x.UnixSocketGroup
+	}
+	return ""
+}
+
+func (x *SocketConfig) GetDialUnixDatagram() string {
+	if x != nil {
+		return x.DialUnixDatagram
+	}
+	return ""
+}
+
+func (x *SocketConfig) GetFreeBind() bool {
+	if x != nil {
+		return x.FreeBind
+	}
+	return false
+}
+
+// HappyEyeballsConfig controls how connection attempts are raced when a
+// domain resolves to multiple IP addresses, as described in RFC 8305.
+type HappyEyeballsConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Disable racing, as if this config is not set.
+	Disabled bool `protobuf:"varint,1,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	// Delay in milliseconds before starting the next connection attempt.
+	// 250 milliseconds if not set.
+	TryDelayMs uint32 `protobuf:"varint,2,opt,name=try_delay_ms,json=tryDelayMs,proto3" json:"try_delay_ms,omitempty"`
+	// Try IPv4 addresses first when no family preference has been learnt.
+	PreferIpv4 bool `protobuf:"varint,3,opt,name=prefer_ipv4,json=preferIpv4,proto3" json:"prefer_ipv4,omitempty"`
+	// Number of addresses of the preferred family to try before switching to
+	// the other family. 1 if not set.
+	FirstAddressFamilyCount uint32 `protobuf:"varint,4,opt,name=first_address_family_count,json=firstAddressFamilyCount,proto3" json:"first_address_family_count,omitempty"`
+}
+
+func (x *HappyEyeballsConfig) Reset() {
+	*x = HappyEyeballsConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_transport_internet_config_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HappyEyeballsConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HappyEyeballsConfig) ProtoMessage() {}
+
+func (x *HappyEyeballsConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_internet_config_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HappyEyeballsConfig.ProtoReflect.Descriptor instead.
+func (*HappyEyeballsConfig) Descriptor() ([]byte, []int) {
+	return file_transport_internet_config_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *HappyEyeballsConfig) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+func (x *HappyEyeballsConfig) GetTryDelayMs() uint32 {
+	if x != nil {
+		return x.TryDelayMs
+	}
+	return 0
+}
+
+func (x *HappyEyeballsConfig) GetPreferIpv4() bool {
+	if x != nil {
+		return x.PreferIpv4
+	}
+	return false
+}
+
+func (x *HappyEyeballsConfig) GetFirstAddressFamilyCount() uint32 {
+	if x != nil {
+		return x.FirstAddressFamilyCount
+	}
+	return 0
+}
+
 var File_transport_internet_config_proto protoreflect.FileDescriptor
 
 var file_transport_internet_config_proto_rawDesc = []byte{
@@ -605,7 +782,7 @@ var file_transport_internet_config_proto_rawDesc = []byte{
 	0x0a, 0x13, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x4c, 0x61, 0x79, 0x65, 0x72,
 	0x50, 0x72, 0x6f, 0x78, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x13, 0x74, 0x72, 0x61,
 	0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x4c, 0x61, 0x79, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x78, 0x79,
-	0x22, 0xfd, 0x05, 0x0a, 0x0c, 0x53, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x22, 0xb9, 0x09, 0x0a, 0x0c, 0x53, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69,
 	0x67, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x61, 0x72, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
 	0x04, 0x6d, 0x61, 0x72, 0x6b, 0x12, 0x4e, 0x0a, 0x03, 0x74, 0x66, 0x6f, 0x18, 0x02, 0x20, 0x01,
 	0x28, 0x0e, 0x32, 0x3c, 0x2e, 0x76, 0x32, 0x72, 0x61, 0x79, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e,
@@ -646,13 +823,52 @@ var file_transport_internet_config_proto_rawDesc = []byte{
 	0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x78, 0x42, 0x75, 0x66, 0x53, 0x69,
 	0x7a, 0x65, 0x12, 0x24, 0x0a, 0x0e, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x5f, 0x62, 0x75, 0x66, 0x5f,
 	0x73, 0x69, 0x7a, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x66, 0x6f, 0x72, 0x63,
-	0x65, 0x42, 0x75, 0x66, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x35, 0x0a, 0x10, 0x54, 0x43, 0x50, 0x46,
-	0x61, 0x73, 0x74, 0x4f, 0x70, 0x65, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x08, 0x0a, 0x04,
-	0x41, 0x73, 0x49, 0x73, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65,
-	0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x44, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x10, 0x02, 0x22,
-	0x2f, 0x0a, 0x0a, 0x54, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x07, 0x0a,
-	0x03, 0x4f, 0x66, 0x66, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x54, 0x50, 0x72, 0x6f, 0x78, 0x79,
-	0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x52, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x10, 0x02,
+	0x65, 0x42, 0x75, 0x66, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x59, 0x0a, 0x0e, 0x68, 0x61, 0x70, 0x70,
+	0x79, 0x5f, 0x65, 0x79, 0x65, 0x62, 0x61, 0x6c, 0x6c, 0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x32, 0x2e, 0x76, 0x32, 0x72, 0x61, 0x79, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x74, 0x72,
+	0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x2e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x65, 0x74,
+	0x2e, 0x48, 0x61, 0x70, 0x70, 0x79, 0x45, 0x79, 0x65, 0x62, 0x61, 0x6c, 0x6c, 0x73, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x52, 0x0d, 0x68, 0x61, 0x70, 0x70, 0x79, 0x45, 0x79, 0x65, 0x62, 0x61,
+	0x6c, 0x6c, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x70, 0x74, 0x63, 0x70, 0x18, 0x10, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x05, 0x6d, 0x70, 0x74, 0x63, 0x70, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x63, 0x70,
+	0x5f, 0x63, 0x6f, 0x6e, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x11, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x74, 0x63, 0x70, 0x43, 0x6f, 0x6e, 0x67, 0x65, 0x73, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x28, 0x0a, 0x10, 0x74, 0x63, 0x70, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x6f, 0x75, 0x74, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x74, 0x63, 0x70, 0x55,
+	0x73, 0x65, 0x72, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x2b, 0x0a, 0x12, 0x74, 0x63,
+	0x70, 0x5f, 0x6e, 0x6f, 0x74, 0x5f, 0x73, 0x65, 0x6e, 0x74, 0x5f, 0x6c, 0x6f, 0x77, 0x61, 0x74,
+	0x18, 0x13, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x74, 0x63, 0x70, 0x4e, 0x6f, 0x74, 0x53, 0x65,
+	0x6e, 0x74, 0x4c, 0x6f, 0x77, 0x61, 0x74, 0x12, 0x28, 0x0a, 0x10, 0x75, 0x6e, 0x69, 0x78, 0x5f,
+	0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x14, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0e, 0x75, 0x6e, 0x69, 0x78, 0x53, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x4d, 0x6f, 0x64,
+	0x65, 0x12, 0x2a, 0x0a, 0x11, 0x75, 0x6e, 0x69, 0x78, 0x5f, 0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74,
+	0x5f, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x15, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x75, 0x6e,
+	0x69, 0x78, 0x53, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x2a, 0x0a,
+	0x11, 0x75, 0x6e, 0x69, 0x78, 0x5f, 0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x5f, 0x67, 0x72, 0x6f,
+	0x75, 0x70, 0x18, 0x16, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x75, 0x6e, 0x69, 0x78, 0x53, 0x6f,
+	0x63, 0x6b, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x2c, 0x0a, 0x12, 0x64, 0x69, 0x61,
+	0x6c, 0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x67, 0x72, 0x61, 0x6d, 0x18,
+	0x17, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x64, 0x69, 0x61, 0x6c, 0x55, 0x6e, 0x69, 0x78, 0x44,
+	0x61, 0x74, 0x61, 0x67, 0x72, 0x61, 0x6d, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x72, 0x65, 0x65, 0x5f,
+	0x62, 0x69, 0x6e, 0x64, 0x18, 0x18, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x66, 0x72, 0x65, 0x65,
+	0x42, 0x69, 0x6e, 0x64, 0x22, 0x35, 0x0a, 0x10, 0x54, 0x43, 0x50, 0x46, 0x61, 0x73, 0x74, 0x4f,
+	0x70, 0x65, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x41, 0x73, 0x49, 0x73,
+	0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x10, 0x01, 0x12, 0x0b,
+	0x0a, 0x07, 0x44, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x10, 0x02, 0x22, 0x2f, 0x0a, 0x0a, 0x54,
+	0x50, 0x72, 0x6f, 0x78, 0x79, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x07, 0x0a, 0x03, 0x4f, 0x66, 0x66,
+	0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x54, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x10, 0x01, 0x12, 0x0c,
+	0x0a, 0x08, 0x52, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x10, 0x02, 0x22, 0xb1, 0x01, 0x0a,
+	0x13, 0x48, 0x61, 0x70, 0x70, 0x79, 0x45, 0x79, 0x65, 0x62, 0x61, 0x6c, 0x6c, 0x73, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x64,
+	0x12, 0x20, 0x0a, 0x0c, 0x74, 0x72, 0x79, 0x5f, 0x64, 0x65, 0x6c, 0x61, 0x79, 0x5f, 0x6d, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x74, 0x72, 0x79, 0x44, 0x65, 0x6c, 0x61, 0x79,
+	0x4d, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x5f, 0x69, 0x70, 0x76,
+	0x34, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x49,
+	0x70, 0x76, 0x34, 0x12, 0x3b, 0x0a, 0x1a, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x61, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x5f, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x5f, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x17, 0x66, 0x69, 0x72, 0x73, 0x74, 0x41, 0x64,
+	0x64, 0x72, 0x65, 0x73, 0x73, 0x46, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x43, 0x6f, 0x75, 0x6e, 0x74,
 	0x2a, 0x5a, 0x0a, 0x11, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x72, 0x6f,
 	0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x43, 0x50, 0x10, 0x00, 0x12, 0x07,
 	0x0a, 0x03, 0x55, 0x44, 0x50, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x4d, 0x4b, 0x43, 0x50, 0x10,
@@ -682,7 +898,7 @@ func file_transport_internet_config_proto_rawDescGZIP() []byte {
 }
 
 var file_transport_internet_config_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_transport_internet_config_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_transport_internet_config_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_transport_internet_config_proto_goTypes = []interface{}{
 	(TransportProtocol)(0),             // 0: v2ray.core.transport.internet.TransportProtocol
 	(SocketConfig_TCPFastOpenState)(0), // 1: v2ray.core.transport.internet.SocketConfig.TCPFastOpenState
@@ -691,22 +907,24 @@ var file_transport_internet_config_proto_goTypes = []interface{}{
 	(*StreamConfig)(nil),               // 4: v2ray.core.transport.internet.StreamConfig
 	(*ProxyConfig)(nil),                // 5: v2ray.core.transport.internet.ProxyConfig
 	(*SocketConfig)(nil),               // 6: v2ray.core.transport.internet.SocketConfig
-	(*anypb.Any)(nil),                  // 7: google.protobuf.Any
+	(*HappyEyeballsConfig)(nil),        // 7: v2ray.core.transport.internet.HappyEyeballsConfig
+	(*anypb.Any)(nil),                  // 8: google.protobuf.Any
 }
 var file_transport_internet_config_proto_depIdxs = []int32{
 	0, // 0: v2ray.core.transport.internet.TransportConfig.protocol:type_name -> v2ray.core.transport.internet.TransportProtocol
-	7, // 1: v2ray.core.transport.internet.TransportConfig.settings:type_name -> google.protobuf.Any
+	8, // 1: v2ray.core.transport.internet.TransportConfig.settings:type_name -> google.protobuf.Any
 	0, // 2: v2ray.core.transport.internet.StreamConfig.protocol:type_name -> v2ray.core.transport.internet.TransportProtocol
 	3, // 3: v2ray.core.transport.internet.StreamConfig.transport_settings:type_name -> v2ray.core.transport.internet.TransportConfig
-	7, // 4: v2ray.core.transport.internet.StreamConfig.security_settings:type_name -> google.protobuf.Any
+	8, // 4: v2ray.core.transport.internet.StreamConfig.security_settings:type_name -> google.protobuf.Any
 	6, // 5: v2ray.core.transport.internet.StreamConfig.socket_settings:type_name -> v2ray.core.transport.internet.SocketConfig
 	1, // 6: v2ray.core.transport.internet.SocketConfig.tfo:type_name -> v2ray.core.transport.internet.SocketConfig.TCPFastOpenState
 	2, // 7: v2ray.core.transport.internet.SocketConfig.tproxy:type_name -> v2ray.core.transport.internet.SocketConfig.TProxyMode
-	8, // [8:8] is the sub-list for method output_type
-	8, // [8:8] is the sub-list for method input_type
-	8, // [8:8] is the sub-list for extension type_name
-	8, // [8:8] is the sub-list for extension extendee
-	0, // [0:8] is the sub-list for field type_name
+	7, // 8: v2ray.core.transport.internet.SocketConfig.happy_eyeballs:type_name -> v2ray.core.transport.internet.HappyEyeballsConfig
+	9, // [9:9] is the sub-list for method output_type
+	9, // [9:9] is the sub-list for method input_type
+	9, // [9:9] is the sub-list for extension type_name
+	9, // [9:9] is the sub-list for extension extendee
+	0, // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_transport_internet_config_proto_init() }
@@ -763,6 +981,18 @@ func file_transport_internet_config_proto_init() {
 				return nil
 			}
 		}
+		file_transport_internet_config_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HappyEyeballsConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -770,7 +1000,7 @@ func file_transport_internet_config_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_transport_internet_config_proto_rawDesc,
 			NumEnums:      3,
-			NumMessages:   4,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
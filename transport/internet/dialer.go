@@ -3,6 +3,7 @@ package internet
 import (
 	"context"
 
+	"github.com/v2fly/v2ray-core/v5/common/dice"
 	"github.com/v2fly/v2ray-core/v5/common/net"
 	"github.com/v2fly/v2ray-core/v5/common/session"
 	"github.com/v2fly/v2ray-core/v5/transport/internet/tagged"
@@ -79,7 +80,23 @@ func DialSystem(ctx context.Context, dest net.Destination, sockopt *SocketConfig
 		return DialTaggedOutbound(ctx, dest, transportLayerOutgoingTag)
 	}
 
+	// Unix datagram sockets are dialed only if configured, as destinations may come from remote clients.
+	if name := sockopt.GetDialUnixDatagram(); name != "" && dest.Network == net.Network_UDP {
+		newError("dialing to unix datagram socket ", name, " for ", dest).WriteToLog(session.ExportIDToError(ctx))
+		return dialUnixgram(ctx, name)
+	}
+
 	originalAddr := dest.Address
+	if outbound != nil && outbound.MultiResolver != nil && dest.Address.Family().IsDomain() {
+		addrs := outbound.MultiResolver(ctx, dest.Address.Domain())
+		switch {
+		case len(addrs) > 1 && dest.Network == net.Network_TCP && happyEyeballsEnabled(sockopt):
+			newError("dialing to ", dest, " with happy eyeballs over ", len(addrs), " addresses").WriteToLog(session.ExportIDToError(ctx))
+			return dialHappyEyeballs(ctx, effectiveSystemDialer, src, dest, addrs, sockopt)
+		case len(addrs) > 0:
+			dest.Address = addrs[dice.Roll(len(addrs))]
+		}
+	}
 	if outbound != nil && outbound.Resolver != nil && dest.Address.Family().IsDomain() {
 		if addr := outbound.Resolver(ctx, dest.Address.Domain()); addr != nil {
 			dest.Address = addr
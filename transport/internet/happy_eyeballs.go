@@ -0,0 +1,229 @@
+package internet
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/session"
+)
+
+const (
+	defaultHappyEyeballsTryDelay  = 250 * time.Millisecond
+	happyEyeballsFamilyCacheTTL   = 10 * time.Minute
+	happyEyeballsFamilyCacheLimit = 4096
+)
+
+type familyRecord struct {
+	ipv6    bool
+	expires time.Time
+}
+
+// familyCache remembers which address family won the last race for a domain,
+// so that the next dial towards the same domain starts with that family.
+type familyCache struct {
+	sync.Mutex
+	records map[string]familyRecord
+}
+
+var happyEyeballsFamilies = &familyCache{
+	records: make(map[string]familyRecord),
+}
+
+func (c *familyCache) get(domain string) (ipv6 bool, found bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	record, found := c.records[domain]
+	if !found {
+		return false, false
+	}
+	if time.Now().After(record.expires) {
+		delete(c.records, domain)
+		return false, false
+	}
+	return record.ipv6, true
+}
+
+func (c *familyCache) set(domain string, ipv6 bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	now := time.Now()
+	if len(c.records) >= happyEyeballsFamilyCacheLimit {
+		for d, record := range c.records {
+			if now.After(record.expires) {
+				delete(c.records, d)
+			}
+		}
+		if len(c.records) >= happyEyeballsFamilyCacheLimit {
+			c.records = make(map[string]familyRecord)
+		}
+	}
+	c.records[domain] = familyRecord{
+		ipv6:    ipv6,
+		expires: now.Add(happyEyeballsFamilyCacheTTL),
+	}
+}
+
+// happyEyeballsEnabled returns whether Happy Eyeballs is configured. It is opt-in, so that dialing
+// without the config keeps picking one of the resolved addresses.
+func happyEyeballsEnabled(sockopt *SocketConfig) bool {
+	return sockopt.GetHappyEyeballs() != nil && !sockopt.HappyEyeballs.Disabled
+}
+
+func happyEyeballsTryDelay(sockopt *SocketConfig) time.Duration {
+	if sockopt != nil && sockopt.HappyEyeballs != nil && sockopt.HappyEyeballs.TryDelayMs > 0 {
+		return time.Duration(sockopt.HappyEyeballs.TryDelayMs) * time.Millisecond
+	}
+	return defaultHappyEyeballsTryDelay
+}
+
+// sortHappyEyeballsAddresses orders addresses for connection attempts as in RFC 8305 section 4:
+// firstFamilyCount addresses of the preferred family first, then alternating between families.
+func sortHappyEyeballsAddresses(addrs []net.Address, preferIPv6 bool, firstFamilyCount int) []net.Address {
+	var preferred, other []net.Address
+	for _, addr := range addrs {
+		if addr.Family().IsIPv6() == preferIPv6 {
+			preferred = append(preferred, addr)
+		} else {
+			other = append(other, addr)
+		}
+	}
+	if firstFamilyCount < 1 {
+		firstFamilyCount = 1
+	}
+
+	result := make([]net.Address, 0, len(addrs))
+	for len(preferred) > 0 || len(other) > 0 {
+		for i := 0; i < firstFamilyCount && len(preferred) > 0; i++ {
+			result = append(result, preferred[0])
+			preferred = preferred[1:]
+		}
+		firstFamilyCount = 1
+		if len(other) > 0 {
+			result = append(result, other[0])
+			other = other[1:]
+		}
+	}
+	return result
+}
+
+// filterAddressesForSource drops addresses that are unreachable from the given source address.
+func filterAddressesForSource(addrs []net.Address, src net.Address) []net.Address {
+	if src == nil || src == net.AnyIP || !src.Family().IsIP() {
+		return addrs
+	}
+	filtered := make([]net.Address, 0, len(addrs))
+	for _, addr := range addrs {
+		if addr.Family() == src.Family() {
+			filtered = append(filtered, addr)
+		}
+	}
+	return filtered
+}
+
+type happyEyeballsResult struct {
+	conn net.Conn
+	err  error
+	addr net.Address
+}
+
+// dialHappyEyeballs races connection attempts to the given addresses as described in RFC 8305.
+// A new attempt is started every try delay, or immediately when an earlier attempt fails. The
+// first established connection wins and all other attempts are cancelled.
+func dialHappyEyeballs(ctx context.Context, dialer SystemDialer, src net.Address, dest net.Destination, addrs []net.Address, sockopt *SocketConfig) (net.Conn, error) {
+	domain := dest.Address.Domain()
+
+	preferIPv6 := true
+	firstFamilyCount := 1
+	if config := sockopt.GetHappyEyeballs(); config != nil {
+		preferIPv6 = !config.PreferIpv4
+		if config.FirstAddressFamilyCount > 0 {
+			firstFamilyCount = int(config.FirstAddressFamilyCount)
+		}
+	}
+	if ipv6, found := happyEyeballsFamilies.get(domain); found {
+		preferIPv6 = ipv6
+	}
+
+	addrs = filterAddressesForSource(addrs, src)
+	if len(addrs) == 0 {
+		return nil, newError("no address of the gateway family is available for ", dest)
+	}
+	addrs = sortHappyEyeballsAddresses(addrs, preferIPv6, firstFamilyCount)
+	delay := happyEyeballsTryDelay(sockopt)
+
+	dialCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan happyEyeballsResult, len(addrs))
+	next, pending := 0, 0
+	startNext := func() {
+		d := dest
+		d.Address = addrs[next]
+		next++
+		pending++
+		newError("happy eyeballs: dialing to ", d, " resolved from ", domain).AtDebug().WriteToLog(session.ExportIDToError(ctx))
+		go func() {
+			conn, err := dialer.Dial(dialCtx, src, d, sockopt)
+			results <- happyEyeballsResult{conn: conn, err: err, addr: d.Address}
+		}()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	startNext()
+	var lastErr error
+	for {
+		var timeout <-chan time.Time
+		if next < len(addrs) {
+			timeout = timer.C
+		}
+
+		select {
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				happyEyeballsFamilies.set(domain, r.addr.Family().IsIPv6())
+				cancel()
+				go drainHappyEyeballsResults(results, pending)
+				return r.conn, nil
+			}
+			lastErr = r.err
+			if next < len(addrs) {
+				resetTimer(timer, delay)
+				startNext()
+			} else if pending == 0 {
+				return nil, newError("all connection attempts to ", dest, " failed").Base(lastErr)
+			}
+		case <-timeout:
+			timer.Reset(delay)
+			startNext()
+		case <-ctx.Done():
+			go drainHappyEyeballsResults(results, pending)
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// drainHappyEyeballsResults closes connections of attempts that completed after the race was decided.
+func drainHappyEyeballsResults(results <-chan happyEyeballsResult, pending int) {
+	for ; pending > 0; pending-- {
+		if r := <-results; r.conn != nil {
+			r.conn.Close()
+		}
+	}
+}
+
+func resetTimer(timer *time.Timer, d time.Duration) {
+	if !timer.Stop() {
+		select {
+		case <-timer.C:
+		default:
+		}
+	}
+	timer.Reset(d)
+}
@@ -0,0 +1,186 @@
+package internet_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	gonet "net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/common/session"
+	"github.com/v2fly/v2ray-core/v5/testing/servers/tcp"
+	. "github.com/v2fly/v2ray-core/v5/transport/internet"
+)
+
+func TestDialHappyEyeballs(t *testing.T) {
+	server := &tcp.Server{}
+	dest, err := server.Start()
+	common.Must(err)
+	defer server.Close()
+
+	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{
+		MultiResolver: func(ctx context.Context, domain string) []net.Address {
+			return []net.Address{
+				net.ParseAddress("::1"),
+				net.ParseAddress("127.0.0.2"),
+				net.LocalHostIP,
+			}
+		},
+	})
+	sockopt := &SocketConfig{
+		HappyEyeballs: &HappyEyeballsConfig{
+			TryDelayMs:              50,
+			FirstAddressFamilyCount: 2,
+		},
+	}
+
+	for i := 0; i < 2; i++ {
+		conn, err := DialSystem(ctx, net.TCPDestination(net.DomainAddress("example.com"), dest.Port), sockopt)
+		common.Must(err)
+		if r := cmp.Diff(conn.RemoteAddr().String(), "127.0.0.1:"+dest.Port.String()); r != "" {
+			t.Error(r)
+		}
+		conn.Close()
+	}
+}
+
+// raceDialer records connection attempts, and succeeds only for the winner address.
+// Attempts to addresses in fail fail immediately, and others block until cancelled.
+type raceDialer struct {
+	sync.Mutex
+	winner   net.Address
+	fail     map[net.Address]bool
+	attempts []net.Address
+	started  []time.Time
+}
+
+func (d *raceDialer) Dial(ctx context.Context, src net.Address, dest net.Destination, sockopt *SocketConfig) (net.Conn, error) {
+	d.Lock()
+	d.attempts = append(d.attempts, dest.Address)
+	d.started = append(d.started, time.Now())
+	d.Unlock()
+
+	switch {
+	case dest.Address == d.winner:
+		conn, peer := gonet.Pipe()
+		peer.Close()
+		return conn, nil
+	case d.fail[dest.Address]:
+		return nil, errors.New("refused")
+	default:
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+}
+
+func (d *raceDialer) result() ([]net.Address, []time.Time) {
+	d.Lock()
+	defer d.Unlock()
+	return append([]net.Address(nil), d.attempts...), append([]time.Time(nil), d.started...)
+}
+
+func happyEyeballsContext(addrs ...net.Address) context.Context {
+	return session.ContextWithOutbound(context.Background(), &session.Outbound{
+		MultiResolver: func(ctx context.Context, domain string) []net.Address {
+			return addrs
+		},
+	})
+}
+
+func TestDialHappyEyeballsRacing(t *testing.T) {
+	ipv6a, ipv6b := net.ParseAddress("2001:db8::1"), net.ParseAddress("2001:db8::2")
+	ipv4a, ipv4b := net.ParseAddress("192.0.2.1"), net.ParseAddress("192.0.2.2")
+	dialer := &raceDialer{winner: ipv6b}
+	UseAlternativeSystemDialer(dialer)
+	defer UseAlternativeSystemDialer(nil)
+
+	const delay = 50 * time.Millisecond
+	sockopt := &SocketConfig{
+		HappyEyeballs: &HappyEyeballsConfig{TryDelayMs: uint32(delay / time.Millisecond)},
+	}
+	ctx := happyEyeballsContext(ipv4a, ipv4b, ipv6a, ipv6b)
+	conn, err := DialSystem(ctx, net.TCPDestination(net.DomainAddress("racing.example"), 443), sockopt)
+	common.Must(err)
+	conn.Close()
+
+	// IPv6 is preferred, and families alternate afterwards. Attempts that do not fail are
+	// raced, each started a try delay after the previous one, until one succeeds.
+	attempts, started := dialer.result()
+	if r := cmp.Diff(attempts, []net.Address{ipv6a, ipv4a, ipv6b}); r != "" {
+		t.Fatal(r)
+	}
+	for i := 1; i < len(started); i++ {
+		if gap := started[i].Sub(started[i-1]); gap < delay*9/10 {
+			t.Error("attempt ", i, " started ", gap, " after the previous one")
+		}
+	}
+}
+
+func TestDialHappyEyeballsFallback(t *testing.T) {
+	ipv6 := net.ParseAddress("2001:db8::1")
+	ipv4a, ipv4b := net.ParseAddress("192.0.2.1"), net.ParseAddress("192.0.2.2")
+	dialer := &raceDialer{
+		winner: ipv4b,
+		fail:   map[net.Address]bool{ipv6: true, ipv4a: true},
+	}
+	UseAlternativeSystemDialer(dialer)
+	defer UseAlternativeSystemDialer(nil)
+
+	// Failed attempts start the next attempt at once, without waiting for the try delay.
+	sockopt := &SocketConfig{
+		HappyEyeballs: &HappyEyeballsConfig{TryDelayMs: 10000},
+	}
+	ctx := happyEyeballsContext(ipv4a, ipv4b, ipv6)
+	// The learnt family is cached per domain across runs of the test.
+	dest := net.TCPDestination(net.DomainAddress(fmt.Sprint("fallback-", time.Now().UnixNano(), ".example")), 443)
+	begin := time.Now()
+	conn, err := DialSystem(ctx, dest, sockopt)
+	common.Must(err)
+	conn.Close()
+	if elapsed := time.Since(begin); elapsed > time.Second {
+		t.Error("fallback took ", elapsed)
+	}
+	attempts, _ := dialer.result()
+	if r := cmp.Diff(attempts, []net.Address{ipv6, ipv4a, ipv4b}); r != "" {
+		t.Error(r)
+	}
+
+	// The family of the winner is tried first next time.
+	dialer.attempts = nil
+	conn, err = DialSystem(ctx, dest, sockopt)
+	common.Must(err)
+	conn.Close()
+	attempts, _ = dialer.result()
+	if r := cmp.Diff(attempts, []net.Address{ipv4a, ipv6, ipv4b}); r != "" {
+		t.Error(r)
+	}
+}
+
+func TestDialHappyEyeballsOptIn(t *testing.T) {
+	ipv6 := net.ParseAddress("2001:db8::1")
+	ipv4 := net.ParseAddress("192.0.2.1")
+	dialer := &raceDialer{
+		winner: ipv4,
+		fail:   map[net.Address]bool{ipv6: true},
+	}
+	UseAlternativeSystemDialer(dialer)
+	defer UseAlternativeSystemDialer(nil)
+
+	// Without the config, a single resolved address is dialed.
+	ctx := happyEyeballsContext(ipv4, ipv6)
+	for _, sockopt := range []*SocketConfig{nil, {}, {HappyEyeballs: &HappyEyeballsConfig{Disabled: true}}} {
+		dialer.attempts = nil
+		if conn, err := DialSystem(ctx, net.TCPDestination(net.DomainAddress("optin.example"), 443), sockopt); err == nil {
+			conn.Close()
+		}
+		if attempts, _ := dialer.result(); len(attempts) != 1 {
+			t.Error("dialed ", attempts, " with ", sockopt)
+		}
+	}
+}
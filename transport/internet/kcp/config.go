@@ -98,6 +98,59 @@ func (c *Config) GetReceivingInFlightSize() uint32 {
 	return size
 }
 
+// IsFECEnabled returns true if forward error correction is configured.
+func (c *Config) IsFECEnabled() bool {
+	return c != nil && c.Fec != nil && c.Fec.DataShards > 0 && c.Fec.ParityShards > 0
+}
+
+// GetFECOverhead returns the number of bytes reserved in each segment for FEC parity headers.
+func (c *Config) GetFECOverhead() uint32 {
+	if !c.IsFECEnabled() {
+		return 0
+	}
+	return FECSegmentOverhead + 8*c.Fec.DataShards + 2
+}
+
+// GetPacketOverhead returns the number of bytes added to each segment by the header and the security.
+func (c *Config) GetPacketOverhead() (uint32, error) {
+	writer := &KCPPacketWriter{}
+	header, err := c.GetPackerHeader()
+	if err != nil {
+		return 0, newError("failed to create packet header").Base(err)
+	}
+	writer.Header = header
+	security, err := c.GetSecurity()
+	if err != nil {
+		return 0, newError("failed to create security").Base(err)
+	}
+	writer.Security = security
+	return uint32(writer.Overhead()), nil
+}
+
+// CheckFEC returns an error if the FEC overhead takes more than half of a segment, given the overhead of packets.
+func (c *Config) CheckFEC(packetOverhead uint32) error {
+	if !c.IsFECEnabled() {
+		return nil
+	}
+	mtu := c.GetMTUValue()
+	if mtu <= packetOverhead+DataSegmentOverhead {
+		return newError("MTU ", mtu, " is too small for packets of ", packetOverhead, " bytes overhead")
+	}
+	mss := mtu - packetOverhead - DataSegmentOverhead
+	if overhead := c.GetFECOverhead(); overhead > mss/2 {
+		return newError("FEC overhead of ", c.Fec.DataShards, " data shards is ", overhead, " bytes, too large for segments of ", mss, " bytes")
+	}
+	return nil
+}
+
+// GetPacingRate returns the maximum number of bytes sent per second, or 0 if pacing is disabled.
+func (c *Config) GetPacingRate() uint64 {
+	if c == nil || !c.Pacing {
+		return 0
+	}
+	return uint64(c.GetUplinkCapacityValue()) * 1024 * 1024
+}
+
 func (c *Config) GetReceivingBufferSize() uint32 {
 	return c.GetReadBufferSize() / c.GetMTUValue()
 }
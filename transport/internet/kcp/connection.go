@@ -171,6 +171,8 @@ type ConnMetadata struct {
 	LocalAddr    net.Addr
 	RemoteAddr   net.Addr
 	Conversation uint16
+	// Dialed is true for connections dialed to the peer, and false for those accepted by listeners.
+	Dialed bool
 }
 
 // Connection is a KCP connection over UDP.
@@ -195,7 +197,10 @@ type Connection struct {
 	receivingWorker *ReceivingWorker
 	sendingWorker   *SendingWorker
 
-	output SegmentWriter
+	output    SegmentWriter
+	fecWriter *FECSegmentWriter
+	fecInput  *FECDecoder
+	peerFEC   uint32
 
 	dataUpdater *Updater
 	pingUpdater *Updater
@@ -220,6 +225,21 @@ func NewConnection(meta ConnMetadata, writer PacketWriter, closer io.Closer, con
 		},
 	}
 
+	if config.IsFECEnabled() {
+		fecWriter, err := NewFECSegmentWriter(writer, config.Fec, conn.isPeerFECEnabled, meta.Dialed)
+		if err == nil {
+			err = config.CheckFEC(uint32(writer.Overhead()))
+		}
+		if err != nil {
+			newError("#", meta.Conversation, " FEC is disabled").Base(err).AtWarning().WriteToLog()
+		} else {
+			conn.fecWriter = fecWriter
+			conn.fecInput = NewFECDecoder()
+			conn.output = NewRetryableWriter(fecWriter)
+			conn.mss -= config.GetFECOverhead()
+		}
+	}
+
 	conn.receivingWorker = NewReceivingWorker(conn)
 	conn.sendingWorker = NewSendingWorker(conn)
 
@@ -540,11 +560,30 @@ func (c *Connection) Terminate() {
 }
 
 func (c *Connection) HandleOption(opt SegmentOption) {
+	if (opt&SegmentOptionFEC) == SegmentOptionFEC && c.fecInput != nil {
+		atomic.StoreUint32(&c.peerFEC, 1)
+	}
 	if (opt & SegmentOptionClose) == SegmentOptionClose {
 		c.OnPeerClosed()
 	}
 }
 
+func (c *Connection) isPeerFECEnabled() bool {
+	return atomic.LoadUint32(&c.peerFEC) == 1
+}
+
+func (c *Connection) processDataSegment(seg *DataSegment) {
+	c.HandleOption(seg.Option)
+	if c.fecInput != nil {
+		c.fecInput.AddData(seg)
+	}
+	c.receivingWorker.ProcessSegment(seg)
+	if c.receivingWorker.IsDataAvailable() {
+		c.dataInput.Signal()
+	}
+	c.dataUpdater.WakeUp()
+}
+
 func (c *Connection) OnPeerClosed() {
 	switch c.State() {
 	case StateReadyToClose:
@@ -566,12 +605,16 @@ func (c *Connection) Input(segments []Segment) {
 
 		switch seg := seg.(type) {
 		case *DataSegment:
+			c.processDataSegment(seg)
+		case *FECSegment:
+			if c.fecInput == nil {
+				seg.Release()
+				break
+			}
 			c.HandleOption(seg.Option)
-			c.receivingWorker.ProcessSegment(seg)
-			if c.receivingWorker.IsDataAvailable() {
-				c.dataInput.Signal()
+			for _, recovered := range c.fecInput.AddParity(seg) {
+				c.processDataSegment(recovered)
 			}
-			c.dataUpdater.WakeUp()
 		case *AckSegment:
 			c.HandleOption(seg.Option)
 			c.sendingWorker.ProcessSegment(current, seg, c.roundTrip.Timeout())
@@ -589,7 +632,7 @@ func (c *Connection) Input(segments []Segment) {
 					c.SetState(StateTerminated)
 				}
 			}
-			if seg.Option == SegmentOptionClose || seg.Command() == CommandTerminate {
+			if (seg.Option&SegmentOptionClose) == SegmentOptionClose || seg.Command() == CommandTerminate {
 				c.dataInput.Signal()
 				c.dataOutput.Signal()
 			}
@@ -635,6 +678,9 @@ func (c *Connection) flush() {
 	// flush acknowledges
 	c.receivingWorker.Flush(current)
 	c.sendingWorker.Flush(current)
+	if c.fecWriter != nil {
+		c.fecWriter.Flush()
+	}
 
 	if current-atomic.LoadUint32(&c.lastPingTime) >= 3000 {
 		c.Ping(current, CommandPing)
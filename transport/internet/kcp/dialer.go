@@ -77,6 +77,7 @@ func DialKCP(ctx context.Context, dest net.Destination, streamSettings *internet
 		LocalAddr:    rawConn.LocalAddr(),
 		RemoteAddr:   rawConn.RemoteAddr(),
 		Conversation: conv,
+		Dialed:       true,
 	}, writer, rawConn, kcpSettings)
 
 	go fetchInput(ctx, rawConn, reader, session)
@@ -0,0 +1,324 @@
+package kcp
+
+import (
+	"encoding/binary"
+	"io"
+	"sync"
+
+	"github.com/klauspost/reedsolomon"
+
+	"github.com/v2fly/v2ray-core/v5/common/buf"
+)
+
+const (
+	// fecShardLengthSize is the size of the length prefix of each data shard.
+	fecShardLengthSize = 2
+	// fecReceivedLimit is the number of received data segments kept for recovery.
+	fecReceivedLimit = 256
+	// fecGroupLimit is the number of groups kept for recovery.
+	fecGroupLimit = 16
+	// fecFlushAge is the number of flushes after which an incomplete group is protected anyway.
+	fecFlushAge = 2
+)
+
+// FECSegmentWriter is a SegmentWriter that sends Reed-Solomon parity segments after every group of data segments,
+// once the peer has advertised SegmentOptionFEC.
+//
+// Segments advertise SegmentOptionFEC back to peers that have advertised it. Writers of dialed connections
+// advertise it first, but not on segments with SegmentOptionClose, as peers without FEC compare options of
+// closing segments to SegmentOptionClose exactly.
+type FECSegmentWriter struct {
+	sync.Mutex
+	buffer       *buf.Buffer
+	writer       io.Writer
+	encoder      reedsolomon.Encoder
+	dataShards   int
+	parityShards int
+	peerEnabled  func() bool
+	initiator    bool
+
+	conv   uint16
+	group  uint32
+	age    int
+	ids    []FECSegmentID
+	shards [][]byte
+}
+
+func NewFECSegmentWriter(writer io.Writer, config *FEC, peerEnabled func() bool, initiator bool) (*FECSegmentWriter, error) {
+	encoder, err := reedsolomon.New(int(config.DataShards), int(config.ParityShards))
+	if err != nil {
+		return nil, newError("failed to create FEC encoder").Base(err)
+	}
+	return &FECSegmentWriter{
+		buffer:       buf.New(),
+		writer:       writer,
+		encoder:      encoder,
+		dataShards:   int(config.DataShards),
+		parityShards: int(config.ParityShards),
+		peerEnabled:  peerEnabled,
+		initiator:    initiator,
+	}, nil
+}
+
+func (w *FECSegmentWriter) Write(seg Segment) error {
+	w.Lock()
+	defer w.Unlock()
+
+	w.buffer.Clear()
+	rawBytes := w.buffer.Extend(seg.ByteSize())
+	seg.Serialize(rawBytes)
+	peerEnabled := w.peerEnabled()
+	if peerEnabled || (w.initiator && SegmentOption(rawBytes[3])&SegmentOptionClose == 0) {
+		rawBytes[3] |= byte(SegmentOptionFEC)
+	}
+	if _, err := w.writer.Write(rawBytes); err != nil {
+		return err
+	}
+
+	if dataSeg, ok := seg.(*DataSegment); ok && peerEnabled {
+		w.conv = dataSeg.Conv
+		w.ids = append(w.ids, FECSegmentID{Number: dataSeg.Number, Timestamp: dataSeg.Timestamp})
+		w.shards = append(w.shards, append([]byte(nil), rawBytes...))
+		if len(w.shards) == w.dataShards {
+			w.flushGroup()
+		}
+	}
+	return nil
+}
+
+// Flush sends parity segments for the current group if it has been incomplete for a while.
+func (w *FECSegmentWriter) Flush() {
+	w.Lock()
+	defer w.Unlock()
+
+	if len(w.shards) == 0 {
+		return
+	}
+	w.age++
+	if w.age >= fecFlushAge {
+		w.flushGroup()
+	}
+}
+
+func (w *FECSegmentWriter) flushGroup() {
+	shardLen := 0
+	for _, raw := range w.shards {
+		if l := len(raw) + fecShardLengthSize; l > shardLen {
+			shardLen = l
+		}
+	}
+
+	shards := make([][]byte, w.dataShards+w.parityShards)
+	for i := range shards {
+		shards[i] = make([]byte, shardLen)
+		if i < len(w.shards) {
+			binary.BigEndian.PutUint16(shards[i], uint16(len(w.shards[i])))
+			copy(shards[i][fecShardLengthSize:], w.shards[i])
+		}
+	}
+
+	if err := w.encoder.Encode(shards); err != nil {
+		newError("failed to encode FEC group ", w.group).Base(err).WriteToLog()
+	} else {
+		for i := 0; i < w.parityShards; i++ {
+			seg := NewFECSegment()
+			seg.Conv = w.conv
+			seg.Option = SegmentOptionFEC
+			seg.Group = w.group
+			seg.Index = byte(i)
+			seg.DataShards = byte(w.dataShards)
+			seg.ParityShards = byte(w.parityShards)
+			seg.Segments = w.ids
+			seg.Data().Write(shards[w.dataShards+i])
+
+			w.buffer.Clear()
+			rawBytes := w.buffer.Extend(seg.ByteSize())
+			seg.Serialize(rawBytes)
+			seg.Release()
+			if _, err := w.writer.Write(rawBytes); err != nil {
+				newError("failed to write FEC segment").Base(err).AtDebug().WriteToLog()
+			}
+		}
+	}
+
+	w.group++
+	w.age = 0
+	w.ids = nil
+	w.shards = nil
+}
+
+type fecGroup struct {
+	segments     []FECSegmentID
+	dataShards   int
+	parityShards int
+	parity       [][]byte
+}
+
+// FECDecoder recovers lost data segments from parity segments sent by a FECSegmentWriter.
+type FECDecoder struct {
+	sync.Mutex
+	received map[FECSegmentID][]byte
+	order    []FECSegmentID
+	groups   map[uint32]*fecGroup
+	latest   uint32
+	encoders map[[2]int]reedsolomon.Encoder
+}
+
+func NewFECDecoder() *FECDecoder {
+	return &FECDecoder{
+		received: make(map[FECSegmentID][]byte),
+		groups:   make(map[uint32]*fecGroup),
+		encoders: make(map[[2]int]reedsolomon.Encoder),
+	}
+}
+
+// AddData records a received data segment so that it can be used to recover other segments of its group.
+func (d *FECDecoder) AddData(seg *DataSegment) {
+	rawBytes := make([]byte, seg.ByteSize())
+	seg.Serialize(rawBytes)
+
+	d.Lock()
+	defer d.Unlock()
+
+	d.addRaw(FECSegmentID{Number: seg.Number, Timestamp: seg.Timestamp}, rawBytes)
+}
+
+func (d *FECDecoder) addRaw(id FECSegmentID, rawBytes []byte) {
+	if _, found := d.received[id]; found {
+		return
+	}
+	if len(d.order) >= fecReceivedLimit {
+		delete(d.received, d.order[0])
+		d.order = d.order[1:]
+	}
+	d.received[id] = rawBytes
+	d.order = append(d.order, id)
+}
+
+func (d *FECDecoder) getEncoder(dataShards, parityShards int) (reedsolomon.Encoder, error) {
+	key := [2]int{dataShards, parityShards}
+	if encoder, found := d.encoders[key]; found {
+		return encoder, nil
+	}
+	encoder, err := reedsolomon.New(dataShards, parityShards)
+	if err != nil {
+		return nil, err
+	}
+	d.encoders[key] = encoder
+	return encoder, nil
+}
+
+// AddParity records a parity segment and returns the data segments recovered with it, if any.
+func (d *FECDecoder) AddParity(seg *FECSegment) []*DataSegment {
+	defer seg.Release()
+
+	d.Lock()
+	defer d.Unlock()
+
+	if seg.DataShards == 0 || seg.ParityShards == 0 || seg.Index >= seg.ParityShards {
+		return nil
+	}
+	if seg.Group-d.latest < 0x7FFFFFFF {
+		d.latest = seg.Group
+	}
+	for id := range d.groups {
+		if d.latest-id >= fecGroupLimit {
+			delete(d.groups, id)
+		}
+	}
+	if d.latest-seg.Group >= fecGroupLimit {
+		return nil
+	}
+
+	group, found := d.groups[seg.Group]
+	if !found {
+		group = &fecGroup{
+			segments:     seg.Segments,
+			dataShards:   int(seg.DataShards),
+			parityShards: int(seg.ParityShards),
+			parity:       make([][]byte, seg.ParityShards),
+		}
+		d.groups[seg.Group] = group
+	}
+	if group == nil || group.dataShards != int(seg.DataShards) || group.parityShards != int(seg.ParityShards) {
+		return nil
+	}
+	group.parity[seg.Index] = append([]byte(nil), seg.Data().Bytes()...)
+
+	recovered := d.recover(group)
+	if recovered != nil {
+		// Mark the group as done, so that later parity segments are ignored.
+		d.groups[seg.Group] = nil
+	}
+	return recovered
+}
+
+func (d *FECDecoder) recover(group *fecGroup) []*DataSegment {
+	shardLen := 0
+	available := 0
+	for _, parity := range group.parity {
+		if parity != nil {
+			shardLen = len(parity)
+			available++
+		}
+	}
+
+	shards := make([][]byte, group.dataShards+group.parityShards)
+	var missing []int
+	for i := 0; i < group.dataShards; i++ {
+		if i >= len(group.segments) {
+			shards[i] = make([]byte, shardLen)
+			available++
+			continue
+		}
+		rawBytes, found := d.received[group.segments[i]]
+		if !found {
+			missing = append(missing, i)
+			continue
+		}
+		if len(rawBytes)+fecShardLengthSize > shardLen {
+			return nil
+		}
+		shards[i] = make([]byte, shardLen)
+		binary.BigEndian.PutUint16(shards[i], uint16(len(rawBytes)))
+		copy(shards[i][fecShardLengthSize:], rawBytes)
+		available++
+	}
+	if len(missing) == 0 {
+		return []*DataSegment{}
+	}
+	if available < group.dataShards {
+		return nil
+	}
+	copy(shards[group.dataShards:], group.parity)
+
+	encoder, err := d.getEncoder(group.dataShards, group.parityShards)
+	if err != nil {
+		return nil
+	}
+	if err := encoder.ReconstructData(shards); err != nil {
+		newError("failed to reconstruct FEC group").Base(err).AtDebug().WriteToLog()
+		return []*DataSegment{}
+	}
+
+	result := make([]*DataSegment, 0, len(missing))
+	for _, i := range missing {
+		shard := shards[i]
+		length := int(binary.BigEndian.Uint16(shard))
+		if length+fecShardLengthSize > len(shard) {
+			continue
+		}
+		rawBytes := shard[fecShardLengthSize : fecShardLengthSize+length]
+		seg, _ := ReadSegment(rawBytes)
+		dataSeg, ok := seg.(*DataSegment)
+		if !ok || dataSeg.Number != group.segments[i].Number {
+			if seg != nil {
+				seg.Release()
+			}
+			continue
+		}
+		d.addRaw(group.segments[i], append([]byte(nil), rawBytes...))
+		result = append(result, dataSeg)
+	}
+	return result
+}
@@ -0,0 +1,145 @@
+package kcp_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	. "github.com/v2fly/v2ray-core/v5/transport/internet/kcp"
+)
+
+type segmentRecorder struct {
+	packets [][]byte
+}
+
+func (r *segmentRecorder) Write(b []byte) (int, error) {
+	r.packets = append(r.packets, append([]byte(nil), b...))
+	return len(b), nil
+}
+
+func TestFECRecovery(t *testing.T) {
+	recorder := &segmentRecorder{}
+	writer, err := NewFECSegmentWriter(recorder, &FEC{DataShards: 3, ParityShards: 1}, func() bool { return true }, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payloads := []string{"first", "second segment", "3"}
+	for i, payload := range payloads {
+		seg := NewDataSegment()
+		seg.Conv = 1
+		seg.Number = uint32(i)
+		seg.Timestamp = 100
+		seg.Data().Write([]byte(payload))
+		if err := writer.Write(seg); err != nil {
+			t.Fatal(err)
+		}
+		seg.Release()
+	}
+	if len(recorder.packets) != 4 {
+		t.Fatal("expect 3 data segments and 1 parity segment, but got ", len(recorder.packets))
+	}
+
+	decoder := NewFECDecoder()
+	// Drop the second data segment.
+	for _, i := range []int{0, 2} {
+		seg, _ := ReadSegment(recorder.packets[i])
+		decoder.AddData(seg.(*DataSegment))
+	}
+	parity, _ := ReadSegment(recorder.packets[3])
+	recovered := decoder.AddParity(parity.(*FECSegment))
+	if len(recovered) != 1 {
+		t.Fatal("expect 1 recovered segment, but got ", len(recovered))
+	}
+	if recovered[0].Number != 1 {
+		t.Error("unexpected segment number: ", recovered[0].Number)
+	}
+	if r := cmp.Diff(string(recovered[0].Data().Bytes()), payloads[1]); r != "" {
+		t.Error(r)
+	}
+}
+
+func TestFECNegotiation(t *testing.T) {
+	dataSegment := func(number uint32) *DataSegment {
+		seg := NewDataSegment()
+		seg.Conv = 1
+		seg.Number = number
+		seg.Data().Write([]byte("payload"))
+		return seg
+	}
+	closeSegment := &CmdOnlySegment{Conv: 1, Cmd: CommandPing, Option: SegmentOptionClose}
+	options := func(r *segmentRecorder) []SegmentOption {
+		var result []SegmentOption
+		for _, packet := range r.packets {
+			result = append(result, SegmentOption(packet[3]))
+		}
+		return result
+	}
+
+	testCases := []struct {
+		name        string
+		initiator   bool
+		peerEnabled bool
+		options     []SegmentOption
+	}{
+		{
+			// Accepted connections do not advertise FEC to peers that may not support it.
+			name:    "accepted",
+			options: []SegmentOption{0, 0, SegmentOptionClose},
+		},
+		{
+			// Dialed connections advertise FEC, but keep options of closing segments intact for legacy peers.
+			name:      "dialed",
+			initiator: true,
+			options:   []SegmentOption{SegmentOptionFEC, SegmentOptionFEC, SegmentOptionClose},
+		},
+		{
+			// Parity segments follow data segments once the peer has advertised FEC.
+			name:        "negotiated",
+			peerEnabled: true,
+			options:     []SegmentOption{SegmentOptionFEC, SegmentOptionFEC, SegmentOptionFEC, SegmentOptionClose | SegmentOptionFEC},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := &segmentRecorder{}
+			peerEnabled := tc.peerEnabled
+			writer, err := NewFECSegmentWriter(recorder, &FEC{DataShards: 2, ParityShards: 1}, func() bool { return peerEnabled }, tc.initiator)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := uint32(0); i < 2; i++ {
+				seg := dataSegment(i)
+				if err := writer.Write(seg); err != nil {
+					t.Fatal(err)
+				}
+				seg.Release()
+			}
+			if err := writer.Write(closeSegment); err != nil {
+				t.Fatal(err)
+			}
+			if r := cmp.Diff(options(recorder), tc.options); r != "" {
+				t.Error(r)
+			}
+		})
+	}
+}
+
+func TestFECOverheadCheck(t *testing.T) {
+	config := &Config{Fec: &FEC{DataShards: 10, ParityShards: 3}}
+	if err := config.CheckFEC(0); err != nil {
+		t.Error(err)
+	}
+
+	config.Fec.DataShards = 200
+	if err := config.CheckFEC(0); err == nil {
+		t.Error("expect an error for FEC overhead larger than the segment")
+	}
+
+	recorder := &segmentRecorder{}
+	conn := NewConnection(ConnMetadata{Conversation: 1, Dialed: true}, &KCPPacketWriter{Writer: recorder}, NoOpCloser(0), config)
+	defer conn.Close()
+	if _, err := conn.Write(make([]byte, 4096)); err != nil {
+		t.Fatal(err)
+	}
+}
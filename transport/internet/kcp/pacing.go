@@ -0,0 +1,47 @@
+package kcp
+
+// pacer is a token bucket that limits the rate of data segments put on the wire,
+// so that a full sending window is spread over time instead of sent in one burst.
+type pacer struct {
+	rate   uint64 // bytes per second
+	burst  uint64
+	tokens uint64
+	last   uint32
+}
+
+func newPacer(rate uint64, tti uint32, mtu uint32) *pacer {
+	burst := rate * uint64(tti) * 2 / 1000
+	if min := uint64(mtu) * 2; burst < min {
+		burst = min
+	}
+	return &pacer{
+		rate:   rate,
+		burst:  burst,
+		tokens: burst,
+	}
+}
+
+func (p *pacer) refill(current uint32) {
+	elapsed := current - p.last
+	if elapsed >= 0x7FFFFFFF {
+		return
+	}
+	p.last = current
+	p.tokens += p.rate * uint64(elapsed) / 1000
+	if p.tokens > p.burst {
+		p.tokens = p.burst
+	}
+}
+
+// Take returns true if size bytes may be sent at the given time.
+func (p *pacer) Take(current uint32, size uint32) bool {
+	if p == nil {
+		return true
+	}
+	p.refill(current)
+	if p.tokens < uint64(size) {
+		return false
+	}
+	p.tokens -= uint64(size)
+	return true
+}
@@ -0,0 +1,62 @@
+package kcp
+
+import "testing"
+
+func TestPacer(t *testing.T) {
+	// 100 KB/s, with a burst of two TTIs of 20 ms, which is 4000 bytes.
+	p := newPacer(100000, 20, 1350)
+	if p.burst != 4000 {
+		t.Fatal("unexpected burst ", p.burst)
+	}
+
+	current := uint32(1000)
+	sent := 0
+	for p.Take(current, 1000) {
+		sent++
+	}
+	if sent != 4 {
+		t.Error("expect a burst of 4 segments, but got ", sent)
+	}
+
+	// 10 ms refills 1000 bytes.
+	current += 10
+	if !p.Take(current, 1000) {
+		t.Error("expect a segment to be sent after refilling")
+	}
+	if p.Take(current, 1000) {
+		t.Error("expect no segment to be sent beyond the rate")
+	}
+
+	// Idle time does not accumulate tokens beyond the burst.
+	current += 10000
+	sent = 0
+	for p.Take(current, 1000) {
+		sent++
+	}
+	if sent != 4 {
+		t.Error("expect a burst of 4 segments after idle, but got ", sent)
+	}
+
+	// Time going backwards does not refill.
+	if p.Take(current-5, 1000) {
+		t.Error("expect no refill from the past")
+	}
+}
+
+func TestPacerMinimumBurst(t *testing.T) {
+	// The burst is at least two MTUs, so that a full segment can always be sent.
+	p := newPacer(1000, 10, 1350)
+	if p.burst != 2700 {
+		t.Error("unexpected burst ", p.burst)
+	}
+	if !p.Take(0, 1350) {
+		t.Error("expect a full segment to be sent")
+	}
+}
+
+func TestNilPacer(t *testing.T) {
+	var p *pacer
+	if !p.Take(0, 1<<20) {
+		t.Error("expect nil pacer to not limit")
+	}
+}
@@ -18,12 +18,16 @@ const (
 	CommandTerminate Command = 2
 	// CommandPing indicates a ping.
 	CommandPing Command = 3
+	// CommandFEC indicates a FECSegment. It is only sent to peers that advertise SegmentOptionFEC.
+	CommandFEC Command = 4
 )
 
 type SegmentOption byte
 
 const (
 	SegmentOptionClose SegmentOption = 1
+	// SegmentOptionFEC advertises that the sender accepts FEC segments.
+	SegmentOptionFEC SegmentOption = 2
 )
 
 type Segment interface {
@@ -273,6 +277,113 @@ func (s *CmdOnlySegment) Serialize(b []byte) {
 
 func (*CmdOnlySegment) Release() {}
 
+// FECSegmentID identifies a data segment protected by a FECSegment.
+type FECSegmentID struct {
+	Number    uint32
+	Timestamp uint32
+}
+
+const (
+	FECSegmentOverhead = 14
+)
+
+// FECSegment carries a Reed-Solomon parity shard computed over a group of data segments.
+type FECSegment struct {
+	Conv         uint16
+	Option       SegmentOption
+	Group        uint32
+	Index        byte
+	DataShards   byte
+	ParityShards byte
+	Segments     []FECSegmentID
+
+	payload *buf.Buffer
+}
+
+func NewFECSegment() *FECSegment {
+	return new(FECSegment)
+}
+
+func (s *FECSegment) parse(conv uint16, cmd Command, opt SegmentOption, buf []byte) (bool, []byte) {
+	s.Conv = conv
+	s.Option = opt
+	if len(buf) < 8 {
+		return false, nil
+	}
+
+	s.Group = binary.BigEndian.Uint32(buf)
+	s.Index = buf[4]
+	s.DataShards = buf[5]
+	s.ParityShards = buf[6]
+	count := int(buf[7])
+	buf = buf[8:]
+
+	if count > int(s.DataShards) || len(buf) < count*8+2 {
+		return false, nil
+	}
+	s.Segments = make([]FECSegmentID, count)
+	for i := range s.Segments {
+		s.Segments[i].Number = binary.BigEndian.Uint32(buf)
+		s.Segments[i].Timestamp = binary.BigEndian.Uint32(buf[4:])
+		buf = buf[8:]
+	}
+
+	dataLen := int(binary.BigEndian.Uint16(buf))
+	buf = buf[2:]
+
+	if len(buf) < dataLen {
+		return false, nil
+	}
+	s.Data().Clear()
+	s.Data().Write(buf[:dataLen])
+	buf = buf[dataLen:]
+
+	return true, buf
+}
+
+func (s *FECSegment) Conversation() uint16 {
+	return s.Conv
+}
+
+func (*FECSegment) Command() Command {
+	return CommandFEC
+}
+
+func (s *FECSegment) Data() *buf.Buffer {
+	if s.payload == nil {
+		s.payload = buf.New()
+	}
+	return s.payload
+}
+
+func (s *FECSegment) ByteSize() int32 {
+	return FECSegmentOverhead + int32(len(s.Segments)*8) + s.payload.Len()
+}
+
+func (s *FECSegment) Serialize(b []byte) {
+	binary.BigEndian.PutUint16(b, s.Conv)
+	b[2] = byte(CommandFEC)
+	b[3] = byte(s.Option)
+	binary.BigEndian.PutUint32(b[4:], s.Group)
+	b[8] = s.Index
+	b[9] = s.DataShards
+	b[10] = s.ParityShards
+	b[11] = byte(len(s.Segments))
+	n := 12
+	for _, id := range s.Segments {
+		binary.BigEndian.PutUint32(b[n:], id.Number)
+		binary.BigEndian.PutUint32(b[n+4:], id.Timestamp)
+		n += 8
+	}
+	binary.BigEndian.PutUint16(b[n:], uint16(s.payload.Len()))
+	copy(b[n+2:], s.payload.Bytes())
+}
+
+func (s *FECSegment) Release() {
+	s.payload.Release()
+	s.payload = nil
+}
+
 func ReadSegment(buf []byte) (Segment, []byte) {
 	if len(buf) < 4 {
 		return nil, nil
@@ -291,6 +402,8 @@ func ReadSegment(buf []byte) (Segment, []byte) {
 		seg = NewDataSegment()
 	case CommandACK:
 		seg = NewAckSegment()
+	case CommandFEC:
+		seg = NewFECSegment()
 	default:
 		seg = NewCmdOnlySegment()
 	}
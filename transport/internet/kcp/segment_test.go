@@ -105,3 +105,32 @@ func TestCmdSegment(t *testing.T) {
 		t.Error(r)
 	}
 }
+
+func TestFECSegment(t *testing.T) {
+	seg := &FECSegment{
+		Conv:         1,
+		Option:       SegmentOptionFEC,
+		Group:        2,
+		Index:        1,
+		DataShards:   3,
+		ParityShards: 2,
+		Segments: []FECSegmentID{
+			{Number: 4, Timestamp: 5},
+			{Number: 6, Timestamp: 7},
+		},
+	}
+	seg.Data().Write([]byte{'a', 'b', 'c', 'd'})
+
+	nBytes := seg.ByteSize()
+	bytes := make([]byte, nBytes)
+	seg.Serialize(bytes)
+
+	iseg, _ := ReadSegment(bytes)
+	seg2 := iseg.(*FECSegment)
+	if r := cmp.Diff(seg2, seg, cmpopts.IgnoreUnexported(FECSegment{})); r != "" {
+		t.Error(r)
+	}
+	if r := cmp.Diff(seg2.Data().Bytes(), seg.Data().Bytes()); r != "" {
+		t.Error(r)
+	}
+}
@@ -12,6 +12,7 @@ type SendingWindow struct {
 	totalInFlightSize uint32
 	writer            SegmentWriter
 	onPacketLoss      func(uint32)
+	pacer             *pacer
 }
 
 func NewSendingWindow(writer SegmentWriter, onPacketLoss func(uint32)) *SendingWindow {
@@ -107,6 +108,9 @@ func (sw *SendingWindow) Flush(current uint32, rto uint32, maxInFlightSize uint3
 		if current-segment.timeout >= 0x7FFFFFFF {
 			return true
 		}
+		if !sw.pacer.Take(current, uint32(segment.ByteSize())) {
+			return false
+		}
 		if segment.transmit == 0 {
 			// First time
 			sw.totalInFlightSize++
@@ -173,6 +177,9 @@ func NewSendingWorker(kcp *Connection) *SendingWorker {
 		windowSize:       kcp.Config.GetSendingBufferSize(),
 	}
 	worker.window = NewSendingWindow(worker, worker.OnPacketLoss)
+	if rate := kcp.Config.GetPacingRate(); rate > 0 {
+		worker.window.pacer = newPacer(rate, kcp.Config.GetTTIValue(), kcp.Config.GetMTUValue())
+	}
 	return worker
 }
 
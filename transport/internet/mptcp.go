@@ -0,0 +1,9 @@
+package internet
+
+import "errors"
+
+var errMultipathTCPUnsupported = errors.New("multipath tcp is not supported")
+
+func useMultipathTCP(network string, sockopt *SocketConfig) bool {
+	return sockopt != nil && sockopt.Mptcp && isTCPSocket(network)
+}
@@ -0,0 +1,239 @@
+package internet
+
+import (
+	"context"
+	gonet "net"
+	"os"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/v2fly/v2ray-core/v5/common/net"
+)
+
+// fdRawConn exposes a raw socket to control functions written for syscall.RawConn.
+type fdRawConn int
+
+func (c fdRawConn) Control(f func(fd uintptr)) error {
+	f(uintptr(c))
+	return nil
+}
+
+func (c fdRawConn) Read(f func(fd uintptr) (done bool)) error {
+	return newError("read on raw mptcp socket is not supported")
+}
+
+func (c fdRawConn) Write(f func(fd uintptr) (done bool)) error {
+	return newError("write on raw mptcp socket is not supported")
+}
+
+func isMultipathTCPUnsupported(err error) bool {
+	return err == unix.EPROTONOSUPPORT || err == unix.EINVAL || err == unix.ENOPROTOOPT || err == unix.EAFNOSUPPORT
+}
+
+func toSockaddr(ip net.IP, port int) (int, string, unix.Sockaddr) {
+	if ip4 := ip.To4(); ip4 != nil {
+		sa := &unix.SockaddrInet4{Port: port}
+		copy(sa.Addr[:], ip4)
+		return unix.AF_INET, "tcp4", sa
+	}
+	sa := &unix.SockaddrInet6{Port: port}
+	copy(sa.Addr[:], ip.To16())
+	return unix.AF_INET6, "tcp6", sa
+}
+
+func newMultipathTCPSocket(family int) (int, error) {
+	fd, err := unix.Socket(family, unix.SOCK_STREAM|unix.SOCK_CLOEXEC, unix.IPPROTO_MPTCP)
+	if err != nil {
+		if isMultipathTCPUnsupported(err) {
+			return -1, errMultipathTCPUnsupported
+		}
+		return -1, newError("failed to create mptcp socket").Base(err)
+	}
+	return fd, nil
+}
+
+func dialMultipathTCP(ctx context.Context, dialer *net.Dialer, dest net.Destination) (net.Conn, error) {
+	if dialer.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, dialer.Timeout)
+		defer cancel()
+	}
+
+	var ips []net.IP
+	if dest.Address.Family().IsDomain() {
+		resolver := dialer.Resolver
+		if resolver == nil {
+			resolver = gonet.DefaultResolver
+		}
+		addrs, err := resolver.LookupIPAddr(ctx, dest.Address.Domain())
+		if err != nil {
+			return nil, newError("failed to resolve ", dest.Address).Base(err)
+		}
+		for _, addr := range addrs {
+			ips = append(ips, addr.IP)
+		}
+		if len(ips) == 0 {
+			return nil, newError("no address found for ", dest.Address)
+		}
+	} else {
+		ips = []net.IP{dest.Address.IP()}
+	}
+
+	// Addresses are tried one by one, like the dialer of the standard library does.
+	var lastErr error
+	for _, ip := range ips {
+		conn, err := dialMultipathTCPAddr(ctx, dialer, dest, ip)
+		if err == nil || err == errMultipathTCPUnsupported {
+			return conn, err
+		}
+		lastErr = err
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, lastErr
+}
+
+func dialMultipathTCPAddr(ctx context.Context, dialer *net.Dialer, dest net.Destination, ip net.IP) (net.Conn, error) {
+	family, network, sockaddr := toSockaddr(ip, int(dest.Port))
+	fd, err := newMultipathTCPSocket(family)
+	if err != nil {
+		return nil, err
+	}
+	if dialer.Control != nil {
+		if err := dialer.Control(network, dest.NetAddr(), fdRawConn(fd)); err != nil {
+			unix.Close(fd)
+			return nil, err
+		}
+	}
+	if local, ok := dialer.LocalAddr.(*net.TCPAddr); ok && local != nil {
+		_, _, localSockaddr := toSockaddr(local.IP, local.Port)
+		if err := unix.Bind(fd, localSockaddr); err != nil {
+			unix.Close(fd)
+			return nil, newError("failed to bind mptcp socket to ", local).Base(err)
+		}
+	}
+	if err := unix.SetNonblock(fd, true); err != nil {
+		unix.Close(fd)
+		return nil, newError("failed to set mptcp socket non-blocking").Base(err)
+	}
+	if err := unix.Connect(fd, sockaddr); err != nil && err != unix.EINPROGRESS {
+		unix.Close(fd)
+		return nil, newError("failed to connect to ", ip).Base(err)
+	}
+
+	// The file of a non-blocking socket is registered to the runtime poller, which waits for
+	// the connection to be established.
+	file := os.NewFile(uintptr(fd), "mptcp")
+	defer file.Close()
+	if err := waitMultipathTCPConnect(ctx, file); err != nil {
+		return nil, newError("failed to connect to ", ip).Base(err)
+	}
+	conn, err := net.FileConn(file)
+	if err != nil {
+		return nil, err
+	}
+	if dialer.KeepAlive >= 0 {
+		if tcpConn, ok := conn.(*net.TCPConn); ok {
+			tcpConn.SetKeepAlive(true)
+		}
+	}
+	return conn, nil
+}
+
+// waitMultipathTCPConnect waits for the socket being connected to be writable, and returns the result of connecting.
+func waitMultipathTCPConnect(ctx context.Context, file *os.File) error {
+	rawConn, err := file.SyscallConn()
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		file.SetWriteDeadline(deadline)
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			// Wakes the waiting write up.
+			file.SetWriteDeadline(time.Unix(1, 0))
+		case <-done:
+		}
+	}()
+
+	var connectErr error
+	err = rawConn.Write(func(fd uintptr) bool {
+		soErr, err := unix.GetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_ERROR)
+		if err != nil {
+			connectErr = err
+			return true
+		}
+		switch syscall.Errno(soErr) {
+		case unix.EINPROGRESS, unix.EALREADY, unix.EINTR:
+			return false
+		case 0:
+			// Writable sockets without errors may be still connecting.
+			if _, err := unix.Getpeername(int(fd)); err == unix.ENOTCONN {
+				return false
+			}
+			return true
+		default:
+			connectErr = syscall.Errno(soErr)
+			return true
+		}
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	if connectErr != nil {
+		return connectErr
+	}
+	return file.SetWriteDeadline(time.Time{})
+}
+
+func listenMultipathTCP(ctx context.Context, lc *net.ListenConfig, addr *net.TCPAddr) (net.Listener, error) {
+	ip := addr.IP
+	if ip == nil {
+		ip = net.AnyIPv6.IP()
+	}
+	family, network, sockaddr := toSockaddr(ip, addr.Port)
+	fd, err := newMultipathTCPSocket(family)
+	if err != nil {
+		return nil, err
+	}
+	success := false
+	defer func() {
+		if !success {
+			unix.Close(fd)
+		}
+	}()
+
+	if err := setReuseAddr(uintptr(fd)); err != nil {
+		return nil, err
+	}
+	if lc.Control != nil {
+		if err := lc.Control(network, addr.String(), fdRawConn(fd)); err != nil {
+			return nil, err
+		}
+	}
+	if err := unix.Bind(fd, sockaddr); err != nil {
+		return nil, newError("failed to bind mptcp socket to ", addr).Base(err)
+	}
+	if err := unix.Listen(fd, unix.SOMAXCONN); err != nil {
+		return nil, newError("failed to listen on mptcp socket").Base(err)
+	}
+
+	success = true
+	file := os.NewFile(uintptr(fd), "mptcp")
+	defer file.Close()
+	l, err := net.FileListener(file)
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+// +build !linux
+
+package internet
+
+import (
+	"context"
+
+	"github.com/v2fly/v2ray-core/v5/common/net"
+)
+
+func dialMultipathTCP(ctx context.Context, dialer *net.Dialer, dest net.Destination) (net.Conn, error) {
+	return nil, errMultipathTCPUnsupported
+}
+
+func listenMultipathTCP(ctx context.Context, lc *net.ListenConfig, addr *net.TCPAddr) (net.Listener, error) {
+	return nil, errMultipathTCPUnsupported
+}
@@ -74,6 +74,10 @@ func applyOutboundSocketOptions(network string, address string, fd uintptr, conf
 				return newError("failed to set SO_KEEPALIVE").Base(err)
 			}
 		}
+
+		if err := applyTCPTuningOptions(fd, config); err != nil {
+			return err
+		}
 	}
 
 	if config.Tproxy.IsEnabled() {
@@ -82,6 +86,12 @@ func applyOutboundSocketOptions(network string, address string, fd uintptr, conf
 		}
 	}
 
+	if config.FreeBind {
+		if err := setFreeBind(fd); err != nil {
+			return err
+		}
+	}
+
 	if config.BindToDevice != "" {
 		if err := unix.BindToDevice(int(fd), config.BindToDevice); err != nil {
 			return newError("failed to set SO_BINDTODEVICE").Base(err)
@@ -144,6 +154,10 @@ func applyInboundSocketOptions(network string, fd uintptr, config *SocketConfig)
 				return newError("failed to set SO_KEEPALIVE", err)
 			}
 		}
+
+		if err := applyTCPTuningOptions(fd, config); err != nil {
+			return err
+		}
 	}
 
 	if config.Tproxy.IsEnabled() {
@@ -152,6 +166,12 @@ func applyInboundSocketOptions(network string, fd uintptr, config *SocketConfig)
 		}
 	}
 
+	if config.FreeBind {
+		if err := setFreeBind(fd); err != nil {
+			return err
+		}
+	}
+
 	if config.ReceiveOriginalDestAddress && isUDPSocket(network) {
 		err1 := syscall.SetsockoptInt(int(fd), syscall.SOL_IPV6, unix.IPV6_RECVORIGDSTADDR, 1)
 		err2 := syscall.SetsockoptInt(int(fd), syscall.SOL_IP, syscall.IP_RECVORIGDSTADDR, 1)
@@ -188,6 +208,34 @@ func applyInboundSocketOptions(network string, fd uintptr, config *SocketConfig)
 	return nil
 }
 
+func setFreeBind(fd uintptr) error {
+	err1 := syscall.SetsockoptInt(int(fd), syscall.SOL_IPV6, unix.IPV6_FREEBIND, 1)
+	err2 := syscall.SetsockoptInt(int(fd), syscall.SOL_IP, unix.IP_FREEBIND, 1)
+	if err1 != nil && err2 != nil {
+		return newError("failed to set IP_FREEBIND").Base(err2)
+	}
+	return nil
+}
+
+func applyTCPTuningOptions(fd uintptr, config *SocketConfig) error {
+	if config.TcpCongestion != "" {
+		if err := unix.SetsockoptString(int(fd), unix.IPPROTO_TCP, unix.TCP_CONGESTION, config.TcpCongestion); err != nil {
+			return newError("failed to set TCP_CONGESTION=", config.TcpCongestion).Base(err)
+		}
+	}
+	if config.TcpUserTimeout > 0 {
+		if err := unix.SetsockoptInt(int(fd), unix.IPPROTO_TCP, unix.TCP_USER_TIMEOUT, int(config.TcpUserTimeout)); err != nil {
+			return newError("failed to set TCP_USER_TIMEOUT").Base(err)
+		}
+	}
+	if config.TcpNotSentLowat > 0 {
+		if err := unix.SetsockoptInt(int(fd), unix.IPPROTO_TCP, unix.TCP_NOTSENT_LOWAT, int(config.TcpNotSentLowat)); err != nil {
+			return newError("failed to set TCP_NOTSENT_LOWAT").Base(err)
+		}
+	}
+	return nil
+}
+
 func setReuseAddr(fd uintptr) error {
 	if err := syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_REUSEADDR, 1); err != nil {
 		return newError("failed to set SO_REUSEADDR").Base(err).AtWarning()
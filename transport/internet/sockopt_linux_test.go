@@ -2,9 +2,13 @@ package internet_test
 
 import (
 	"context"
+	"io"
+	"strings"
 	"syscall"
 	"testing"
 
+	"golang.org/x/sys/unix"
+
 	"github.com/v2fly/v2ray-core/v5/common"
 	"github.com/v2fly/v2ray-core/v5/common/net"
 	"github.com/v2fly/v2ray-core/v5/testing/servers/tcp"
@@ -40,3 +44,132 @@ func TestSockOptMark(t *testing.T) {
 	})
 	common.Must(err)
 }
+
+func TestSockOptTCPTuning(t *testing.T) {
+	tcpServer := tcp.Server{
+		MsgProcessor: func(b []byte) []byte {
+			return b
+		},
+	}
+	dest, err := tcpServer.Start()
+	common.Must(err)
+	defer tcpServer.Close()
+
+	const userTimeout = 10000
+	dialer := DefaultSystemDialer{}
+	conn, err := dialer.Dial(context.Background(), nil, dest, &SocketConfig{
+		TcpCongestion:  "reno",
+		TcpUserTimeout: userTimeout,
+	})
+	common.Must(err)
+	defer conn.Close()
+
+	rawConn, err := conn.(*net.TCPConn).SyscallConn()
+	common.Must(err)
+	err = rawConn.Control(func(fd uintptr) {
+		cc, err := unix.GetsockoptString(int(fd), unix.IPPROTO_TCP, unix.TCP_CONGESTION)
+		common.Must(err)
+		if cc = strings.TrimRight(cc, "\x00"); cc != "reno" {
+			t.Error("unexpected congestion control ", cc)
+		}
+		timeout, err := unix.GetsockoptInt(int(fd), unix.IPPROTO_TCP, unix.TCP_USER_TIMEOUT)
+		common.Must(err)
+		if timeout != userTimeout {
+			t.Error("unexpected user timeout ", timeout, " want ", userTimeout)
+		}
+	})
+	common.Must(err)
+}
+
+func TestSockOptFreeBind(t *testing.T) {
+	tcpServer := tcp.Server{
+		MsgProcessor: func(b []byte) []byte {
+			return b
+		},
+	}
+	dest, err := tcpServer.Start()
+	common.Must(err)
+	defer tcpServer.Close()
+
+	dialer := DefaultSystemDialer{}
+	conn, err := dialer.Dial(context.Background(), nil, dest, &SocketConfig{FreeBind: true})
+	common.Must(err)
+	defer conn.Close()
+
+	rawConn, err := conn.(*net.TCPConn).SyscallConn()
+	common.Must(err)
+	err = rawConn.Control(func(fd uintptr) {
+		v, err := unix.GetsockoptInt(int(fd), unix.SOL_IP, unix.IP_FREEBIND)
+		common.Must(err)
+		if v != 1 {
+			t.Error("IP_FREEBIND is not set")
+		}
+	})
+	common.Must(err)
+}
+
+// mptcpInfo is MPTCP_INFO of SOL_MPTCP, which fails on sockets not using MPTCP or fallen back to TCP.
+const mptcpInfo = 1
+
+func isUsingMultipathTCP(conn net.Conn) bool {
+	rawConn, err := conn.(syscall.Conn).SyscallConn()
+	common.Must(err)
+	var protocol int
+	var infoErr error
+	common.Must(rawConn.Control(func(fd uintptr) {
+		protocol, err = unix.GetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_PROTOCOL)
+		_, infoErr = unix.GetsockoptString(int(fd), unix.SOL_MPTCP, mptcpInfo)
+	}))
+	common.Must(err)
+	return protocol == unix.IPPROTO_MPTCP && infoErr == nil
+}
+
+func TestSockOptMultipathTCP(t *testing.T) {
+	if fd, err := unix.Socket(unix.AF_INET, unix.SOCK_STREAM, unix.IPPROTO_MPTCP); err != nil {
+		t.Skip("mptcp is not supported: ", err)
+	} else {
+		unix.Close(fd)
+	}
+
+	sockopt := &SocketConfig{Mptcp: true}
+	listener, err := ListenSystem(context.Background(), &net.TCPAddr{IP: net.LocalHostIP.IP()}, sockopt)
+	common.Must(err)
+	defer listener.Close()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			close(accepted)
+			return
+		}
+		io.Copy(conn, conn)
+		accepted <- conn
+	}()
+
+	dest := net.DestinationFromAddr(listener.Addr())
+	dialer := DefaultSystemDialer{}
+	conn, err := dialer.Dial(context.Background(), nil, dest, sockopt)
+	common.Must(err)
+	defer conn.Close()
+
+	payload := []byte("mptcp")
+	common.Must2(conn.Write(payload))
+	b := make([]byte, len(payload))
+	common.Must2(io.ReadFull(conn, b))
+	if string(b) != string(payload) {
+		t.Error("unexpected response ", string(b))
+	}
+	if !isUsingMultipathTCP(conn) {
+		t.Error("dialed connection does not use mptcp")
+	}
+	common.Must(conn.(*net.TCPConn).CloseWrite())
+	if serverConn := <-accepted; serverConn == nil {
+		t.Error("failed to accept")
+	} else {
+		if !isUsingMultipathTCP(serverConn) {
+			t.Error("accepted connection does not use mptcp")
+		}
+		serverConn.Close()
+	}
+}
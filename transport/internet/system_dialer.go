@@ -41,6 +41,16 @@ func hasBindAddr(sockopt *SocketConfig) bool {
 	return sockopt != nil && len(sockopt.BindAddress) > 0 && sockopt.BindPort > 0
 }
 
+// dialUnixgram connects a datagram unix socket to the given path or abstract name.
+// On linux the local end is auto-bound to a unique abstract name, so that the peer is
+// able to send replies back.
+func dialUnixgram(ctx context.Context, name string) (net.Conn, error) {
+	dialer := &net.Dialer{
+		Control: autobindUnixSocket,
+	}
+	return dialer.DialContext(ctx, "unixgram", unixSocketName(name))
+}
+
 func (d *DefaultSystemDialer) Dial(ctx context.Context, src net.Address, dest net.Destination, sockopt *SocketConfig) (net.Conn, error) {
 	if dest.Network == net.Network_UDP && !hasBindAddr(sockopt) {
 		srcAddr := resolveSrcAddr(net.Network_UDP, src)
@@ -96,6 +106,14 @@ func (d *DefaultSystemDialer) Dial(ctx context.Context, src net.Address, dest ne
 		}
 	}
 
+	if useMultipathTCP(dest.Network.SystemString(), sockopt) {
+		conn, err := dialMultipathTCP(ctx, dialer, dest)
+		if err != errMultipathTCPUnsupported {
+			return conn, err
+		}
+		newError("multipath tcp is not supported, falling back to tcp").AtWarning().WriteToLog(session.ExportIDToError(ctx))
+	}
+
 	return dialer.DialContext(ctx, dest.Network.SystemString(), dest.NetAddr())
 }
 
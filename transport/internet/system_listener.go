@@ -2,6 +2,7 @@ package internet
 
 import (
 	"context"
+	"os"
 	"runtime"
 	"syscall"
 	"time"
@@ -53,18 +54,24 @@ func (dl *DefaultListener) Listen(ctx context.Context, addr net.Addr, sockopt *S
 		if sockopt != nil && (sockopt.TcpKeepAliveInterval != 0 || sockopt.TcpKeepAliveIdle != 0) {
 			lc.KeepAlive = time.Duration(-1)
 		}
+		if useMultipathTCP(network, sockopt) {
+			l, err = listenMultipathTCP(ctx, &lc, addr)
+			if err == nil {
+				return wrapProxyProtocolListener(l, sockopt), nil
+			}
+			if err != errMultipathTCPUnsupported {
+				return nil, err
+			}
+			newError("multipath tcp is not supported, falling back to tcp").AtWarning().WriteToLog(session.ExportIDToError(ctx))
+		}
 	case *net.UnixAddr:
 		lc.Control = nil
 		network = addr.Network()
 		address = addr.Name
-		if (runtime.GOOS == "linux" || runtime.GOOS == "android") && address[0] == '@' {
+		if (runtime.GOOS == "linux" || runtime.GOOS == "android") && isAbstractUnixSocket(address) {
 			// linux abstract unix domain socket is lockfree
-			if len(address) > 1 && address[1] == '@' {
-				// but may need padding to work with haproxy
-				fullAddr := make([]byte, len(syscall.RawSockaddrUnix{}.Path))
-				copy(fullAddr, address[1:])
-				address = string(fullAddr)
-			}
+			// but may need padding to work with haproxy
+			address = unixSocketName(address)
 		} else {
 			// normal unix domain socket needs lock
 			locker := &FileLocker{
@@ -79,16 +86,67 @@ func (dl *DefaultListener) Listen(ctx context.Context, addr net.Addr, sockopt *S
 	}
 
 	l, err = lc.Listen(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := addr.(*net.UnixAddr); ok {
+		if err := ApplyUnixSocketPermissions(address, sockopt); err != nil {
+			l.Close()
+			return nil, err
+		}
+	}
+	return wrapProxyProtocolListener(l, sockopt), nil
+}
+
+func listenUnixgram(ctx context.Context, lc *net.ListenConfig, addr *net.UnixAddr, sockopt *SocketConfig) (net.PacketConn, error) {
+	address := addr.Name
+	if (runtime.GOOS == "linux" || runtime.GOOS == "android") && isAbstractUnixSocket(address) {
+		return lc.ListenPacket(ctx, "unixgram", unixSocketName(address))
+	}
+
+	// Unlike stream listeners, datagram sockets leave their file behind, so a stale file is
+	// removed once the lock guarantees that no other instance is using it.
+	locker := &FileLocker{
+		path: address + ".lock",
+	}
+	if err := locker.Acquire(); err != nil {
+		return nil, err
+	}
+	if err := os.Remove(address); err != nil && !os.IsNotExist(err) {
+		locker.Release()
+		return nil, newError("failed to remove stale unix socket ", address).Base(err)
+	}
+	conn, err := lc.ListenPacket(ctx, "unixgram", address)
+	if err != nil {
+		locker.Release()
+		return nil, err
+	}
+	pc := &unixPacketConn{
+		UnixConn: conn.(*net.UnixConn),
+		locker:   locker,
+	}
+	if err := ApplyUnixSocketPermissions(address, sockopt); err != nil {
+		pc.Close()
+		return nil, err
+	}
+	return pc, nil
+}
+
+func wrapProxyProtocolListener(l net.Listener, sockopt *SocketConfig) net.Listener {
 	if sockopt != nil && sockopt.AcceptProxyProtocol {
 		policyFunc := func(upstream net.Addr) (proxyproto.Policy, error) { return proxyproto.REQUIRE, nil }
 		l = &proxyproto.Listener{Listener: l, Policy: policyFunc}
 	}
-	return l, err
+	return l
 }
 
 func (dl *DefaultListener) ListenPacket(ctx context.Context, addr net.Addr, sockopt *SocketConfig) (net.PacketConn, error) {
 	var lc net.ListenConfig
 
+	if addr, ok := addr.(*net.UnixAddr); ok {
+		return listenUnixgram(ctx, &lc, addr, sockopt)
+	}
+
 	lc.Control = getControlFunc(ctx, sockopt, dl.controllers)
 
 	return lc.ListenPacket(ctx, addr.Network(), addr.String())
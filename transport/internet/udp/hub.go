@@ -24,7 +24,7 @@ func HubReceiveOriginalDestination(r bool) HubOption {
 }
 
 type Hub struct {
-	conn         *net.UDPConn
+	conn         net.PacketConn
 	cache        chan *udp.Packet
 	capacity     int
 	recvOrigDest bool
@@ -47,15 +47,27 @@ func ListenUDP(ctx context.Context, address net.Address, port net.Port, streamSe
 		hub.recvOrigDest = true
 	}
 
-	udpConn, err := internet.ListenSystemPacket(ctx, &net.UDPAddr{
-		IP:   address.IP(),
-		Port: int(port),
-	}, sockopt)
-	if err != nil {
-		return nil, err
+	if internet.IsUnixSocketAddress(address) {
+		unixConn, err := internet.ListenSystemPacket(ctx, &net.UnixAddr{
+			Name: address.Domain(),
+			Net:  "unixgram",
+		}, sockopt)
+		if err != nil {
+			return nil, newError("failed to listen unix datagram socket on ", address).Base(err)
+		}
+		newError("listening unix datagram socket on ", address).WriteToLog()
+		hub.conn = unixConn
+	} else {
+		udpConn, err := internet.ListenSystemPacket(ctx, &net.UDPAddr{
+			IP:   address.IP(),
+			Port: int(port),
+		}, sockopt)
+		if err != nil {
+			return nil, err
+		}
+		newError("listening UDP on ", address, ":", port).WriteToLog()
+		hub.conn = udpConn.(*net.UDPConn)
 	}
-	newError("listening UDP on ", address, ":", port).WriteToLog()
-	hub.conn = udpConn.(*net.UDPConn)
 	hub.cache = make(chan *udp.Packet, hub.capacity)
 
 	go hub.start()
@@ -69,7 +81,13 @@ func (h *Hub) Close() error {
 }
 
 func (h *Hub) WriteTo(payload []byte, dest net.Destination) (int, error) {
-	return h.conn.WriteToUDP(payload, &net.UDPAddr{
+	if dest.Network == net.Network_UNIX {
+		return h.conn.WriteTo(payload, &net.UnixAddr{
+			Name: dest.Address.Domain(),
+			Net:  "unixgram",
+		})
+	}
+	return h.conn.WriteTo(payload, &net.UDPAddr{
 		IP:   dest.Address.IP(),
 		Port: int(dest.Port),
 	})
@@ -79,6 +97,12 @@ func (h *Hub) start() {
 	c := h.cache
 	defer close(c)
 
+	udpConn, ok := h.conn.(*net.UDPConn)
+	if !ok {
+		h.startUnix()
+		return
+	}
+
 	oobBytes := make([]byte, 256)
 
 	for {
@@ -87,7 +111,7 @@ func (h *Hub) start() {
 		var addr *net.UDPAddr
 		rawBytes := buffer.Extend(buf.Size)
 
-		n, noob, _, addr, err := ReadUDPMsg(h.conn, rawBytes, oobBytes)
+		n, noob, _, addr, err := ReadUDPMsg(udpConn, rawBytes, oobBytes)
 		if err != nil {
 			newError("failed to read UDP msg").Base(err).WriteToLog()
 			buffer.Release()
@@ -122,6 +146,45 @@ func (h *Hub) start() {
 	}
 }
 
+// startUnix receives datagrams from a unix socket. Peers are identified by the name of
+// the socket they are bound to, and replies are only possible to bound peers.
+func (h *Hub) startUnix() {
+	c := h.cache
+	for {
+		buffer := buf.New()
+		rawBytes := buffer.Extend(buf.Size)
+
+		n, addr, err := h.conn.ReadFrom(rawBytes)
+		if err != nil {
+			newError("failed to read unix datagram").Base(err).WriteToLog()
+			buffer.Release()
+			break
+		}
+		buffer.Resize(0, int32(n))
+
+		if buffer.IsEmpty() {
+			buffer.Release()
+			continue
+		}
+
+		var name string
+		if addr != nil {
+			name = addr.String()
+		}
+		payload := &udp.Packet{
+			Payload: buffer,
+			Source:  net.UnixDestination(net.DomainAddress(name)),
+		}
+
+		select {
+		case c <- payload:
+		default:
+			buffer.Release()
+			payload.Payload = nil
+		}
+	}
+}
+
 // Addr implements net.Listener.
 func (h *Hub) Addr() net.Addr {
 	return h.conn.LocalAddr()
@@ -0,0 +1,109 @@
+package internet
+
+import (
+	"os"
+	"os/user"
+	"strconv"
+	"syscall"
+
+	"github.com/v2fly/v2ray-core/v5/common/net"
+)
+
+// IsUnixSocketAddress returns true if the address is a path of a unix domain socket,
+// or a name in the abstract namespace prefixed with '@'.
+func IsUnixSocketAddress(addr net.Address) bool {
+	if addr == nil || !addr.Family().IsDomain() {
+		return false
+	}
+	domain := addr.Domain()
+	return len(domain) > 0 && (domain[0] == '/' || domain[0] == '@')
+}
+
+// isAbstractUnixSocket returns true if the name refers to the linux abstract namespace.
+func isAbstractUnixSocket(name string) bool {
+	return len(name) > 0 && name[0] == '@'
+}
+
+// unixSocketName converts a configured socket name into the name passed to the system.
+// An abstract name prefixed with "@@" is padded to the full length of sun_path, as some
+// apps, eg. haproxy, expect.
+func unixSocketName(name string) string {
+	if len(name) > 1 && name[0] == '@' && name[1] == '@' {
+		fullAddr := make([]byte, len(syscall.RawSockaddrUnix{}.Path))
+		copy(fullAddr, name[1:])
+		return string(fullAddr)
+	}
+	return name
+}
+
+func lookupUnixSocketOwner(owner string) (int, error) {
+	if owner == "" {
+		return -1, nil
+	}
+	if uid, err := strconv.Atoi(owner); err == nil {
+		return uid, nil
+	}
+	u, err := user.Lookup(owner)
+	if err != nil {
+		return -1, newError("unknown unix socket owner: ", owner).Base(err)
+	}
+	return strconv.Atoi(u.Uid)
+}
+
+func lookupUnixSocketGroup(group string) (int, error) {
+	if group == "" {
+		return -1, nil
+	}
+	if gid, err := strconv.Atoi(group); err == nil {
+		return gid, nil
+	}
+	g, err := user.LookupGroup(group)
+	if err != nil {
+		return -1, newError("unknown unix socket group: ", group).Base(err)
+	}
+	return strconv.Atoi(g.Gid)
+}
+
+// ApplyUnixSocketPermissions sets the file mode and ownership of a unix domain socket
+// on the file system as configured in sockopt. Abstract sockets are left untouched.
+func ApplyUnixSocketPermissions(name string, sockopt *SocketConfig) error {
+	if sockopt == nil || isAbstractUnixSocket(name) {
+		return nil
+	}
+	if sockopt.UnixSocketMode != 0 {
+		if err := os.Chmod(name, os.FileMode(sockopt.UnixSocketMode)&os.ModePerm); err != nil {
+			return newError("failed to set mode of unix socket ", name).Base(err)
+		}
+	}
+	if sockopt.UnixSocketOwner == "" && sockopt.UnixSocketGroup == "" {
+		return nil
+	}
+	uid, err := lookupUnixSocketOwner(sockopt.UnixSocketOwner)
+	if err != nil {
+		return err
+	}
+	gid, err := lookupUnixSocketGroup(sockopt.UnixSocketGroup)
+	if err != nil {
+		return err
+	}
+	if err := os.Lchown(name, uid, gid); err != nil {
+		return newError("failed to set owner of unix socket ", name).Base(err)
+	}
+	return nil
+}
+
+// unixPacketConn is a datagram unix socket bound to a path on the file system.
+// The socket file is removed when the connection is closed.
+type unixPacketConn struct {
+	*net.UnixConn
+	locker *FileLocker
+}
+
+func (c *unixPacketConn) Close() error {
+	err := c.UnixConn.Close()
+	if err := os.Remove(c.LocalAddr().String()); err != nil && !os.IsNotExist(err) {
+		newError("failed to remove unix socket ", c.LocalAddr()).Base(err).WriteToLog()
+	}
+	c.locker.Release()
+	return err
+}
@@ -0,0 +1,24 @@
+//go:build linux
+// +build linux
+
+package internet
+
+import (
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// autobindUnixSocket binds a unix socket to a unique name in the abstract namespace.
+func autobindUnixSocket(network, address string, c syscall.RawConn) error {
+	var err error
+	if ctrlErr := c.Control(func(fd uintptr) {
+		err = unix.Bind(int(fd), &unix.SockaddrUnix{})
+	}); ctrlErr != nil {
+		return ctrlErr
+	}
+	if err != nil {
+		return newError("failed to auto-bind unix socket").Base(err)
+	}
+	return nil
+}
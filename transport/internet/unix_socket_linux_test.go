@@ -0,0 +1,80 @@
+package internet_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/v2fly/v2ray-core/v5/common"
+	"github.com/v2fly/v2ray-core/v5/common/net"
+	"github.com/v2fly/v2ray-core/v5/transport/internet"
+)
+
+func TestUnixDatagramSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dgram.sock")
+
+	conn, err := internet.ListenSystemPacket(context.Background(), &net.UnixAddr{
+		Name: path,
+		Net:  "unixgram",
+	}, &internet.SocketConfig{
+		UnixSocketMode: 0600,
+	})
+	common.Must(err)
+
+	info, err := os.Stat(path)
+	common.Must(err)
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Error("unexpected socket mode: ", mode)
+	}
+
+	client, err := internet.DialSystem(context.Background(), net.UDPDestination(net.DomainAddress("www.v2fly.org"), 53), &internet.SocketConfig{
+		DialUnixDatagram: path,
+	})
+	common.Must(err)
+	defer client.Close()
+
+	common.Must2(client.Write([]byte("ping")))
+
+	b := make([]byte, 16)
+	n, addr, err := conn.ReadFrom(b)
+	common.Must(err)
+	if string(b[:n]) != "ping" {
+		t.Error("unexpected request: ", string(b[:n]))
+	}
+
+	common.Must2(conn.WriteTo([]byte("pong"), addr))
+	n, err = client.Read(b)
+	common.Must(err)
+	if string(b[:n]) != "pong" {
+		t.Error("unexpected response: ", string(b[:n]))
+	}
+
+	common.Must(conn.Close())
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected socket file to be removed, but got ", err)
+	}
+}
+
+func TestUnixDatagramSocketNotDialedByDestination(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dgram.sock")
+
+	conn, err := internet.ListenSystemPacket(context.Background(), &net.UnixAddr{
+		Name: path,
+		Net:  "unixgram",
+	}, nil)
+	common.Must(err)
+	defer conn.Close()
+
+	// Destinations looking like unix socket paths are not dialed as unix sockets.
+	client, err := internet.DialSystem(context.Background(), net.UDPDestination(net.DomainAddress(path), 53), nil)
+	if err == nil {
+		client.Write([]byte("ping"))
+		client.Close()
+	}
+	common.Must(conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond)))
+	if n, _, err := conn.ReadFrom(make([]byte, 16)); err == nil {
+		t.Error("unexpected datagram of ", n, " bytes")
+	}
+}
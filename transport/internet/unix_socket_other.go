@@ -0,0 +1,14 @@
+//go:build !linux
+// +build !linux
+
+package internet
+
+import (
+	"syscall"
+)
+
+// autobindUnixSocket is a no-op without the abstract namespace. Datagram sockets dialed
+// on such systems are unable to receive replies.
+func autobindUnixSocket(network, address string, c syscall.RawConn) error {
+	return nil
+}